@@ -2,12 +2,17 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -15,8 +20,12 @@ import (
 	"github.com/jmylchreest/go-decluttarr/internal/config"
 	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
 	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs/manage"
 	"github.com/jmylchreest/go-decluttarr/internal/jobs/removal"
 	"github.com/jmylchreest/go-decluttarr/internal/logging"
+	"github.com/jmylchreest/go-decluttarr/internal/notify"
+	"github.com/jmylchreest/go-decluttarr/internal/server"
+	"github.com/jmylchreest/go-decluttarr/internal/strikes"
 	"github.com/jmylchreest/go-decluttarr/internal/version"
 )
 
@@ -25,6 +34,11 @@ func main() {
 	configPath := flag.String("config", "", "Path to config file (default: ./config.yaml or /app/config.yaml)")
 	dataDir := flag.String("data", "./data", "Directory for persistent data (strikes, etc.)")
 	showVersion := flag.Bool("version", false, "Show version and exit")
+	dumpSchema := flag.Bool("dump-schema", false, "Print the config JSON Schema and exit")
+	healthcheck := flag.Bool("healthcheck", false, "Check the health of a running instance and exit (0 = healthy)")
+	purgeStrikes := flag.Bool("purge-strikes", false, "Remove stale entries from the strikes file and exit")
+	olderThan := flag.Duration("older-than", strikes.DefaultMaxAge, "With -purge-strikes, remove strikes not seen for this long")
+	importConfig := flag.String("import-config", "", "Convert a config of the original (Python) decluttarr to YAML on stdout and exit")
 	flag.Parse()
 
 	if *showVersion {
@@ -37,6 +51,28 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *dumpSchema {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(config.Schema()); err != nil {
+			slog.Error("failed to write config schema", "error", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if *healthcheck {
+		os.Exit(runHealthcheck(*configPath))
+	}
+
+	if *purgeStrikes {
+		os.Exit(runPurgeStrikes(filepath.Join(*dataDir, "strikes.json"), *olderThan, os.Stdout))
+	}
+
+	if *importConfig != "" {
+		os.Exit(runImportConfig(*importConfig, os.Stdout, os.Stderr))
+	}
+
 	// Load config
 	cfg, err := config.Load(*configPath)
 	if err != nil {
@@ -66,28 +102,60 @@ func main() {
 	strikesPath := filepath.Join(*dataDir, "strikes.json")
 	manager := jobs.NewManager(cfg, logger, strikesPath)
 	defer manager.Close()
+	if manager.GetStrikesHandler().MemoryOnly() && cfg.General.RequireWritableData {
+		logger.Error("data directory is not writable and require_writable_data is set, exiting", "data_dir", *dataDir)
+		os.Exit(1)
+	}
 
-	registerAllJobs(manager, cfg, logger)
+	registerClients(manager, cfg, logger)
+	notifier := notify.NewRegistry(cfg.Notifications, logger)
+	runner := newCycleRunner(manager, notifier, cfg, logger)
 
 	// Setup graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+
+	// Start HTTP server for health checks
+	if cfg.Server.Enabled {
+		srv := server.New(cfg.Server.ListenAddress(), manager, logger)
+		if cfg.Server.APIToken != "" {
+			srv.EnableRun(cfg.Server.APIToken, func() bool { return runner.start(ctx) })
+		}
+		if err := srv.Start(); err != nil {
+			logger.Error("failed to start http server", "error", err)
+			os.Exit(1)
+		}
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = srv.Shutdown(shutdownCtx)
+		}()
+	}
 
 	// Main loop
 	ticker := time.NewTicker(cfg.General.Timer)
 	defer ticker.Stop()
 
 	// Run immediately on startup
-	runCycle(ctx, manager, logger, cfg.General.TestRun)
+	runner.run(ctx)
 
 	for {
 		select {
 		case <-ticker.C:
-			runCycle(ctx, manager, logger, cfg.General.TestRun)
+			runner.run(ctx)
+		case <-reloadChan:
+			// Wait for any cycle started over HTTP, so clients aren't
+			// swapped out under it
+			runner.exclusive(func() {
+				reloadAPIKeys(*configPath, manager, logger)
+			})
 		case <-sigChan:
 			logger.Info("shutdown signal received")
 			cancel()
+			runner.wait()
 			return
 		case <-ctx.Done():
 			return
@@ -95,140 +163,431 @@ func main() {
 	}
 }
 
-func runCycle(ctx context.Context, manager *jobs.Manager, logger *slog.Logger, testRun bool) {
+// reloadAPIKeys re-reads the config file and rebuilds the clients of *arr
+// instances whose API key was rotated, keeping strikes and search history
+func reloadAPIKeys(configPath string, manager *jobs.Manager, logger *slog.Logger) {
+	newCfg, err := config.Load(configPath)
+	if err != nil {
+		logger.Error("failed to reload config, keeping current API keys", "error", err)
+		return
+	}
+
+	rebuilt := manager.Reconfigure(newCfg, arrClientFactory(newCfg, logger))
+	logger.Info("reloaded config", "rotated_api_keys", rebuilt)
+}
+
+// runHealthcheck probes the local instance's /healthz endpoint, returning the
+// process exit code. The port comes from config, falling back to the default
+// if the config can't be loaded.
+func runHealthcheck(configPath string) int {
+	port := config.DefaultServerPort
+	if cfg, err := config.Load(configPath); err == nil {
+		if !cfg.Server.Enabled {
+			fmt.Fprintln(os.Stderr, "healthcheck: http server is disabled")
+			return 1
+		}
+		port = cfg.Server.Port
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/healthz", port)
+	if err := server.Check(ctx, url); err != nil {
+		fmt.Fprintf(os.Stderr, "healthcheck: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// runPurgeStrikes removes strikes not seen within olderThan from the strikes
+// file, returning the process exit code
+func runPurgeStrikes(path string, olderThan time.Duration, out io.Writer) int {
+	if olderThan < 0 {
+		fmt.Fprintln(os.Stderr, "purge-strikes: -older-than cannot be negative")
+		return 1
+	}
+
+	removed, remaining, err := strikes.Purge(path, olderThan, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "purge-strikes: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(out, "purged %d stale strikes from %s (%d remaining)\n", removed, path, remaining)
+	return 0
+}
+
+// runImportConfig converts a legacy decluttarr config to go-decluttarr YAML on
+// out, listing settings with no equivalent on errOut
+func runImportConfig(path string, out, errOut io.Writer) int {
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(errOut, "import-config: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	unmapped, err := config.ImportLegacy(f, out)
+	if err != nil {
+		fmt.Fprintf(errOut, "import-config: %v\n", err)
+		return 1
+	}
+
+	if len(unmapped) > 0 {
+		fmt.Fprintf(errOut, "import-config: settings without an equivalent were skipped: %s\n", strings.Join(unmapped, ", "))
+	}
+	return 0
+}
+
+// cycleRunner runs cycles, forcing test-run mode for the first cycle after
+// startup when general.first_run_dry_run is set. Only one cycle runs at a
+// time, whether started by the timer or over HTTP.
+type cycleRunner struct {
+	mu          sync.Mutex // held while a cycle runs
+	manager     *jobs.Manager
+	notifier    *notify.Registry
+	cfg         *config.Config
+	logger      *slog.Logger
+	sampler     *logging.Sampler // thins out job debug lines, reset each cycle
+	firstCycle  bool
+	registered  bool
+	jobsTestRun bool // test-run mode the registered jobs are in
+}
+
+// newCycleRunner creates a cycle runner; jobs are registered on the first run
+func newCycleRunner(manager *jobs.Manager, notifier *notify.Registry, cfg *config.Config, logger *slog.Logger) *cycleRunner {
+	return &cycleRunner{
+		manager:    manager,
+		notifier:   notifier,
+		cfg:        cfg,
+		logger:     logger,
+		sampler:    logging.NewSampler(cfg.General.DebugLogSampling),
+		firstCycle: true,
+	}
+}
+
+// run executes a single cycle, returning false without running it if another
+// cycle is still in progress
+func (r *cycleRunner) run(ctx context.Context) bool {
+	if !r.mu.TryLock() {
+		r.logger.Info("skipping cycle, the previous cycle is still running")
+		return false
+	}
+	defer r.mu.Unlock()
+
+	r.cycle(ctx)
+	return true
+}
+
+// start begins a cycle in the background, returning false if another cycle
+// is still in progress
+func (r *cycleRunner) start(ctx context.Context) bool {
+	if !r.mu.TryLock() {
+		return false
+	}
+
+	go func() {
+		defer r.mu.Unlock()
+		r.cycle(ctx)
+	}()
+	return true
+}
+
+// wait blocks until any cycle in progress has finished
+func (r *cycleRunner) wait() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+}
+
+// exclusive runs fn once any cycle in progress has finished, with no cycle
+// starting until it returns
+func (r *cycleRunner) exclusive(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn()
+}
+
+// cycle executes a single cycle, switching the registered jobs' test-run mode
+// when it changes. Jobs keep their state (e.g. the downloads they paused)
+// across the switch. The caller must hold r.mu.
+func (r *cycleRunner) cycle(ctx context.Context) {
+	testRun := r.cfg.General.TestRun
+	if r.firstCycle && r.cfg.General.FirstRunDryRun && !testRun {
+		r.logger.Warn("FIRST RUN: this cycle is a dry run and will make no changes; " +
+			"removals start from the next cycle (set general.first_run_dry_run: false to disable)")
+		testRun = true
+	}
+
+	switch {
+	case !r.registered:
+		registerJobs(r.manager, r.cfg, r.sampler.Logger(r.logger), testRun)
+		r.registered = true
+		r.jobsTestRun = testRun
+	case r.jobsTestRun != testRun:
+		r.manager.SetTestRun(testRun)
+		r.jobsTestRun = testRun
+	}
+
+	r.sampler.Reset()
+
+	// A cycle skipped outside active hours doesn't use up the first-run dry run
+	if runCycle(ctx, r.manager, r.notifier, r.logger, testRun) {
+		r.firstCycle = false
+		if err := writeCycleSummary(r.cfg.General.CycleSummary, r.manager.GetLastStats(), testRun); err != nil {
+			r.logger.Warn("failed to write cycle summary", "output", r.cfg.General.CycleSummary, "error", err)
+		}
+	}
+}
+
+// writeCycleSummary writes the cycle's NDJSON summary to stdout, or appends
+// it to the file at output. An empty output writes nothing.
+func writeCycleSummary(output string, stats *jobs.CycleStats, testRun bool) error {
+	if output == "" || stats == nil {
+		return nil
+	}
+	if output == "stdout" {
+		return jobs.WriteCycleSummary(os.Stdout, stats, testRun)
+	}
+
+	f, err := os.OpenFile(output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := jobs.WriteCycleSummary(f, stats, testRun); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// runCycle runs all jobs and sends notifications, returning false if the
+// cycle was skipped outside active hours
+func runCycle(ctx context.Context, manager *jobs.Manager, notifier *notify.Registry, logger *slog.Logger, testRun bool) bool {
 	if testRun {
 		logger.Info("running in TEST MODE - no changes will be made")
 	}
 	if err := manager.RunAll(ctx); err != nil {
+		if errors.Is(err, jobs.ErrOutsideActiveHours) {
+			return false
+		}
 		logger.Error("cycle had errors", "error", err)
 		// Continue running - don't exit!
 	}
+	notifier.NotifyAll(ctx, manager.GetLastStats())
+	return true
+}
+
+// newArrClient creates an *arr API client for an instance, applying any
+// per-instance request timeout and API version overrides
+func newArrClient(inst config.InstanceConfig, defaultAPIVersion string, cfg *config.Config, logger *slog.Logger) *arrapi.Client {
+	return arrapi.NewClient(arrapi.ClientConfig{
+		Name:       inst.Name,
+		BaseURL:    inst.URL,
+		APIKey:     inst.APIKey,
+		APIVersion: apiVersionFor(inst, defaultAPIVersion),
+		Timeout:    cfg.General.TimeoutFor(inst.RequestTimeout),
+		UserAgent:  inst.UserAgent,
+		Headers:    inst.Headers,
+		Logger:     logger,
+
+		RequestsPerSecond: inst.RequestsPerSecond,
+	})
+}
+
+// defaultAPIVersions are the API versions used for each *arr kind unless an
+// instance sets api_version
+var defaultAPIVersions = map[string]string{
+	"sonarr":   "v3",
+	"radarr":   "v3",
+	"lidarr":   "v1",
+	"readarr":  "v1",
+	"whisparr": "v3",
+}
+
+// arrClientFactory builds *arr clients the way registerClients does, for
+// clients rebuilt on reload
+func arrClientFactory(cfg *config.Config, logger *slog.Logger) jobs.ArrClientFactory {
+	return func(kind string, inst config.InstanceConfig) *arrapi.Client {
+		return newArrClient(inst, defaultAPIVersions[kind], cfg, logger)
+	}
+}
+
+// apiVersionFor returns the instance's api_version override, or the app default
+func apiVersionFor(inst config.InstanceConfig, defaultAPIVersion string) string {
+	if inst.APIVersion != "" {
+		return strings.ToLower(inst.APIVersion)
+	}
+	return defaultAPIVersion
+}
+
+// newQBittorrentClient creates a qBittorrent client, applying any per-client
+// request timeout override
+func newQBittorrentClient(dc config.QbittorrentConfig, cfg *config.Config, logger *slog.Logger) (*downloadclient.QBittorrentClient, error) {
+	return downloadclient.NewQBittorrentClient(downloadclient.QBittorrentConfig{
+		BaseURL:   dc.URL,
+		Username:  dc.Username,
+		Password:  dc.Password,
+		Timeout:   cfg.General.TimeoutFor(dc.RequestTimeout),
+		SkipTLS:   dc.SkipTLS,
+		UserAgent: dc.UserAgent,
+		Headers:   dc.Headers,
+		Logger:    logger,
+
+		TrackerFallback:   dc.TrackerFallback,
+		PropertyWorkers:   cfg.General.PropertyWorkers,
+		RequestsPerSecond: dc.RequestsPerSecond,
+	})
 }
 
-func registerAllJobs(manager *jobs.Manager, cfg *config.Config, logger *slog.Logger) {
-	// Register arr clients (Sonarr/Radarr use v3, Lidarr/Readarr use v1)
+// registerClients registers the configured *arr instances and download clients
+func registerClients(manager *jobs.Manager, cfg *config.Config, logger *slog.Logger) {
+	// Register arr clients (Sonarr/Radarr default to v3, Lidarr/Readarr to v1)
 	for _, inst := range cfg.Instances.Sonarr {
-		client := arrapi.NewClient(arrapi.ClientConfig{
-			Name:       inst.Name,
-			BaseURL:    inst.URL,
-			APIKey:     inst.APIKey,
-			APIVersion: "v3",
-			Timeout:    cfg.General.RequestTimeout,
-			Logger:     logger,
-		})
+		client := newArrClient(inst, "v3", cfg, logger)
 		manager.RegisterArrClient(inst.Name, client)
-		logger.Debug("registered sonarr instance", "name", inst.Name, "url", inst.URL, "api", "v3")
+		logger.Debug("registered sonarr instance", "name", inst.Name, "url", inst.URL, "api", apiVersionFor(inst, "v3"))
 	}
 	for _, inst := range cfg.Instances.Radarr {
-		client := arrapi.NewClient(arrapi.ClientConfig{
-			Name:       inst.Name,
-			BaseURL:    inst.URL,
-			APIKey:     inst.APIKey,
-			APIVersion: "v3",
-			Timeout:    cfg.General.RequestTimeout,
-			Logger:     logger,
-		})
+		client := newArrClient(inst, "v3", cfg, logger)
 		manager.RegisterArrClient(inst.Name, client)
-		logger.Debug("registered radarr instance", "name", inst.Name, "url", inst.URL, "api", "v3")
+		logger.Debug("registered radarr instance", "name", inst.Name, "url", inst.URL, "api", apiVersionFor(inst, "v3"))
 	}
 	for _, inst := range cfg.Instances.Lidarr {
-		client := arrapi.NewClient(arrapi.ClientConfig{
-			Name:       inst.Name,
-			BaseURL:    inst.URL,
-			APIKey:     inst.APIKey,
-			APIVersion: "v1",
-			Timeout:    cfg.General.RequestTimeout,
-			Logger:     logger,
-		})
+		client := newArrClient(inst, "v1", cfg, logger)
 		manager.RegisterArrClient(inst.Name, client)
-		logger.Debug("registered lidarr instance", "name", inst.Name, "url", inst.URL, "api", "v1")
+		logger.Debug("registered lidarr instance", "name", inst.Name, "url", inst.URL, "api", apiVersionFor(inst, "v1"))
 	}
 	for _, inst := range cfg.Instances.Readarr {
-		client := arrapi.NewClient(arrapi.ClientConfig{
-			Name:       inst.Name,
-			BaseURL:    inst.URL,
-			APIKey:     inst.APIKey,
-			APIVersion: "v1",
-			Timeout:    cfg.General.RequestTimeout,
-			Logger:     logger,
-		})
+		client := newArrClient(inst, "v1", cfg, logger)
 		manager.RegisterArrClient(inst.Name, client)
-		logger.Debug("registered readarr instance", "name", inst.Name, "url", inst.URL, "api", "v1")
+		logger.Debug("registered readarr instance", "name", inst.Name, "url", inst.URL, "api", apiVersionFor(inst, "v1"))
 	}
 	for _, inst := range cfg.Instances.Whisparr {
-		client := arrapi.NewClient(arrapi.ClientConfig{
-			Name:       inst.Name,
-			BaseURL:    inst.URL,
-			APIKey:     inst.APIKey,
-			APIVersion: "v3",
-			Timeout:    cfg.General.RequestTimeout,
-			Logger:     logger,
-		})
+		client := newArrClient(inst, "v3", cfg, logger)
 		manager.RegisterArrClient(inst.Name, client)
-		logger.Debug("registered whisparr instance", "name", inst.Name, "url", inst.URL, "api", "v3")
+		logger.Debug("registered whisparr instance", "name", inst.Name, "url", inst.URL, "api", apiVersionFor(inst, "v3"))
 	}
 
 	// Register download clients
 	for _, dc := range cfg.DownloadClients.Qbittorrent {
-		client, err := downloadclient.NewQBittorrentClient(downloadclient.QBittorrentConfig{
-			BaseURL:  dc.URL,
-			Username: dc.Username,
-			Password: dc.Password,
-			Timeout:  cfg.General.RequestTimeout,
-			Logger:   logger,
-		})
+		client, err := newQBittorrentClient(dc, cfg, logger)
 		if err != nil {
 			logger.Error("failed to create qbittorrent client", "name", dc.Name, "error", err)
 			continue
 		}
 		manager.RegisterDownloadClient(dc.Name, client)
 		logger.Debug("registered qbittorrent client", "name", dc.Name, "url", dc.URL)
+		checkDownloadClient("qbittorrent", dc.Name, client, logger)
+	}
+
+	// Usenet clients aren't used by jobs yet, but surface connection problems early
+	for _, dc := range cfg.DownloadClients.Sabnzbd {
+		client := downloadclient.NewSABnzbdClient(downloadclient.SABnzbdConfig{
+			BaseURL:   dc.URL,
+			APIKey:    dc.APIKey,
+			Timeout:   cfg.General.TimeoutFor(dc.RequestTimeout),
+			SkipTLS:   dc.SkipTLS,
+			UserAgent: dc.UserAgent,
+			Headers:   dc.Headers,
+			Logger:    logger,
+
+			RequestsPerSecond: dc.RequestsPerSecond,
+		})
+		checkDownloadClient("sabnzbd", dc.Name, client, logger)
+		client.Close()
+	}
+	for _, dc := range cfg.DownloadClients.Nzbget {
+		client := downloadclient.NewNZBGetClient(downloadclient.NZBGetConfig{
+			BaseURL:   dc.URL,
+			Username:  dc.Username,
+			Password:  dc.Password,
+			Timeout:   cfg.General.TimeoutFor(dc.RequestTimeout),
+			SkipTLS:   dc.SkipTLS,
+			UserAgent: dc.UserAgent,
+			Headers:   dc.Headers,
+			Logger:    logger,
+
+			RequestsPerSecond: dc.RequestsPerSecond,
+		})
+		checkDownloadClient("nzbget", dc.Name, client, logger)
+		client.Close()
+	}
+
+	logger.Debug("initialization complete",
+		"arr_instances", len(cfg.Instances.Sonarr)+len(cfg.Instances.Radarr)+len(cfg.Instances.Lidarr)+len(cfg.Instances.Readarr)+len(cfg.Instances.Whisparr),
+		"download_clients", len(cfg.DownloadClients.Qbittorrent)+len(cfg.DownloadClients.Sabnzbd)+len(cfg.DownloadClients.Nzbget),
+	)
+}
+
+// checkDownloadClient pings a download client so connection and credential
+// problems are reported at startup rather than when a job first uses it
+func checkDownloadClient(kind, name string, client downloadclient.Pinger, logger *slog.Logger) {
+	if err := client.Ping(context.Background()); err != nil {
+		logger.Error("download client connectivity check failed", "type", kind, "name", name, "error", err)
+		return
 	}
+	logger.Info("download client connected", "type", kind, "name", name)
+}
 
+// registerJobs registers the enabled jobs, built with the given test-run mode
+func registerJobs(manager *jobs.Manager, cfg *config.Config, logger *slog.Logger, testRun bool) {
 	// Register removal jobs - all using Pattern 1: (name, cfg, defaults, manager, logger, testRun)
 	if cfg.Jobs.RemoveStalled.Enabled {
-		job := removal.NewStalledJob("remove_stalled", &cfg.Jobs.RemoveStalled, &cfg.JobDefaults, manager, logger, cfg.General.TestRun)
+		job := removal.NewStalledJob("remove_stalled", &cfg.Jobs.RemoveStalled, &cfg.JobDefaults, manager, logger, testRun)
 		manager.RegisterJob(job)
 	}
 	if cfg.Jobs.RemoveFailedImports.Enabled {
-		job := removal.NewFailedImportsJob("remove_failed_imports", &cfg.Jobs.RemoveFailedImports, &cfg.JobDefaults, manager, logger, cfg.General.TestRun)
+		job := removal.NewFailedImportsJob("remove_failed_imports", &cfg.Jobs.RemoveFailedImports, &cfg.JobDefaults, manager, logger, testRun)
 		manager.RegisterJob(job)
 	}
 	if cfg.Jobs.RemoveFailedDownloads.Enabled {
-		job := removal.NewFailedDownloadsJob("remove_failed_downloads", &cfg.Jobs.RemoveFailedDownloads, &cfg.JobDefaults, manager, logger, cfg.General.TestRun)
+		job := removal.NewFailedDownloadsJob("remove_failed_downloads", &cfg.Jobs.RemoveFailedDownloads, &cfg.JobDefaults, manager, logger, testRun)
 		manager.RegisterJob(job)
 	}
 	if cfg.Jobs.RemoveOrphans.Enabled {
-		job := removal.NewOrphansJob("remove_orphans", &cfg.Jobs.RemoveOrphans, &cfg.JobDefaults, manager, logger, cfg.General.TestRun)
+		job := removal.NewOrphansJob("remove_orphans", &cfg.Jobs.RemoveOrphans, &cfg.JobDefaults, manager, logger, testRun)
 		manager.RegisterJob(job)
 	}
 	if cfg.Jobs.RemoveMissingFiles.Enabled {
-		job := removal.NewMissingFilesJob("remove_missing_files", &cfg.Jobs.RemoveMissingFiles, &cfg.JobDefaults, manager, logger, cfg.General.TestRun)
+		job := removal.NewMissingFilesJob("remove_missing_files", &cfg.Jobs.RemoveMissingFiles, &cfg.JobDefaults, manager, logger, testRun)
 		manager.RegisterJob(job)
 	}
 	if cfg.Jobs.RemoveUnmonitored.Enabled {
-		job := removal.NewUnmonitoredJob("remove_unmonitored", &cfg.Jobs.RemoveUnmonitored, &cfg.JobDefaults, manager, logger, cfg.General.TestRun)
+		job := removal.NewUnmonitoredJob("remove_unmonitored", &cfg.Jobs.RemoveUnmonitored, &cfg.JobDefaults, manager, logger, testRun)
 		manager.RegisterJob(job)
 	}
 	if cfg.Jobs.RemoveSlow.Enabled {
-		job := removal.NewSlowDownloadJob("remove_slow", &cfg.Jobs.RemoveSlow, &cfg.JobDefaults, manager, logger, cfg.General.TestRun)
+		job := removal.NewSlowDownloadJob("remove_slow", &cfg.Jobs.RemoveSlow, &cfg.JobDefaults, manager, logger, testRun)
 		manager.RegisterJob(job)
 	}
 	if cfg.Jobs.RemoveBadFiles.Enabled {
-		job := removal.NewBadFilesJob("remove_bad_files", &cfg.Jobs.RemoveBadFiles, &cfg.JobDefaults, manager, logger, cfg.General.TestRun)
+		job := removal.NewBadFilesJob("remove_bad_files", &cfg.Jobs.RemoveBadFiles, &cfg.JobDefaults, manager, logger, testRun)
 		manager.RegisterJob(job)
 	}
 	if cfg.Jobs.RemoveMetadataFailed.Enabled {
-		job := removal.NewMetadataMissingJob("remove_metadata_failed", &cfg.Jobs.RemoveMetadataFailed, &cfg.JobDefaults, manager, logger, cfg.General.TestRun)
+		job := removal.NewMetadataMissingJob("remove_metadata_failed", &cfg.Jobs.RemoveMetadataFailed, &cfg.JobDefaults, manager, logger, testRun)
+		manager.RegisterJob(job)
+	}
+	if cfg.Jobs.RemoveNotStarted.Enabled {
+		job := removal.NewNotStartedJob("remove_not_started", &cfg.Jobs.RemoveNotStarted, &cfg.JobDefaults, manager, logger, testRun)
+		manager.RegisterJob(job)
+	}
+	if cfg.Jobs.LimitActiveDownloads.Enabled {
+		job := manage.NewActiveDownloadsJob("limit_active_downloads", &cfg.Jobs.LimitActiveDownloads, &cfg.JobDefaults, manager, logger, testRun)
+		manager.RegisterJob(job)
+	}
+	if cfg.Jobs.PauseNoSpace.Enabled {
+		job := manage.NewDiskSpaceJob("pause_no_space", &cfg.Jobs.PauseNoSpace, manager, logger, testRun)
+		manager.RegisterJob(job)
+	}
+	if cfg.Jobs.CleanObsoleteTags.Enabled {
+		job := removal.NewObsoleteTagsJob("clean_obsolete_tags", &cfg.Jobs.CleanObsoleteTags, manager, logger, testRun)
 		manager.RegisterJob(job)
 	}
 	if cfg.Jobs.RemoveDoneSeeding.Enabled {
-		job := removal.NewDoneSeedingJob("remove_done_seeding", &cfg.Jobs.RemoveDoneSeeding, manager, logger, cfg.General.TestRun)
+		job := manage.NewDoneSeedingJob("remove_done_seeding", &cfg.Jobs.RemoveDoneSeeding, manager, logger, testRun)
 		manager.RegisterJob(job)
 	}
-
-	logger.Debug("initialization complete",
-		"arr_instances", len(cfg.Instances.Sonarr)+len(cfg.Instances.Radarr)+len(cfg.Instances.Lidarr)+len(cfg.Instances.Readarr)+len(cfg.Instances.Whisparr),
-		"download_clients", len(cfg.DownloadClients.Qbittorrent)+len(cfg.DownloadClients.Sabnzbd)+len(cfg.DownloadClients.Nzbget),
-	)
 }
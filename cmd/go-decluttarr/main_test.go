@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+	"github.com/jmylchreest/go-decluttarr/internal/notify"
+	"github.com/jmylchreest/go-decluttarr/internal/strikes"
+)
+
+// slowServer responds after delay, or gives up when the client goes away
+func slowServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		if r.URL.Path == "/api/v2/auth/login" {
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "test_sid"})
+			_, _ = w.Write([]byte("Ok."))
+			return
+		}
+		_, _ = w.Write([]byte(`{"appName":"Sonarr","version":"4.0.0"}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestNewArrClientRequestTimeout(t *testing.T) {
+	ts := slowServer(t, 300*time.Millisecond)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{General: config.GeneralConfig{RequestTimeout: 30 * time.Second}}
+
+	t.Run("per-instance override is used", func(t *testing.T) {
+		inst := config.InstanceConfig{Name: "remote", URL: ts.URL, APIKey: "key", RequestTimeout: 50 * time.Millisecond}
+		client := newArrClient(inst, "v3", cfg, logger)
+
+		_, err := client.GetSystemStatus(context.Background())
+		assert.Error(t, err, "request should time out using the instance timeout")
+	})
+
+	t.Run("global timeout is used without override", func(t *testing.T) {
+		inst := config.InstanceConfig{Name: "local", URL: ts.URL, APIKey: "key"}
+		client := newArrClient(inst, "v3", cfg, logger)
+
+		_, err := client.GetSystemStatus(context.Background())
+		assert.NoError(t, err)
+	})
+}
+
+func TestNewQBittorrentClientRequestTimeout(t *testing.T) {
+	ts := slowServer(t, 300*time.Millisecond)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{General: config.GeneralConfig{RequestTimeout: 30 * time.Second}}
+
+	client, err := newQBittorrentClient(config.QbittorrentConfig{
+		Name:           "qbit",
+		URL:            ts.URL,
+		RequestTimeout: 50 * time.Millisecond,
+	}, cfg, logger)
+	assert.NoError(t, err)
+
+	_, err = client.GetTorrents(context.Background())
+	assert.Error(t, err, "request should time out using the client timeout")
+}
+
+func TestNewArrClientAPIVersion(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"appName":"Sonarr","version":"5.0.0"}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{General: config.GeneralConfig{RequestTimeout: 30 * time.Second}}
+
+	tests := []struct {
+		name       string
+		apiVersion string
+		wantPath   string
+	}{
+		{name: "app default", wantPath: "/api/v3/system/status"},
+		{name: "override", apiVersion: "v4", wantPath: "/api/v4/system/status"},
+		{name: "override is case-insensitive", apiVersion: "V4", wantPath: "/api/v4/system/status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			paths = nil
+			mu.Unlock()
+
+			inst := config.InstanceConfig{Name: "sonarr", URL: ts.URL, APIKey: "key", APIVersion: tt.apiVersion}
+			client := newArrClient(inst, "v3", cfg, logger)
+			defer client.Close()
+
+			_, err := client.GetSystemStatus(context.Background())
+			assert.NoError(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, []string{tt.wantPath}, paths)
+		})
+	}
+}
+
+func TestCycleRunnerFirstRunDryRun(t *testing.T) {
+	var mu sync.Mutex
+	deletes := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			mu.Lock()
+			deletes++
+			mu.Unlock()
+			return
+		}
+		_, _ = w.Write([]byte(`{"page":1,"pageSize":1000,"totalRecords":1,"records":[
+			{"id":1,"title":"Failed.Item","downloadId":"abc","trackedDownloadStatus":"error"}
+		]}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	deleteCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return deletes
+	}
+
+	tests := []struct {
+		name           string
+		testRun        bool
+		firstRunDryRun bool
+		wantFirst      int
+		wantSecond     int
+	}{
+		{name: "first cycle forced to dry run", firstRunDryRun: true, wantFirst: 0, wantSecond: 1},
+		{name: "disabled", firstRunDryRun: false, wantFirst: 1, wantSecond: 2},
+		{name: "test run stays on", testRun: true, firstRunDryRun: true, wantFirst: 0, wantSecond: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			deletes = 0
+			mu.Unlock()
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			cfg := &config.Config{
+				General: config.GeneralConfig{
+					TestRun:                tt.testRun,
+					FirstRunDryRun:         tt.firstRunDryRun,
+					RequestTimeout:         30 * time.Second,
+					PublicTrackerHandling:  "remove",
+					PrivateTrackerHandling: "remove",
+				},
+				JobDefaults: config.JobDefaultsConfig{MaxStrikes: 1},
+				Jobs:        config.JobsConfig{RemoveFailedDownloads: config.JobConfig{Enabled: true}},
+				Instances: config.InstancesConfig{
+					Sonarr: []config.InstanceConfig{{Name: "sonarr", URL: ts.URL, APIKey: "key"}},
+				},
+			}
+
+			manager := jobs.NewManager(cfg, logger, "")
+			t.Cleanup(manager.Close)
+			registerClients(manager, cfg, logger)
+			runner := newCycleRunner(manager, notify.NewRegistry(cfg.Notifications, logger), cfg, logger)
+
+			runner.run(context.Background())
+			assert.Equal(t, tt.wantFirst, deleteCount(), "first cycle")
+
+			runner.run(context.Background())
+			assert.Equal(t, tt.wantSecond, deleteCount(), "second cycle")
+		})
+	}
+}
+
+func TestCycleRunnerOneCycleAtATime(t *testing.T) {
+	// The first queue request blocks until release is closed
+	queued := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v3/queue" {
+			once.Do(func() {
+				close(queued)
+				<-release
+			})
+		}
+		_, _ = w.Write([]byte(`{"page":1,"pageSize":1000,"totalRecords":0,"records":[]}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			RequestTimeout:         30 * time.Second,
+			PublicTrackerHandling:  "remove",
+			PrivateTrackerHandling: "remove",
+		},
+		JobDefaults: config.JobDefaultsConfig{MaxStrikes: 1},
+		Jobs:        config.JobsConfig{RemoveFailedDownloads: config.JobConfig{Enabled: true}},
+		Instances: config.InstancesConfig{
+			Sonarr: []config.InstanceConfig{{Name: "sonarr", URL: ts.URL, APIKey: "key"}},
+		},
+	}
+
+	manager := jobs.NewManager(cfg, logger, "")
+	t.Cleanup(manager.Close)
+	registerClients(manager, cfg, logger)
+	runner := newCycleRunner(manager, notify.NewRegistry(cfg.Notifications, logger), cfg, logger)
+
+	require.True(t, runner.start(context.Background()))
+	<-queued
+
+	assert.False(t, runner.start(context.Background()), "a second cycle can't start over HTTP")
+	assert.False(t, runner.run(context.Background()), "the timer skips its cycle")
+
+	close(release)
+	runner.wait()
+	require.NotNil(t, manager.GetLastStats())
+	assert.True(t, runner.run(context.Background()), "cycles run again once the previous one finished")
+}
+
+func TestRunPurgeStrikes(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	path := filepath.Join(t.TempDir(), "strikes.json")
+
+	h := strikes.NewHandler(path, logger)
+	h.Add("recent", "remove_stalled", "Recent.Item", "download stalled")
+	require.NoError(t, h.Save())
+
+	// Age one record past the threshold by rewriting the file
+	records := h.GetAllRecords()
+	records["old"] = &strikes.StrikeRecord{Count: 1, LastSeen: time.Now().Add(-48 * time.Hour)}
+	data, err := json.Marshal(records)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	var out bytes.Buffer
+	assert.Equal(t, 0, runPurgeStrikes(path, 24*time.Hour, &out))
+	assert.Contains(t, out.String(), "purged 1 stale strikes")
+
+	purged := strikes.NewHandler(path, logger).GetAllRecords()
+	assert.Contains(t, purged, "recent")
+	assert.NotContains(t, purged, "old")
+
+	assert.Equal(t, 1, runPurgeStrikes(path, -time.Hour, io.Discard))
+}
+
+func TestRunImportConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.conf")
+	require.NoError(t, os.WriteFile(path, []byte("[features]\nREMOVE_STALLED = True\nRUN_PERIODIC_RESCANS = {}\n"), 0644))
+
+	var out, errOut bytes.Buffer
+	assert.Equal(t, 0, runImportConfig(path, &out, &errOut))
+	assert.Contains(t, out.String(), "remove_stalled:")
+	assert.Contains(t, errOut.String(), "RUN_PERIODIC_RESCANS")
+
+	assert.Equal(t, 1, runImportConfig(filepath.Join(t.TempDir(), "missing.conf"), io.Discard, io.Discard))
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jmylchreest/go-decluttarr/pkg/httpclient"
@@ -22,6 +23,12 @@ type Client struct {
 	apiVersion string
 	http       *httpclient.Client
 	logger     *slog.Logger
+
+	commandsMu       sync.Mutex
+	commandNamesUsed map[string]string // command -> name the instance accepted for it
+
+	tagsMu sync.Mutex
+	tagIDs map[string]int // lowercase label -> tag ID
 }
 
 // ClientConfig holds configuration for creating a Client
@@ -32,7 +39,12 @@ type ClientConfig struct {
 	APIVersion string // "v1" for Lidarr/Readarr, "v3" for Sonarr/Radarr
 	Timeout    time.Duration
 	SkipTLS    bool
+	UserAgent  string
+	Headers    map[string]string
 	Logger     *slog.Logger
+
+	// RequestsPerSecond paces requests to the instance; 0 = unlimited
+	RequestsPerSecond float64
 }
 
 // NewClient creates a new *arr API client
@@ -49,6 +61,10 @@ func NewClient(cfg ClientConfig) *Client {
 		MaxIdleConns:    10,
 		IdleConnTimeout: 90 * time.Second,
 		SkipTLSVerify:   cfg.SkipTLS,
+		UserAgent:       cfg.UserAgent,
+		Headers:         cfg.Headers,
+
+		RequestsPerSecond: cfg.RequestsPerSecond,
 	}
 
 	logger := cfg.Logger
@@ -63,22 +79,37 @@ func NewClient(cfg ClientConfig) *Client {
 		apiVersion: cfg.APIVersion,
 		http:       httpclient.New(httpCfg),
 		logger:     logger.With("service", cfg.Name),
+
+		commandNamesUsed: make(map[string]string),
+		tagIDs:           make(map[string]int),
 	}
 }
 
-// GetQueue retrieves all items in the download queue
+// GetQueue retrieves all items in the download queue, across all pages
 func (c *Client) GetQueue(ctx context.Context) ([]QueueItem, error) {
-	var queueResp QueueResponse
-	path := fmt.Sprintf("/api/%s/queue?page=1&pageSize=1000", c.apiVersion)
-	if err := c.request(ctx, http.MethodGet, path, nil, &queueResp); err != nil {
+	path := fmt.Sprintf("/api/%s/queue", c.apiVersion)
+	records, err := fetchAllPages[QueueItem](ctx, c, path)
+	if err != nil {
 		return nil, fmt.Errorf("get queue: %w", err)
 	}
 
-	c.logger.DebugContext(ctx, "retrieved queue",
-		"total_items", queueResp.TotalRecords,
-		"page_size", queueResp.PageSize)
+	c.logger.DebugContext(ctx, "retrieved queue", "total_items", len(records))
 
-	return queueResp.Records, nil
+	return records, nil
+}
+
+// GetQueueDetails retrieves the queue from the unpaged queue/details endpoint.
+// Some *arr versions only report an item's full status messages there.
+func (c *Client) GetQueueDetails(ctx context.Context) ([]QueueItem, error) {
+	var records []QueueItem
+	path := fmt.Sprintf("/api/%s/queue/details", c.apiVersion)
+	if err := c.request(ctx, http.MethodGet, path, nil, &records); err != nil {
+		return nil, fmt.Errorf("get queue details: %w", err)
+	}
+
+	c.logger.DebugContext(ctx, "retrieved queue details", "total_items", len(records))
+
+	return records, nil
 }
 
 // DeleteQueueItem removes an item from the queue
@@ -129,6 +160,30 @@ func (c *Client) GetSystemStatus(ctx context.Context) (*SystemStatus, error) {
 	return &status, nil
 }
 
+// ClockOffset estimates how far the instance's clock is ahead of the local
+// clock, from the Date header of a system status response. The header has
+// one-second resolution.
+func (c *Client) ClockOffset(ctx context.Context) (time.Duration, error) {
+	path := fmt.Sprintf("/api/%s/system/status", c.apiVersion)
+
+	sent := time.Now()
+	resp, err := c.send(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return 0, fmt.Errorf("get server time: %w", err)
+	}
+	received := time.Now()
+	_ = resp.Body.Close()
+
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, fmt.Errorf("get server time: no valid Date header: %w", err)
+	}
+
+	// Compare against the midpoint of the round trip
+	local := sent.Add(received.Sub(sent) / 2)
+	return serverTime.Sub(local), nil
+}
+
 // apiURL constructs a full API URL from a path
 func (c *Client) apiURL(path string) string {
 	// Ensure path starts with /
@@ -140,20 +195,42 @@ func (c *Client) apiURL(path string) string {
 
 // request executes an API request with proper authentication and error handling
 func (c *Client) request(ctx context.Context, method, path string, body, result any) error {
+	resp, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// If result is nil, we don't need to decode (e.g., DELETE requests)
+	if result == nil {
+		return nil
+	}
+
+	// Decode response body
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+// send executes an authenticated API request, returning the response of a
+// successful (2xx) request for the caller to close
+func (c *Client) send(ctx context.Context, method, path string, body any) (*http.Response, error) {
 	fullURL := c.apiURL(path)
 
 	var bodyReader io.Reader
 	if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
-			return fmt.Errorf("marshal request body: %w", err)
+			return nil, fmt.Errorf("marshal request body: %w", err)
 		}
 		bodyReader = strings.NewReader(string(jsonData))
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, fullURL, bodyReader)
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return nil, fmt.Errorf("create request: %w", err)
 	}
 
 	// Add API key authentication
@@ -168,30 +245,20 @@ func (c *Client) request(ctx context.Context, method, path string, body, result
 
 	resp, err := c.http.Do(ctx, req)
 	if err != nil {
-		return fmt.Errorf("execute request: %w", err)
+		return nil, fmt.Errorf("execute request: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
 
 	// Handle non-2xx status codes
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer func() { _ = resp.Body.Close() }()
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		c.logger.ErrorContext(ctx, "API error response",
 			"status", resp.StatusCode,
 			"body", string(bodyBytes))
-		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(bodyBytes))
-	}
-
-	// If result is nil, we don't need to decode (e.g., DELETE requests)
-	if result == nil {
-		return nil
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(bodyBytes)}
 	}
 
-	// Decode response body
-	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-		return fmt.Errorf("decode response: %w", err)
-	}
-
-	return nil
+	return resp, nil
 }
 
 // GetMonitoredStatus retrieves the monitored status for an entity
@@ -91,6 +91,36 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestGetQueueDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/queue/details" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]QueueItem{{
+			ID:    1,
+			Title: "Test Episode",
+			StatusMessages: []StatusMessage{
+				{Title: "Test Episode", Messages: []string{"Found potentially dangerous file"}},
+			},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Name: "sonarr", BaseURL: server.URL, APIKey: "test"})
+
+	items, err := client.GetQueueDetails(context.Background())
+	if err != nil {
+		t.Fatalf("GetQueueDetails failed: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("got %d items, want 1", len(items))
+	}
+	if got := items[0].StatusMessages[0].Messages; len(got) != 1 || got[0] != "Found potentially dangerous file" {
+		t.Errorf("status messages = %v", got)
+	}
+}
+
 func TestGetQueue(t *testing.T) {
 	mockQueue := QueueResponse{
 		Page:         1,
@@ -173,6 +203,61 @@ func TestGetQueue(t *testing.T) {
 	}
 }
 
+func TestClientUserAgentAndHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("User-Agent"); got != "decluttarr-proxy-friendly" {
+			t.Errorf("expected custom User-Agent, got %q", got)
+		}
+		if got := r.Header.Get("Remote-User"); got != "admin" {
+			t.Errorf("expected Remote-User header, got %q", got)
+		}
+		if got := r.Header.Get("X-Api-Key"); got != "testkey" {
+			t.Errorf("expected X-Api-Key header, got %q", got)
+		}
+		_ = json.NewEncoder(w).Encode(SystemStatus{AppName: "Sonarr"})
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{
+		Name:      "test",
+		BaseURL:   server.URL,
+		APIKey:    "testkey",
+		UserAgent: "decluttarr-proxy-friendly",
+		Headers:   map[string]string{"Remote-User": "admin"},
+	})
+
+	if _, err := client.GetSystemStatus(context.Background()); err != nil {
+		t.Fatalf("GetSystemStatus failed: %v", err)
+	}
+}
+
+func TestClientRequestsPerSecond(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(SystemStatus{AppName: "Sonarr"})
+	}))
+	defer server.Close()
+
+	timeStatus := func(client *Client) time.Duration {
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			if _, err := client.GetSystemStatus(context.Background()); err != nil {
+				t.Fatalf("GetSystemStatus failed: %v", err)
+			}
+		}
+		return time.Since(start)
+	}
+
+	fragile := NewClient(ClientConfig{Name: "fragile", BaseURL: server.URL, APIKey: "test", RequestsPerSecond: 10})
+	healthy := NewClient(ClientConfig{Name: "healthy", BaseURL: server.URL, APIKey: "test"})
+
+	if elapsed := timeStatus(fragile); elapsed < 200*time.Millisecond {
+		t.Errorf("expected the throttled instance to be paced at 10/s, 3 requests took %v", elapsed)
+	}
+	if elapsed := timeStatus(healthy); elapsed >= 200*time.Millisecond {
+		t.Errorf("expected the unthrottled instance not to be paced, 3 requests took %v", elapsed)
+	}
+}
+
 func TestDeleteQueueItem(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -320,6 +405,35 @@ func TestGetSystemStatus(t *testing.T) {
 	}
 }
 
+func TestClockOffset(t *testing.T) {
+	ahead := 10 * time.Minute
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/system/status" {
+			t.Errorf("expected path /api/v3/system/status, got %s", r.URL.Path)
+		}
+		w.Header().Set("Date", time.Now().Add(ahead).UTC().Format(http.TimeFormat))
+		_ = json.NewEncoder(w).Encode(SystemStatus{AppName: "Sonarr"})
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{
+		Name:    "test",
+		BaseURL: server.URL,
+		APIKey:  "testkey",
+	})
+
+	offset, err := client.ClockOffset(context.Background())
+	if err != nil {
+		t.Fatalf("ClockOffset failed: %v", err)
+	}
+
+	// The Date header has one-second resolution
+	if diff := offset - ahead; diff < -2*time.Second || diff > 2*time.Second {
+		t.Errorf("offset = %v, want about %v", offset, ahead)
+	}
+}
+
 func TestGetMonitoredStatus(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -0,0 +1,89 @@
+package arrapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// commandAliases lists the names a command has gone by across *arr versions,
+// the current name first
+var commandAliases = map[string][]string{
+	"MoviesSearch":  {"MoviesSearch", "MovieSearch"},
+	"EpisodeSearch": {"EpisodeSearch", "EpisodesSearch"},
+}
+
+// command posts a command to the *arr, with fields alongside its name. A
+// version that knows the command by another name rejects it with 400 Bad
+// Request, so each alias is tried in turn, and the name the instance accepted
+// is tried first from then on. If every name is rejected, the error for the
+// first is returned.
+func (c *Client) command(ctx context.Context, name string, fields map[string]any) error {
+	path := fmt.Sprintf("/api/%s/command", c.apiVersion)
+
+	var firstErr error
+	for _, candidate := range c.commandNames(name) {
+		body := make(map[string]any, len(fields)+1)
+		for k, v := range fields {
+			body[k] = v
+		}
+		body["name"] = candidate
+
+		err := c.request(ctx, http.MethodPost, path, body, nil)
+		if err == nil {
+			c.rememberCommandName(name, candidate)
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if !IsBadRequest(err) {
+			break
+		}
+		c.logger.DebugContext(ctx, "command name rejected, trying the next alias",
+			"command", name,
+			"rejected", candidate)
+	}
+
+	return firstErr
+}
+
+// commandNames returns the names to try for a command, starting with the one
+// the instance last accepted
+func (c *Client) commandNames(name string) []string {
+	aliases, ok := commandAliases[name]
+	if !ok {
+		return []string{name}
+	}
+
+	c.commandsMu.Lock()
+	accepted := c.commandNamesUsed[name]
+	c.commandsMu.Unlock()
+	if accepted == "" || accepted == aliases[0] {
+		return aliases
+	}
+
+	names := []string{accepted}
+	for _, alias := range aliases {
+		if alias != accepted {
+			names = append(names, alias)
+		}
+	}
+	return names
+}
+
+// rememberCommandName records the name the instance accepted for a command
+func (c *Client) rememberCommandName(name, accepted string) {
+	if _, ok := commandAliases[name]; !ok {
+		return
+	}
+
+	c.commandsMu.Lock()
+	defer c.commandsMu.Unlock()
+	if c.commandNamesUsed[name] != accepted {
+		c.commandNamesUsed[name] = accepted
+		if accepted != name {
+			c.logger.Info("instance uses an older command name", "command", name, "name", accepted)
+		}
+	}
+}
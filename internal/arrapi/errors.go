@@ -0,0 +1,62 @@
+package arrapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned when an *arr API responds with a non-2xx status
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements error
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsAuthError reports whether the API rejected the credentials (401/403)
+func (e *APIError) IsAuthError() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
+// IsNotFound reports whether the requested resource doesn't exist (404)
+func (e *APIError) IsNotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsBadRequest reports whether the API rejected the request itself (400)
+func (e *APIError) IsBadRequest() bool {
+	return e.StatusCode == http.StatusBadRequest
+}
+
+// IsTransient reports whether the request may succeed if retried (429 or 5xx)
+func (e *APIError) IsTransient() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// IsAuthError reports whether err wraps an APIError for rejected credentials
+func IsAuthError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.IsAuthError()
+}
+
+// IsNotFound reports whether err wraps an APIError for a missing resource
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.IsNotFound()
+}
+
+// IsBadRequest reports whether err wraps an APIError for a rejected request
+func IsBadRequest(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.IsBadRequest()
+}
+
+// IsTransient reports whether err wraps an APIError that may succeed if retried
+func IsTransient(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.IsTransient()
+}
@@ -0,0 +1,73 @@
+package arrapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIErrorClassification(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		wantAuth      bool
+		wantNotFound  bool
+		wantTransient bool
+	}{
+		{name: "401 unauthorized", statusCode: http.StatusUnauthorized, wantAuth: true},
+		{name: "403 forbidden", statusCode: http.StatusForbidden, wantAuth: true},
+		{name: "404 not found", statusCode: http.StatusNotFound, wantNotFound: true},
+		{name: "429 too many requests", statusCode: http.StatusTooManyRequests, wantTransient: true},
+		{name: "500 internal server error", statusCode: http.StatusInternalServerError, wantTransient: true},
+		{name: "400 bad request", statusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(`{"error": "nope"}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(ClientConfig{Name: "test", BaseURL: server.URL, APIKey: "testkey"})
+
+			_, err := client.GetQueue(context.Background())
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected *APIError in chain, got %T: %v", err, err)
+			}
+			if apiErr.StatusCode != tt.statusCode {
+				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.statusCode)
+			}
+			if apiErr.Body != `{"error": "nope"}` {
+				t.Errorf("Body = %q", apiErr.Body)
+			}
+
+			if got := IsAuthError(err); got != tt.wantAuth {
+				t.Errorf("IsAuthError = %v, want %v", got, tt.wantAuth)
+			}
+			if got := IsNotFound(err); got != tt.wantNotFound {
+				t.Errorf("IsNotFound = %v, want %v", got, tt.wantNotFound)
+			}
+			if got := IsTransient(err); got != tt.wantTransient {
+				t.Errorf("IsTransient = %v, want %v", got, tt.wantTransient)
+			}
+		})
+	}
+}
+
+func TestAPIErrorHelpersIgnoreOtherErrors(t *testing.T) {
+	err := fmt.Errorf("get queue: %w", errors.New("connection refused"))
+
+	if IsAuthError(err) || IsNotFound(err) || IsTransient(err) {
+		t.Error("expected non-API errors not to be classified")
+	}
+}
@@ -0,0 +1,54 @@
+package arrapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// Health check types reported by the *arr health endpoint
+const (
+	HealthOK      = "ok"
+	HealthNotice  = "notice"
+	HealthWarning = "warning"
+	HealthError   = "error"
+)
+
+// HealthCheck represents an active health check result in an *arr instance
+type HealthCheck struct {
+	Source  string `json:"source"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	WikiURL string `json:"wikiUrl"`
+}
+
+// downloadClientHealthSources are the health checks that report the
+// instance's download clients as unreachable
+var downloadClientHealthSources = map[string]bool{
+	"DownloadClientCheck":       true,
+	"DownloadClientStatusCheck": true,
+}
+
+// GetHealth retrieves the instance's active health warnings
+func (c *Client) GetHealth(ctx context.Context) ([]HealthCheck, error) {
+	var checks []HealthCheck
+	if err := c.get(ctx, "health", &checks); err != nil {
+		return nil, fmt.Errorf("get health: %w", err)
+	}
+
+	c.logger.DebugContext(ctx, "retrieved health", "count", len(checks))
+
+	return checks, nil
+}
+
+// DownloadClientOffline reports whether the health checks show the instance
+// can't reach any of its download clients. The *arr only raises these as
+// errors when no download client is available; a single unavailable client
+// among several is a warning.
+func DownloadClientOffline(checks []HealthCheck) bool {
+	for _, check := range checks {
+		if check.Type == HealthError && downloadClientHealthSources[check.Source] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package arrapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v3/health" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+			{"source": "IndexerStatusCheck", "type": "warning", "message": "Indexers unavailable due to failures: Example", "wikiUrl": "https://wiki.servarr.com/sonarr/system#indexers-are-unavailable-due-to-failures"},
+			{"source": "DownloadClientCheck", "type": "error", "message": "Unable to communicate with qBittorrent"}
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Name: "sonarr", BaseURL: server.URL, APIKey: "test"})
+
+	checks, err := client.GetHealth(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(checks) != 2 {
+		t.Fatalf("expected 2 health checks, got %d", len(checks))
+	}
+	if checks[0].Source != "IndexerStatusCheck" || checks[0].Type != HealthWarning {
+		t.Errorf("unexpected health check %+v", checks[0])
+	}
+	if !DownloadClientOffline(checks) {
+		t.Error("expected download client to be reported offline")
+	}
+}
+
+func TestDownloadClientOffline(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []HealthCheck
+		want   bool
+	}{
+		{name: "no checks", checks: nil, want: false},
+		{
+			name:   "all download clients unavailable",
+			checks: []HealthCheck{{Source: "DownloadClientStatusCheck", Type: HealthError}},
+			want:   true,
+		},
+		{
+			name:   "one of several download clients unavailable",
+			checks: []HealthCheck{{Source: "DownloadClientStatusCheck", Type: HealthWarning}},
+			want:   false,
+		},
+		{
+			name:   "unrelated error",
+			checks: []HealthCheck{{Source: "IndexerRssCheck", Type: HealthError}},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DownloadClientOffline(tt.checks); got != tt.want {
+				t.Errorf("DownloadClientOffline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
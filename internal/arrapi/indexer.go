@@ -0,0 +1,52 @@
+package arrapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Indexer represents an indexer configured in an *arr instance
+type Indexer struct {
+	ID                      int    `json:"id"`
+	Name                    string `json:"name"`
+	Implementation          string `json:"implementation"`
+	Protocol                string `json:"protocol"`
+	EnableRss               bool   `json:"enableRss"`
+	EnableAutomaticSearch   bool   `json:"enableAutomaticSearch"`
+	EnableInteractiveSearch bool   `json:"enableInteractiveSearch"`
+}
+
+// GetIndexers retrieves the indexers configured in the instance
+func (c *Client) GetIndexers(ctx context.Context) ([]Indexer, error) {
+	var indexers []Indexer
+	if err := c.get(ctx, "indexer", &indexers); err != nil {
+		return nil, fmt.Errorf("get indexers: %w", err)
+	}
+
+	c.logger.DebugContext(ctx, "retrieved indexers", "count", len(indexers))
+
+	return indexers, nil
+}
+
+// DisableIndexer turns off RSS, automatic and interactive search for an indexer
+func (c *Client) DisableIndexer(ctx context.Context, id int) error {
+	// Round-trip the full resource so settings we don't model are preserved
+	var indexer map[string]any
+	if err := c.get(ctx, fmt.Sprintf("indexer/%d", id), &indexer); err != nil {
+		return fmt.Errorf("get indexer %d: %w", id, err)
+	}
+
+	indexer["enableRss"] = false
+	indexer["enableAutomaticSearch"] = false
+	indexer["enableInteractiveSearch"] = false
+
+	path := fmt.Sprintf("/api/%s/indexer/%d", c.apiVersion, id)
+	if err := c.request(ctx, http.MethodPut, path, indexer, nil); err != nil {
+		return fmt.Errorf("disable indexer %d: %w", id, err)
+	}
+
+	c.logger.DebugContext(ctx, "disabled indexer", "id", id, "name", indexer["name"])
+
+	return nil
+}
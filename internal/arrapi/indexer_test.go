@@ -0,0 +1,87 @@
+package arrapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetIndexers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v3/indexer" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+			{"id": 1, "name": "Good Indexer", "protocol": "torrent", "enableRss": true, "enableAutomaticSearch": true},
+			{"id": 2, "name": "Dead Indexer", "protocol": "torrent", "enableRss": true, "enableAutomaticSearch": true}
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Name: "sonarr", BaseURL: server.URL, APIKey: "test"})
+
+	indexers, err := client.GetIndexers(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(indexers) != 2 {
+		t.Fatalf("expected 2 indexers, got %d", len(indexers))
+	}
+	if indexers[1].ID != 2 || indexers[1].Name != "Dead Indexer" || !indexers[1].EnableRss {
+		t.Errorf("unexpected indexer %+v", indexers[1])
+	}
+}
+
+func TestDisableIndexer(t *testing.T) {
+	var updated map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/indexer/2" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{
+				"id": 2,
+				"name": "Dead Indexer",
+				"enableRss": true,
+				"enableAutomaticSearch": true,
+				"enableInteractiveSearch": true,
+				"priority": 25,
+				"fields": [{"name": "baseUrl", "value": "https://indexer.example"}]
+			}`))
+		case http.MethodPut:
+			if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+				t.Errorf("failed to decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Name: "sonarr", BaseURL: server.URL, APIKey: "test"})
+
+	if err := client.DisableIndexer(context.Background(), 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, field := range []string{"enableRss", "enableAutomaticSearch", "enableInteractiveSearch"} {
+		if updated[field] != false {
+			t.Errorf("expected %s to be false, got %v", field, updated[field])
+		}
+	}
+	if updated["priority"] != float64(25) {
+		t.Errorf("expected unmodelled settings to be preserved, got priority %v", updated["priority"])
+	}
+	if fields, ok := updated["fields"].([]any); !ok || len(fields) != 1 {
+		t.Errorf("expected fields to be preserved, got %v", updated["fields"])
+	}
+}
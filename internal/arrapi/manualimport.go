@@ -0,0 +1,141 @@
+package arrapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ManualImportEntity is the minimal form of a media entity (series, episode,
+// movie) referenced by a manual import candidate
+type ManualImportEntity struct {
+	ID int `json:"id"`
+}
+
+// ManualImportRejection explains why a file can't be imported automatically
+type ManualImportRejection struct {
+	Reason string `json:"reason"`
+	Type   string `json:"type"`
+}
+
+// ManualImportCandidate is a file the *arr found for a download that can be
+// imported manually
+type ManualImportCandidate struct {
+	ID           int                     `json:"id"`
+	Path         string                  `json:"path"`
+	RelativePath string                  `json:"relativePath"`
+	FolderName   string                  `json:"folderName"`
+	Name         string                  `json:"name"`
+	Size         int64                   `json:"size"`
+	DownloadID   string                  `json:"downloadId"`
+	ReleaseGroup string                  `json:"releaseGroup"`
+	Quality      json.RawMessage         `json:"quality,omitempty"`
+	Languages    json.RawMessage         `json:"languages,omitempty"`
+	Rejections   []ManualImportRejection `json:"rejections"`
+
+	// Sonarr-specific
+	Series   *ManualImportEntity  `json:"series,omitempty"`
+	Episodes []ManualImportEntity `json:"episodes,omitempty"`
+
+	// Radarr-specific
+	Movie *ManualImportEntity `json:"movie,omitempty"`
+}
+
+// ManualImportFile is a single file in a ManualImport command
+type ManualImportFile struct {
+	Path         string          `json:"path"`
+	FolderName   string          `json:"folderName,omitempty"`
+	DownloadID   string          `json:"downloadId,omitempty"`
+	ReleaseGroup string          `json:"releaseGroup,omitempty"`
+	Quality      json.RawMessage `json:"quality,omitempty"`
+	Languages    json.RawMessage `json:"languages,omitempty"`
+
+	// Sonarr-specific
+	SeriesID   *int  `json:"seriesId,omitempty"`
+	EpisodeIDs []int `json:"episodeIds,omitempty"`
+
+	// Radarr-specific
+	MovieID *int `json:"movieId,omitempty"`
+}
+
+// manualImportCommand is the command body for triggering a manual import
+type manualImportCommand struct {
+	Name       string             `json:"name"`
+	ImportMode string             `json:"importMode"`
+	Files      []ManualImportFile `json:"files"`
+}
+
+// ImportFile converts the candidate into a file for TriggerManualImport.
+// Returns false if the candidate was rejected or isn't matched to any media.
+func (c ManualImportCandidate) ImportFile() (ManualImportFile, bool) {
+	if len(c.Rejections) > 0 {
+		return ManualImportFile{}, false
+	}
+
+	file := ManualImportFile{
+		Path:         c.Path,
+		FolderName:   c.FolderName,
+		DownloadID:   c.DownloadID,
+		ReleaseGroup: c.ReleaseGroup,
+		Quality:      c.Quality,
+		Languages:    c.Languages,
+	}
+
+	switch {
+	case c.Series != nil && len(c.Episodes) > 0:
+		seriesID := c.Series.ID
+		file.SeriesID = &seriesID
+		for _, ep := range c.Episodes {
+			file.EpisodeIDs = append(file.EpisodeIDs, ep.ID)
+		}
+	case c.Movie != nil:
+		movieID := c.Movie.ID
+		file.MovieID = &movieID
+	default:
+		return ManualImportFile{}, false
+	}
+
+	return file, true
+}
+
+// GetManualImportCandidates retrieves the files available for manual import for a download
+func (c *Client) GetManualImportCandidates(ctx context.Context, downloadID string) ([]ManualImportCandidate, error) {
+	params := url.Values{}
+	params.Set("downloadId", downloadID)
+	params.Set("filterExistingFiles", "true")
+
+	var candidates []ManualImportCandidate
+	if err := c.get(ctx, "manualimport?"+params.Encode(), &candidates); err != nil {
+		return nil, fmt.Errorf("get manual import candidates for %s: %w", downloadID, err)
+	}
+
+	c.logger.DebugContext(ctx, "retrieved manual import candidates",
+		"download_id", downloadID,
+		"count", len(candidates))
+
+	return candidates, nil
+}
+
+// TriggerManualImport queues a ManualImport command for the given files
+func (c *Client) TriggerManualImport(ctx context.Context, files []ManualImportFile) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	body := manualImportCommand{
+		Name:       "ManualImport",
+		ImportMode: "auto",
+		Files:      files,
+	}
+
+	path := fmt.Sprintf("/api/%s/command", c.apiVersion)
+	if err := c.request(ctx, http.MethodPost, path, body, nil); err != nil {
+		return fmt.Errorf("trigger manual import: %w", err)
+	}
+
+	c.logger.DebugContext(ctx, "triggered manual import", "files", len(files))
+
+	return nil
+}
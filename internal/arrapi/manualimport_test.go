@@ -0,0 +1,120 @@
+package arrapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetManualImportCandidates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v3/manualimport" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if got := r.URL.Query().Get("downloadId"); got != "ABC123" {
+			t.Errorf("expected downloadId ABC123, got %s", got)
+		}
+
+		_, _ = w.Write([]byte(`[
+			{
+				"id": 1,
+				"path": "/downloads/Show.S01E01.mkv",
+				"downloadId": "ABC123",
+				"series": {"id": 10, "title": "Show"},
+				"episodes": [{"id": 100}, {"id": 101}],
+				"quality": {"quality": {"id": 7}},
+				"rejections": []
+			},
+			{
+				"id": 2,
+				"path": "/downloads/sample.mkv",
+				"downloadId": "ABC123",
+				"rejections": [{"reason": "Sample", "type": "permanent"}]
+			}
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Name: "sonarr", BaseURL: server.URL, APIKey: "test"})
+
+	candidates, err := client.GetManualImportCandidates(context.Background(), "ABC123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %d", len(candidates))
+	}
+
+	file, ok := candidates[0].ImportFile()
+	if !ok {
+		t.Fatal("expected first candidate to be importable")
+	}
+	if file.SeriesID == nil || *file.SeriesID != 10 {
+		t.Errorf("expected series id 10, got %v", file.SeriesID)
+	}
+	if len(file.EpisodeIDs) != 2 || file.EpisodeIDs[0] != 100 || file.EpisodeIDs[1] != 101 {
+		t.Errorf("unexpected episode ids %v", file.EpisodeIDs)
+	}
+
+	if _, ok := candidates[1].ImportFile(); ok {
+		t.Error("expected rejected candidate not to be importable")
+	}
+}
+
+func TestTriggerManualImport(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v3/command" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Name: "radarr", BaseURL: server.URL, APIKey: "test"})
+
+	movieID := 42
+	err := client.TriggerManualImport(context.Background(), []ManualImportFile{{
+		Path:       "/downloads/Movie.2020.mkv",
+		DownloadID: "DEF456",
+		MovieID:    &movieID,
+		Quality:    json.RawMessage(`{"quality":{"id":7}}`),
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if body["name"] != "ManualImport" {
+		t.Errorf("expected command name ManualImport, got %v", body["name"])
+	}
+	if body["importMode"] != "auto" {
+		t.Errorf("expected importMode auto, got %v", body["importMode"])
+	}
+
+	files, ok := body["files"].([]any)
+	if !ok || len(files) != 1 {
+		t.Fatalf("expected 1 file, got %v", body["files"])
+	}
+	file := files[0].(map[string]any)
+	if file["path"] != "/downloads/Movie.2020.mkv" {
+		t.Errorf("unexpected path %v", file["path"])
+	}
+	if file["movieId"] != float64(42) {
+		t.Errorf("expected movieId 42, got %v", file["movieId"])
+	}
+	if file["downloadId"] != "DEF456" {
+		t.Errorf("expected downloadId DEF456, got %v", file["downloadId"])
+	}
+	if _, ok := file["quality"].(map[string]any); !ok {
+		t.Errorf("expected quality to be passed through, got %v", file["quality"])
+	}
+}
@@ -0,0 +1,55 @@
+package arrapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// defaultPageSize is the number of records requested per page from paged endpoints
+const defaultPageSize = 1000
+
+// pagedResponse is the envelope returned by paged *arr endpoints
+// (queue, history, blocklist, wanted)
+type pagedResponse[T any] struct {
+	Page         int `json:"page"`
+	PageSize     int `json:"pageSize"`
+	TotalRecords int `json:"totalRecords"`
+	Records      []T `json:"records"`
+}
+
+// fetchAllPages retrieves every record from a paged endpoint, requesting
+// further pages until TotalRecords have been read. The path may already
+// include query parameters; page and pageSize are appended to it.
+func fetchAllPages[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+
+	var records []T
+	for page := 1; ; page++ {
+		var resp pagedResponse[T]
+		pagePath := fmt.Sprintf("%s%spage=%d&pageSize=%d", path, sep, page, defaultPageSize)
+		if err := c.request(ctx, http.MethodGet, pagePath, nil, &resp); err != nil {
+			return nil, fmt.Errorf("page %d: %w", page, err)
+		}
+
+		records = append(records, resp.Records...)
+
+		// Stop on the last page, or if the server returns an empty page so a
+		// stale TotalRecords can't loop forever
+		pageSize := resp.PageSize
+		if pageSize <= 0 {
+			pageSize = defaultPageSize
+		}
+		if len(resp.Records) == 0 || page*pageSize >= resp.TotalRecords {
+			c.logger.DebugContext(ctx, "retrieved paged records",
+				"path", path,
+				"pages", page,
+				"total_records", resp.TotalRecords)
+			return records, nil
+		}
+	}
+}
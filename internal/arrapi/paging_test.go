@@ -0,0 +1,122 @@
+package arrapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// pagedServer serves total queue records in pages of pageSize, recording the
+// pages requested
+func pagedServer(t *testing.T, total, pageSize int) (*httptest.Server, func() []int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var requested []int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/queue" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("includeUnknownSeriesItems") != "true" {
+			t.Errorf("existing query parameters were dropped: %s", r.URL.RawQuery)
+		}
+
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil {
+			t.Errorf("invalid page %q", r.URL.Query().Get("page"))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		requested = append(requested, page)
+		mu.Unlock()
+
+		resp := QueueResponse{Page: page, PageSize: pageSize, TotalRecords: total}
+		for id := (page-1)*pageSize + 1; id <= total && id <= page*pageSize; id++ {
+			resp.Records = append(resp.Records, QueueItem{ID: id})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), requested...)
+	}
+}
+
+func TestFetchAllPages(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		pageSize  int
+		wantPages int
+	}{
+		{name: "multiple pages", total: 5, pageSize: 2, wantPages: 3},
+		{name: "exact multiple of page size", total: 4, pageSize: 2, wantPages: 2},
+		{name: "single page short-circuits", total: 3, pageSize: 1000, wantPages: 1},
+		{name: "empty", total: 0, pageSize: 1000, wantPages: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requested := pagedServer(t, tt.total, tt.pageSize)
+			client := NewClient(ClientConfig{Name: "sonarr", BaseURL: server.URL, APIKey: "test"})
+
+			records, err := fetchAllPages[QueueItem](context.Background(), client, "/api/v3/queue?includeUnknownSeriesItems=true")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(records) != tt.total {
+				t.Fatalf("expected %d records, got %d", tt.total, len(records))
+			}
+			for i, record := range records {
+				if record.ID != i+1 {
+					t.Errorf("record %d: expected ID %d, got %d", i, i+1, record.ID)
+				}
+			}
+
+			pages := requested()
+			if len(pages) != tt.wantPages {
+				t.Fatalf("expected %d page requests, got %v", tt.wantPages, pages)
+			}
+			for i, page := range pages {
+				if page != i+1 {
+					t.Errorf("expected page %d to be requested in order, got %v", i+1, pages)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchAllPagesError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(QueueResponse{
+			Page:         1,
+			PageSize:     1,
+			TotalRecords: 2,
+			Records:      []QueueItem{{ID: 1}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Name: "sonarr", BaseURL: server.URL, APIKey: "test"})
+
+	_, err := client.GetQueue(context.Background())
+	if !IsTransient(err) {
+		t.Fatalf("expected transient API error from the failed page, got %v", err)
+	}
+}
@@ -2,7 +2,9 @@ package arrapi
 
 import "time"
 
-// QueueItem represents a queue item in the *arr API
+// QueueItem represents a queue item in the *arr API. Fields that some *arr
+// versions return as null or omit decode to their zero values; the library IDs
+// are pointers, nil when the item isn't linked to the library.
 type QueueItem struct {
 	ID                      int             `json:"id"`
 	Title                   string          `json:"title"`
@@ -3,7 +3,6 @@ package arrapi
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 )
 
@@ -60,11 +59,10 @@ func (c *RadarrClient) GetAllMovies(ctx context.Context) ([]Movie, error) {
 	return movies, nil
 }
 
-// SearchMovie triggers a search for a specific movie
+// SearchMovie triggers a search for a specific movie, falling back to the
+// command's older name on versions that reject the current one
 func (c *RadarrClient) SearchMovie(ctx context.Context, movieID int) error {
-	body := strings.NewReader(fmt.Sprintf(`{"name":"MoviesSearch","movieIds":[%d]}`, movieID))
-
-	if err := c.post(ctx, "command", body); err != nil {
+	if err := c.command(ctx, "MoviesSearch", map[string]any{"movieIds": []int{movieID}}); err != nil {
 		return fmt.Errorf("failed to search movie %d: %w", movieID, err)
 	}
 
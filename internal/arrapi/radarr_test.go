@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -346,3 +347,67 @@ func TestRadarrErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+// commandServer accepts only the named command, rejecting others with 400 as
+// an *arr version that doesn't know them does, and records the names it received
+func commandServer(t *testing.T, accepted string) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		name, _ := body["name"].(string)
+		received = append(received, name)
+
+		if name != accepted {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "Unknown command " + name})
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": 1, "status": "queued"})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &received
+}
+
+func TestRadarrSearchMovieFallback(t *testing.T) {
+	server, received := commandServer(t, "MovieSearch")
+	client := NewRadarrClient(ClientConfig{Name: "radarr", BaseURL: server.URL, APIKey: "testkey"})
+
+	if err := client.SearchMovie(context.Background(), 123); err != nil {
+		t.Fatalf("SearchMovie failed: %v", err)
+	}
+	if got := strings.Join(*received, ","); got != "MoviesSearch,MovieSearch" {
+		t.Errorf("expected the rejected name to be retried with the fallback, got %s", got)
+	}
+
+	// The accepted name is used straight away from then on
+	*received = nil
+	if err := client.SearchMovie(context.Background(), 456); err != nil {
+		t.Fatalf("SearchMovie failed: %v", err)
+	}
+	if got := strings.Join(*received, ","); got != "MovieSearch" {
+		t.Errorf("expected only the remembered name to be sent, got %s", got)
+	}
+}
+
+func TestRadarrSearchMovieAllNamesRejected(t *testing.T) {
+	server, received := commandServer(t, "")
+	client := NewRadarrClient(ClientConfig{Name: "radarr", BaseURL: server.URL, APIKey: "testkey"})
+
+	err := client.SearchMovie(context.Background(), 123)
+	if !IsBadRequest(err) {
+		t.Fatalf("expected a bad request error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Unknown command MoviesSearch") {
+		t.Errorf("expected the error for the current name, got %v", err)
+	}
+	if len(*received) != 2 {
+		t.Errorf("expected each name to be tried once, got %v", *received)
+	}
+}
@@ -2,7 +2,6 @@ package arrapi
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -52,17 +51,18 @@ type Statistics struct {
 
 // Episode represents an episode in Sonarr
 type Episode struct {
-	ID              int        `json:"id"`
-	SeriesID        int        `json:"seriesId"`
-	EpisodeFileID   int        `json:"episodeFileId"`
-	SeasonNumber    int        `json:"seasonNumber"`
-	EpisodeNumber   int        `json:"episodeNumber"`
-	Title           string     `json:"title"`
-	AirDate         string     `json:"airDate"`
-	AirDateUTC      time.Time  `json:"airDateUtc"`
-	HasFile         bool       `json:"hasFile"`
-	Monitored       bool       `json:"monitored"`
-	LastSearchTime  *time.Time `json:"lastSearchTime,omitempty"`
+	ID                    int        `json:"id"`
+	SeriesID              int        `json:"seriesId"`
+	EpisodeFileID         int        `json:"episodeFileId"`
+	SeasonNumber          int        `json:"seasonNumber"`
+	EpisodeNumber         int        `json:"episodeNumber"`
+	AbsoluteEpisodeNumber *int       `json:"absoluteEpisodeNumber,omitempty"` // anime series only
+	Title                 string     `json:"title"`
+	AirDate               string     `json:"airDate"`
+	AirDateUTC            time.Time  `json:"airDateUtc"`
+	HasFile               bool       `json:"hasFile"`
+	Monitored             bool       `json:"monitored"`
+	LastSearchTime        *time.Time `json:"lastSearchTime,omitempty"`
 }
 
 // CommandBody represents a command request to Sonarr
@@ -107,16 +107,10 @@ func (c *SonarrClient) GetEpisodes(ctx context.Context, seriesID int) ([]Episode
 	return episodes, nil
 }
 
-// SearchEpisodes triggers a search for specific episodes
+// SearchEpisodes triggers a search for specific episodes, falling back to the
+// command's older name on versions that reject the current one
 func (c *SonarrClient) SearchEpisodes(ctx context.Context, episodeIDs []int) error {
-	// Properly marshal episode IDs as JSON array
-	idsJSON, err := json.Marshal(episodeIDs)
-	if err != nil {
-		return fmt.Errorf("failed to marshal episode IDs: %w", err)
-	}
-	body := strings.NewReader(fmt.Sprintf(`{"name":"EpisodeSearch","episodeIds":%s}`, string(idsJSON)))
-
-	if err := c.post(ctx, "command", body); err != nil {
+	if err := c.command(ctx, "EpisodeSearch", map[string]any{"episodeIds": episodeIDs}); err != nil {
 		return fmt.Errorf("failed to search episodes %v: %w", episodeIDs, err)
 	}
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -410,3 +411,31 @@ func TestSonarrDeleteSeries(t *testing.T) {
 		})
 	}
 }
+
+func TestSonarrSearchEpisodesFallback(t *testing.T) {
+	server, received := commandServer(t, "EpisodesSearch")
+	client := NewSonarrClient(ClientConfig{Name: "sonarr", BaseURL: server.URL, APIKey: "testkey"})
+
+	if err := client.SearchEpisodes(context.Background(), []int{1, 2}); err != nil {
+		t.Fatalf("SearchEpisodes failed: %v", err)
+	}
+	if got := strings.Join(*received, ","); got != "EpisodeSearch,EpisodesSearch" {
+		t.Errorf("expected the rejected name to be retried with the fallback, got %s", got)
+	}
+
+	// Errors other than a rejected request aren't retried under another name
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*received = append(*received, "failing")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	*received = nil
+	client = NewSonarrClient(ClientConfig{Name: "sonarr", BaseURL: failing.URL, APIKey: "testkey"})
+	if err := client.SearchEpisodes(context.Background(), []int{1}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(*received) != 1 {
+		t.Errorf("expected a single attempt on a server error, got %d", len(*received))
+	}
+}
@@ -0,0 +1,146 @@
+package arrapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Tag represents a tag defined in an *arr instance
+type Tag struct {
+	ID    int    `json:"id"`
+	Label string `json:"label"`
+}
+
+// GetTags retrieves the tags defined in the instance
+func (c *Client) GetTags(ctx context.Context) ([]Tag, error) {
+	var tags []Tag
+	if err := c.get(ctx, "tag", &tags); err != nil {
+		return nil, fmt.Errorf("get tags: %w", err)
+	}
+
+	c.logger.DebugContext(ctx, "retrieved tags", "count", len(tags))
+
+	return tags, nil
+}
+
+// tagID returns the ID of the tag with the given label, creating the tag if
+// the instance doesn't have it yet and create is set; ok is false if it
+// doesn't and create isn't. The *arr stores labels lowercased. IDs are cached,
+// as every removal tags its library entry.
+func (c *Client) tagID(ctx context.Context, label string, create bool) (id int, ok bool, err error) {
+	key := strings.ToLower(label)
+
+	c.tagsMu.Lock()
+	defer c.tagsMu.Unlock()
+	if id, ok := c.tagIDs[key]; ok {
+		return id, true, nil
+	}
+
+	tags, err := c.GetTags(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, tag := range tags {
+		if strings.EqualFold(tag.Label, label) {
+			c.tagIDs[key] = tag.ID
+			return tag.ID, true, nil
+		}
+	}
+	if !create {
+		return 0, false, nil
+	}
+
+	var created Tag
+	path := fmt.Sprintf("/api/%s/tag", c.apiVersion)
+	if err := c.request(ctx, http.MethodPost, path, Tag{Label: strings.ToLower(label)}, &created); err != nil {
+		return 0, false, fmt.Errorf("create tag %q: %w", label, err)
+	}
+
+	c.logger.DebugContext(ctx, "created tag", "id", created.ID, "label", created.Label)
+
+	c.tagIDs[key] = created.ID
+	return created.ID, true, nil
+}
+
+// forgetTagID drops a cached tag ID, e.g. after the instance rejected it
+func (c *Client) forgetTagID(label string) {
+	c.tagsMu.Lock()
+	defer c.tagsMu.Unlock()
+	delete(c.tagIDs, strings.ToLower(label))
+}
+
+// AddEntityTag applies the tag with the given label to a library entry, e.g.
+// AddEntityTag(ctx, "series", 42, "decluttarr"), creating the tag if needed.
+// An entry that already has the tag is left as is.
+func (c *Client) AddEntityTag(ctx context.Context, entityType string, id int, label string) error {
+	tagID, _, err := c.tagID(ctx, label, true)
+	if err != nil {
+		return err
+	}
+
+	entity, tags, err := c.getEntityTags(ctx, entityType, id)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(tags, any(float64(tagID))) {
+		return nil
+	}
+	entity["tags"] = append(tags, tagID)
+
+	path := fmt.Sprintf("/api/%s/%s/%d", c.apiVersion, entityType, id)
+	if err := c.request(ctx, http.MethodPut, path, entity, nil); err != nil {
+		// The tag may have been deleted in the instance since it was cached
+		c.forgetTagID(label)
+		return fmt.Errorf("tag %s %d: %w", entityType, id, err)
+	}
+
+	c.logger.DebugContext(ctx, "tagged library entry", "type", entityType, "id", id, "tag", label)
+
+	return nil
+}
+
+// RemoveEntityTag takes the tag with the given label off a library entry.
+// An entry without the tag, or an instance without it, is left as is.
+func (c *Client) RemoveEntityTag(ctx context.Context, entityType string, id int, label string) error {
+	tagID, ok, err := c.tagID(ctx, label, false)
+	if err != nil || !ok {
+		return err
+	}
+
+	entity, tags, err := c.getEntityTags(ctx, entityType, id)
+	if err != nil {
+		return err
+	}
+	remaining := slices.DeleteFunc(slices.Clone(tags), func(tag any) bool { return tag == any(float64(tagID)) })
+	if len(remaining) == len(tags) {
+		return nil
+	}
+	entity["tags"] = remaining
+
+	path := fmt.Sprintf("/api/%s/%s/%d", c.apiVersion, entityType, id)
+	if err := c.request(ctx, http.MethodPut, path, entity, nil); err != nil {
+		return fmt.Errorf("untag %s %d: %w", entityType, id, err)
+	}
+
+	c.logger.DebugContext(ctx, "removed tag from library entry", "type", entityType, "id", id, "tag", label)
+
+	return nil
+}
+
+// getEntityTags fetches a library entry as a generic resource, so fields we
+// don't model are preserved when it's written back, along with its tags
+func (c *Client) getEntityTags(ctx context.Context, entityType string, id int) (map[string]any, []any, error) {
+	var entity map[string]any
+	if err := c.get(ctx, fmt.Sprintf("%s/%d", entityType, id), &entity); err != nil {
+		return nil, nil, fmt.Errorf("get %s %d: %w", entityType, id, err)
+	}
+
+	var tags []any
+	if existing, ok := entity["tags"].([]any); ok {
+		tags = existing
+	}
+	return entity, tags, nil
+}
@@ -0,0 +1,225 @@
+package arrapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v3/tag" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id": 1, "label": "anime"}, {"id": 3, "label": "decluttarr"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Name: "sonarr", BaseURL: server.URL, APIKey: "test"})
+
+	tags, err := client.GetTags(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags) != 2 || tags[1].ID != 3 || tags[1].Label != "decluttarr" {
+		t.Errorf("unexpected tags %+v", tags)
+	}
+}
+
+func TestAddEntityTag(t *testing.T) {
+	tests := []struct {
+		name       string
+		tags       string // the instance's tags
+		seriesTags string // the series' tags
+		wantCreate bool
+		wantTags   []any // nil if the series shouldn't be updated
+	}{
+		{
+			name:       "existing tag",
+			tags:       `[{"id": 1, "label": "anime"}, {"id": 3, "label": "decluttarr"}]`,
+			seriesTags: `[1]`,
+			wantTags:   []any{float64(1), float64(3)},
+		},
+		{
+			name:       "tag created",
+			tags:       `[{"id": 1, "label": "anime"}]`,
+			seriesTags: `[]`,
+			wantCreate: true,
+			wantTags:   []any{float64(7)},
+		},
+		{
+			name:       "already tagged",
+			tags:       `[{"id": 3, "label": "Decluttarr"}]`,
+			seriesTags: `[3]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created map[string]any
+			var updated map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v3/tag":
+					_, _ = w.Write([]byte(tt.tags))
+				case r.Method == http.MethodPost && r.URL.Path == "/api/v3/tag":
+					if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+						t.Errorf("failed to decode body: %v", err)
+					}
+					_, _ = w.Write([]byte(`{"id": 7, "label": "decluttarr"}`))
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v3/series/42":
+					_, _ = w.Write([]byte(`{"id": 42, "title": "Show", "qualityProfileId": 4, "tags": ` + tt.seriesTags + `}`))
+				case r.Method == http.MethodPut && r.URL.Path == "/api/v3/series/42":
+					if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+						t.Errorf("failed to decode body: %v", err)
+					}
+					w.WriteHeader(http.StatusAccepted)
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(ClientConfig{Name: "sonarr", BaseURL: server.URL, APIKey: "test"})
+
+			if err := client.AddEntityTag(context.Background(), "series", 42, "decluttarr"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.wantCreate != (created != nil) {
+				t.Errorf("expected tag created %v, got %v", tt.wantCreate, created)
+			}
+			if created != nil && created["label"] != "decluttarr" {
+				t.Errorf("expected tag label decluttarr, got %v", created["label"])
+			}
+
+			if tt.wantTags == nil {
+				if updated != nil {
+					t.Errorf("expected series not to be updated, got %v", updated)
+				}
+				return
+			}
+			if updated == nil {
+				t.Fatal("expected series to be updated")
+			}
+			tags, _ := updated["tags"].([]any)
+			if len(tags) != len(tt.wantTags) {
+				t.Fatalf("expected tags %v, got %v", tt.wantTags, tags)
+			}
+			for i := range tags {
+				if tags[i] != tt.wantTags[i] {
+					t.Errorf("expected tags %v, got %v", tt.wantTags, tags)
+				}
+			}
+			if updated["qualityProfileId"] != float64(4) {
+				t.Errorf("expected unmodelled fields to be preserved, got %v", updated["qualityProfileId"])
+			}
+		})
+	}
+}
+
+func TestTagIDCached(t *testing.T) {
+	tagRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/tag":
+			tagRequests++
+			_, _ = w.Write([]byte(`[{"id": 3, "label": "decluttarr"}]`))
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"tags": []}`))
+		case r.Method == http.MethodPut:
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Name: "sonarr", BaseURL: server.URL, APIKey: "test"})
+
+	for _, id := range []int{1, 2, 3} {
+		if err := client.AddEntityTag(context.Background(), "series", id, "Decluttarr"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if tagRequests != 1 {
+		t.Errorf("expected the tag ID to be looked up once, got %d lookups", tagRequests)
+	}
+}
+
+func TestRemoveEntityTag(t *testing.T) {
+	tests := []struct {
+		name       string
+		tags       string // the instance's tags
+		seriesTags string // the series' tags
+		wantTags   []any  // nil if the series shouldn't be updated
+	}{
+		{
+			name:       "tagged",
+			tags:       `[{"id": 1, "label": "anime"}, {"id": 3, "label": "decluttarr"}]`,
+			seriesTags: `[1, 3]`,
+			wantTags:   []any{float64(1)},
+		},
+		{
+			name:       "not tagged",
+			tags:       `[{"id": 3, "label": "decluttarr"}]`,
+			seriesTags: `[1]`,
+		},
+		{
+			name: "tag doesn't exist",
+			tags: `[{"id": 1, "label": "anime"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v3/tag":
+					_, _ = w.Write([]byte(tt.tags))
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v3/series/42":
+					_, _ = w.Write([]byte(`{"id": 42, "title": "Show", "qualityProfileId": 4, "tags": ` + tt.seriesTags + `}`))
+				case r.Method == http.MethodPut && r.URL.Path == "/api/v3/series/42":
+					if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+						t.Errorf("failed to decode body: %v", err)
+					}
+					w.WriteHeader(http.StatusAccepted)
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(ClientConfig{Name: "sonarr", BaseURL: server.URL, APIKey: "test"})
+
+			if err := client.RemoveEntityTag(context.Background(), "series", 42, "decluttarr"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.wantTags == nil {
+				if updated != nil {
+					t.Errorf("expected series not to be updated, got %v", updated)
+				}
+				return
+			}
+			if updated == nil {
+				t.Fatal("expected series to be updated")
+			}
+			tags, _ := updated["tags"].([]any)
+			if len(tags) != len(tt.wantTags) || tags[0] != tt.wantTags[0] {
+				t.Errorf("expected tags %v, got %v", tt.wantTags, tags)
+			}
+			if updated["qualityProfileId"] != float64(4) {
+				t.Errorf("expected unmodelled fields to be preserved, got %v", updated["qualityProfileId"])
+			}
+		})
+	}
+}
@@ -1,20 +1,30 @@
 package config
 
-import "time"
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Config represents the complete application configuration
 type Config struct {
+	IncludeDir      string                `mapstructure:"include_dir"`
 	General         GeneralConfig         `mapstructure:"general"`
 	JobDefaults     JobDefaultsConfig     `mapstructure:"job_defaults"`
 	Jobs            JobsConfig            `mapstructure:"jobs"`
 	Instances       InstancesConfig       `mapstructure:"instances"`
 	DownloadClients DownloadClientsConfig `mapstructure:"download_clients"`
+	Server          ServerConfig          `mapstructure:"server"`
+	Notifications   NotificationsConfig   `mapstructure:"notifications"`
 }
 
 // GeneralConfig contains global application settings
 type GeneralConfig struct {
 	LogLevel               string        `mapstructure:"log_level"`
 	TestRun                bool          `mapstructure:"test_run"`
+	FirstRunDryRun         bool          `mapstructure:"first_run_dry_run"` // force test run for the first cycle after startup
 	Timer                  time.Duration `mapstructure:"timer"`
 	SSLVerification        bool          `mapstructure:"ssl_verification"`
 	RequestTimeout         time.Duration `mapstructure:"request_timeout"`
@@ -23,11 +33,177 @@ type GeneralConfig struct {
 	IgnoreDownloadClients  []string      `mapstructure:"ignore_download_clients"`
 	ObsoleteTag            string        `mapstructure:"obsolete_tag"`
 	ProtectedTag           string        `mapstructure:"protected_tag"`
+	ProtectedCategories    []string      `mapstructure:"protected_categories"`
+	ProtectedStates        []string      `mapstructure:"protected_states"` // client states (e.g. checkingDL, moving) in which torrents aren't touched
+	StatsHistorySize       int           `mapstructure:"stats_history_size"`
+	BlocklistPublic        *bool         `mapstructure:"blocklist_public"`     // nil = job default
+	BlocklistPrivate       *bool         `mapstructure:"blocklist_private"`    // nil = job default
+	RequireDeleteOptin     bool          `mapstructure:"require_delete_optin"` // only delete from instances with allow_delete
+	TraceDecisions         bool          `mapstructure:"trace_decisions"`      // log a debug entry per queue item explaining each removal job's decision
+	DebugLogSampling       int           `mapstructure:"debug_log_sampling"`   // log every Nth repeat of a job's debug line each cycle; 0 or 1 = every line
+	CycleSummary           string        `mapstructure:"cycle_summary"`        // "stdout" or a file path each cycle's NDJSON summary is appended to; empty = off
+	PropertyWorkers        int           `mapstructure:"property_workers"`     // per-torrent property requests in flight per download client
+	PreRemoveHook          string        `mapstructure:"pre_remove_hook"`      // command run before each removal; a non-zero exit skips the removal
+	PreRemoveHookTimeout   time.Duration `mapstructure:"pre_remove_hook_timeout"`
+	ActiveHours            ActiveHours   `mapstructure:"active_hours"`            // cycles outside this window are skipped
+	CorrectClockSkew       bool          `mapstructure:"correct_clock_skew"`      // measure each *arr's clock every cycle and correct its queue timestamps
+	QueueDetails           bool          `mapstructure:"queue_details"`           // also fetch queue/details for status messages the paged queue leaves out
+	RequireWritableData    bool          `mapstructure:"require_writable_data"`   // exit at startup instead of keeping strikes in memory when the data dir is read-only
+	ForceDeleteAfter       int           `mapstructure:"force_delete_after"`      // failed *arr removals of a download before it's deleted from the download client directly; 0 = never
+	BlocklistRegrabsAfter  int           `mapstructure:"blocklist_regrabs_after"` // recent removals of a download before its next removal blocklists the release; 0 = never
+	ProcessedTag           string        `mapstructure:"processed_tag"`           // *arr tag applied to the series/movie/artist/author of each removal; empty = off
+	ProcessedTagTTL        time.Duration `mapstructure:"processed_tag_ttl"`       // how long removal jobs skip a tagged entry's downloads; 0 = 24h
+}
+
+// ActiveHours is a daily window, in local time, during which cycles run
+type ActiveHours struct {
+	Start string   `mapstructure:"start"` // HH:MM; empty = always active
+	End   string   `mapstructure:"end"`   // HH:MM; earlier than start for a window crossing midnight
+	Days  []string `mapstructure:"days"`  // mon, tue, ... on which the window starts; empty = every day
+}
+
+// weekdays maps the day names accepted in active_hours.days to weekdays
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Enabled reports whether an active window is configured
+func (a ActiveHours) Enabled() bool {
+	return a.Start != "" || a.End != ""
+}
+
+// Contains reports whether t falls within the active window. A window that
+// crosses midnight belongs to the day it starts on, so a Friday 22:00-06:00
+// window includes early Saturday morning. Unparseable settings, which
+// validation rejects, count as always active.
+func (a ActiveHours) Contains(t time.Time) bool {
+	if !a.Enabled() {
+		return true
+	}
+
+	start, errStart := parseClock(a.Start)
+	end, errEnd := parseClock(a.End)
+	if errStart != nil || errEnd != nil || start == end {
+		return true
+	}
+
+	now := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+
+	if start < end {
+		return now >= start && now < end && a.onDay(day)
+	}
+
+	// Crosses midnight
+	if now >= start {
+		return a.onDay(day)
+	}
+	if now < end {
+		return a.onDay((day + 6) % 7) // started the day before
+	}
+	return false
+}
+
+// onDay reports whether the window starts on day
+func (a ActiveHours) onDay(day time.Weekday) bool {
+	if len(a.Days) == 0 {
+		return true
+	}
+	for _, name := range a.Days {
+		if d, ok := weekdays[strings.ToLower(name)]; ok && d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock parses an HH:MM time of day into minutes since midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// DefaultServerPort is the port the built-in HTTP server listens on by default
+const DefaultServerPort = 9595
+
+// ServerConfig configures the built-in HTTP server used for health checks
+type ServerConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Address  string `mapstructure:"address"`
+	Port     int    `mapstructure:"port"`
+	APIToken string `mapstructure:"api_token"` // bearer token for POST /run; empty disables it
+}
+
+// ListenAddress returns the host:port the server should listen on
+func (s ServerConfig) ListenAddress() string {
+	return net.JoinHostPort(s.Address, strconv.Itoa(s.Port))
+}
+
+// TimeoutFor returns the request timeout to use for a client, preferring a
+// non-zero per-client override over the global request_timeout
+func (g GeneralConfig) TimeoutFor(override time.Duration) time.Duration {
+	if override > 0 {
+		return override
+	}
+	return g.RequestTimeout
+}
+
+// NotificationsConfig contains notification service configurations
+type NotificationsConfig struct {
+	Pushover PushoverConfig `mapstructure:"pushover"`
+	Email    EmailConfig    `mapstructure:"email"`
+}
+
+// PushoverConfig configures Pushover notifications
+type PushoverConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Token    string `mapstructure:"token"`
+	User     string `mapstructure:"user"`
+	Priority int    `mapstructure:"priority"` // -2 (lowest) to 2 (emergency)
+
+	// Emergency (priority 2) messages repeat every Retry until acknowledged
+	// or Expire has passed; Pushover requires both
+	Retry  time.Duration `mapstructure:"retry"`  // at least 30s
+	Expire time.Duration `mapstructure:"expire"` // at most 3h
+
+	NotifyEvents `mapstructure:",squash"`
+}
+
+// EmailConfig configures SMTP email notifications
+type EmailConfig struct {
+	Enabled    bool     `mapstructure:"enabled"`
+	Host       string   `mapstructure:"host"`
+	Port       int      `mapstructure:"port"`
+	Username   string   `mapstructure:"username"`
+	Password   string   `mapstructure:"password"`
+	From       string   `mapstructure:"from"`
+	To         []string `mapstructure:"to"`
+	Encryption string   `mapstructure:"encryption"` // starttls, tls, or none
+
+	NotifyEvents `mapstructure:",squash"`
+}
+
+// NotifyEvents selects the strike events a notifier reports alongside
+// removals and errors. Both are off by default.
+type NotifyEvents struct {
+	NearRemoval        bool `mapstructure:"near_removal"`        // report downloads one strike from removal
+	ApproachingStrikes int  `mapstructure:"approaching_strikes"` // report downloads with at least this many strikes; 0 disables
 }
 
 // JobDefaultsConfig contains default settings for all jobs
 type JobDefaultsConfig struct {
 	MaxStrikes          int           `mapstructure:"max_strikes"`
+	MaxStrikesPrivate   int           `mapstructure:"max_strikes_private"`
+	MaxStrikesPublic    int           `mapstructure:"max_strikes_public"`
 	NoStalled           bool          `mapstructure:"no_stalled"`
 	NoSlow              bool          `mapstructure:"no_slow"`
 	NoActive            bool          `mapstructure:"no_active"`
@@ -35,6 +211,9 @@ type JobDefaultsConfig struct {
 	PermittedAttempts   int           `mapstructure:"permitted_attempts"`
 	MinDownloadSpeed    float64       `mapstructure:"min_download_speed"`
 	MinTimeLeft         time.Duration `mapstructure:"min_time_left"`
+	MinGrabAge          time.Duration `mapstructure:"min_grab_age"`
+	ProtectNewest       int           `mapstructure:"protect_newest"`
+	DeferLastActive     bool          `mapstructure:"defer_last_active"`
 	MinRatio            float64       `mapstructure:"min_ratio"`
 	MaxRatio            float64       `mapstructure:"max_ratio"`
 	MaxSeedTime         time.Duration `mapstructure:"max_seed_time"`
@@ -43,6 +222,8 @@ type JobDefaultsConfig struct {
 	ApplyImportedAction bool          `mapstructure:"apply_imported_action"`
 	ApplyNotImported    bool          `mapstructure:"apply_not_imported"`
 	ApplyTags           bool          `mapstructure:"apply_tags"`
+	ReviewCategory      string        `mapstructure:"review_category"`
+	ReasonTags          bool          `mapstructure:"reason_tags"`
 }
 
 // JobsConfig contains individual job configurations
@@ -57,9 +238,13 @@ type JobsConfig struct {
 	RemoveBadFiles           JobConfig               `mapstructure:"remove_bad_files"`
 	TagOrphans               JobConfig               `mapstructure:"tag_orphans"`
 	RemoveMetadataFailed     JobConfig               `mapstructure:"remove_metadata_failed"`
+	RemoveNotStarted         JobConfig               `mapstructure:"remove_not_started"`
 	EnforceSeedingLimits     JobConfig               `mapstructure:"enforce_seeding_limits"`
 	ManageFreeSpace          JobConfig               `mapstructure:"manage_free_space"`
 	RemoveDuplicateDownloads JobConfig               `mapstructure:"remove_duplicate_downloads"`
+	LimitActiveDownloads     JobConfig               `mapstructure:"limit_active_downloads"`
+	PauseNoSpace             JobConfig               `mapstructure:"pause_no_space"`
+	CleanObsoleteTags        JobConfig               `mapstructure:"clean_obsolete_tags"`
 	RemoveDoneSeeding        RemoveDoneSeedingConfig `mapstructure:"remove_done_seeding"`
 	SearchMissing            SearchJobConfig         `mapstructure:"search_missing"`
 	SearchUnmetCutoff        SearchJobConfig         `mapstructure:"search_unmet_cutoff"`
@@ -67,40 +252,82 @@ type JobsConfig struct {
 
 // JobConfig represents configuration for a specific job
 type JobConfig struct {
-	Enabled             bool          `mapstructure:"enabled"`
-	MaxStrikes          *int          `mapstructure:"max_strikes"`
-	NoStalled           *bool         `mapstructure:"no_stalled"`
-	NoSlow              *bool         `mapstructure:"no_slow"`
-	NoActive            *bool         `mapstructure:"no_active"`
-	NoUploading         *bool         `mapstructure:"no_uploading"`
-	PermittedAttempts   *int          `mapstructure:"permitted_attempts"`
-	MinDownloadSpeed    *float64      `mapstructure:"min_download_speed"`
-	MinTimeLeft         *time.Duration `mapstructure:"min_time_left"`
-	MinRatio            *float64      `mapstructure:"min_ratio"`
-	MaxRatio            *float64      `mapstructure:"max_ratio"`
-	MaxSeedTime         *time.Duration `mapstructure:"max_seed_time"`
-	MaxActiveDownloads  *int          `mapstructure:"max_active_downloads"`
-	FreeSpaceThreshold  *int64        `mapstructure:"free_space_threshold"`
-	ApplyImportedAction *bool         `mapstructure:"apply_imported_action"`
-	ApplyNotImported    *bool         `mapstructure:"apply_not_imported"`
-	ApplyTags           *bool         `mapstructure:"apply_tags"`
-	TagsToApply         []string      `mapstructure:"tags_to_apply"`
-	MessagePatterns     []string      `mapstructure:"message_patterns"`
-	KeepArchives        *bool         `mapstructure:"keep_archives"`
+	Enabled                bool           `mapstructure:"enabled"`
+	MaxStrikes             *int           `mapstructure:"max_strikes"`
+	MaxStrikesPrivate      *int           `mapstructure:"max_strikes_private"` // max_strikes for private tracker torrents, 0 = max_strikes
+	MaxStrikesPublic       *int           `mapstructure:"max_strikes_public"`  // max_strikes for public tracker torrents, 0 = max_strikes
+	NoStalled              *bool          `mapstructure:"no_stalled"`
+	NoSlow                 *bool          `mapstructure:"no_slow"`
+	NoActive               *bool          `mapstructure:"no_active"`
+	NoUploading            *bool          `mapstructure:"no_uploading"`
+	PermittedAttempts      *int           `mapstructure:"permitted_attempts"`
+	MinDownloadSpeed       *float64       `mapstructure:"min_download_speed"`
+	MinTimeLeft            *time.Duration `mapstructure:"min_time_left"`
+	SlowMinProgressExempt  *float64       `mapstructure:"slow_min_progress_exempt"` // remove_slow: downloads at least this fraction complete aren't struck
+	MinGrabAge             *time.Duration `mapstructure:"min_grab_age"`             // downloads the *arr grabbed more recently aren't removed
+	ProtectNewest          *int           `mapstructure:"protect_newest"`           // remove_stalled/remove_slow: the N newest grabs per series/movie aren't removed
+	DeferLastActive        *bool          `mapstructure:"defer_last_active"`        // keep the only active download in a category until another is active
+	MinRatio               *float64       `mapstructure:"min_ratio"`
+	MaxRatio               *float64       `mapstructure:"max_ratio"`
+	MaxSeedTime            *time.Duration `mapstructure:"max_seed_time"`
+	MaxActiveDownloads     *int           `mapstructure:"max_active_downloads"`
+	FreeSpaceThreshold     *int64         `mapstructure:"free_space_threshold"`
+	ApplyImportedAction    *bool          `mapstructure:"apply_imported_action"`
+	ApplyNotImported       *bool          `mapstructure:"apply_not_imported"`
+	ApplyTags              *bool          `mapstructure:"apply_tags"`
+	ReviewCategory         *string        `mapstructure:"review_category"`
+	ReasonTags             *bool          `mapstructure:"reason_tags"`  // tag torrents kept in the client with why they were removed
+	DeleteFiles            *bool          `mapstructure:"delete_files"` // delete removed torrents' files from disk; nil = job default
+	TagsToApply            []string       `mapstructure:"tags_to_apply"`
+	MessagePatterns        []string       `mapstructure:"message_patterns"`
+	ImportBlocked          *bool          `mapstructure:"import_blocked"`
+	BlockedMessagePatterns []string       `mapstructure:"blocked_message_patterns"`
+	KeepArchives           *bool          `mapstructure:"keep_archives"`
+	BlocklistRedownload    *bool          `mapstructure:"blocklist_redownload"`
+	StalledGrace           *time.Duration `mapstructure:"stalled_grace"`         // torrents added more recently aren't struck
+	DisableIndexerAfter    *int           `mapstructure:"disable_indexer_after"` // removals traced to one indexer before it's disabled; nil/0 = never
+	ManualImport           *bool          `mapstructure:"manual_import"`
+	ImportPendingAfter     *time.Duration `mapstructure:"import_pending_after"` // completed downloads grabbed longer ago still pending import are handled; nil/0 = never
+	MaxGrabToDownload      *time.Duration `mapstructure:"max_grab_to_download"` // remove_not_started: downloads grabbed longer ago still at 0% are removed; nil/0 = 6h
+	Interval               *time.Duration `mapstructure:"interval"`             // nil = every cycle
+	Order                  *int           `mapstructure:"order"`                // position in the cycle; nil = default (removals before searches)
+	TargetCategories       []string       `mapstructure:"target_categories"`
+	IgnoreCategories       []string       `mapstructure:"ignore_categories"`
+	Protocols              []string       `mapstructure:"protocols"` // torrent and/or usenet; empty = all
+	Instances              []string       `mapstructure:"instances"` // *arr instances (or, for client-only jobs, download clients) to act on; empty = all
 }
 
 // SearchJobConfig represents configuration for search jobs
 type SearchJobConfig struct {
-	Enabled                bool `mapstructure:"enabled"`
-	MinDaysBetweenSearches int  `mapstructure:"min_days_between_searches"`
-	MaxConcurrentSearches  int  `mapstructure:"max_concurrent_searches"`
+	Enabled                bool          `mapstructure:"enabled"`
+	MinDaysBetweenSearches int           `mapstructure:"min_days_between_searches"`
+	MaxConcurrentSearches  int           `mapstructure:"max_concurrent_searches"`
+	EpisodesPerSearch      int           `mapstructure:"episodes_per_search"` // search_missing only: most episodes per Sonarr search command; 0 = 50
+	IncludeAnime           bool          `mapstructure:"include_anime"`       // search episodes without an air date that have an absolute number
+	Interval               time.Duration `mapstructure:"interval"`            // 0 = every cycle
+	Order                  *int          `mapstructure:"order"`               // position in the cycle; nil = after removal jobs
+	Instances              []string      `mapstructure:"instances"`           // *arr instances to search; empty = all
 }
 
 // RemoveDoneSeedingConfig represents configuration for remove_done_seeding job
 type RemoveDoneSeedingConfig struct {
-	Enabled          bool     `mapstructure:"enabled"`
-	TargetTags       []string `mapstructure:"target_tags"`
-	TargetCategories []string `mapstructure:"target_categories"`
+	Enabled           bool                   `mapstructure:"enabled"`
+	TargetTags        []string               `mapstructure:"target_tags"`
+	TargetCategories  []string               `mapstructure:"target_categories"`
+	ExcludeTags       []string               `mapstructure:"exclude_tags"`       // never removed, even when targeted and done seeding
+	ExcludeCategories []string               `mapstructure:"exclude_categories"` // never removed, even when targeted and done seeding
+	Goals             map[string]SeedingGoal `mapstructure:"goals"`              // tag or category -> goal, used when the client sets no limit
+	DeleteFiles       *bool                  `mapstructure:"delete_files"`       // delete removed torrents' files from disk; nil = false
+	Interval          time.Duration          `mapstructure:"interval"`           // 0 = every cycle
+	Order             *int                   `mapstructure:"order"`              // position in the cycle; nil = with the other removal jobs
+	Instances         []string               `mapstructure:"instances"`          // download clients to act on; empty = all
+}
+
+// SeedingGoal is a ratio and/or seed time after which a torrent is done
+// seeding. Zero values are unset; the torrent is done once either set goal is met.
+type SeedingGoal struct {
+	Ratio    float64       `mapstructure:"ratio"`
+	SeedTime time.Duration `mapstructure:"seed_time"`
 }
 
 // InstancesConfig contains all *arr instance configurations
@@ -114,16 +341,41 @@ type InstancesConfig struct {
 
 // InstanceConfig represents a single *arr instance
 type InstanceConfig struct {
-	Name                   string   `mapstructure:"name"`
-	URL                    string   `mapstructure:"url"`
-	APIKey                 string   `mapstructure:"api_key"`
-	Enabled                bool     `mapstructure:"enabled"`
-	EnabledJobs            []string `mapstructure:"enabled_jobs"`
-	DisabledJobs           []string `mapstructure:"disabled_jobs"`
-	ProtectedTags          []string `mapstructure:"protected_tags"`
-	IgnoreTags             []string `mapstructure:"ignore_tags"`
-	OnlyTags               []string `mapstructure:"only_tags"`
-	DownloadClientPriority []string `mapstructure:"download_client_priority"`
+	Name                   string            `mapstructure:"name"`
+	URL                    string            `mapstructure:"url"`
+	APIKey                 string            `mapstructure:"api_key"`
+	Enabled                bool              `mapstructure:"enabled"`
+	EnabledJobs            []string          `mapstructure:"enabled_jobs"`
+	DisabledJobs           []string          `mapstructure:"disabled_jobs"`
+	ProtectedTags          []string          `mapstructure:"protected_tags"`
+	IgnoreTags             []string          `mapstructure:"ignore_tags"`
+	OnlyTags               []string          `mapstructure:"only_tags"`
+	DownloadClientPriority []string          `mapstructure:"download_client_priority"`
+	RequestTimeout         time.Duration     `mapstructure:"request_timeout"`     // 0 = use general.request_timeout
+	APIVersion             string            `mapstructure:"api_version"`         // "" = app default (v3 Sonarr/Radarr, v1 Lidarr/Readarr)
+	UserAgent              string            `mapstructure:"user_agent"`          // "" = Go default
+	Headers                map[string]string `mapstructure:"headers"`             // extra headers sent on every request
+	AllowDelete            bool              `mapstructure:"allow_delete"`        // opt in to deletion when general.require_delete_optin is set
+	RequestsPerSecond      float64           `mapstructure:"requests_per_second"` // pace requests to this instance; 0 = unlimited
+}
+
+// DeleteAllowed reports whether removal jobs may delete from the named *arr
+// instance. Without general.require_delete_optin every instance may; with it,
+// only instances that set allow_delete, and the rest are treated as a test run.
+func (c *Config) DeleteAllowed(instanceName string) bool {
+	if !c.General.RequireDeleteOptin {
+		return true
+	}
+
+	for _, instances := range [][]InstanceConfig{c.Instances.Sonarr, c.Instances.Radarr, c.Instances.Lidarr, c.Instances.Readarr, c.Instances.Whisparr} {
+		for _, inst := range instances {
+			if inst.Name == instanceName {
+				return inst.AllowDelete
+			}
+		}
+	}
+
+	return false
 }
 
 // DownloadClientsConfig contains all download client configurations
@@ -135,26 +387,42 @@ type DownloadClientsConfig struct {
 
 // QbittorrentConfig represents a qBittorrent client
 type QbittorrentConfig struct {
-	Name     string `mapstructure:"name"`
-	URL      string `mapstructure:"url"`
-	Username string `mapstructure:"username"`
-	Password string `mapstructure:"password"`
-	Enabled  bool   `mapstructure:"enabled"`
+	Name              string            `mapstructure:"name"`
+	URL               string            `mapstructure:"url"`
+	Username          string            `mapstructure:"username"`
+	Password          string            `mapstructure:"password"`
+	Enabled           bool              `mapstructure:"enabled"`
+	RequestTimeout    time.Duration     `mapstructure:"request_timeout"` // 0 = use general.request_timeout
+	SkipTLS           bool              `mapstructure:"skip_tls"`
+	UserAgent         string            `mapstructure:"user_agent"`          // "" = Go default
+	Headers           map[string]string `mapstructure:"headers"`             // extra headers sent on every request
+	TrackerFallback   bool              `mapstructure:"tracker_fallback"`    // fetch the tracker list of torrents the summary reports no tracker for
+	RequestsPerSecond float64           `mapstructure:"requests_per_second"` // pace requests to this client; 0 = unlimited
 }
 
 // SabnzbdConfig represents a SABnzbd client
 type SabnzbdConfig struct {
-	Name    string `mapstructure:"name"`
-	URL     string `mapstructure:"url"`
-	APIKey  string `mapstructure:"api_key"`
-	Enabled bool   `mapstructure:"enabled"`
+	Name              string            `mapstructure:"name"`
+	URL               string            `mapstructure:"url"`
+	APIKey            string            `mapstructure:"api_key"`
+	Enabled           bool              `mapstructure:"enabled"`
+	RequestTimeout    time.Duration     `mapstructure:"request_timeout"` // 0 = use general.request_timeout
+	SkipTLS           bool              `mapstructure:"skip_tls"`
+	UserAgent         string            `mapstructure:"user_agent"`          // "" = Go default
+	Headers           map[string]string `mapstructure:"headers"`             // extra headers sent on every request
+	RequestsPerSecond float64           `mapstructure:"requests_per_second"` // pace requests to this client; 0 = unlimited
 }
 
 // NzbgetConfig represents an NZBGet client
 type NzbgetConfig struct {
-	Name     string `mapstructure:"name"`
-	URL      string `mapstructure:"url"`
-	Username string `mapstructure:"username"`
-	Password string `mapstructure:"password"`
-	Enabled  bool   `mapstructure:"enabled"`
+	Name              string            `mapstructure:"name"`
+	URL               string            `mapstructure:"url"`
+	Username          string            `mapstructure:"username"`
+	Password          string            `mapstructure:"password"`
+	Enabled           bool              `mapstructure:"enabled"`
+	RequestTimeout    time.Duration     `mapstructure:"request_timeout"` // 0 = use general.request_timeout
+	SkipTLS           bool              `mapstructure:"skip_tls"`
+	UserAgent         string            `mapstructure:"user_agent"`          // "" = Go default
+	Headers           map[string]string `mapstructure:"headers"`             // extra headers sent on every request
+	RequestsPerSecond float64           `mapstructure:"requests_per_second"` // pace requests to this client; 0 = unlimited
 }
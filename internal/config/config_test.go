@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteAllowed(t *testing.T) {
+	cfg := &Config{
+		General: GeneralConfig{RequireDeleteOptin: true},
+		Instances: InstancesConfig{
+			Sonarr:   []InstanceConfig{{Name: "sonarr", AllowDelete: true}},
+			Radarr:   []InstanceConfig{{Name: "radarr"}},
+			Whisparr: []InstanceConfig{{Name: "whisparr", AllowDelete: true}},
+		},
+	}
+
+	assert.True(t, cfg.DeleteAllowed("sonarr"))
+	assert.False(t, cfg.DeleteAllowed("radarr"), "instances must opt in")
+	assert.True(t, cfg.DeleteAllowed("whisparr"))
+	assert.False(t, cfg.DeleteAllowed("unknown"))
+
+	cfg.General.RequireDeleteOptin = false
+	assert.True(t, cfg.DeleteAllowed("radarr"), "every instance may delete without require_delete_optin")
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// legacyBools maps boolean keys of the original (Python) decluttarr config to
+// their go-decluttarr config keys
+var legacyBools = map[string]string{
+	"TEST_RUN":                "general.test_run",
+	"SSL_VERIFICATION":        "general.ssl_verification",
+	"REMOVE_BAD_FILES":        "jobs.remove_bad_files.enabled",
+	"REMOVE_FAILED":           "jobs.remove_failed_downloads.enabled",
+	"REMOVE_FAILED_IMPORTS":   "jobs.remove_failed_imports.enabled",
+	"REMOVE_METADATA_MISSING": "jobs.remove_metadata_failed.enabled",
+	"REMOVE_MISSING_FILES":    "jobs.remove_missing_files.enabled",
+	"REMOVE_ORPHANS":          "jobs.remove_orphans.enabled",
+	"REMOVE_SLOW":             "jobs.remove_slow.enabled",
+	"REMOVE_STALLED":          "jobs.remove_stalled.enabled",
+	"REMOVE_UNMONITORED":      "jobs.remove_unmonitored.enabled",
+}
+
+// legacyLists maps JSON list keys of the legacy config to their config keys
+var legacyLists = map[string]string{
+	"FAILED_IMPORT_MESSAGE_PATTERNS": "jobs.remove_failed_imports.message_patterns",
+	"IGNORED_DOWNLOAD_CLIENTS":       "general.ignore_download_clients",
+}
+
+// legacyInstances are the *arr apps configured by <APP>_URL and <APP>_KEY
+var legacyInstances = []string{"sonarr", "radarr", "lidarr", "readarr", "whisparr"}
+
+// ImportLegacy reads a config of the original (Python) decluttarr, either its
+// config.conf (INI) or .env (KEY=value) form, and writes the equivalent
+// go-decluttarr YAML to w. Only the settings found are written, so the rest
+// keep their defaults. Returns the keys that have no equivalent.
+func ImportLegacy(r io.Reader, w io.Writer) ([]string, error) {
+	values, err := parseLegacy(r)
+	if err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	mapped := make(map[string]bool)
+
+	for legacyKey, key := range legacyBools {
+		raw, ok := values[legacyKey]
+		if !ok {
+			continue
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: expected a boolean, got %q", legacyKey, raw)
+		}
+		v.Set(key, b)
+		mapped[legacyKey] = true
+	}
+
+	for legacyKey, key := range legacyLists {
+		raw, ok := values[legacyKey]
+		if !ok {
+			continue
+		}
+		var list []string
+		if err := json.Unmarshal([]byte(raw), &list); err != nil {
+			return nil, fmt.Errorf("%s: expected a JSON list of strings: %w", legacyKey, err)
+		}
+		v.Set(key, list)
+		mapped[legacyKey] = true
+	}
+
+	if level, ok := values["LOG_LEVEL"]; ok {
+		level = strings.ToLower(level)
+		if level == "verbose" {
+			level = "debug"
+		}
+		v.Set("general.log_level", level)
+		mapped["LOG_LEVEL"] = true
+	}
+
+	if timer, ok := values["REMOVE_TIMER"]; ok {
+		minutes, err := strconv.ParseFloat(timer, 64)
+		if err != nil {
+			return nil, fmt.Errorf("REMOVE_TIMER: expected minutes, got %q", timer)
+		}
+		v.Set("general.timer", time.Duration(minutes*float64(time.Minute)).String())
+		mapped["REMOVE_TIMER"] = true
+	}
+
+	if attempts, ok := values["PERMITTED_ATTEMPTS"]; ok {
+		// The legacy permitted attempts are the strikes before removal
+		n, err := strconv.Atoi(attempts)
+		if err != nil {
+			return nil, fmt.Errorf("PERMITTED_ATTEMPTS: expected a number, got %q", attempts)
+		}
+		v.Set("job_defaults.max_strikes", n)
+		mapped["PERMITTED_ATTEMPTS"] = true
+	}
+
+	if speed, ok := values["MIN_DOWNLOAD_SPEED"]; ok {
+		kbps, err := strconv.ParseFloat(speed, 64)
+		if err != nil {
+			return nil, fmt.Errorf("MIN_DOWNLOAD_SPEED: expected a number, got %q", speed)
+		}
+		v.Set("job_defaults.min_download_speed", kbps)
+		mapped["MIN_DOWNLOAD_SPEED"] = true
+	}
+
+	if tag, ok := values["NO_STALLED_REMOVAL_QBIT_TAG"]; ok {
+		v.Set("general.protected_tag", tag)
+		mapped["NO_STALLED_REMOVAL_QBIT_TAG"] = true
+	}
+
+	if raw, ok := values["IGNORE_PRIVATE_TRACKERS"]; ok {
+		ignore, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("IGNORE_PRIVATE_TRACKERS: expected a boolean, got %q", raw)
+		}
+		handling := "remove"
+		if ignore {
+			handling = "skip"
+		}
+		v.Set("general.private_tracker_handling", handling)
+		mapped["IGNORE_PRIVATE_TRACKERS"] = true
+	}
+
+	for _, app := range legacyInstances {
+		prefix := strings.ToUpper(app)
+		url, ok := values[prefix+"_URL"]
+		if !ok {
+			continue
+		}
+		v.Set("instances."+app, []map[string]any{{
+			"name":    app,
+			"url":     url,
+			"api_key": values[prefix+"_KEY"],
+			"enabled": true,
+		}})
+		mapped[prefix+"_URL"] = true
+		mapped[prefix+"_KEY"] = true
+	}
+
+	if url, ok := values["QBITTORRENT_URL"]; ok {
+		v.Set("download_clients.qbittorrent", []map[string]any{{
+			"name":     "qbittorrent",
+			"url":      url,
+			"username": values["QBITTORRENT_USERNAME"],
+			"password": values["QBITTORRENT_PASSWORD"],
+			"enabled":  true,
+		}})
+		mapped["QBITTORRENT_URL"] = true
+		mapped["QBITTORRENT_USERNAME"] = true
+		mapped["QBITTORRENT_PASSWORD"] = true
+	}
+
+	if err := v.WriteConfigTo(w); err != nil {
+		return nil, fmt.Errorf("failed to write config: %w", err)
+	}
+
+	var unmapped []string
+	for key := range values {
+		if !mapped[key] {
+			unmapped = append(unmapped, key)
+		}
+	}
+	sort.Strings(unmapped)
+
+	return unmapped, nil
+}
+
+// parseLegacy reads KEY=value pairs, ignoring blank lines, # and ; comments,
+// INI section headers and an "export " prefix. Keys are upper-cased and
+// surrounding quotes are stripped from values.
+func parseLegacy(r io.Reader) (map[string]string, error) {
+	values := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=value", lineNum)
+		}
+
+		key = strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(key, "export ")))
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read legacy config: %w", err)
+	}
+
+	return values, nil
+}
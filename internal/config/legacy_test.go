@@ -0,0 +1,109 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const legacyConfig = `[general]
+LOG_LEVEL = VERBOSE
+TEST_RUN = True
+REMOVE_TIMER = 10
+
+[features]
+REMOVE_FAILED = True
+REMOVE_FAILED_IMPORTS = True
+REMOVE_STALLED = True
+REMOVE_SLOW = False
+RUN_PERIODIC_RESCANS = {"SONARR": {"MISSING": true}}
+
+[feature_settings]
+MIN_DOWNLOAD_SPEED = 100
+PERMITTED_ATTEMPTS = 3
+NO_STALLED_REMOVAL_QBIT_TAG = Don't Kill
+IGNORE_PRIVATE_TRACKERS = True
+FAILED_IMPORT_MESSAGE_PATTERNS = ["Not a Custom Format upgrade", "Not an upgrade"]
+
+[sonarr]
+SONARR_URL = http://sonarr:8989
+SONARR_KEY = sonarrkey
+
+[qbittorrent]
+QBITTORRENT_URL = http://qbittorrent:8080
+QBITTORRENT_USERNAME = admin
+QBITTORRENT_PASSWORD = "secret"
+`
+
+func TestImportLegacy(t *testing.T) {
+	var out bytes.Buffer
+	unmapped, err := ImportLegacy(strings.NewReader(legacyConfig), &out)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"RUN_PERIODIC_RESCANS"}, unmapped)
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(&out))
+
+	var cfg Config
+	require.NoError(t, v.Unmarshal(&cfg))
+
+	assert.Equal(t, "debug", cfg.General.LogLevel)
+	assert.True(t, cfg.General.TestRun)
+	assert.Equal(t, "10m0s", v.GetString("general.timer"))
+	assert.True(t, cfg.Jobs.RemoveFailedDownloads.Enabled)
+	assert.True(t, cfg.Jobs.RemoveFailedImports.Enabled)
+	assert.True(t, cfg.Jobs.RemoveStalled.Enabled)
+	assert.False(t, cfg.Jobs.RemoveSlow.Enabled)
+	assert.False(t, v.IsSet("jobs.remove_orphans"), "settings not in the legacy config are left out")
+	assert.Equal(t, 100.0, cfg.JobDefaults.MinDownloadSpeed)
+	assert.Equal(t, 3, cfg.JobDefaults.MaxStrikes)
+	assert.Equal(t, "Don't Kill", cfg.General.ProtectedTag)
+	assert.Equal(t, "skip", cfg.General.PrivateTrackerHandling)
+	assert.Equal(t, []string{"Not a Custom Format upgrade", "Not an upgrade"}, cfg.Jobs.RemoveFailedImports.MessagePatterns)
+
+	require.Len(t, cfg.Instances.Sonarr, 1)
+	assert.Equal(t, InstanceConfig{Name: "sonarr", URL: "http://sonarr:8989", APIKey: "sonarrkey", Enabled: true}, cfg.Instances.Sonarr[0])
+
+	require.Len(t, cfg.DownloadClients.Qbittorrent, 1)
+	qbit := cfg.DownloadClients.Qbittorrent[0]
+	assert.Equal(t, "http://qbittorrent:8080", qbit.URL)
+	assert.Equal(t, "admin", qbit.Username)
+	assert.Equal(t, "secret", qbit.Password)
+}
+
+func TestImportLegacyEnvFile(t *testing.T) {
+	env := "# decluttarr .env\nexport RADARR_URL=http://radarr:7878\nRADARR_KEY='radarrkey'\nREMOVE_ORPHANS=true\n"
+
+	var out bytes.Buffer
+	unmapped, err := ImportLegacy(strings.NewReader(env), &out)
+	require.NoError(t, err)
+	assert.Empty(t, unmapped)
+	assert.Contains(t, out.String(), "api_key: radarrkey")
+	assert.Contains(t, out.String(), "url: http://radarr:7878")
+	assert.Contains(t, out.String(), "remove_orphans:")
+}
+
+func TestImportLegacyInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		errContains string
+	}{
+		{name: "not key=value", input: "REMOVE_STALLED\n", errContains: "line 1: expected KEY=value"},
+		{name: "bad boolean", input: "REMOVE_STALLED = maybe\n", errContains: "REMOVE_STALLED: expected a boolean"},
+		{name: "bad list", input: "FAILED_IMPORT_MESSAGE_PATTERNS = Not a list\n", errContains: "FAILED_IMPORT_MESSAGE_PATTERNS: expected a JSON list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportLegacy(strings.NewReader(tt.input), &bytes.Buffer{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
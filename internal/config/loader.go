@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -28,7 +30,10 @@ func Load(configPath string) (*Config, error) {
 	}
 	if configPath == "" {
 		// Try default locations
-		defaultPaths := []string{"config.yaml", "config.yml", "/app/config.yaml"}
+		defaultPaths := []string{
+			"config.yaml", "config.yml", "config.json", "config.toml",
+			"/app/config.yaml", "/app/config.json", "/app/config.toml",
+		}
 		for _, p := range defaultPaths {
 			if _, err := os.Stat(p); err == nil {
 				configPath = p
@@ -37,23 +42,10 @@ func Load(configPath string) (*Config, error) {
 		}
 	}
 
-	// Read config file if found, expanding environment variables
+	// Read config file (or directory of fragments) if found
 	if configPath != "" {
-		// Read file content
-		content, err := os.ReadFile(configPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
-		}
-
-		// Expand environment variables in the config content
-		expandedContent := os.ExpandEnv(string(content))
-
-		// Set config type based on file extension
-		v.SetConfigType("yaml")
-
-		// Read expanded content
-		if err := v.ReadConfig(strings.NewReader(expandedContent)); err != nil {
-			return nil, fmt.Errorf("failed to parse config file %s: %w", configPath, err)
+		if err := readConfigFiles(v, configPath); err != nil {
+			return nil, err
 		}
 	}
 	// If no file found, continue with defaults and env vars
@@ -72,11 +64,145 @@ func Load(configPath string) (*Config, error) {
 	return &cfg, nil
 }
 
+// listMergeKeys are the config keys whose lists are concatenated across
+// fragment files instead of being replaced
+var listMergeKeys = []string{
+	"instances.sonarr",
+	"instances.radarr",
+	"instances.lidarr",
+	"instances.readarr",
+	"instances.whisparr",
+	"download_clients.qbittorrent",
+	"download_clients.sabnzbd",
+	"download_clients.nzbget",
+}
+
+// readConfigFiles reads the config at configPath into v.
+//
+// If configPath is a directory, every supported config file in it is loaded in
+// lexical order. If configPath is a file that sets include_dir, the fragments
+// in that directory (relative to the config file) are loaded after it.
+// Later files override earlier ones, except instance and download client
+// lists, which are concatenated.
+func readConfigFiles(v *viper.Viper, configPath string) error {
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+
+	var files []string
+	if info.IsDir() {
+		files, err = fragmentFiles(configPath)
+		if err != nil {
+			return err
+		}
+	} else {
+		files = []string{configPath}
+	}
+
+	lists := make(map[string][]interface{})
+
+	for i := 0; i < len(files); i++ {
+		fv, err := parseConfigFile(files[i])
+		if err != nil {
+			return err
+		}
+
+		// The main config file may pull in a directory of fragments
+		if !info.IsDir() && i == 0 {
+			if includeDir := fv.GetString("include_dir"); includeDir != "" {
+				if !filepath.IsAbs(includeDir) {
+					includeDir = filepath.Join(filepath.Dir(configPath), includeDir)
+				}
+				fragments, err := fragmentFiles(includeDir)
+				if err != nil {
+					return err
+				}
+				files = append(files, fragments...)
+			}
+		}
+
+		for _, key := range listMergeKeys {
+			if items, ok := fv.Get(key).([]interface{}); ok {
+				lists[key] = append(lists[key], items...)
+			}
+		}
+
+		if err := v.MergeConfigMap(fv.AllSettings()); err != nil {
+			return fmt.Errorf("failed to merge config file %s: %w", files[i], err)
+		}
+	}
+
+	for key, items := range lists {
+		v.Set(key, items)
+	}
+
+	return nil
+}
+
+// fragmentFiles returns the config fragment files in dir, sorted by name
+func fragmentFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config directory %s: %w", dir, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if _, ok := configTypes[strings.ToLower(filepath.Ext(entry.Name()))]; ok {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// configTypes maps supported config file extensions to their viper config type
+var configTypes = map[string]string{
+	".yaml": "yaml",
+	".yml":  "yaml",
+	".json": "json",
+	".toml": "toml",
+}
+
+// configType returns the viper config type for a file, based on its extension.
+// Unknown extensions are treated as YAML.
+func configType(path string) string {
+	if t, ok := configTypes[strings.ToLower(filepath.Ext(path))]; ok {
+		return t
+	}
+	return "yaml"
+}
+
+// parseConfigFile reads a single config file, expanding environment variables
+func parseConfigFile(path string) (*viper.Viper, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	// Expand environment variables in the config content
+	expandedContent := os.ExpandEnv(string(content))
+
+	fv := viper.New()
+	fv.SetConfigType(configType(path))
+	if err := fv.ReadConfig(strings.NewReader(expandedContent)); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return fv, nil
+}
+
 // setDefaults sets default values for all configuration options
 func setDefaults(v *viper.Viper) {
 	// General defaults
 	v.SetDefault("general.log_level", "info")
 	v.SetDefault("general.test_run", false)
+	v.SetDefault("general.first_run_dry_run", true)
 	v.SetDefault("general.timer", 5*time.Minute)
 	v.SetDefault("general.ssl_verification", true)
 	v.SetDefault("general.request_timeout", 30*time.Second)
@@ -85,9 +211,26 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("general.ignore_download_clients", []string{})
 	v.SetDefault("general.obsolete_tag", "Obsolete")
 	v.SetDefault("general.protected_tag", "Keep")
+	v.SetDefault("general.protected_states", []string{"checkingDL", "checkingUP", "checkingResumeData", "moving", "allocating"})
+	v.SetDefault("general.stats_history_size", 50)
+	v.SetDefault("general.require_delete_optin", false)
+	v.SetDefault("general.trace_decisions", false)
+	v.SetDefault("general.property_workers", 4)
+	v.SetDefault("general.pre_remove_hook_timeout", 30*time.Second)
+
+	// HTTP server defaults
+	v.SetDefault("server.enabled", true)
+	v.SetDefault("server.address", "")
+	v.SetDefault("server.port", DefaultServerPort)
+
+	// Notification defaults
+	v.SetDefault("notifications.email.port", 587)
+	v.SetDefault("notifications.email.encryption", "starttls")
 
 	// Job defaults
 	v.SetDefault("job_defaults.max_strikes", 3)
+	v.SetDefault("job_defaults.max_strikes_private", 0) // 0 = max_strikes
+	v.SetDefault("job_defaults.max_strikes_public", 0)  // 0 = max_strikes
 	v.SetDefault("job_defaults.no_stalled", false)
 	v.SetDefault("job_defaults.no_slow", false)
 	v.SetDefault("job_defaults.no_active", false)
@@ -95,14 +238,18 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("job_defaults.permitted_attempts", 3)
 	v.SetDefault("job_defaults.min_download_speed", 100.0) // KB/s
 	v.SetDefault("job_defaults.min_time_left", 0*time.Second)
+	v.SetDefault("job_defaults.min_grab_age", 0*time.Second)
+	v.SetDefault("job_defaults.protect_newest", 0)
+	v.SetDefault("job_defaults.defer_last_active", false)
 	v.SetDefault("job_defaults.min_ratio", 0.0)
-	v.SetDefault("job_defaults.max_ratio", 0.0) // 0 = unlimited
-	v.SetDefault("job_defaults.max_seed_time", 0*time.Second) // 0 = unlimited
-	v.SetDefault("job_defaults.max_active_downloads", 0) // 0 = unlimited
+	v.SetDefault("job_defaults.max_ratio", 0.0)                          // 0 = unlimited
+	v.SetDefault("job_defaults.max_seed_time", 0*time.Second)            // 0 = unlimited
+	v.SetDefault("job_defaults.max_active_downloads", 0)                 // 0 = unlimited
 	v.SetDefault("job_defaults.free_space_threshold", 10*1024*1024*1024) // 10GB
 	v.SetDefault("job_defaults.apply_imported_action", true)
 	v.SetDefault("job_defaults.apply_not_imported", false)
 	v.SetDefault("job_defaults.apply_tags", false)
+	v.SetDefault("job_defaults.reason_tags", false)
 
 	// Individual jobs - all disabled by default
 	v.SetDefault("jobs.remove_stalled.enabled", false)
@@ -113,10 +260,14 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("jobs.remove_missing_files.enabled", false)
 	v.SetDefault("jobs.tag_orphans.enabled", false)
 	v.SetDefault("jobs.remove_metadata_failed.enabled", false)
+	v.SetDefault("jobs.remove_not_started.enabled", false)
 	v.SetDefault("jobs.enforce_seeding_limits.enabled", false)
 	v.SetDefault("jobs.manage_free_space.enabled", false)
 	v.SetDefault("jobs.remove_duplicate_downloads.enabled", false)
 	v.SetDefault("jobs.remove_done_seeding.enabled", false)
+	v.SetDefault("jobs.limit_active_downloads.enabled", false)
+	v.SetDefault("jobs.pause_no_space.enabled", false)
+	v.SetDefault("jobs.clean_obsolete_tags.enabled", false)
 
 	// Instances - empty by default
 	v.SetDefault("instances.sonarr", []InstanceConfig{})
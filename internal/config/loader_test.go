@@ -0,0 +1,246 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile writes content to name inside dir and returns the full path
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+const generalFragment = `
+general:
+  log_level: debug
+  timer: 10m
+instances:
+  sonarr:
+    - name: sonarr-main
+      url: http://sonarr:8989
+      api_key: key1
+      enabled: true
+`
+
+const instanceFragment = `
+general:
+  timer: 15m
+instances:
+  sonarr:
+    - name: sonarr-4k
+      url: http://sonarr-4k:8989
+      api_key: key2
+      enabled: true
+  radarr:
+    - name: radarr
+      url: http://radarr:7878
+      api_key: key3
+      enabled: true
+`
+
+func TestLoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "10-general.yaml", generalFragment)
+	writeFile(t, dir, "20-instances.yml", instanceFragment)
+	writeFile(t, dir, "README.md", "not config")
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+
+	// Scalars from earlier fragments are kept unless overridden
+	assert.Equal(t, "debug", cfg.General.LogLevel)
+	assert.Equal(t, 15*time.Minute, cfg.General.Timer)
+
+	// Instance lists are concatenated in file order
+	require.Len(t, cfg.Instances.Sonarr, 2)
+	assert.Equal(t, "sonarr-main", cfg.Instances.Sonarr[0].Name)
+	assert.Equal(t, "sonarr-4k", cfg.Instances.Sonarr[1].Name)
+	require.Len(t, cfg.Instances.Radarr, 1)
+	assert.Equal(t, "radarr", cfg.Instances.Radarr[0].Name)
+
+	// Defaults still apply
+	assert.Equal(t, 3, cfg.JobDefaults.MaxStrikes)
+	assert.True(t, cfg.Server.Enabled)
+	assert.Equal(t, ":9595", cfg.Server.ListenAddress())
+}
+
+func TestLoadIncludeDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "conf.d"), 0o755))
+	writeFile(t, filepath.Join(dir, "conf.d"), "instances.yaml", instanceFragment)
+	path := writeFile(t, dir, "config.yaml", "include_dir: conf.d\n"+generalFragment)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "debug", cfg.General.LogLevel)
+	assert.Equal(t, 15*time.Minute, cfg.General.Timer)
+	require.Len(t, cfg.Instances.Sonarr, 2)
+	assert.Equal(t, "sonarr-main", cfg.Instances.Sonarr[0].Name)
+	assert.Equal(t, "sonarr-4k", cfg.Instances.Sonarr[1].Name)
+	require.Len(t, cfg.Instances.Radarr, 1)
+}
+
+func TestLoadIncludeDirMissing(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.yaml", "include_dir: missing\n"+generalFragment)
+
+	_, err := Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read config directory")
+}
+
+const baselineYAML = `
+general:
+  log_level: warn
+  timer: 10m
+  private_tracker_handling: keep
+  ignore_download_clients:
+    - sabnzbd
+job_defaults:
+  max_strikes: 5
+  min_download_speed: 250.5
+jobs:
+  remove_stalled:
+    enabled: true
+    max_strikes: 2
+  remove_orphans:
+    enabled: true
+    ignore_categories:
+      - manual
+instances:
+  sonarr:
+    - name: sonarr
+      url: http://sonarr:8989
+      api_key: key1
+      enabled: true
+download_clients:
+  qbittorrent:
+    - name: qbittorrent
+      url: http://qbittorrent:8080
+      username: admin
+      password: secret
+      enabled: true
+`
+
+const baselineJSON = `{
+  "general": {
+    "log_level": "warn",
+    "timer": "10m",
+    "private_tracker_handling": "keep",
+    "ignore_download_clients": ["sabnzbd"]
+  },
+  "job_defaults": {
+    "max_strikes": 5,
+    "min_download_speed": 250.5
+  },
+  "jobs": {
+    "remove_stalled": {"enabled": true, "max_strikes": 2},
+    "remove_orphans": {"enabled": true, "ignore_categories": ["manual"]}
+  },
+  "instances": {
+    "sonarr": [
+      {"name": "sonarr", "url": "http://sonarr:8989", "api_key": "key1", "enabled": true}
+    ]
+  },
+  "download_clients": {
+    "qbittorrent": [
+      {"name": "qbittorrent", "url": "http://qbittorrent:8080", "username": "admin", "password": "secret", "enabled": true}
+    ]
+  }
+}`
+
+const baselineTOML = `
+[general]
+log_level = "warn"
+timer = "10m"
+private_tracker_handling = "keep"
+ignore_download_clients = ["sabnzbd"]
+
+[job_defaults]
+max_strikes = 5
+min_download_speed = 250.5
+
+[jobs.remove_stalled]
+enabled = true
+max_strikes = 2
+
+[jobs.remove_orphans]
+enabled = true
+ignore_categories = ["manual"]
+
+[[instances.sonarr]]
+name = "sonarr"
+url = "http://sonarr:8989"
+api_key = "key1"
+enabled = true
+
+[[download_clients.qbittorrent]]
+name = "qbittorrent"
+url = "http://qbittorrent:8080"
+username = "admin"
+password = "secret"
+enabled = true
+`
+
+func TestLoadFormats(t *testing.T) {
+	dir := t.TempDir()
+
+	baseline, err := Load(writeFile(t, dir, "config.yaml", baselineYAML))
+	require.NoError(t, err)
+	require.NotNil(t, baseline.Jobs.RemoveStalled.MaxStrikes)
+	assert.Equal(t, 2, *baseline.Jobs.RemoveStalled.MaxStrikes)
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "json", file: "config.json", content: baselineJSON},
+		{name: "toml", file: "config.toml", content: baselineTOML},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load(writeFile(t, dir, tt.file, tt.content))
+			require.NoError(t, err)
+			assert.Equal(t, baseline, cfg)
+		})
+	}
+}
+
+func TestLoadNotifyEvents(t *testing.T) {
+	cfg, err := Load(writeFile(t, t.TempDir(), "config.yaml", generalFragment+`
+notifications:
+  pushover:
+    near_removal: true
+  email:
+    approaching_strikes: 2
+`))
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Notifications.Pushover.NearRemoval)
+	assert.Equal(t, 2, cfg.Notifications.Email.ApproachingStrikes)
+}
+
+func TestLoadDirectoryMixedFormats(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "10-general.yaml", generalFragment)
+	writeFile(t, dir, "20-radarr.json", `{"instances": {"radarr": [{"name": "radarr", "url": "http://radarr:7878", "api_key": "key3"}]}}`)
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Instances.Sonarr, 1)
+	require.Len(t, cfg.Instances.Radarr, 1)
+	assert.Equal(t, "radarr", cfg.Instances.Radarr[0].Name)
+}
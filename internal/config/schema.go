@@ -0,0 +1,115 @@
+package config
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// schemaURI is the JSON Schema dialect used by Schema
+const schemaURI = "https://json-schema.org/draft/2020-12/schema"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Schema returns a JSON Schema describing the config file, derived from the
+// mapstructure tags on Config and the defaults applied by Load
+func Schema() map[string]interface{} {
+	v := viper.New()
+	setDefaults(v)
+
+	schema := typeSchema(reflect.TypeOf(Config{}), "", v)
+	schema["$schema"] = schemaURI
+	schema["title"] = "go-decluttarr configuration"
+
+	return schema
+}
+
+// typeSchema builds the schema for t; path is the dotted config key of t,
+// used to look up defaults
+func typeSchema(t reflect.Type, path string, defaults *viper.Viper) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		return map[string]interface{}{
+			"type":        "string",
+			"description": "Duration, e.g. 30s, 5m, 1h",
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			key := field.Tag.Get("mapstructure")
+			if key == "" || key == "-" {
+				continue
+			}
+
+			// A squashed struct's keys belong to the enclosing struct
+			if key == ",squash" {
+				embedded := typeSchema(field.Type, path, defaults)
+				for k, prop := range embedded["properties"].(map[string]interface{}) {
+					properties[k] = prop
+				}
+				continue
+			}
+
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+
+			prop := typeSchema(field.Type, fieldPath, defaults)
+			if def, ok := schemaDefault(field.Type, fieldPath, defaults); ok {
+				prop["default"] = def
+			}
+			properties[key] = prop
+		}
+		return map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+		}
+	case reflect.Slice:
+		// Items of a list have no config key of their own
+		return map[string]interface{}{
+			"type":  "array",
+			"items": typeSchema(t.Elem(), "", defaults),
+		}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
+
+// schemaDefault returns the default for a config key, if Load sets one.
+// Defaults for objects and lists of objects are omitted.
+func schemaDefault(t reflect.Type, path string, defaults *viper.Viper) (interface{}, bool) {
+	if path == "" || !defaults.IsSet(path) {
+		return nil, false
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && t != durationType {
+		return nil, false
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct {
+		return nil, false
+	}
+
+	def := defaults.Get(path)
+	if d, ok := def.(time.Duration); ok {
+		return d.String(), true
+	}
+	return def, true
+}
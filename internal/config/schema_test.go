@@ -0,0 +1,59 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaProperty walks a dotted key through the schema's properties
+func schemaProperty(t *testing.T, schema map[string]interface{}, key string) map[string]interface{} {
+	t.Helper()
+
+	node := schema
+	for _, part := range strings.Split(key, ".") {
+		props, ok := node["properties"].(map[string]interface{})
+		require.True(t, ok, "no properties at %s", part)
+		node, ok = props[part].(map[string]interface{})
+		require.True(t, ok, "missing property %s in %s", part, key)
+	}
+	return node
+}
+
+func TestSchema(t *testing.T) {
+	schema := Schema()
+
+	assert.Equal(t, schemaURI, schema["$schema"])
+	assert.Equal(t, "object", schema["type"])
+
+	timer := schemaProperty(t, schema, "general.timer")
+	assert.Equal(t, "string", timer["type"])
+	assert.Equal(t, "5m0s", timer["default"])
+
+	maxStrikes := schemaProperty(t, schema, "jobs.remove_stalled.max_strikes")
+	assert.Equal(t, "integer", maxStrikes["type"])
+
+	defaultStrikes := schemaProperty(t, schema, "job_defaults.max_strikes")
+	assert.Equal(t, 3, defaultStrikes["default"])
+
+	sonarr := schemaProperty(t, schema, "instances.sonarr")
+	assert.Equal(t, "array", sonarr["type"])
+	assert.NotContains(t, sonarr, "default")
+	items, ok := sonarr["items"].(map[string]interface{})
+	require.True(t, ok)
+	props, ok := items["properties"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Contains(t, props, "api_key")
+
+	// Squashed structs' keys are flattened into their parent
+	nearRemoval := schemaProperty(t, schema, "notifications.pushover.near_removal")
+	assert.Equal(t, "boolean", nearRemoval["type"])
+	assert.NotContains(t, schemaProperty(t, schema, "notifications.email")["properties"], ",squash")
+
+	// Must serialize cleanly
+	_, err := json.Marshal(schema)
+	require.NoError(t, err)
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/jmylchreest/go-decluttarr/internal/match"
 )
 
 // Validate checks the configuration for errors and inconsistencies
@@ -18,6 +20,11 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("job defaults: %w", err)
 	}
 
+	// Validate per-job settings
+	if err := c.validateJobs(); err != nil {
+		return fmt.Errorf("jobs: %w", err)
+	}
+
 	// Validate instances
 	if err := c.validateInstances(); err != nil {
 		return fmt.Errorf("instances: %w", err)
@@ -28,6 +35,16 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("download clients: %w", err)
 	}
 
+	// Validate HTTP server
+	if err := c.validateServer(); err != nil {
+		return fmt.Errorf("server: %w", err)
+	}
+
+	// Validate notifications
+	if err := c.validateNotifications(); err != nil {
+		return fmt.Errorf("notifications: %w", err)
+	}
+
 	// Ensure at least one instance is configured
 	hasInstance := len(c.Instances.Sonarr) > 0 ||
 		len(c.Instances.Radarr) > 0 ||
@@ -64,11 +81,41 @@ func (c *Config) validateGeneral() error {
 	}
 
 	// Validate request timeout
-	if c.General.RequestTimeout < 1*time.Second {
-		return fmt.Errorf("request_timeout must be at least 1 second")
+	if err := validateRequestTimeout(c.General.RequestTimeout); err != nil {
+		return err
 	}
-	if c.General.RequestTimeout > 5*time.Minute {
-		return fmt.Errorf("request_timeout must not exceed 5 minutes")
+
+	// Validate stats history size
+	if c.General.StatsHistorySize < 0 {
+		return fmt.Errorf("stats_history_size cannot be negative")
+	}
+
+	if c.General.PropertyWorkers < 0 {
+		return fmt.Errorf("property_workers cannot be negative")
+	}
+
+	if c.General.DebugLogSampling < 0 {
+		return fmt.Errorf("debug_log_sampling cannot be negative")
+	}
+
+	if c.General.ForceDeleteAfter < 0 {
+		return fmt.Errorf("force_delete_after cannot be negative")
+	}
+
+	if c.General.BlocklistRegrabsAfter < 0 {
+		return fmt.Errorf("blocklist_regrabs_after cannot be negative")
+	}
+
+	if c.General.ProcessedTagTTL < 0 {
+		return fmt.Errorf("processed_tag_ttl cannot be negative")
+	}
+
+	if c.General.PreRemoveHookTimeout < 0 {
+		return fmt.Errorf("pre_remove_hook_timeout cannot be negative")
+	}
+
+	if err := c.General.ActiveHours.validate(); err != nil {
+		return fmt.Errorf("active_hours: %w", err)
 	}
 
 	// Validate tracker handling
@@ -83,12 +130,71 @@ func (c *Config) validateGeneral() error {
 	return nil
 }
 
+func (c *Config) validateServer() error {
+	if !c.Server.Enabled {
+		return nil
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+
+	return nil
+}
+
+func (c *Config) validateNotifications() error {
+	if p := c.Notifications.Pushover; p.Enabled {
+		if p.Token == "" || p.User == "" {
+			return fmt.Errorf("pushover: token and user are required")
+		}
+		if p.Priority < -2 || p.Priority > 2 {
+			return fmt.Errorf("pushover: priority must be between -2 and 2")
+		}
+		if p.Priority == 2 {
+			if p.Retry < 30*time.Second {
+				return fmt.Errorf("pushover: priority 2 requires retry of at least 30s")
+			}
+			if p.Expire <= 0 || p.Expire > 3*time.Hour {
+				return fmt.Errorf("pushover: priority 2 requires expire of at most 3h")
+			}
+		}
+		if p.ApproachingStrikes < 0 {
+			return fmt.Errorf("pushover: approaching_strikes cannot be negative")
+		}
+	}
+
+	if e := c.Notifications.Email; e.Enabled {
+		if e.Host == "" {
+			return fmt.Errorf("email: host is required")
+		}
+		if e.Port < 1 || e.Port > 65535 {
+			return fmt.Errorf("email: port must be between 1 and 65535")
+		}
+		if e.From == "" || len(e.To) == 0 {
+			return fmt.Errorf("email: from and to are required")
+		}
+		validEncryption := []string{"starttls", "tls", "none"}
+		if !isValidChoice(e.Encryption, validEncryption) {
+			return fmt.Errorf("email: encryption must be one of: %s", strings.Join(validEncryption, ", "))
+		}
+		if e.ApproachingStrikes < 0 {
+			return fmt.Errorf("email: approaching_strikes cannot be negative")
+		}
+	}
+
+	return nil
+}
+
 func (c *Config) validateJobDefaults() error {
 	// Validate max strikes
 	if c.JobDefaults.MaxStrikes < 1 {
 		return fmt.Errorf("max_strikes must be at least 1")
 	}
 
+	if c.JobDefaults.MaxStrikesPrivate < 0 || c.JobDefaults.MaxStrikesPublic < 0 {
+		return fmt.Errorf("max_strikes_private and max_strikes_public cannot be negative")
+	}
+
 	// Validate permitted attempts
 	if c.JobDefaults.PermittedAttempts < 0 {
 		return fmt.Errorf("permitted_attempts cannot be negative")
@@ -115,6 +221,14 @@ func (c *Config) validateJobDefaults() error {
 		return fmt.Errorf("max_seed_time cannot be negative")
 	}
 
+	if c.JobDefaults.MinGrabAge < 0 {
+		return fmt.Errorf("min_grab_age cannot be negative")
+	}
+
+	if c.JobDefaults.ProtectNewest < 0 {
+		return fmt.Errorf("protect_newest cannot be negative")
+	}
+
 	// Validate max active downloads
 	if c.JobDefaults.MaxActiveDownloads < 0 {
 		return fmt.Errorf("max_active_downloads cannot be negative")
@@ -128,6 +242,104 @@ func (c *Config) validateJobDefaults() error {
 	return nil
 }
 
+func (c *Config) validateJobs() error {
+	known := c.instanceNames()
+	intervals := map[string]time.Duration{
+		"remove_done_seeding": c.Jobs.RemoveDoneSeeding.Interval,
+		"search_missing":      c.Jobs.SearchMissing.Interval,
+		"search_unmet_cutoff": c.Jobs.SearchUnmetCutoff.Interval,
+	}
+	for name, job := range map[string]JobConfig{
+		"remove_stalled":          c.Jobs.RemoveStalled,
+		"remove_slow":             c.Jobs.RemoveSlow,
+		"remove_failed_imports":   c.Jobs.RemoveFailedImports,
+		"remove_failed_downloads": c.Jobs.RemoveFailedDownloads,
+		"remove_unmonitored":      c.Jobs.RemoveUnmonitored,
+		"remove_orphans":          c.Jobs.RemoveOrphans,
+		"remove_missing_files":    c.Jobs.RemoveMissingFiles,
+		"remove_bad_files":        c.Jobs.RemoveBadFiles,
+		"remove_metadata_failed":  c.Jobs.RemoveMetadataFailed,
+		"remove_not_started":      c.Jobs.RemoveNotStarted,
+		"limit_active_downloads":  c.Jobs.LimitActiveDownloads,
+		"pause_no_space":          c.Jobs.PauseNoSpace,
+		"clean_obsolete_tags":     c.Jobs.CleanObsoleteTags,
+	} {
+		if job.Interval != nil {
+			intervals[name] = *job.Interval
+		}
+		for _, protocol := range job.Protocols {
+			if !isValidChoice(protocol, validProtocols) {
+				return fmt.Errorf("%s: protocols must be one of: %s", name, strings.Join(validProtocols, ", "))
+			}
+		}
+		if job.StalledGrace != nil && *job.StalledGrace < 0 {
+			return fmt.Errorf("%s: stalled_grace cannot be negative", name)
+		}
+		if (job.MaxStrikesPrivate != nil && *job.MaxStrikesPrivate < 0) || (job.MaxStrikesPublic != nil && *job.MaxStrikesPublic < 0) {
+			return fmt.Errorf("%s: max_strikes_private and max_strikes_public cannot be negative", name)
+		}
+		if job.MinGrabAge != nil && *job.MinGrabAge < 0 {
+			return fmt.Errorf("%s: min_grab_age cannot be negative", name)
+		}
+		if job.ProtectNewest != nil && *job.ProtectNewest < 0 {
+			return fmt.Errorf("%s: protect_newest cannot be negative", name)
+		}
+		if job.SlowMinProgressExempt != nil && (*job.SlowMinProgressExempt < 0 || *job.SlowMinProgressExempt > 1) {
+			return fmt.Errorf("%s: slow_min_progress_exempt must be between 0 and 1", name)
+		}
+		if job.ImportPendingAfter != nil && *job.ImportPendingAfter < 0 {
+			return fmt.Errorf("%s: import_pending_after cannot be negative", name)
+		}
+		if job.MaxGrabToDownload != nil && *job.MaxGrabToDownload < 0 {
+			return fmt.Errorf("%s: max_grab_to_download cannot be negative", name)
+		}
+		if job.DisableIndexerAfter != nil && *job.DisableIndexerAfter < 0 {
+			return fmt.Errorf("%s: disable_indexer_after cannot be negative", name)
+		}
+		for _, instance := range job.Instances {
+			if !known[strings.ToLower(instance)] {
+				return fmt.Errorf("%s: instances: '%s' is not a configured instance or download client", name, instance)
+			}
+		}
+		if err := match.Validate(job.MessagePatterns); err != nil {
+			return fmt.Errorf("%s: message_patterns: %w", name, err)
+		}
+		if err := match.Validate(job.BlockedMessagePatterns); err != nil {
+			return fmt.Errorf("%s: blocked_message_patterns: %w", name, err)
+		}
+	}
+
+	for name, instances := range map[string][]string{
+		"remove_done_seeding": c.Jobs.RemoveDoneSeeding.Instances,
+		"search_missing":      c.Jobs.SearchMissing.Instances,
+		"search_unmet_cutoff": c.Jobs.SearchUnmetCutoff.Instances,
+	} {
+		for _, instance := range instances {
+			if !known[strings.ToLower(instance)] {
+				return fmt.Errorf("%s: instances: '%s' is not a configured instance or download client", name, instance)
+			}
+		}
+	}
+
+	for key, goal := range c.Jobs.RemoveDoneSeeding.Goals {
+		if goal.Ratio < 0 || goal.SeedTime < 0 {
+			return fmt.Errorf("remove_done_seeding: goals.%s: ratio and seed_time cannot be negative", key)
+		}
+	}
+
+	if c.Jobs.SearchMissing.EpisodesPerSearch < 0 {
+		return fmt.Errorf("search_missing: episodes_per_search cannot be negative")
+	}
+
+	for name, interval := range intervals {
+		if interval < 0 {
+			return fmt.Errorf("%s: interval cannot be negative", name)
+		}
+	}
+
+	return nil
+}
+
 func (c *Config) validateInstances() error {
 	// Track instance names to ensure uniqueness
 	instanceNames := make(map[string]bool)
@@ -182,6 +394,21 @@ func validateInstance(instance InstanceConfig, instanceType string, instanceName
 		return fmt.Errorf("%s instance '%s': API key is required", instanceType, instance.Name)
 	}
 
+	// Validate request timeout override
+	if instance.RequestTimeout != 0 {
+		if err := validateRequestTimeout(instance.RequestTimeout); err != nil {
+			return fmt.Errorf("%s instance '%s': %w", instanceType, instance.Name, err)
+		}
+	}
+	if instance.RequestsPerSecond < 0 {
+		return fmt.Errorf("%s instance '%s': requests_per_second cannot be negative", instanceType, instance.Name)
+	}
+
+	// Validate API version override
+	if instance.APIVersion != "" && !isValidChoice(instance.APIVersion, validAPIVersions) {
+		return fmt.Errorf("%s instance '%s': api_version must be one of: %s", instanceType, instance.Name, strings.Join(validAPIVersions, ", "))
+	}
+
 	// Validate that enabled_jobs and disabled_jobs don't overlap
 	enabledMap := make(map[string]bool)
 	for _, job := range instance.EnabledJobs {
@@ -196,6 +423,30 @@ func validateInstance(instance InstanceConfig, instanceType string, instanceName
 	return nil
 }
 
+// instanceNames returns the lowercased names of every configured *arr
+// instance and download client, which a job's instances can refer to
+func (c *Config) instanceNames() map[string]bool {
+	names := make(map[string]bool)
+	for _, instances := range [][]InstanceConfig{
+		c.Instances.Sonarr, c.Instances.Radarr, c.Instances.Lidarr,
+		c.Instances.Readarr, c.Instances.Whisparr,
+	} {
+		for _, instance := range instances {
+			names[strings.ToLower(instance.Name)] = true
+		}
+	}
+	for _, client := range c.DownloadClients.Qbittorrent {
+		names[strings.ToLower(client.Name)] = true
+	}
+	for _, client := range c.DownloadClients.Sabnzbd {
+		names[strings.ToLower(client.Name)] = true
+	}
+	for _, client := range c.DownloadClients.Nzbget {
+		names[strings.ToLower(client.Name)] = true
+	}
+	return names
+}
+
 func (c *Config) validateDownloadClients() error {
 	// Track client names to ensure uniqueness
 	clientNames := make(map[string]bool)
@@ -240,6 +491,14 @@ func validateQbittorrent(client QbittorrentConfig, clientNames map[string]bool)
 		return fmt.Errorf("qbittorrent client '%s': URL must start with http:// or https://", client.Name)
 	}
 
+	if client.RequestTimeout != 0 {
+		if err := validateRequestTimeout(client.RequestTimeout); err != nil {
+			return fmt.Errorf("qbittorrent client '%s': %w", client.Name, err)
+		}
+	}
+	if client.RequestsPerSecond < 0 {
+		return fmt.Errorf("qbittorrent client '%s': requests_per_second cannot be negative", client.Name)
+	}
 	return nil
 }
 
@@ -263,6 +522,14 @@ func validateSabnzbd(client SabnzbdConfig, clientNames map[string]bool) error {
 		return fmt.Errorf("sabnzbd client '%s': API key is required", client.Name)
 	}
 
+	if client.RequestTimeout != 0 {
+		if err := validateRequestTimeout(client.RequestTimeout); err != nil {
+			return fmt.Errorf("sabnzbd client '%s': %w", client.Name, err)
+		}
+	}
+	if client.RequestsPerSecond < 0 {
+		return fmt.Errorf("sabnzbd client '%s': requests_per_second cannot be negative", client.Name)
+	}
 	return nil
 }
 
@@ -282,6 +549,39 @@ func validateNzbget(client NzbgetConfig, clientNames map[string]bool) error {
 		return fmt.Errorf("nzbget client '%s': URL must start with http:// or https://", client.Name)
 	}
 
+	if client.RequestTimeout != 0 {
+		if err := validateRequestTimeout(client.RequestTimeout); err != nil {
+			return fmt.Errorf("nzbget client '%s': %w", client.Name, err)
+		}
+	}
+	if client.RequestsPerSecond < 0 {
+		return fmt.Errorf("nzbget client '%s': requests_per_second cannot be negative", client.Name)
+	}
+	return nil
+}
+
+// validate checks that an active window has both ends and known days
+func (a ActiveHours) validate() error {
+	if !a.Enabled() {
+		if len(a.Days) > 0 {
+			return fmt.Errorf("days requires start and end")
+		}
+		return nil
+	}
+	if a.Start == "" || a.End == "" {
+		return fmt.Errorf("start and end must both be set")
+	}
+	if _, err := parseClock(a.Start); err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	if _, err := parseClock(a.End); err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	for _, day := range a.Days {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("days must be one of: mon, tue, wed, thu, fri, sat, sun")
+		}
+	}
 	return nil
 }
 
@@ -295,3 +595,20 @@ func isValidChoice(value string, choices []string) bool {
 	}
 	return false
 }
+
+// validProtocols are the queue item protocols a job can be scoped to
+var validProtocols = []string{"torrent", "usenet"}
+
+// validAPIVersions are the *arr API versions accepted for api_version
+var validAPIVersions = []string{"v1", "v3", "v4"}
+
+// validateRequestTimeout checks a request timeout is within sane bounds
+func validateRequestTimeout(timeout time.Duration) error {
+	if timeout < 1*time.Second {
+		return fmt.Errorf("request_timeout must be at least 1 second")
+	}
+	if timeout > 5*time.Minute {
+		return fmt.Errorf("request_timeout must not exceed 5 minutes")
+	}
+	return nil
+}
@@ -0,0 +1,444 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validConfig returns a minimal config that passes validation
+func validConfig() *Config {
+	return &Config{
+		General: GeneralConfig{
+			LogLevel:               "info",
+			Timer:                  5 * time.Minute,
+			RequestTimeout:         30 * time.Second,
+			PrivateTrackerHandling: "keep",
+			PublicTrackerHandling:  "remove",
+		},
+		JobDefaults: JobDefaultsConfig{MaxStrikes: 3},
+		Instances: InstancesConfig{
+			Sonarr: []InstanceConfig{{Name: "sonarr", URL: "http://sonarr:8989", APIKey: "key"}},
+		},
+	}
+}
+
+func TestValidateRequestTimeoutOverride(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*Config)
+		errContains string
+	}{
+		{
+			name:   "no overrides",
+			modify: func(c *Config) {},
+		},
+		{
+			name: "valid instance override",
+			modify: func(c *Config) {
+				c.Instances.Sonarr[0].RequestTimeout = 2 * time.Minute
+			},
+		},
+		{
+			name: "instance override too short",
+			modify: func(c *Config) {
+				c.Instances.Sonarr[0].RequestTimeout = 500 * time.Millisecond
+			},
+			errContains: "sonarr instance 'sonarr': request_timeout must be at least 1 second",
+		},
+		{
+			name: "download client override too long",
+			modify: func(c *Config) {
+				c.DownloadClients.Qbittorrent = []QbittorrentConfig{{
+					Name:           "qbit",
+					URL:            "http://qbit:8080",
+					RequestTimeout: 10 * time.Minute,
+				}}
+			},
+			errContains: "qbittorrent client 'qbit': request_timeout must not exceed 5 minutes",
+		},
+		{
+			name: "valid requests_per_second",
+			modify: func(c *Config) {
+				c.Instances.Sonarr[0].RequestsPerSecond = 2.5
+			},
+		},
+		{
+			name: "negative requests_per_second",
+			modify: func(c *Config) {
+				c.DownloadClients.Sabnzbd = []SabnzbdConfig{{
+					Name:              "sab",
+					URL:               "http://sab:8080",
+					APIKey:            "key",
+					RequestsPerSecond: -1,
+				}}
+			},
+			errContains: "sabnzbd client 'sab': requests_per_second cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if tt.errContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestTimeoutFor(t *testing.T) {
+	general := GeneralConfig{RequestTimeout: 30 * time.Second}
+
+	assert.Equal(t, 30*time.Second, general.TimeoutFor(0))
+	assert.Equal(t, 5*time.Second, general.TimeoutFor(5*time.Second))
+}
+
+func TestValidateNotifications(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*Config)
+		errContains string
+	}{
+		{
+			name: "valid pushover",
+			modify: func(c *Config) {
+				c.Notifications.Pushover = PushoverConfig{Enabled: true, Token: "t", User: "u", Priority: 1}
+			},
+		},
+		{
+			name: "pushover missing user",
+			modify: func(c *Config) {
+				c.Notifications.Pushover = PushoverConfig{Enabled: true, Token: "t"}
+			},
+			errContains: "token and user are required",
+		},
+		{
+			name: "pushover priority out of range",
+			modify: func(c *Config) {
+				c.Notifications.Pushover = PushoverConfig{Enabled: true, Token: "t", User: "u", Priority: 3}
+			},
+			errContains: "priority must be between -2 and 2",
+		},
+		{
+			name: "valid pushover emergency",
+			modify: func(c *Config) {
+				c.Notifications.Pushover = PushoverConfig{Enabled: true, Token: "t", User: "u", Priority: 2, Retry: time.Minute, Expire: time.Hour}
+			},
+		},
+		{
+			name: "pushover emergency without retry",
+			modify: func(c *Config) {
+				c.Notifications.Pushover = PushoverConfig{Enabled: true, Token: "t", User: "u", Priority: 2, Expire: time.Hour}
+			},
+			errContains: "priority 2 requires retry of at least 30s",
+		},
+		{
+			name: "pushover emergency expire too long",
+			modify: func(c *Config) {
+				c.Notifications.Pushover = PushoverConfig{Enabled: true, Token: "t", User: "u", Priority: 2, Retry: time.Minute, Expire: 4 * time.Hour}
+			},
+			errContains: "priority 2 requires expire of at most 3h",
+		},
+		{
+			name: "pushover negative approaching strikes",
+			modify: func(c *Config) {
+				c.Notifications.Pushover = PushoverConfig{Enabled: true, Token: "t", User: "u", NotifyEvents: NotifyEvents{ApproachingStrikes: -1}}
+			},
+			errContains: "approaching_strikes cannot be negative",
+		},
+		{
+			name: "valid email",
+			modify: func(c *Config) {
+				c.Notifications.Email = EmailConfig{
+					Enabled: true, Host: "smtp.example.com", Port: 587,
+					From: "a@example.com", To: []string{"b@example.com"}, Encryption: "starttls",
+				}
+			},
+		},
+		{
+			name: "email invalid encryption",
+			modify: func(c *Config) {
+				c.Notifications.Email = EmailConfig{
+					Enabled: true, Host: "smtp.example.com", Port: 587,
+					From: "a@example.com", To: []string{"b@example.com"}, Encryption: "ssl",
+				}
+			},
+			errContains: "encryption must be one of",
+		},
+		{
+			name: "email encryption is case-insensitive",
+			modify: func(c *Config) {
+				c.Notifications.Email = EmailConfig{
+					Enabled: true, Host: "smtp.example.com", Port: 465,
+					From: "a@example.com", To: []string{"b@example.com"}, Encryption: "TLS",
+				}
+			},
+		},
+		{
+			name: "email without recipients",
+			modify: func(c *Config) {
+				c.Notifications.Email = EmailConfig{
+					Enabled: true, Host: "smtp.example.com", Port: 587,
+					From: "a@example.com", Encryption: "tls",
+				}
+			},
+			errContains: "from and to are required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if tt.errContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestValidateAPIVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiVersion string
+		wantErr    bool
+	}{
+		{name: "unset", apiVersion: ""},
+		{name: "v3", apiVersion: "v3"},
+		{name: "v4", apiVersion: "v4"},
+		{name: "upper case", apiVersion: "V4"},
+		{name: "unknown version", apiVersion: "v9", wantErr: true},
+		{name: "missing prefix", apiVersion: "3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Instances.Sonarr[0].APIVersion = tt.apiVersion
+
+			err := cfg.Validate()
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "sonarr instance 'sonarr': api_version must be one of")
+		})
+	}
+}
+
+func TestValidateJobs(t *testing.T) {
+	negative := -time.Minute
+
+	tests := []struct {
+		name        string
+		modify      func(*Config)
+		errContains string
+	}{
+		{
+			name: "valid protocols",
+			modify: func(c *Config) {
+				c.Jobs.RemoveStalled.Protocols = []string{"torrent", "Usenet"}
+			},
+		},
+		{
+			name: "unknown protocol",
+			modify: func(c *Config) {
+				c.Jobs.RemoveStalled.Protocols = []string{"ftp"}
+			},
+			errContains: "remove_stalled: protocols must be one of: torrent, usenet",
+		},
+		{
+			name: "configured instances",
+			modify: func(c *Config) {
+				c.Jobs.RemoveStalled.Instances = []string{"Sonarr"}
+			},
+		},
+		{
+			name: "unknown instance",
+			modify: func(c *Config) {
+				c.Jobs.RemoveStalled.Instances = []string{"radarr-4k"}
+			},
+			errContains: "remove_stalled: instances: 'radarr-4k' is not a configured instance or download client",
+		},
+		{
+			name: "unknown search instance",
+			modify: func(c *Config) {
+				c.Jobs.SearchMissing.Instances = []string{"radarr-4k"}
+			},
+			errContains: "search_missing: instances: 'radarr-4k' is not a configured instance or download client",
+		},
+		{
+			name: "negative max_strikes_private",
+			modify: func(c *Config) {
+				strikes := -1
+				c.Jobs.RemoveStalled.MaxStrikesPrivate = &strikes
+			},
+			errContains: "remove_stalled: max_strikes_private and max_strikes_public cannot be negative",
+		},
+		{
+			name: "slow_min_progress_exempt above 1",
+			modify: func(c *Config) {
+				progress := 1.5
+				c.Jobs.RemoveSlow.SlowMinProgressExempt = &progress
+			},
+			errContains: "remove_slow: slow_min_progress_exempt must be between 0 and 1",
+		},
+		{
+			name: "negative interval",
+			modify: func(c *Config) {
+				c.Jobs.RemoveOrphans.Interval = &negative
+			},
+			errContains: "remove_orphans: interval cannot be negative",
+		},
+		{
+			name: "negative episodes per search",
+			modify: func(c *Config) {
+				c.Jobs.SearchMissing.EpisodesPerSearch = -1
+			},
+			errContains: "search_missing: episodes_per_search cannot be negative",
+		},
+		{
+			name: "negative stalled grace",
+			modify: func(c *Config) {
+				c.Jobs.RemoveStalled.StalledGrace = &negative
+			},
+			errContains: "remove_stalled: stalled_grace cannot be negative",
+		},
+		{
+			name: "negative import pending after",
+			modify: func(c *Config) {
+				c.Jobs.RemoveFailedImports.ImportPendingAfter = &negative
+			},
+			errContains: "remove_failed_imports: import_pending_after cannot be negative",
+		},
+		{
+			name: "negative disable indexer threshold",
+			modify: func(c *Config) {
+				threshold := -1
+				c.Jobs.RemoveFailedDownloads.DisableIndexerAfter = &threshold
+			},
+			errContains: "remove_failed_downloads: disable_indexer_after cannot be negative",
+		},
+		{
+			name: "valid regex pattern",
+			modify: func(c *Config) {
+				c.Jobs.RemoveFailedImports.MessagePatterns = []string{"*Sample*", "re:^not an upgrade"}
+			},
+		},
+		{
+			name: "invalid regex pattern",
+			modify: func(c *Config) {
+				c.Jobs.RemoveFailedImports.BlockedMessagePatterns = []string{"re:(unclosed"}
+			},
+			errContains: "remove_failed_imports: blocked_message_patterns: invalid pattern",
+		},
+		{
+			name: "valid seeding goals",
+			modify: func(c *Config) {
+				c.Jobs.RemoveDoneSeeding.Goals = map[string]SeedingGoal{
+					"public":  {Ratio: 1},
+					"private": {SeedTime: 14 * 24 * time.Hour},
+				}
+			},
+		},
+		{
+			name: "negative seeding goal",
+			modify: func(c *Config) {
+				c.Jobs.RemoveDoneSeeding.Goals = map[string]SeedingGoal{"public": {SeedTime: negative}}
+			},
+			errContains: "remove_done_seeding: goals.public: ratio and seed_time cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if tt.errContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestActiveHours(t *testing.T) {
+	// 2024-01-05 is a Friday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name  string
+		hours ActiveHours
+		t     time.Time
+		want  bool
+	}{
+		{name: "unset is always active", t: at(5, 12, 0), want: true},
+		{name: "inside window", hours: ActiveHours{Start: "01:00", End: "06:00"}, t: at(5, 3, 0), want: true},
+		{name: "end is exclusive", hours: ActiveHours{Start: "01:00", End: "06:00"}, t: at(5, 6, 0), want: false},
+		{name: "outside window", hours: ActiveHours{Start: "01:00", End: "06:00"}, t: at(5, 12, 0), want: false},
+		{name: "across midnight, evening", hours: ActiveHours{Start: "22:00", End: "06:00"}, t: at(5, 23, 30), want: true},
+		{name: "across midnight, morning", hours: ActiveHours{Start: "22:00", End: "06:00"}, t: at(6, 5, 59), want: true},
+		{name: "across midnight, daytime", hours: ActiveHours{Start: "22:00", End: "06:00"}, t: at(6, 12, 0), want: false},
+		{name: "wrong day", hours: ActiveHours{Start: "01:00", End: "06:00", Days: []string{"sat", "sun"}}, t: at(5, 3, 0), want: false},
+		{name: "listed day", hours: ActiveHours{Start: "01:00", End: "06:00", Days: []string{"Fri"}}, t: at(5, 3, 0), want: true},
+		// Saturday morning belongs to Friday night's window
+		{name: "across midnight from listed day", hours: ActiveHours{Start: "22:00", End: "06:00", Days: []string{"fri"}}, t: at(6, 3, 0), want: true},
+		{name: "across midnight into listed day", hours: ActiveHours{Start: "22:00", End: "06:00", Days: []string{"sat"}}, t: at(6, 3, 0), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.hours.Contains(tt.t))
+		})
+	}
+}
+
+func TestValidateActiveHours(t *testing.T) {
+	tests := []struct {
+		name        string
+		hours       ActiveHours
+		errContains string
+	}{
+		{name: "unset"},
+		{name: "valid", hours: ActiveHours{Start: "22:00", End: "06:00", Days: []string{"Mon", "fri"}}},
+		{name: "missing end", hours: ActiveHours{Start: "22:00"}, errContains: "active_hours: start and end must both be set"},
+		{name: "bad time", hours: ActiveHours{Start: "10pm", End: "06:00"}, errContains: `active_hours: start: invalid time "10pm"`},
+		{name: "bad day", hours: ActiveHours{Start: "22:00", End: "06:00", Days: []string{"funday"}}, errContains: "active_hours: days must be one of"},
+		{name: "days without window", hours: ActiveHours{Days: []string{"mon"}}, errContains: "active_hours: days requires start and end"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.General.ActiveHours = tt.hours
+			err := cfg.Validate()
+			if tt.errContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
@@ -2,27 +2,77 @@ package downloadclient
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
-// Client defines the interface for interacting with download clients
+// ErrUnsupported is returned by clients asked for something their kind of
+// download doesn't have, such as the torrent properties of a usenet download
+var ErrUnsupported = errors.New("not supported by this download client")
+
+// Client defines the interface for interacting with download clients. Hashes
+// identify a download in the client; usenet clients use their own IDs.
 type Client interface {
+	// Name returns the kind of client, e.g. "qBittorrent"
 	Name() string
+	// GetTorrents returns every download in the client
 	GetTorrents(ctx context.Context) ([]Torrent, error)
+	// GetTorrent returns one download, or an error if it isn't in the client
 	GetTorrent(ctx context.Context, hash string) (*Torrent, error)
+	// DeleteTorrent removes a download, and its files when deleteFiles is set
 	DeleteTorrent(ctx context.Context, hash string, deleteFiles bool) error
+	// PauseTorrent pauses a download
 	PauseTorrent(ctx context.Context, hash string) error
+	// ResumeTorrent resumes a paused download
 	ResumeTorrent(ctx context.Context, hash string) error
-	GetTorrentProperties(ctx context.Context, hash string) (*TorrentProperties, error)
-	AddTags(ctx context.Context, hash string, tags []string) error
+	// SetCategory moves a download to another category
+	SetCategory(ctx context.Context, hash string, category string) error
+	// IsPrivateTracker reports whether a download came from a private tracker
 	IsPrivateTracker(ctx context.Context, hash string) (bool, error)
 }
 
+// Optional capabilities are separate interfaces that jobs type-assert, so a
+// client only implements what it supports
+
+// TaggableClient is implemented by clients that can tag a torrent
+type TaggableClient interface {
+	AddTags(ctx context.Context, hash string, tags []string) error
+}
+
+// PropertiesClient is implemented by clients that report a torrent's
+// properties, such as its own seeding limits. Usenet clients return
+// ErrUnsupported, as their downloads have none.
+type PropertiesClient interface {
+	GetTorrentProperties(ctx context.Context, hash string) (*TorrentProperties, error)
+}
+
+// BulkTagger is implemented by clients that can tag many torrents in one request
+type BulkTagger interface {
+	AddTagsBulk(ctx context.Context, hashes []string, tags []string) error
+}
+
+// TagRemover is implemented by clients that can remove tags from a torrent
+type TagRemover interface {
+	RemoveTags(ctx context.Context, hash string, tags []string) error
+}
+
+// Pinger is implemented by clients that can verify connectivity and credentials
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// SeedingClient is implemented by clients whose downloads can seed after
+// completing, and so can have seeding goals. Usenet clients report false.
+type SeedingClient interface {
+	SeedingCapable() bool
+}
+
 // Torrent represents a torrent in a download client
 type Torrent struct {
 	Hash          string
 	Name          string
 	State         TorrentState
+	ClientState   string  // the client's own name for the state, e.g. qBittorrent's "checkingDL"
 	Progress      float64 // 0.0 to 1.0
 	Size          int64
 	Downloaded    int64
@@ -34,6 +84,7 @@ type Torrent struct {
 	AddedOn       time.Time
 	CompletedOn   *time.Time
 	SavePath      string
+	ContentPath   string // file or root folder of the torrent's content; empty if unknown
 	Category      string
 	Tags          []string
 	Trackers      []string
@@ -54,31 +105,31 @@ const (
 
 // TorrentProperties represents detailed properties of a torrent
 type TorrentProperties struct {
-	IsPrivate         bool    `json:"is_private"`
-	RatioLimit        float64 `json:"ratio_limit"`
-	SeedingTimeLimit  int64   `json:"seeding_time_limit"`
-	AdditionDate      int64   `json:"addition_date"`
-	CompletionDate    int64   `json:"completion_date"`
-	CreatedBy         string  `json:"created_by"`
-	CreationDate      int64   `json:"creation_date"`
-	Comment           string  `json:"comment"`
-	TotalSize         int64   `json:"total_size"`
-	PieceSize         int64   `json:"piece_size"`
-	PiecesHave        int     `json:"pieces_have"`
-	PiecesNum         int     `json:"pieces_num"`
-	Reannounce        int64   `json:"reannounce"`
-	SavePath          string  `json:"save_path"`
-	SeedingTime       int64   `json:"seeding_time"`
-	Seeds             int     `json:"seeds"`
-	SeedsTotal        int     `json:"seeds_total"`
-	ShareRatio        float64 `json:"share_ratio"`
-	TimeElapsed       int64   `json:"time_elapsed"`
-	TotalDownloaded   int64   `json:"total_downloaded"`
-	TotalUploaded     int64   `json:"total_uploaded"`
-	UploadLimit       int64   `json:"up_limit"`
-	DownloadLimit     int64   `json:"dl_limit"`
-	NbConnections     int     `json:"nb_connections"`
-	NbConnectionsLimit int    `json:"nb_connections_limit"`
+	IsPrivate          bool    `json:"is_private"`
+	RatioLimit         float64 `json:"ratio_limit"`
+	SeedingTimeLimit   int64   `json:"seeding_time_limit"`
+	AdditionDate       int64   `json:"addition_date"`
+	CompletionDate     int64   `json:"completion_date"`
+	CreatedBy          string  `json:"created_by"`
+	CreationDate       int64   `json:"creation_date"`
+	Comment            string  `json:"comment"`
+	TotalSize          int64   `json:"total_size"`
+	PieceSize          int64   `json:"piece_size"`
+	PiecesHave         int     `json:"pieces_have"`
+	PiecesNum          int     `json:"pieces_num"`
+	Reannounce         int64   `json:"reannounce"`
+	SavePath           string  `json:"save_path"`
+	SeedingTime        int64   `json:"seeding_time"`
+	Seeds              int     `json:"seeds"`
+	SeedsTotal         int     `json:"seeds_total"`
+	ShareRatio         float64 `json:"share_ratio"`
+	TimeElapsed        int64   `json:"time_elapsed"`
+	TotalDownloaded    int64   `json:"total_downloaded"`
+	TotalUploaded      int64   `json:"total_uploaded"`
+	UploadLimit        int64   `json:"up_limit"`
+	DownloadLimit      int64   `json:"dl_limit"`
+	NbConnections      int     `json:"nb_connections"`
+	NbConnectionsLimit int     `json:"nb_connections_limit"`
 }
 
 // TrackerInfo represents information about a torrent tracker
@@ -102,3 +153,10 @@ func HasTag(torrent *Torrent, tag string) bool {
 	}
 	return false
 }
+
+// UsesDownloadSlot reports whether a torrent is an active download, using one
+// of the client's download slots
+func UsesDownloadSlot(torrent Torrent) bool {
+	return torrent.Progress < 1 &&
+		(torrent.State == StateDownloading || torrent.State == StateStalled)
+}
@@ -24,11 +24,17 @@ type NZBGetClient struct {
 
 // NZBGetConfig holds configuration for NZBGet client
 type NZBGetConfig struct {
-	BaseURL  string
-	Username string
-	Password string
-	Timeout  time.Duration
-	Logger   *slog.Logger
+	BaseURL   string
+	Username  string
+	Password  string
+	Timeout   time.Duration
+	SkipTLS   bool
+	UserAgent string
+	Headers   map[string]string
+	Logger    *slog.Logger
+
+	// RequestsPerSecond paces requests to the client; 0 = unlimited
+	RequestsPerSecond float64
 }
 
 // NZBGetGroup represents a download group in NZBGet queue
@@ -79,7 +85,11 @@ func NewNZBGetClient(cfg NZBGetConfig) *NZBGetClient {
 		Timeout:         cfg.Timeout,
 		MaxIdleConns:    10,
 		IdleConnTimeout: 90 * time.Second,
-		SkipTLSVerify:   false,
+		SkipTLSVerify:   cfg.SkipTLS,
+		UserAgent:       cfg.UserAgent,
+		Headers:         cfg.Headers,
+
+		RequestsPerSecond: cfg.RequestsPerSecond,
 	}
 
 	return &NZBGetClient{
@@ -96,6 +106,17 @@ func (c *NZBGetClient) Name() string {
 	return "NZBGet"
 }
 
+// SeedingCapable reports that usenet downloads never seed
+func (c *NZBGetClient) SeedingCapable() bool {
+	return false
+}
+
+// GetTorrentProperties returns ErrUnsupported, as usenet downloads have no
+// torrent properties
+func (c *NZBGetClient) GetTorrentProperties(ctx context.Context, nzbID string) (*TorrentProperties, error) {
+	return nil, fmt.Errorf("nzbget properties for %s: %w", nzbID, ErrUnsupported)
+}
+
 // rpcCall performs a JSON-RPC call to NZBGet
 func (c *NZBGetClient) rpcCall(ctx context.Context, method string, params []any, result any) error {
 	// Build endpoint URL
@@ -167,6 +188,17 @@ func (c *NZBGetClient) rpcCall(ctx context.Context, method string, params []any,
 	return nil
 }
 
+// Ping requests the NZBGet version to verify connectivity and credentials
+func (c *NZBGetClient) Ping(ctx context.Context) error {
+	var version string
+	if err := c.rpcCall(ctx, "version", []any{}, &version); err != nil {
+		return fmt.Errorf("failed to get version: %w", err)
+	}
+
+	c.logger.Debug("Pinged NZBGet", "version", version)
+	return nil
+}
+
 // GetQueue retrieves the current download queue from NZBGet
 func (c *NZBGetClient) GetQueue(ctx context.Context) ([]NZBGetGroup, error) {
 	var groups []NZBGetGroup
@@ -197,10 +229,15 @@ func (c *NZBGetClient) GetHistory(ctx context.Context) ([]NZBGetHistoryItem, err
 	return history, nil
 }
 
-// DeleteItem deletes an item from the queue by NZB ID
-func (c *NZBGetClient) DeleteItem(ctx context.Context, nzbID int) error {
-	// editqueue with GroupFinalDelete action
-	params := []any{"GroupFinalDelete", 0, "", []int{nzbID}}
+// DeleteItem deletes an item from the queue by NZB ID. With deleteFiles it's
+// removed for good along with its files (GroupFinalDelete); otherwise it's
+// moved to the history and its downloaded files are kept (GroupDelete).
+func (c *NZBGetClient) DeleteItem(ctx context.Context, nzbID int, deleteFiles bool) error {
+	action := "GroupDelete"
+	if deleteFiles {
+		action = "GroupFinalDelete"
+	}
+	params := []any{action, 0, "", []int{nzbID}}
 
 	var success bool
 	err := c.rpcCall(ctx, "editqueue", params, &success)
@@ -214,6 +251,7 @@ func (c *NZBGetClient) DeleteItem(ctx context.Context, nzbID int) error {
 
 	c.logger.Debug("deleted nzbget item",
 		"nzbID", nzbID,
+		"delete_files", deleteFiles,
 	)
 
 	return nil
@@ -262,3 +300,60 @@ func (c *NZBGetClient) ResumeItem(ctx context.Context, nzbID int) error {
 
 	return nil
 }
+
+// PauseDownload pauses the whole download queue
+func (c *NZBGetClient) PauseDownload(ctx context.Context) error {
+	var success bool
+	if err := c.rpcCall(ctx, "pausedownload", []any{}, &success); err != nil {
+		return fmt.Errorf("failed to pause download queue: %w", err)
+	}
+
+	if !success {
+		return fmt.Errorf("pause operation failed for download queue")
+	}
+
+	c.logger.Debug("paused nzbget download queue")
+	return nil
+}
+
+// ResumeDownload resumes the whole download queue
+func (c *NZBGetClient) ResumeDownload(ctx context.Context) error {
+	var success bool
+	if err := c.rpcCall(ctx, "resumedownload", []any{}, &success); err != nil {
+		return fmt.Errorf("failed to resume download queue: %w", err)
+	}
+
+	if !success {
+		return fmt.Errorf("resume operation failed for download queue")
+	}
+
+	c.logger.Debug("resumed nzbget download queue")
+	return nil
+}
+
+// SetRate limits the download speed in KB/s; 0 removes the limit
+func (c *NZBGetClient) SetRate(ctx context.Context, kbps int) error {
+	if kbps < 0 {
+		return fmt.Errorf("download rate cannot be negative, got %d", kbps)
+	}
+
+	var success bool
+	if err := c.rpcCall(ctx, "rate", []any{kbps}, &success); err != nil {
+		return fmt.Errorf("failed to set download rate: %w", err)
+	}
+
+	if !success {
+		return fmt.Errorf("rate operation failed for %d KB/s", kbps)
+	}
+
+	c.logger.Debug("set nzbget download rate",
+		"kbps", kbps,
+	)
+
+	return nil
+}
+
+// Close closes the HTTP client
+func (c *NZBGetClient) Close() {
+	c.http.Close()
+}
@@ -318,7 +318,30 @@ func TestNZBGetEditQueue(t *testing.T) {
 				_ = json.NewEncoder(w).Encode(resp)
 			},
 			callFunc: func(client *NZBGetClient, ctx context.Context, nzbID int) error {
-				return client.DeleteItem(ctx, nzbID)
+				return client.DeleteItem(ctx, nzbID, true)
+			},
+			wantErr: false,
+		},
+		{
+			name:   "delete keeping files",
+			method: "GroupDelete",
+			nzbID:  12345,
+			serverResponse: func(w http.ResponseWriter, r *http.Request) {
+				var req rpcRequest
+				_ = json.NewDecoder(r.Body).Decode(&req)
+
+				assert.Equal(t, "editqueue", req.Method)
+				assert.Equal(t, "GroupDelete", req.Params[0], "the files are kept")
+
+				resp := rpcResponse{
+					Version: "1.1",
+					Result:  json.RawMessage(`true`),
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(resp)
+			},
+			callFunc: func(client *NZBGetClient, ctx context.Context, nzbID int) error {
+				return client.DeleteItem(ctx, nzbID, false)
 			},
 			wantErr: false,
 		},
@@ -335,7 +358,7 @@ func TestNZBGetEditQueue(t *testing.T) {
 				_ = json.NewEncoder(w).Encode(resp)
 			},
 			callFunc: func(client *NZBGetClient, ctx context.Context, nzbID int) error {
-				return client.DeleteItem(ctx, nzbID)
+				return client.DeleteItem(ctx, nzbID, true)
 			},
 			wantErr:     true,
 			errContains: "delete operation failed",
@@ -452,6 +475,77 @@ func TestNZBGetEditQueue(t *testing.T) {
 	}
 }
 
+func TestNZBGetQueueControls(t *testing.T) {
+	tests := []struct {
+		name       string
+		callFunc   func(client *NZBGetClient, ctx context.Context) error
+		wantMethod string
+		wantParams []any
+		result     string
+		wantErr    string
+	}{
+		{
+			name:       "pause download",
+			callFunc:   func(client *NZBGetClient, ctx context.Context) error { return client.PauseDownload(ctx) },
+			wantMethod: "pausedownload",
+			wantParams: []any{},
+			result:     `true`,
+		},
+		{
+			name:       "resume download",
+			callFunc:   func(client *NZBGetClient, ctx context.Context) error { return client.ResumeDownload(ctx) },
+			wantMethod: "resumedownload",
+			wantParams: []any{},
+			result:     `true`,
+		},
+		{
+			name:       "set rate",
+			callFunc:   func(client *NZBGetClient, ctx context.Context) error { return client.SetRate(ctx, 2048) },
+			wantMethod: "rate",
+			wantParams: []any{float64(2048)},
+			result:     `true`,
+		},
+		{
+			name:       "rate rejected",
+			callFunc:   func(client *NZBGetClient, ctx context.Context) error { return client.SetRate(ctx, 0) },
+			wantMethod: "rate",
+			wantParams: []any{float64(0)},
+			result:     `false`,
+			wantErr:    "rate operation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req rpcRequest
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, tt.wantMethod, req.Method)
+				assert.Equal(t, tt.wantParams, req.Params)
+
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(rpcResponse{Version: "1.1", Result: json.RawMessage(tt.result)})
+			}))
+			defer server.Close()
+
+			client := NewNZBGetClient(NZBGetConfig{BaseURL: server.URL})
+			err := tt.callFunc(client, context.Background())
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNZBGetSetRateNegative(t *testing.T) {
+	client := NewNZBGetClient(NZBGetConfig{BaseURL: "http://127.0.0.1:1"})
+	assert.Error(t, client.SetRate(context.Background(), -1))
+}
+
 func TestNZBGetRPCCall(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -635,3 +729,61 @@ func mustMarshal(v interface{}) []byte {
 	}
 	return data
 }
+
+func TestNZBGetSkipTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"1.1","result":[]}`))
+	}))
+	defer server.Close()
+
+	t.Run("self-signed cert rejected by default", func(t *testing.T) {
+		client := NewNZBGetClient(NZBGetConfig{BaseURL: server.URL})
+		_, err := client.GetQueue(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("self-signed cert accepted with skip tls", func(t *testing.T) {
+		client := NewNZBGetClient(NZBGetConfig{BaseURL: server.URL, SkipTLS: true})
+		_, err := client.GetQueue(context.Background())
+		assert.NoError(t, err)
+	})
+}
+
+func TestNZBGetPing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "nzbget" || password != "tegbzn6789" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var req rpcRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "version", req.Method)
+
+		_ = json.NewEncoder(w).Encode(rpcResponse{Version: "1.1", Result: json.RawMessage(`"21.1"`)})
+	}))
+	defer server.Close()
+
+	t.Run("valid credentials", func(t *testing.T) {
+		client := NewNZBGetClient(NZBGetConfig{BaseURL: server.URL, Username: "nzbget", Password: "tegbzn6789"})
+		assert.NoError(t, client.Ping(context.Background()))
+	})
+
+	t.Run("bad credentials", func(t *testing.T) {
+		client := NewNZBGetClient(NZBGetConfig{BaseURL: server.URL, Username: "nzbget", Password: "wrong"})
+		err := client.Ping(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP 401")
+	})
+}
+
+func TestNZBGetGetTorrentProperties(t *testing.T) {
+	client := NewNZBGetClient(NZBGetConfig{BaseURL: "http://localhost:6789"})
+
+	var _ PropertiesClient = client
+
+	props, err := client.GetTorrentProperties(context.Background(), "1")
+	assert.Nil(t, props)
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
@@ -0,0 +1,63 @@
+package downloadclient
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultPropertyWorkers is the number of torrents whose properties are
+// fetched at once when no worker count is configured
+const DefaultPropertyWorkers = 4
+
+// PropertiesResult is the outcome of fetching one torrent's properties
+type PropertiesResult struct {
+	Properties *TorrentProperties
+	Err        error
+}
+
+// FetchProperties fetches the properties of each torrent in hashes, with at
+// most workers requests to the client in flight. Results are keyed by hash.
+// Once ctx is cancelled no further requests are made, and the torrents not yet
+// fetched report the context's error.
+func FetchProperties(ctx context.Context, client PropertiesClient, hashes []string, workers int) map[string]PropertiesResult {
+	if workers <= 0 {
+		workers = DefaultPropertyWorkers
+	}
+
+	results := make(map[string]PropertiesResult, len(hashes))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	work := make(chan string)
+	for i := 0; i < min(workers, len(hashes)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for hash := range work {
+				props, err := client.GetTorrentProperties(ctx, hash)
+				mu.Lock()
+				results[hash] = PropertiesResult{Properties: props, Err: err}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i, hash := range hashes {
+		select {
+		case work <- hash:
+			continue
+		case <-ctx.Done():
+		}
+
+		mu.Lock()
+		for _, skipped := range hashes[i:] {
+			results[skipped] = PropertiesResult{Err: ctx.Err()}
+		}
+		mu.Unlock()
+		break
+	}
+	close(work)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,94 @@
+package downloadclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// propsClient serves torrent properties, tracking how many requests are in flight
+type propsClient struct {
+	Client // only GetTorrentProperties is used
+
+	delay       time.Duration
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+	calls       atomic.Int32
+}
+
+func (c *propsClient) GetTorrentProperties(ctx context.Context, hash string) (*TorrentProperties, error) {
+	c.calls.Add(1)
+	n := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	for {
+		peak := c.maxInFlight.Load()
+		if n <= peak || c.maxInFlight.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if hash == "broken" {
+		return nil, fmt.Errorf("torrent not found")
+	}
+	return &TorrentProperties{SavePath: "/downloads/" + hash}, nil
+}
+
+func TestFetchProperties(t *testing.T) {
+	client := &propsClient{delay: 5 * time.Millisecond}
+
+	var hashes []string
+	for i := 0; i < 50; i++ {
+		hashes = append(hashes, fmt.Sprintf("hash%d", i))
+	}
+	hashes = append(hashes, "broken")
+
+	results := FetchProperties(context.Background(), client, hashes, 3)
+
+	require.Len(t, results, len(hashes))
+	for _, hash := range hashes[:50] {
+		require.NoError(t, results[hash].Err)
+		assert.Equal(t, "/downloads/"+hash, results[hash].Properties.SavePath)
+	}
+	assert.Error(t, results["broken"].Err)
+
+	assert.Equal(t, int32(len(hashes)), client.calls.Load())
+	assert.LessOrEqual(t, client.maxInFlight.Load(), int32(3))
+	assert.Greater(t, client.maxInFlight.Load(), int32(1), "properties should be fetched concurrently")
+}
+
+func TestFetchPropertiesCancelled(t *testing.T) {
+	client := &propsClient{delay: time.Hour}
+
+	hashes := []string{"a", "b", "c", "d", "e"}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var results map[string]PropertiesResult
+	go func() {
+		defer wg.Done()
+		results = FetchProperties(ctx, client, hashes, 2)
+	}()
+
+	require.Eventually(t, func() bool { return client.inFlight.Load() == 2 }, 5*time.Second, time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	require.Len(t, results, len(hashes))
+	for _, hash := range hashes {
+		assert.ErrorIs(t, results[hash].Err, context.Canceled, hash)
+	}
+	assert.Equal(t, int32(2), client.calls.Load(), "no requests should be made after cancellation")
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jmylchreest/go-decluttarr/pkg/httpclient"
@@ -20,17 +21,35 @@ type QBittorrentClient struct {
 	password string
 	http     *httpclient.Client
 	logger   *slog.Logger
-	sid      string // session cookie
+	sidMu    sync.RWMutex
+	sid      string     // session cookie, guarded by sidMu
+	loginMu  sync.Mutex // serializes logins
+
+	trackerFallback bool
+	propertyWorkers int
 }
 
 // QBittorrentConfig holds configuration for creating a QBittorrentClient
 type QBittorrentConfig struct {
-	BaseURL  string
-	Username string
-	Password string
-	Timeout  time.Duration
-	SkipTLS  bool
-	Logger   *slog.Logger
+	BaseURL   string
+	Username  string
+	Password  string
+	Timeout   time.Duration
+	SkipTLS   bool
+	UserAgent string
+	Headers   map[string]string
+	Logger    *slog.Logger
+
+	// RequestsPerSecond paces requests to the client; 0 = unlimited
+	RequestsPerSecond float64
+
+	// TrackerFallback fetches the tracker list of torrents whose summary
+	// reports no tracker, to fill in Trackers and IsPrivate
+	TrackerFallback bool
+
+	// PropertyWorkers is the most per-torrent requests in flight, e.g. for
+	// the tracker fallback; 0 = DefaultPropertyWorkers
+	PropertyWorkers int
 }
 
 // qBitTorrentInfo represents the API response for torrent info
@@ -49,6 +68,7 @@ type qBitTorrentInfo struct {
 	AddedOn        int64   `json:"added_on"`
 	CompletionOn   int64   `json:"completion_on"`
 	SavePath       string  `json:"save_path"`
+	ContentPath    string  `json:"content_path"`
 	Category       string  `json:"category"`
 	Tags           string  `json:"tags"`
 	TrackerHost    string  `json:"tracker"`
@@ -76,6 +96,10 @@ func NewQBittorrentClient(cfg QBittorrentConfig) (*QBittorrentClient, error) {
 		MaxIdleConns:    10,
 		IdleConnTimeout: 90 * time.Second,
 		SkipTLSVerify:   cfg.SkipTLS,
+		UserAgent:       cfg.UserAgent,
+		Headers:         cfg.Headers,
+
+		RequestsPerSecond: cfg.RequestsPerSecond,
 	}
 
 	logger := cfg.Logger
@@ -89,11 +113,94 @@ func NewQBittorrentClient(cfg QBittorrentConfig) (*QBittorrentClient, error) {
 		password: cfg.Password,
 		http:     httpclient.New(httpCfg),
 		logger:   logger.With("service", "qbittorrent"),
+
+		trackerFallback: cfg.TrackerFallback,
+		propertyWorkers: cfg.PropertyWorkers,
+	}
+	if client.propertyWorkers <= 0 {
+		client.propertyWorkers = DefaultPropertyWorkers
 	}
 
 	return client, nil
 }
 
+// session returns the current session cookie, or "" if not logged in
+func (c *QBittorrentClient) session() string {
+	c.sidMu.RLock()
+	defer c.sidMu.RUnlock()
+	return c.sid
+}
+
+// setSession stores the session cookie from a login
+func (c *QBittorrentClient) setSession(sid string) {
+	c.sidMu.Lock()
+	defer c.sidMu.Unlock()
+	c.sid = sid
+}
+
+// ensureSession returns the session cookie, logging in first if there is no
+// session. Logins are serialized, so requests that find the session missing
+// at the same time share one login.
+func (c *QBittorrentClient) ensureSession(ctx context.Context) (string, error) {
+	if sid := c.session(); sid != "" {
+		return sid, nil
+	}
+
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	// Another request may have logged in while this one waited
+	if sid := c.session(); sid != "" {
+		return sid, nil
+	}
+	if err := c.Login(ctx); err != nil {
+		return "", err
+	}
+	return c.session(), nil
+}
+
+// expireSession forgets the session cookie sid after qBittorrent rejected it,
+// unless another request has already replaced it with a new session
+func (c *QBittorrentClient) expireSession(sid string) {
+	c.sidMu.Lock()
+	defer c.sidMu.Unlock()
+	if c.sid == sid {
+		c.sid = ""
+	}
+}
+
+// do sends the request built by newReq with the session cookie, logging in
+// first if needed. If the session has expired it logs in again and retries
+// once with a freshly built request.
+func (c *QBittorrentClient) do(ctx context.Context, newReq func() (*http.Request, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		sid, err := c.ensureSession(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("authentication required: %w", err)
+		}
+
+		req, err := newReq()
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("Cookie", fmt.Sprintf("SID=%s", sid))
+
+		resp, err := c.http.Do(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("execute request: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusForbidden || attempt > 0 {
+			return resp, nil
+		}
+
+		// Session expired, re-login
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		c.expireSession(sid)
+	}
+}
+
 // Login authenticates with the qBittorrent WebUI and retrieves session cookie
 func (c *QBittorrentClient) Login(ctx context.Context) error {
 	loginURL := c.baseURL + "/api/v2/auth/login"
@@ -134,7 +241,7 @@ func (c *QBittorrentClient) Login(ctx context.Context) error {
 	// Extract SID cookie
 	for _, cookie := range resp.Cookies() {
 		if cookie.Name == "SID" {
-			c.sid = cookie.Value
+			c.setSession(cookie.Value)
 			c.logger.DebugContext(ctx, "authenticated with qbittorrent")
 			return nil
 		}
@@ -143,40 +250,54 @@ func (c *QBittorrentClient) Login(ctx context.Context) error {
 	return fmt.Errorf("SID cookie not found in login response")
 }
 
+// Ping logs in and requests a single torrent to verify connectivity and credentials
+func (c *QBittorrentClient) Ping(ctx context.Context) error {
+	if err := c.Login(ctx); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v2/torrents/info?limit=1", nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Cookie", fmt.Sprintf("SID=%s", c.session()))
+
+	resp, err := c.http.Do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
 // Name returns the client name
 func (c *QBittorrentClient) Name() string {
 	return "qBittorrent"
 }
 
+// SeedingCapable reports that qBittorrent torrents seed after completing
+func (c *QBittorrentClient) SeedingCapable() bool {
+	return true
+}
+
 // GetTorrents retrieves all torrents from qBittorrent
 func (c *QBittorrentClient) GetTorrents(ctx context.Context) ([]Torrent, error) {
-	if c.sid == "" {
-		if err := c.Login(ctx); err != nil {
-			return nil, fmt.Errorf("authentication required: %w", err)
-		}
-	}
-
 	apiURL := c.baseURL + "/api/v2/torrents/info"
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Cookie", fmt.Sprintf("SID=%s", c.sid))
-
-	resp, err := c.http.Do(ctx, req)
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
+		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode == http.StatusForbidden {
-		// Session expired, re-login
-		c.sid = ""
-		return c.GetTorrents(ctx)
-	}
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
@@ -191,6 +312,7 @@ func (c *QBittorrentClient) GetTorrents(ctx context.Context) ([]Torrent, error)
 	for i, qt := range qbitTorrents {
 		torrents[i] = c.convertTorrent(&qt)
 	}
+	c.fillAllTrackers(ctx, torrents)
 
 	c.logger.DebugContext(ctx, "retrieved torrents", "count", len(torrents))
 	return torrents, nil
@@ -198,33 +320,19 @@ func (c *QBittorrentClient) GetTorrents(ctx context.Context) ([]Torrent, error)
 
 // GetTorrent retrieves a specific torrent by hash
 func (c *QBittorrentClient) GetTorrent(ctx context.Context, hash string) (*Torrent, error) {
-	if c.sid == "" {
-		if err := c.Login(ctx); err != nil {
-			return nil, fmt.Errorf("authentication required: %w", err)
-		}
-	}
-
+	// qBittorrent only matches lowercase hashes, while the *arrs report them
+	// in uppercase
+	hash = strings.ToLower(hash)
 	apiURL := c.baseURL + "/api/v2/torrents/info?hashes=" + hash
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Cookie", fmt.Sprintf("SID=%s", c.sid))
-
-	resp, err := c.http.Do(ctx, req)
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
+		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode == http.StatusForbidden {
-		// Session expired, re-login
-		c.sid = ""
-		return c.GetTorrent(ctx, hash)
-	}
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
@@ -240,17 +348,12 @@ func (c *QBittorrentClient) GetTorrent(ctx context.Context, hash string) (*Torre
 	}
 
 	torrent := c.convertTorrent(&qbitTorrents[0])
+	c.fillTrackers(ctx, &torrent)
 	return &torrent, nil
 }
 
 // DeleteTorrent removes a torrent from qBittorrent
 func (c *QBittorrentClient) DeleteTorrent(ctx context.Context, hash string, deleteFiles bool) error {
-	if c.sid == "" {
-		if err := c.Login(ctx); err != nil {
-			return fmt.Errorf("authentication required: %w", err)
-		}
-	}
-
 	apiURL := c.baseURL + "/api/v2/torrents/delete"
 
 	data := url.Values{}
@@ -261,110 +364,182 @@ func (c *QBittorrentClient) DeleteTorrent(ctx context.Context, hash string, dele
 		data.Set("deleteFiles", "false")
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return err
 	}
+	defer func() { _ = resp.Body.Close() }()
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Cookie", fmt.Sprintf("SID=%s", c.sid))
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
 
-	resp, err := c.http.Do(ctx, req)
+	c.logger.DebugContext(ctx, "deleted torrent from qbittorrent", "hash", hash, "delete_files", deleteFiles)
+	return nil
+}
+
+// PauseTorrent pauses a torrent in qBittorrent
+func (c *QBittorrentClient) PauseTorrent(ctx context.Context, hash string) error {
+	apiURL := c.baseURL + "/api/v2/torrents/pause"
+
+	data := url.Values{}
+	data.Set("hashes", hash)
+
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
 	if err != nil {
-		return fmt.Errorf("execute request: %w", err)
+		return err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode == http.StatusForbidden {
-		// Session expired, re-login
-		c.sid = ""
-		return c.DeleteTorrent(ctx, hash, deleteFiles)
-	}
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
-	c.logger.DebugContext(ctx, "deleted torrent from qbittorrent", "hash", hash, "delete_files", deleteFiles)
+	c.logger.DebugContext(ctx, "paused torrent", "hash", hash)
 	return nil
 }
 
-// PauseTorrent pauses a torrent in qBittorrent
-func (c *QBittorrentClient) PauseTorrent(ctx context.Context, hash string) error {
-	if c.sid == "" {
-		if err := c.Login(ctx); err != nil {
-			return fmt.Errorf("authentication required: %w", err)
+// SetCategory moves a torrent to a category in qBittorrent, creating the
+// category first if it doesn't exist, as qBittorrent rejects unknown ones
+func (c *QBittorrentClient) SetCategory(ctx context.Context, hash string, category string) error {
+	if category != "" {
+		categories, err := c.categories(ctx)
+		if err != nil {
+			return err
+		}
+		if _, ok := categories[category]; !ok {
+			if err := c.CreateCategory(ctx, category, ""); err != nil {
+				return err
+			}
 		}
 	}
 
-	apiURL := c.baseURL + "/api/v2/torrents/pause"
+	apiURL := c.baseURL + "/api/v2/torrents/setCategory"
 
 	data := url.Values{}
 	data.Set("hashes", hash)
+	data.Set("category", category)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return err
 	}
+	defer func() { _ = resp.Body.Close() }()
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Cookie", fmt.Sprintf("SID=%s", c.sid))
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
 
-	resp, err := c.http.Do(ctx, req)
+	c.logger.DebugContext(ctx, "set torrent category", "hash", hash, "category", category)
+	return nil
+}
+
+// qBitCategory is a category as returned by qBittorrent's categories endpoint
+type qBitCategory struct {
+	Name     string `json:"name"`
+	SavePath string `json:"savePath"`
+}
+
+// categories retrieves the categories defined in qBittorrent, keyed by name
+func (c *QBittorrentClient) categories(ctx context.Context) (map[string]qBitCategory, error) {
+	apiURL := c.baseURL + "/api/v2/torrents/categories"
+
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	})
 	if err != nil {
-		return fmt.Errorf("execute request: %w", err)
+		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode == http.StatusForbidden {
-		// Session expired, re-login
-		c.sid = ""
-		return c.PauseTorrent(ctx, hash)
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var categories map[string]qBitCategory
+	if err := c.http.DecodeJSON(resp, &categories); err != nil {
+		return nil, fmt.Errorf("decode categories: %w", err)
 	}
 
+	return categories, nil
+}
+
+// CreateCategory creates a category in qBittorrent. An empty savePath uses
+// qBittorrent's default, a subdirectory named after the category.
+func (c *QBittorrentClient) CreateCategory(ctx context.Context, name, savePath string) error {
+	apiURL := c.baseURL + "/api/v2/torrents/createCategory"
+
+	data := url.Values{}
+	data.Set("category", name)
+	data.Set("savePath", savePath)
+
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		return fmt.Errorf("create category %q: API returned status %d: %s", name, resp.StatusCode, string(body))
 	}
 
-	c.logger.DebugContext(ctx, "paused torrent", "hash", hash)
+	c.logger.DebugContext(ctx, "created category", "category", name, "save_path", savePath)
 	return nil
 }
 
 // ResumeTorrent resumes a paused torrent in qBittorrent
 func (c *QBittorrentClient) ResumeTorrent(ctx context.Context, hash string) error {
-	if c.sid == "" {
-		if err := c.Login(ctx); err != nil {
-			return fmt.Errorf("authentication required: %w", err)
-		}
-	}
-
 	apiURL := c.baseURL + "/api/v2/torrents/resume"
 
 	data := url.Values{}
 	data.Set("hashes", hash)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Cookie", fmt.Sprintf("SID=%s", c.sid))
-
-	resp, err := c.http.Do(ctx, req)
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
 	if err != nil {
-		return fmt.Errorf("execute request: %w", err)
+		return err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode == http.StatusForbidden {
-		// Session expired, re-login
-		c.sid = ""
-		return c.ResumeTorrent(ctx, hash)
-	}
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
@@ -376,32 +551,16 @@ func (c *QBittorrentClient) ResumeTorrent(ctx context.Context, hash string) erro
 
 // GetTrackers retrieves the tracker list for a torrent
 func (c *QBittorrentClient) GetTrackers(ctx context.Context, hash string) ([]TrackerInfo, error) {
-	if c.sid == "" {
-		if err := c.Login(ctx); err != nil {
-			return nil, fmt.Errorf("authentication required: %w", err)
-		}
-	}
-
 	apiURL := c.baseURL + "/api/v2/torrents/trackers?hash=" + hash
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Cookie", fmt.Sprintf("SID=%s", c.sid))
-
-	resp, err := c.http.Do(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
+		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode == http.StatusForbidden {
-		c.sid = ""
-		return c.GetTrackers(ctx, hash)
-	}
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
@@ -415,6 +574,67 @@ func (c *QBittorrentClient) GetTrackers(ctx context.Context, hash string) ([]Tra
 	return trackers, nil
 }
 
+// privateTorrentMsg is the message qBittorrent gives its DHT, PeX and LSD
+// pseudo-trackers when they're disabled for a private torrent
+const privateTorrentMsg = "This torrent is private"
+
+// fillAllTrackers runs fillTrackers for each torrent, with at most
+// propertyWorkers tracker list requests in flight
+func (c *QBittorrentClient) fillAllTrackers(ctx context.Context, torrents []Torrent) {
+	if !c.trackerFallback {
+		return
+	}
+
+	sem := make(chan struct{}, c.propertyWorkers)
+	var wg sync.WaitGroup
+	for i := range torrents {
+		if len(torrents[i].Trackers) > 0 {
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func(torrent *Torrent) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			c.fillTrackers(ctx, torrent)
+		}(&torrents[i])
+	}
+	wg.Wait()
+}
+
+// fillTrackers fetches the tracker list of a torrent whose summary reported
+// no tracker, when the fallback is enabled. The DHT, PeX and LSD entries
+// aren't trackers, but tell whether the torrent is private. Errors are
+// logged and leave the torrent unchanged.
+func (c *QBittorrentClient) fillTrackers(ctx context.Context, torrent *Torrent) {
+	if !c.trackerFallback || len(torrent.Trackers) > 0 {
+		return
+	}
+
+	trackers, err := c.GetTrackers(ctx, torrent.Hash)
+	if err != nil {
+		c.logger.DebugContext(ctx, "failed to get trackers for torrent without tracker info",
+			"hash", torrent.Hash,
+			"error", err)
+		return
+	}
+
+	for _, tracker := range trackers {
+		if strings.HasPrefix(tracker.URL, "** [") {
+			if tracker.Msg == privateTorrentMsg {
+				torrent.IsPrivate = true
+			}
+			continue
+		}
+		torrent.Trackers = append(torrent.Trackers, tracker.URL)
+	}
+}
+
 // IsPrivateTracker checks if a torrent uses a private tracker
 func (c *QBittorrentClient) IsPrivateTracker(ctx context.Context, hash string) (bool, error) {
 	props, err := c.GetTorrentProperties(ctx, hash)
@@ -427,59 +647,56 @@ func (c *QBittorrentClient) IsPrivateTracker(ctx context.Context, hash string) (
 
 // AddTags adds tags to a torrent
 func (c *QBittorrentClient) AddTags(ctx context.Context, hash string, tags []string) error {
-	if c.sid == "" {
-		if err := c.Login(ctx); err != nil {
-			return fmt.Errorf("authentication required: %w", err)
-		}
+	return c.AddTagsBulk(ctx, []string{hash}, tags)
+}
+
+// AddTagsBulk adds tags to multiple torrents in a single request.
+// qBittorrent accepts pipe-separated hashes on the addTags endpoint.
+func (c *QBittorrentClient) AddTagsBulk(ctx context.Context, hashes []string, tags []string) error {
+	if err := validateTags(tags); err != nil {
+		return err
 	}
 
-	if len(tags) == 0 {
+	hashes = c.skipEmptyHashes(ctx, hashes, "add tags")
+	if len(tags) == 0 || len(hashes) == 0 {
 		return nil
 	}
 
 	apiURL := c.baseURL + "/api/v2/torrents/addTags"
 
 	data := url.Values{}
-	data.Set("hashes", hash)
+	data.Set("hashes", strings.Join(hashes, "|"))
 	data.Set("tags", strings.Join(tags, ","))
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Cookie", fmt.Sprintf("SID=%s", c.sid))
-
-	resp, err := c.http.Do(ctx, req)
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
 	if err != nil {
-		return fmt.Errorf("execute request: %w", err)
+		return err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode == http.StatusForbidden {
-		c.sid = ""
-		return c.AddTags(ctx, hash, tags)
-	}
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
-	c.logger.DebugContext(ctx, "added tags to torrent", "hash", hash, "tags", tags)
+	c.logger.DebugContext(ctx, "added tags to torrents", "count", len(hashes), "tags", tags)
 	return nil
 }
 
 // RemoveTags removes tags from a torrent
 func (c *QBittorrentClient) RemoveTags(ctx context.Context, hash string, tags []string) error {
-	if c.sid == "" {
-		if err := c.Login(ctx); err != nil {
-			return fmt.Errorf("authentication required: %w", err)
-		}
+	if err := validateTags(tags); err != nil {
+		return err
 	}
 
-	if len(tags) == 0 {
+	if len(c.skipEmptyHashes(ctx, []string{hash}, "remove tags")) == 0 || len(tags) == 0 {
 		return nil
 	}
 
@@ -489,25 +706,19 @@ func (c *QBittorrentClient) RemoveTags(ctx context.Context, hash string, tags []
 	data.Set("hashes", hash)
 	data.Set("tags", strings.Join(tags, ","))
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Cookie", fmt.Sprintf("SID=%s", c.sid))
-
-	resp, err := c.http.Do(ctx, req)
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
 	if err != nil {
-		return fmt.Errorf("execute request: %w", err)
+		return err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode == http.StatusForbidden {
-		c.sid = ""
-		return c.RemoveTags(ctx, hash, tags)
-	}
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
@@ -517,34 +728,48 @@ func (c *QBittorrentClient) RemoveTags(ctx context.Context, hash string, tags []
 	return nil
 }
 
-// GetTorrentProperties retrieves detailed properties for a torrent
-func (c *QBittorrentClient) GetTorrentProperties(ctx context.Context, hash string) (*TorrentProperties, error) {
-	if c.sid == "" {
-		if err := c.Login(ctx); err != nil {
-			return nil, fmt.Errorf("authentication required: %w", err)
+// validateTags rejects tags that qBittorrent can't represent. Tags are sent
+// comma-separated and stored comma-joined with no escaping, so a tag containing
+// a comma would be split into several; pipes are rejected too as they
+// separate hashes in the same requests.
+func validateTags(tags []string) error {
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("invalid tag %q: tag cannot be empty", tag)
+		}
+		if strings.ContainsAny(tag, ",|") {
+			return fmt.Errorf("invalid tag %q: qBittorrent tags cannot contain ',' or '|'", tag)
 		}
 	}
+	return nil
+}
 
-	apiURL := c.baseURL + "/api/v2/torrents/properties?hash=" + hash
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+// skipEmptyHashes drops empty hashes with a warning, since qBittorrent
+// ignores or misapplies tag operations on them
+func (c *QBittorrentClient) skipEmptyHashes(ctx context.Context, hashes []string, operation string) []string {
+	valid := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		if strings.TrimSpace(hash) == "" {
+			c.logger.WarnContext(ctx, "skipping torrent with empty hash", "operation", operation)
+			continue
+		}
+		valid = append(valid, hash)
 	}
+	return valid
+}
 
-	req.Header.Set("Cookie", fmt.Sprintf("SID=%s", c.sid))
+// GetTorrentProperties retrieves detailed properties for a torrent
+func (c *QBittorrentClient) GetTorrentProperties(ctx context.Context, hash string) (*TorrentProperties, error) {
+	apiURL := c.baseURL + "/api/v2/torrents/properties?hash=" + hash
 
-	resp, err := c.http.Do(ctx, req)
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
+		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode == http.StatusForbidden {
-		c.sid = ""
-		return c.GetTorrentProperties(ctx, hash)
-	}
-
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
@@ -564,6 +789,7 @@ func (c *QBittorrentClient) convertTorrent(qt *qBitTorrentInfo) Torrent {
 		Hash:          qt.Hash,
 		Name:          qt.Name,
 		State:         mapQBitState(qt.State),
+		ClientState:   qt.State,
 		Progress:      qt.Progress,
 		Size:          qt.Size,
 		Downloaded:    qt.Downloaded,
@@ -574,6 +800,7 @@ func (c *QBittorrentClient) convertTorrent(qt *qBitTorrentInfo) Torrent {
 		SeedTime:      time.Duration(qt.SeedingTime) * time.Second,
 		AddedOn:       time.Unix(qt.AddedOn, 0),
 		SavePath:      qt.SavePath,
+		ContentPath:   qt.ContentPath,
 		Category:      qt.Category,
 	}
 
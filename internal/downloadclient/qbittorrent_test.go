@@ -3,8 +3,12 @@ package downloadclient
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -337,6 +341,23 @@ func TestQBitGetTorrent(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "uppercase hash from the arr",
+			hash: "ABC123",
+			serverResponse: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/v2/auth/login" {
+					http.SetCookie(w, &http.Cookie{Name: "SID", Value: "test_sid"})
+					w.WriteHeader(http.StatusOK)
+					_, _ = w.Write([]byte("Ok."))
+					return
+				}
+
+				assert.Contains(t, r.URL.String(), "hashes=abc123")
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode([]qBitTorrentInfo{mockTorrent})
+			},
+			wantErr: false,
+		},
 		{
 			name: "torrent not found",
 			hash: "nonexistent",
@@ -379,13 +400,82 @@ func TestQBitGetTorrent(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				require.NotNil(t, torrent)
-				assert.Equal(t, tt.hash, torrent.Hash)
+				assert.Equal(t, strings.ToLower(tt.hash), torrent.Hash)
 				assert.Equal(t, "Test Torrent", torrent.Name)
 			}
 		})
 	}
 }
 
+func TestQBitTrackerFallback(t *testing.T) {
+	tests := []struct {
+		name         string
+		fallback     bool
+		wantTrackers []string
+		wantPrivate  bool
+		wantRequests int32
+	}{
+		{
+			name:         "fallback fills trackers and private status",
+			fallback:     true,
+			wantTrackers: []string{"https://private.example/announce"},
+			wantPrivate:  true,
+			wantRequests: 1,
+		},
+		{
+			name: "disabled leaves trackers empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var trackerRequests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v2/auth/login":
+					http.SetCookie(w, &http.Cookie{Name: "SID", Value: "test_sid"})
+					_, _ = w.Write([]byte("Ok."))
+				case "/api/v2/torrents/info":
+					_ = json.NewEncoder(w).Encode([]qBitTorrentInfo{
+						{Hash: "public1", Name: "Public", TrackerHost: "https://public.example/announce"},
+						{Hash: "private1", Name: "Private"},
+					})
+				case "/api/v2/torrents/trackers":
+					trackerRequests.Add(1)
+					assert.Equal(t, "private1", r.URL.Query().Get("hash"), "only torrents without tracker info are looked up")
+					_ = json.NewEncoder(w).Encode([]TrackerInfo{
+						{URL: "** [DHT] **", Msg: "This torrent is private"},
+						{URL: "** [PeX] **", Msg: "This torrent is private"},
+						{URL: "** [LSD] **", Msg: "This torrent is private"},
+						{URL: "https://private.example/announce", Status: 4, Msg: "unregistered torrent"},
+					})
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client, err := NewQBittorrentClient(QBittorrentConfig{
+				BaseURL:         server.URL,
+				Username:        "admin",
+				Password:        "adminpass",
+				TrackerFallback: tt.fallback,
+			})
+			require.NoError(t, err)
+
+			torrents, err := client.GetTorrents(context.Background())
+			require.NoError(t, err)
+			require.Len(t, torrents, 2)
+
+			assert.Equal(t, []string{"https://public.example/announce"}, torrents[0].Trackers)
+			assert.False(t, torrents[0].IsPrivate)
+			assert.Equal(t, tt.wantTrackers, torrents[1].Trackers)
+			assert.Equal(t, tt.wantPrivate, torrents[1].IsPrivate)
+			assert.Equal(t, tt.wantRequests, trackerRequests.Load())
+		})
+	}
+}
+
 func TestQBitDeleteTorrent(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -495,6 +585,230 @@ func TestQBitPauseTorrent(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestQBitAddTagsBulk(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/auth/login" {
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "test_sid"})
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("Ok."))
+			return
+		}
+
+		calls++
+		assert.Equal(t, "/api/v2/torrents/addTags", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		_ = r.ParseForm()
+		assert.Equal(t, "abc|def|ghi", r.FormValue("hashes"))
+		assert.Equal(t, "Obsolete,Other", r.FormValue("tags"))
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := QBittorrentConfig{
+		BaseURL:  server.URL,
+		Username: "admin",
+		Password: "adminpass",
+	}
+
+	client, err := NewQBittorrentClient(cfg)
+	require.NoError(t, err)
+
+	var _ BulkTagger = client
+
+	err = client.AddTagsBulk(context.Background(), []string{"abc", "def", "ghi"}, []string{"Obsolete", "Other"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls, "all torrents should be tagged in a single request")
+
+	// Nothing to do should not hit the API
+	require.NoError(t, client.AddTagsBulk(context.Background(), nil, []string{"Obsolete"}))
+	assert.Equal(t, 1, calls)
+}
+
+func TestQBitTagValidation(t *testing.T) {
+	var calls int
+	var hashes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/auth/login" {
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "test_sid"})
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("Ok."))
+			return
+		}
+
+		calls++
+		_ = r.ParseForm()
+		hashes = append(hashes, r.FormValue("hashes"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewQBittorrentClient(QBittorrentConfig{
+		BaseURL:  server.URL,
+		Username: "admin",
+		Password: "adminpass",
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// Tags containing delimiters are rejected before anything is sent
+	err = client.AddTags(ctx, "abc", []string{"Obsolete,Other"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid tag "Obsolete,Other"`)
+
+	err = client.RemoveTags(ctx, "abc", []string{"a|b"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot contain")
+
+	require.Error(t, client.AddTags(ctx, "abc", []string{" "}))
+	assert.Equal(t, 0, calls)
+
+	// Empty hashes are skipped, leaving the rest of the bulk request intact
+	require.NoError(t, client.AddTagsBulk(ctx, []string{"abc", "", "def"}, []string{"Obsolete"}))
+	require.NoError(t, client.AddTags(ctx, "", []string{"Obsolete"}))
+	require.NoError(t, client.RemoveTags(ctx, "", []string{"Obsolete"}))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []string{"abc|def"}, hashes)
+}
+
+func TestQBitSetCategory(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories string
+		wantCreate bool
+	}{
+		{
+			name:       "existing category",
+			categories: `{"manual": {"name": "manual", "savePath": "/downloads/manual"}}`,
+		},
+		{
+			name:       "missing category is created",
+			categories: `{"tv": {"name": "tv", "savePath": ""}}`,
+			wantCreate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created, set bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v2/auth/login":
+					http.SetCookie(w, &http.Cookie{Name: "SID", Value: "test_sid"})
+					w.WriteHeader(http.StatusOK)
+					_, _ = w.Write([]byte("Ok."))
+				case "/api/v2/torrents/categories":
+					assert.Equal(t, http.MethodGet, r.Method)
+					_, _ = w.Write([]byte(tt.categories))
+				case "/api/v2/torrents/createCategory":
+					assert.Equal(t, http.MethodPost, r.Method)
+					assert.False(t, set, "the category should be created before it's set")
+					_ = r.ParseForm()
+					assert.Equal(t, "manual", r.FormValue("category"))
+					created = true
+					w.WriteHeader(http.StatusOK)
+				case "/api/v2/torrents/setCategory":
+					assert.Equal(t, http.MethodPost, r.Method)
+					_ = r.ParseForm()
+					assert.Equal(t, "abc123", r.FormValue("hashes"))
+					assert.Equal(t, "manual", r.FormValue("category"))
+					set = true
+					w.WriteHeader(http.StatusOK)
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			cfg := QBittorrentConfig{
+				BaseURL:  server.URL,
+				Username: "admin",
+				Password: "adminpass",
+			}
+
+			client, err := NewQBittorrentClient(cfg)
+			require.NoError(t, err)
+
+			err = client.SetCategory(context.Background(), "abc123", "manual")
+			assert.NoError(t, err)
+			assert.True(t, set)
+			assert.Equal(t, tt.wantCreate, created)
+		})
+	}
+}
+
+func TestQBitCreateCategoryFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "test_sid"})
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("Ok."))
+		case "/api/v2/torrents/categories":
+			_, _ = w.Write([]byte(`{}`))
+		case "/api/v2/torrents/createCategory":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte("Category name is invalid"))
+		default:
+			t.Errorf("torrent should not be moved to a category that couldn't be created: %s", r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewQBittorrentClient(QBittorrentConfig{BaseURL: server.URL, Username: "admin", Password: "adminpass"})
+	require.NoError(t, err)
+
+	err = client.SetCategory(context.Background(), "abc123", "bad\\name")
+	assert.ErrorContains(t, err, "create category")
+}
+
+func TestQBitGetTorrentProperties(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/auth/login" {
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "test_sid"})
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("Ok."))
+			return
+		}
+
+		assert.Equal(t, "/api/v2/torrents/properties", r.URL.Path)
+		if r.URL.Query().Get("hash") != "abc123" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"is_private":true,"ratio_limit":2,"seeding_time_limit":1440}`))
+	}))
+	defer server.Close()
+
+	cfg := QBittorrentConfig{
+		BaseURL:  server.URL,
+		Username: "admin",
+		Password: "adminpass",
+	}
+
+	client, err := NewQBittorrentClient(cfg)
+	require.NoError(t, err)
+
+	var _ PropertiesClient = client
+
+	props, err := client.GetTorrentProperties(context.Background(), "abc123")
+	require.NoError(t, err)
+	assert.True(t, props.IsPrivate)
+	assert.Equal(t, 2.0, props.RatioLimit)
+	assert.Equal(t, int64(1440), props.SeedingTimeLimit)
+
+	_, err = client.GetTorrentProperties(context.Background(), "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
 func TestQBitResumeTorrent(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/api/v2/auth/login" {
@@ -570,6 +884,57 @@ func TestQBitSessionExpired(t *testing.T) {
 	assert.Equal(t, 2, callCount) // Should have called twice
 }
 
+func TestQBitSessionExpiredConcurrent(t *testing.T) {
+	var logins atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/auth/login" {
+			logins.Add(1)
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "new_sid"})
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("Ok."))
+			return
+		}
+
+		if r.URL.Path == "/api/v2/torrents/info" {
+			if cookie, err := r.Cookie("SID"); err != nil || cookie.Value != "new_sid" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode([]qBitTorrentInfo{})
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewQBittorrentClient(QBittorrentConfig{
+		BaseURL:  server.URL,
+		Username: "admin",
+		Password: "adminpass",
+	})
+	require.NoError(t, err)
+
+	// Every request made with the old session is rejected once
+	client.sid = "old_sid"
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetTorrents(context.Background())
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), logins.Load(), "expired session should trigger exactly one re-login")
+}
+
 func TestMapQBitState(t *testing.T) {
 	tests := []struct {
 		qbitState     string
@@ -602,3 +967,91 @@ func TestMapQBitState(t *testing.T) {
 		})
 	}
 }
+
+func TestQBitPing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			require.NoError(t, r.ParseForm())
+			if r.Form.Get("password") != "adminpass" {
+				_, _ = w.Write([]byte("Fails."))
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "test_sid"})
+			_, _ = w.Write([]byte("Ok."))
+		case "/api/v2/torrents/info":
+			assert.Equal(t, "1", r.URL.Query().Get("limit"))
+			if cookie, err := r.Cookie("SID"); err != nil || cookie.Value != "test_sid" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte("[]"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("valid credentials", func(t *testing.T) {
+		client, err := NewQBittorrentClient(QBittorrentConfig{BaseURL: server.URL, Username: "admin", Password: "adminpass"})
+		require.NoError(t, err)
+		assert.NoError(t, client.Ping(context.Background()))
+	})
+
+	t.Run("bad credentials", func(t *testing.T) {
+		client, err := NewQBittorrentClient(QBittorrentConfig{BaseURL: server.URL, Username: "admin", Password: "wrong"})
+		require.NoError(t, err)
+		err = client.Ping(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "login failed")
+	})
+}
+
+func TestQBitTrackerFallbackBounded(t *testing.T) {
+	var inFlight, maxInFlight, trackerRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "test_sid"})
+			_, _ = w.Write([]byte("Ok."))
+		case "/api/v2/torrents/info":
+			var infos []qBitTorrentInfo
+			for i := 0; i < 10; i++ {
+				infos = append(infos, qBitTorrentInfo{Hash: fmt.Sprintf("hash%d", i)})
+			}
+			_ = json.NewEncoder(w).Encode(infos)
+		case "/api/v2/torrents/trackers":
+			trackerRequests.Add(1)
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				highest := maxInFlight.Load()
+				if n <= highest || maxInFlight.CompareAndSwap(highest, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			_ = json.NewEncoder(w).Encode([]TrackerInfo{{URL: "https://tracker.example/announce"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewQBittorrentClient(QBittorrentConfig{
+		BaseURL:         server.URL,
+		Username:        "admin",
+		Password:        "adminpass",
+		TrackerFallback: true,
+		PropertyWorkers: 2,
+	})
+	require.NoError(t, err)
+
+	torrents, err := client.GetTorrents(context.Background())
+	require.NoError(t, err)
+	for _, torrent := range torrents {
+		assert.Equal(t, []string{"https://tracker.example/announce"}, torrent.Trackers)
+	}
+	assert.Equal(t, int32(10), trackerRequests.Load())
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2), "tracker requests should be bounded by property_workers")
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jmylchreest/go-decluttarr/pkg/httpclient"
@@ -21,10 +22,16 @@ type SABnzbdClient struct {
 
 // SABnzbdConfig holds configuration for SABnzbd client
 type SABnzbdConfig struct {
-	BaseURL string
-	APIKey  string
-	Timeout time.Duration
-	Logger  *slog.Logger
+	BaseURL   string
+	APIKey    string
+	Timeout   time.Duration
+	SkipTLS   bool
+	UserAgent string
+	Headers   map[string]string
+	Logger    *slog.Logger
+
+	// RequestsPerSecond paces requests to the client; 0 = unlimited
+	RequestsPerSecond float64
 }
 
 // SABnzbdSlot represents an item in the SABnzbd queue
@@ -78,7 +85,11 @@ func NewSABnzbdClient(cfg SABnzbdConfig) *SABnzbdClient {
 		Timeout:         cfg.Timeout,
 		MaxIdleConns:    10,
 		IdleConnTimeout: 90 * time.Second,
-		SkipTLSVerify:   false,
+		SkipTLSVerify:   cfg.SkipTLS,
+		UserAgent:       cfg.UserAgent,
+		Headers:         cfg.Headers,
+
+		RequestsPerSecond: cfg.RequestsPerSecond,
 	}
 
 	return &SABnzbdClient{
@@ -94,6 +105,11 @@ func (c *SABnzbdClient) Name() string {
 	return "SABnzbd"
 }
 
+// SeedingCapable reports that usenet downloads never seed
+func (c *SABnzbdClient) SeedingCapable() bool {
+	return false
+}
+
 // buildURL constructs API URL with mode and apikey parameters
 func (c *SABnzbdClient) buildURL(mode string, extraParams map[string]string) string {
 	params := url.Values{}
@@ -108,6 +124,41 @@ func (c *SABnzbdClient) buildURL(mode string, extraParams map[string]string) str
 	return fmt.Sprintf("%s/api?%s", c.baseURL, params.Encode())
 }
 
+// Ping requests the version and a single queue slot to verify connectivity
+// and the API key (SABnzbd answers the version without checking the key)
+func (c *SABnzbdClient) Ping(ctx context.Context) error {
+	resp, err := c.http.Get(ctx, c.buildURL("version", nil))
+	if err != nil {
+		return fmt.Errorf("failed to fetch version: %w", err)
+	}
+
+	var version struct {
+		Version string `json:"version"`
+	}
+	if err := c.http.DecodeJSON(resp, &version); err != nil {
+		return fmt.Errorf("failed to decode version response: %w", err)
+	}
+
+	resp, err = c.http.Get(ctx, c.buildURL("queue", map[string]string{"limit": "1"}))
+	if err != nil {
+		return fmt.Errorf("failed to fetch queue: %w", err)
+	}
+
+	var status struct {
+		Status *bool  `json:"status"`
+		Error  string `json:"error"`
+	}
+	if err := c.http.DecodeJSON(resp, &status); err != nil {
+		return fmt.Errorf("failed to decode queue response: %w", err)
+	}
+	if status.Error != "" || (status.Status != nil && !*status.Status) {
+		return fmt.Errorf("api error: %s", status.Error)
+	}
+
+	c.logger.Debug("pinged sabnzbd", "version", version.Version)
+	return nil
+}
+
 // GetQueue retrieves the current download queue
 func (c *SABnzbdClient) GetQueue(ctx context.Context) ([]SABnzbdSlot, error) {
 	apiURL := c.buildURL("queue", nil)
@@ -208,6 +259,59 @@ func (c *SABnzbdClient) ResumeSlot(ctx context.Context, nzoID string) error {
 	return nil
 }
 
+// PauseQueue pauses the whole download queue
+func (c *SABnzbdClient) PauseQueue(ctx context.Context) error {
+	c.logger.Debug("pausing SABnzbd queue")
+
+	resp, err := c.http.Get(ctx, c.buildURL("pause", nil))
+	if err != nil {
+		return fmt.Errorf("failed to pause queue: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	c.logger.Debug("paused sabnzbd queue")
+	return nil
+}
+
+// ResumeQueue resumes the whole download queue
+func (c *SABnzbdClient) ResumeQueue(ctx context.Context) error {
+	c.logger.Debug("resuming SABnzbd queue")
+
+	resp, err := c.http.Get(ctx, c.buildURL("resume", nil))
+	if err != nil {
+		return fmt.Errorf("failed to resume queue: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	c.logger.Debug("resumed sabnzbd queue")
+	return nil
+}
+
+// SetSpeedLimit limits the download speed to a percentage of SABnzbd's
+// configured maximum line speed; 100 removes the limit
+func (c *SABnzbdClient) SetSpeedLimit(ctx context.Context, percent int) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("speed limit must be between 0 and 100 percent, got %d", percent)
+	}
+
+	params := map[string]string{
+		"name":  "speedlimit",
+		"value": strconv.Itoa(percent),
+	}
+	apiURL := c.buildURL("config", params)
+
+	c.logger.Debug("setting SABnzbd speed limit", "percent", percent)
+
+	resp, err := c.http.Get(ctx, apiURL)
+	if err != nil {
+		return fmt.Errorf("failed to set speed limit: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	c.logger.Debug("set sabnzbd speed limit", "percent", percent)
+	return nil
+}
+
 // GetTorrents adapts SABnzbd queue to Client interface (returns queue items as Torrent-like objects)
 func (c *SABnzbdClient) GetTorrents(ctx context.Context) ([]Torrent, error) {
 	slots, err := c.GetQueue(ctx)
@@ -228,7 +332,7 @@ func (c *SABnzbdClient) GetTorrents(ctx context.Context) ([]Torrent, error) {
 	return torrents, nil
 }
 
-// GetTorrent retrieves a single item by NZO ID
+// GetTorrent retrieves a single item by NZO ID, matched case-insensitively
 func (c *SABnzbdClient) GetTorrent(ctx context.Context, nzoID string) (*Torrent, error) {
 	slots, err := c.GetQueue(ctx)
 	if err != nil {
@@ -236,7 +340,7 @@ func (c *SABnzbdClient) GetTorrent(ctx context.Context, nzoID string) (*Torrent,
 	}
 
 	for _, slot := range slots {
-		if slot.NzoID == nzoID {
+		if strings.EqualFold(slot.NzoID, nzoID) {
 			torrent, err := c.slotToTorrent(slot)
 			if err != nil {
 				return nil, err
@@ -263,6 +367,12 @@ func (c *SABnzbdClient) ResumeTorrent(ctx context.Context, nzoID string) error {
 	return c.ResumeSlot(ctx, nzoID)
 }
 
+// GetTorrentProperties returns ErrUnsupported, as usenet downloads have no
+// torrent properties
+func (c *SABnzbdClient) GetTorrentProperties(ctx context.Context, nzoID string) (*TorrentProperties, error) {
+	return nil, fmt.Errorf("sabnzbd properties for %s: %w", nzoID, ErrUnsupported)
+}
+
 // slotToTorrent converts SABnzbd slot to Torrent structure
 func (c *SABnzbdClient) slotToTorrent(slot SABnzbdSlot) (Torrent, error) {
 	var state TorrentState
@@ -289,16 +399,17 @@ func (c *SABnzbdClient) slotToTorrent(slot SABnzbdSlot) (Torrent, error) {
 	downloaded := size - int64(slot.MBLeft*1024*1024)
 
 	return Torrent{
-		Hash:       slot.NzoID,
-		Name:       slot.Filename,
-		State:      state,
-		Progress:   progress,
-		Size:       size,
-		Downloaded: downloaded,
-		Category:   slot.Category,
-		Tags:       []string{},
-		Trackers:   []string{},
-		IsPrivate:  false,
+		Hash:        slot.NzoID,
+		Name:        slot.Filename,
+		State:       state,
+		ClientState: slot.Status,
+		Progress:    progress,
+		Size:        size,
+		Downloaded:  downloaded,
+		Category:    slot.Category,
+		Tags:        []string{},
+		Trackers:    []string{},
+		IsPrivate:   false,
 	}, nil
 }
 
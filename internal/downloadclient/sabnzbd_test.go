@@ -357,6 +357,48 @@ func TestSABResumeSlot(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSABPauseResumeQueue(t *testing.T) {
+	var modes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api", r.URL.Path)
+		assert.Empty(t, r.URL.Query().Get("name"), "the whole queue is paused, not a slot")
+		assert.Equal(t, "test_api_key", r.URL.Query().Get("apikey"))
+		modes = append(modes, r.URL.Query().Get("mode"))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status": true}`))
+	}))
+	defer server.Close()
+
+	client := NewSABnzbdClient(SABnzbdConfig{BaseURL: server.URL, APIKey: "test_api_key"})
+	require.NoError(t, client.PauseQueue(context.Background()))
+	require.NoError(t, client.ResumeQueue(context.Background()))
+	assert.Equal(t, []string{"pause", "resume"}, modes)
+}
+
+func TestSABSetSpeedLimit(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/api", r.URL.Path)
+		assert.Equal(t, "config", r.URL.Query().Get("mode"))
+		assert.Equal(t, "speedlimit", r.URL.Query().Get("name"))
+		assert.Equal(t, "50", r.URL.Query().Get("value"))
+		assert.Equal(t, "test_api_key", r.URL.Query().Get("apikey"))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status": true}`))
+	}))
+	defer server.Close()
+
+	client := NewSABnzbdClient(SABnzbdConfig{BaseURL: server.URL, APIKey: "test_api_key"})
+	require.NoError(t, client.SetSpeedLimit(context.Background(), 50))
+
+	assert.Error(t, client.SetSpeedLimit(context.Background(), 150))
+	assert.Error(t, client.SetSpeedLimit(context.Background(), -1))
+	assert.Equal(t, 1, requests, "out of range limits aren't sent")
+}
+
 func TestSABGetTorrents(t *testing.T) {
 	mockQueue := SABnzbdQueueResponse{
 		Queue: struct {
@@ -451,6 +493,11 @@ func TestSABGetTorrent(t *testing.T) {
 			nzoID:   "SABnzbd_nzo_abc123",
 			wantErr: false,
 		},
+		{
+			name:    "found regardless of case",
+			nzoID:   "sabnzbd_nzo_ABC123",
+			wantErr: false,
+		},
 		{
 			name:        "not found",
 			nzoID:       "SABnzbd_nzo_notfound",
@@ -483,7 +530,7 @@ func TestSABGetTorrent(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				require.NotNil(t, torrent)
-				assert.Equal(t, tt.nzoID, torrent.Hash)
+				assert.Equal(t, "SABnzbd_nzo_abc123", torrent.Hash)
 			}
 		})
 	}
@@ -628,3 +675,71 @@ func TestSABSlotToTorrent(t *testing.T) {
 		})
 	}
 }
+
+func TestSABSkipTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(SABnzbdQueueResponse{})
+	}))
+	defer server.Close()
+
+	t.Run("self-signed cert rejected by default", func(t *testing.T) {
+		client := NewSABnzbdClient(SABnzbdConfig{BaseURL: server.URL, APIKey: "test"})
+		defer client.Close()
+
+		_, err := client.GetQueue(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("self-signed cert accepted with skip tls", func(t *testing.T) {
+		client := NewSABnzbdClient(SABnzbdConfig{BaseURL: server.URL, APIKey: "test", SkipTLS: true})
+		defer client.Close()
+
+		_, err := client.GetQueue(context.Background())
+		assert.NoError(t, err)
+	})
+}
+
+func TestSABPing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch query.Get("mode") {
+		case "version":
+			// SABnzbd answers the version without checking the key
+			_, _ = w.Write([]byte(`{"version": "4.2.1"}`))
+		case "queue":
+			if query.Get("apikey") != "good_key" {
+				_, _ = w.Write([]byte(`{"status": false, "error": "API Key Incorrect"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(SABnzbdQueueResponse{})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("valid api key", func(t *testing.T) {
+		client := NewSABnzbdClient(SABnzbdConfig{BaseURL: server.URL, APIKey: "good_key"})
+		defer client.Close()
+		assert.NoError(t, client.Ping(context.Background()))
+	})
+
+	t.Run("bad api key", func(t *testing.T) {
+		client := NewSABnzbdClient(SABnzbdConfig{BaseURL: server.URL, APIKey: "bad_key"})
+		defer client.Close()
+		err := client.Ping(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "API Key Incorrect")
+	})
+}
+
+func TestSABGetTorrentProperties(t *testing.T) {
+	client := NewSABnzbdClient(SABnzbdConfig{BaseURL: "http://localhost:8080", APIKey: "test_api_key"})
+	defer client.Close()
+
+	var _ PropertiesClient = client
+
+	props, err := client.GetTorrentProperties(context.Background(), "SABnzbd_nzo_1")
+	assert.Nil(t, props)
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
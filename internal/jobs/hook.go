@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+)
+
+// DefaultPreRemoveHookTimeout is how long the pre-remove hook may run when no
+// timeout is configured
+const DefaultPreRemoveHookTimeout = 30 * time.Second
+
+// ErrRemovalDenied is returned by RemoveQueueItem and DeleteClientTorrent
+// when the pre-remove hook refuses a removal
+var ErrRemovalDenied = errors.New("removal denied by pre_remove_hook")
+
+// runPreRemoveHook runs the configured pre-remove hook for a queue item,
+// returning ErrRemovalDenied if it exits non-zero or can't be run. The item is
+// passed as arguments (instance, queue ID, download ID, title) and as
+// DECLUTTARR_* environment variables. Downloads removed from a client
+// directly pass the client's name as the instance and an empty queue ID.
+func (m *Manager) runPreRemoveHook(ctx context.Context, instanceName string, item arrapi.QueueItem) error {
+	hook := m.cfg.General.PreRemoveHook
+	if hook == "" {
+		return nil
+	}
+
+	timeout := m.cfg.General.PreRemoveHookTimeout
+	if timeout <= 0 {
+		timeout = DefaultPreRemoveHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var queueID string
+	if item.ID != 0 {
+		queueID = strconv.Itoa(item.ID)
+	}
+	cmd := exec.CommandContext(ctx, hook, instanceName, queueID, item.DownloadID, item.Title)
+	cmd.Env = append(os.Environ(),
+		"DECLUTTARR_INSTANCE="+instanceName,
+		"DECLUTTARR_QUEUE_ID="+queueID,
+		"DECLUTTARR_DOWNLOAD_ID="+item.DownloadID,
+		"DECLUTTARR_TITLE="+item.Title,
+		"DECLUTTARR_PROTOCOL="+item.Protocol,
+		"DECLUTTARR_INDEXER="+item.Indexer,
+	)
+	// Don't wait on children the hook left holding its output once it's killed
+	cmd.WaitDelay = time.Second
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		m.logger.Warn("pre_remove_hook denied removal",
+			"instance", instanceName,
+			"download_id", item.DownloadID,
+			"title", item.Title,
+			"error", err,
+			"output", strings.TrimSpace(output.String()))
+		return fmt.Errorf("%w: %v", ErrRemovalDenied, err)
+	}
+
+	m.logger.Debug("pre_remove_hook approved removal",
+		"instance", instanceName,
+		"download_id", item.DownloadID,
+		"output", strings.TrimSpace(output.String()))
+	return nil
+}
@@ -0,0 +1,85 @@
+package jobs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+)
+
+// writeHook writes an executable shell script to a temp dir and returns its path
+func writeHook(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need a POSIX shell")
+	}
+
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755))
+	return path
+}
+
+func TestPreRemoveHook(t *testing.T) {
+	tests := []struct {
+		name        string
+		script      string
+		timeout     time.Duration
+		wantDeleted bool
+	}{
+		{name: "approves", script: "exit 0\n", wantDeleted: true},
+		{name: "denies", script: "echo not today\nexit 1\n", wantDeleted: false},
+		{name: "times out", script: "sleep 5\n", timeout: 100 * time.Millisecond, wantDeleted: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deletes atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodDelete && r.URL.Path == "/api/v3/queue/7" {
+					deletes.Add(1)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			outFile := filepath.Join(t.TempDir(), "args")
+			hook := writeHook(t, `echo "$@" "$DECLUTTARR_INDEXER" > `+outFile+"\n"+tt.script)
+
+			cfg := &config.Config{General: config.GeneralConfig{PreRemoveHook: hook, PreRemoveHookTimeout: tt.timeout}}
+			m := NewManager(cfg, testLogger(), "")
+			defer m.Close()
+			m.RegisterArrClient("sonarr", arrapi.NewClient(arrapi.ClientConfig{
+				Name:    "sonarr",
+				BaseURL: server.URL,
+				APIKey:  "test",
+				Logger:  testLogger(),
+			}))
+
+			item := arrapi.QueueItem{ID: 7, DownloadID: "abc", Title: "Some.Show.S01E01", Indexer: "Example"}
+			err := m.RemoveQueueItem(context.Background(), "sonarr", item, arrapi.DeleteOptions{}, "", "", true)
+
+			if tt.wantDeleted {
+				require.NoError(t, err)
+				assert.Equal(t, int32(1), deletes.Load())
+			} else {
+				assert.ErrorIs(t, err, ErrRemovalDenied)
+				assert.Zero(t, deletes.Load(), "denied removal must not reach the arr")
+			}
+
+			args, err := os.ReadFile(outFile)
+			require.NoError(t, err)
+			assert.Equal(t, "sonarr 7 abc Some.Show.S01E01 Example\n", string(args))
+		})
+	}
+}
@@ -1,6 +1,13 @@
 package jobs
 
-import "context"
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+)
 
 // Job represents a task that can be executed by the job manager
 type Job interface {
@@ -26,3 +33,59 @@ type StatsJob interface {
 	// Stats returns the statistics from the last run
 	Stats() JobStats
 }
+
+// Default job orders: removals run before searches so a cycle doesn't search
+// for items a later removal would have re-queued anyway
+const (
+	RemovalJobOrder = 100
+	SearchJobOrder  = 200
+)
+
+// OrderedJob is an optional interface for jobs with a position in the cycle.
+// Jobs run in ascending order; ties, and jobs without an order (sorted as 0),
+// keep their registration order.
+type OrderedJob interface {
+	Job
+	// Order returns the job's position in the cycle
+	Order() int
+}
+
+// ScheduledJob is an optional interface for jobs that run less often than
+// every cycle
+type ScheduledJob interface {
+	Job
+	// Interval returns the minimum time between runs, or 0 to run every cycle
+	Interval() time.Duration
+}
+
+// TestRunJob is an optional interface for jobs whose test-run mode can be
+// switched between cycles without losing the state they carry across runs
+type TestRunJob interface {
+	Job
+	// SetTestRun switches the job in or out of test-run mode
+	SetTestRun(testRun bool)
+}
+
+// DetectingJob is an optional interface for jobs whose detection can be
+// evaluated against queue items without side effects, used by WhatIf
+type DetectingJob interface {
+	Job
+	// Detect returns the items of an instance's queue the job would act on,
+	// applying the job's scope and filters as a run does. torrents are the
+	// download clients' torrents, keyed by lowercase hash.
+	Detect(instanceName string, queue []arrapi.QueueItem, torrents map[string]downloadclient.Torrent) []arrapi.QueueItem
+}
+
+// Unsupported reports whether err is a 404 from an *arr endpoint, which
+// means the instance doesn't provide it (e.g. Lidarr and Readarr v1 lack some
+// v3 endpoints). It logs that the endpoint is unsupported so the caller can
+// skip it and carry on instead of failing the job.
+func Unsupported(logger *slog.Logger, instanceName, endpoint string, err error) bool {
+	if !arrapi.IsNotFound(err) {
+		return false
+	}
+	logger.Info("not supported on this instance, skipping",
+		"instance", instanceName,
+		"endpoint", endpoint)
+	return true
+}
@@ -0,0 +1,193 @@
+package manage
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+// ActiveDownloadsJob keeps the number of active downloads in each download
+// client at or below max_active_downloads by pausing the newest excess
+// torrents, resuming them once slots free up
+type ActiveDownloadsJob struct {
+	base
+	cfg       *config.JobConfig
+	maxActive int
+	paused    map[string]map[string]time.Time // client name -> hash -> added on, for torrents this job paused
+}
+
+// NewActiveDownloadsJob creates a new active downloads throttle job
+func NewActiveDownloadsJob(
+	name string,
+	cfg *config.JobConfig,
+	defaults *config.JobDefaultsConfig,
+	manager *jobs.Manager,
+	logger *slog.Logger,
+	testRun bool,
+) *ActiveDownloadsJob {
+	maxActive := defaults.MaxActiveDownloads
+	if cfg.MaxActiveDownloads != nil {
+		maxActive = *cfg.MaxActiveDownloads
+	}
+
+	return &ActiveDownloadsJob{
+		base:      newBase(name, cfg.Enabled, jobInterval(cfg), cfg.Order, manager, logger, testRun),
+		cfg:       cfg,
+		maxActive: maxActive,
+		paused:    make(map[string]map[string]time.Time),
+	}
+}
+
+// Run executes the active downloads throttle job
+func (j *ActiveDownloadsJob) Run(ctx context.Context) error {
+	j.logger.Debug("starting active downloads job", "test_run", j.testRun, "max_active_downloads", j.maxActive)
+
+	if j.maxActive <= 0 {
+		j.logger.Debug("max_active_downloads not set, skipping")
+		return nil
+	}
+
+	totalExcess := 0
+	totalPaused := 0
+
+	for clientName, client := range j.manager.GetAllDownloadClients() {
+		if !jobs.InInstances(j.cfg.Instances, clientName) {
+			continue
+		}
+
+		torrents, err := client.GetTorrents(ctx)
+		if err != nil {
+			j.logger.Error("failed to get torrents from client",
+				"client", clientName,
+				"error", err)
+			continue
+		}
+
+		var active []downloadclient.Torrent
+		present := make(map[string]downloadclient.Torrent, len(torrents))
+		for _, torrent := range torrents {
+			present[torrent.Hash] = torrent
+			if downloadclient.UsesDownloadSlot(torrent) {
+				active = append(active, torrent)
+			}
+		}
+
+		// Forget torrents we paused that were since removed or resumed elsewhere
+		paused := j.paused[clientName]
+		for hash := range paused {
+			if torrent, ok := present[hash]; !ok || torrent.State != downloadclient.StatePaused {
+				delete(paused, hash)
+			}
+		}
+
+		j.logger.Debug("counted active downloads",
+			"client", clientName,
+			"active", len(active),
+			"paused_by_job", len(paused))
+
+		switch {
+		case len(active) > j.maxActive:
+			excess := len(active) - j.maxActive
+			totalExcess += excess
+			totalPaused += j.pauseNewest(ctx, clientName, client, active, excess)
+		case len(active) < j.maxActive && len(paused) > 0:
+			j.resumeOldest(ctx, clientName, client, j.maxActive-len(active))
+		}
+	}
+
+	j.logger.Debug("active downloads job completed",
+		"excess", totalExcess,
+		"paused", totalPaused,
+		"test_run", j.testRun,
+	)
+
+	j.lastFound = totalExcess
+	j.lastActed = totalPaused
+
+	return nil
+}
+
+// pauseNewest pauses the count most recently added active torrents, returning
+// the number paused
+func (j *ActiveDownloadsJob) pauseNewest(ctx context.Context, clientName string, client downloadclient.Client, active []downloadclient.Torrent, count int) int {
+	sort.SliceStable(active, func(a, b int) bool {
+		return active[a].AddedOn.After(active[b].AddedOn)
+	})
+
+	paused := 0
+	for _, torrent := range active[:count] {
+		if j.testRun {
+			j.logger.Info("[TEST RUN] would pause download over the active limit",
+				"client", clientName,
+				"hash", torrent.Hash,
+				"name", torrent.Name,
+			)
+			paused++
+			continue
+		}
+
+		if err := client.PauseTorrent(ctx, torrent.Hash); err != nil {
+			j.logger.Error("failed to pause download",
+				"client", clientName,
+				"hash", torrent.Hash,
+				"error", err,
+			)
+			continue
+		}
+
+		if j.paused[clientName] == nil {
+			j.paused[clientName] = make(map[string]time.Time)
+		}
+		j.paused[clientName][torrent.Hash] = torrent.AddedOn
+		paused++
+
+		j.logger.Info("paused download over the active limit",
+			"client", clientName,
+			"hash", torrent.Hash,
+			"name", torrent.Name,
+		)
+	}
+
+	return paused
+}
+
+// resumeOldest resumes up to slots torrents this job paused, oldest first
+func (j *ActiveDownloadsJob) resumeOldest(ctx context.Context, clientName string, client downloadclient.Client, slots int) {
+	paused := j.paused[clientName]
+
+	hashes := make([]string, 0, len(paused))
+	for hash := range paused {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(a, b int) bool {
+		return paused[hashes[a]].Before(paused[hashes[b]])
+	})
+
+	if len(hashes) > slots {
+		hashes = hashes[:slots]
+	}
+
+	for _, hash := range hashes {
+		if j.testRun {
+			j.logger.Info("[TEST RUN] would resume download", "client", clientName, "hash", hash)
+			continue
+		}
+
+		if err := client.ResumeTorrent(ctx, hash); err != nil {
+			j.logger.Error("failed to resume download",
+				"client", clientName,
+				"hash", hash,
+				"error", err,
+			)
+			continue
+		}
+
+		delete(paused, hash)
+		j.logger.Info("resumed download, active downloads below the limit", "client", clientName, "hash", hash)
+	}
+}
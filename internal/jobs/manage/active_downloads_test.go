@@ -0,0 +1,73 @@
+package manage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+)
+
+func TestActiveDownloadsPausesNewestExcess(t *testing.T) {
+	now := time.Now()
+	client := newFakeDownloadClient(
+		downloadclient.Torrent{Hash: "oldest", State: downloadclient.StateDownloading, AddedOn: now.Add(-4 * time.Hour)},
+		downloadclient.Torrent{Hash: "older", State: downloadclient.StateStalled, AddedOn: now.Add(-3 * time.Hour)},
+		downloadclient.Torrent{Hash: "newer", State: downloadclient.StateDownloading, AddedOn: now.Add(-2 * time.Hour)},
+		downloadclient.Torrent{Hash: "newest", State: downloadclient.StateDownloading, AddedOn: now.Add(-time.Hour)},
+		downloadclient.Torrent{Hash: "seeding", State: downloadclient.StateSeeding, Progress: 1, AddedOn: now},
+		downloadclient.Torrent{Hash: "queued", State: downloadclient.StateQueued, AddedOn: now},
+	)
+
+	cfg := testConfig()
+	cfg.JobDefaults.MaxActiveDownloads = 2
+	m := newTestManager(t, cfg, nil)
+	m.RegisterDownloadClient("qbittorrent", client)
+
+	job := NewActiveDownloadsJob("limit_active_downloads", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+
+	// Four active downloads with a limit of two: the two newest are paused
+	require.NoError(t, job.Run(context.Background()))
+	assert.Equal(t, map[string]bool{"newer": true, "newest": true}, client.paused)
+	assert.Equal(t, 2, job.Stats().Removed)
+
+	// Reflect the pause in the client and let one download finish
+	client.mu.Lock()
+	for i := range client.torrents {
+		switch client.torrents[i].Hash {
+		case "newer", "newest":
+			client.torrents[i].State = downloadclient.StatePaused
+		case "oldest":
+			client.torrents[i].State = downloadclient.StateSeeding
+			client.torrents[i].Progress = 1
+		}
+	}
+	client.mu.Unlock()
+
+	// One slot is free, so the oldest torrent the job paused is resumed
+	require.NoError(t, job.Run(context.Background()))
+	assert.Equal(t, map[string]bool{"newer": true}, client.resumed)
+}
+
+func TestActiveDownloadsTestRun(t *testing.T) {
+	client := newFakeDownloadClient(
+		downloadclient.Torrent{Hash: "a", State: downloadclient.StateDownloading},
+		downloadclient.Torrent{Hash: "b", State: downloadclient.StateDownloading},
+	)
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, nil)
+	m.RegisterDownloadClient("qbittorrent", client)
+
+	limit := 1
+	jobCfg := &config.JobConfig{Enabled: true, MaxActiveDownloads: &limit}
+	job := NewActiveDownloadsJob("limit_active_downloads", jobCfg, &cfg.JobDefaults, m, testLogger(), true)
+
+	require.NoError(t, job.Run(context.Background()))
+	assert.Empty(t, client.paused, "test run must not pause downloads")
+	assert.Equal(t, 1, job.Stats().Found)
+}
@@ -0,0 +1,181 @@
+package manage
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+// DiskSpaceJob pauses downloads the *arr reports as failing for lack of disk
+// space. Removing and blocklisting them wouldn't help while the disk is full,
+// so they are paused and a warning is logged for the user to free up space.
+type DiskSpaceJob struct {
+	base
+	cfg    *config.JobConfig
+	paused map[string]bool // download IDs this job has paused
+}
+
+// NewDiskSpaceJob creates a new disk space warning job
+func NewDiskSpaceJob(
+	name string,
+	cfg *config.JobConfig,
+	manager *jobs.Manager,
+	logger *slog.Logger,
+	testRun bool,
+) *DiskSpaceJob {
+	return &DiskSpaceJob{
+		base:   newBase(name, cfg.Enabled, jobInterval(cfg), cfg.Order, manager, logger, testRun),
+		cfg:    cfg,
+		paused: make(map[string]bool),
+	}
+}
+
+// diskSpaceKeywords contains keywords indicating the download ran out of disk space
+var diskSpaceKeywords = []string{
+	"no space",
+	"not enough free space",
+	"disk full",
+}
+
+// FindAffected identifies queue items with disk space warnings
+func (j *DiskSpaceJob) FindAffected(queue []arrapi.QueueItem) []arrapi.QueueItem {
+	var affected []arrapi.QueueItem
+	for _, item := range queue {
+		if j.hasDiskSpaceIssue(item) {
+			affected = append(affected, item)
+		}
+	}
+	return affected
+}
+
+// Detect returns the items of an instance's queue a run would pause
+func (j *DiskSpaceJob) Detect(instanceName string, queue []arrapi.QueueItem, _ map[string]downloadclient.Torrent) []arrapi.QueueItem {
+	if !jobs.InInstances(j.cfg.Instances, instanceName) {
+		return nil
+	}
+	return j.FindAffected(jobs.FilterProtocols(queue, j.cfg.Protocols))
+}
+
+// hasDiskSpaceIssue checks a queue item's messages for disk space keywords
+func (j *DiskSpaceJob) hasDiskSpaceIssue(item arrapi.QueueItem) bool {
+	if containsDiskSpaceKeyword(item.ErrorMessage) {
+		return true
+	}
+
+	for _, statusMsg := range item.StatusMessages {
+		if containsDiskSpaceKeyword(statusMsg.Title) {
+			return true
+		}
+		for _, msg := range statusMsg.Messages {
+			if containsDiskSpaceKeyword(msg) {
+				return true
+			}
+		}
+	}
+
+	// User-defined patterns augment the built-in keywords
+	return jobs.MatchesMessages(item, j.cfg.MessagePatterns)
+}
+
+// containsDiskSpaceKeyword reports whether text mentions running out of disk space
+func containsDiskSpaceKeyword(text string) bool {
+	textLower := strings.ToLower(text)
+	for _, keyword := range diskSpaceKeywords {
+		if strings.Contains(textLower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run executes the disk space warning job
+func (j *DiskSpaceJob) Run(ctx context.Context) error {
+	j.logger.Debug("starting disk space job", "test_run", j.testRun)
+
+	queues, queueErr := j.manager.GetAllQueues(ctx)
+	queues = jobs.ScopeQueues(queues, j.cfg.Instances)
+
+	totalFound := 0
+	totalPaused := 0
+	seen := make(map[string]bool)
+
+	for instanceName, queue := range queues {
+		affected := j.Detect(instanceName, queue, nil)
+		j.logger.Debug("found items with disk space warnings",
+			"instance", instanceName,
+			"count", len(affected),
+		)
+
+		for _, item := range affected {
+			totalFound++
+			seen[item.DownloadID] = true
+
+			if j.paused[item.DownloadID] {
+				j.logger.Debug("download already paused for lack of disk space",
+					"title", item.Title,
+					"download_id", item.DownloadID,
+				)
+				continue
+			}
+
+			reason := jobs.QueueItemReason(item, "out of disk space")
+
+			if j.testRun {
+				j.logger.Warn("[TEST RUN] would pause download, the download client is out of disk space",
+					"title", item.Title,
+					"download_id", item.DownloadID,
+					"reason", reason,
+					"instance", instanceName,
+				)
+				totalPaused++
+				continue
+			}
+
+			if err := j.manager.PauseDownload(ctx, item.DownloadID); err != nil {
+				j.logger.Warn("download client is out of disk space but the download could not be paused, free up space",
+					"title", item.Title,
+					"download_id", item.DownloadID,
+					"reason", reason,
+					"instance", instanceName,
+					"error", err,
+				)
+				continue
+			}
+
+			j.paused[item.DownloadID] = true
+			totalPaused++
+
+			j.logger.Warn("paused download, the download client is out of disk space; free up space and resume it",
+				"title", item.Title,
+				"download_id", item.DownloadID,
+				"reason", reason,
+				"instance", instanceName,
+			)
+		}
+	}
+
+	// Forget downloads that no longer report disk space issues, unless some
+	// queues couldn't be fetched and their downloads may still be affected
+	if queueErr == nil {
+		for downloadID := range j.paused {
+			if !seen[downloadID] {
+				delete(j.paused, downloadID)
+			}
+		}
+	}
+
+	j.logger.Debug("disk space job completed",
+		"found", totalFound,
+		"paused", totalPaused,
+		"test_run", j.testRun)
+
+	j.lastFound = totalFound
+	j.lastActed = totalPaused
+
+	return jobs.QueueError(queueErr)
+}
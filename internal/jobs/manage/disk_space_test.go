@@ -0,0 +1,100 @@
+package manage
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+)
+
+func TestDiskSpacePausesInsteadOfRemoving(t *testing.T) {
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		{
+			ID:         1,
+			Title:      "Disk.Full.Item",
+			DownloadID: "diskfull",
+			StatusMessages: []arrapi.StatusMessage{{
+				Title:    "Disk.Full.Item",
+				Messages: []string{"Not enough free space on /downloads to import"},
+			}},
+		},
+		{ID: 2, Title: "Healthy.Item", DownloadID: "healthy"},
+	})
+
+	client := newFakeDownloadClient(
+		downloadclient.Torrent{Hash: "diskfull", State: downloadclient.StateDownloading},
+		downloadclient.Torrent{Hash: "healthy", State: downloadclient.StateDownloading},
+	)
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, map[string]*fakeArr{"radarr": arr})
+	m.RegisterDownloadClient("qbittorrent", client)
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	job := NewDiskSpaceJob("pause_no_space", &config.JobConfig{Enabled: true}, m, logger, false)
+
+	require.NoError(t, job.Run(context.Background()))
+
+	assert.Equal(t, map[string]bool{"diskfull": true}, client.paused)
+	assert.Equal(t, 0, arr.deleteCount(), "disk space items must not be removed")
+	_, deleted := client.wasDeleted("diskfull")
+	assert.False(t, deleted)
+	assert.Contains(t, logs.String(), "level=WARN")
+	assert.Contains(t, logs.String(), "out of disk space")
+	assert.Contains(t, logs.String(), "download_id=diskfull")
+	assert.Equal(t, 1, job.Stats().Removed)
+
+	// The warning is raised once per download, not every cycle
+	logs.Reset()
+	require.NoError(t, job.Run(context.Background()))
+	assert.Empty(t, logs.String())
+}
+
+func TestDiskSpaceKeywords(t *testing.T) {
+	tests := []struct {
+		name string
+		item arrapi.QueueItem
+		want bool
+	}{
+		{
+			name: "no space left on device",
+			item: arrapi.QueueItem{ErrorMessage: "Write failed: No space left on device"},
+			want: true,
+		},
+		{
+			name: "disk full in status message",
+			item: arrapi.QueueItem{StatusMessages: []arrapi.StatusMessage{{Messages: []string{"Download paused: disk full"}}}},
+			want: true,
+		},
+		{
+			name: "unrelated warning",
+			item: arrapi.QueueItem{ErrorMessage: "Unable to parse file"},
+			want: false,
+		},
+		{
+			name: "sparse item",
+			item: arrapi.QueueItem{ID: 1, StatusMessages: []arrapi.StatusMessage{{}}},
+			want: false,
+		},
+	}
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, nil)
+	job := NewDiskSpaceJob("pause_no_space", &config.JobConfig{Enabled: true}, m, testLogger(), true)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			affected := job.FindAffected([]arrapi.QueueItem{tt.item})
+			assert.Equal(t, tt.want, len(affected) == 1)
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package manage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+// DoneSeedingJob removes completed torrents that have met their seeding goals
+type DoneSeedingJob struct {
+	base
+	cfg *config.RemoveDoneSeedingConfig
+}
+
+// NewDoneSeedingJob creates a new done seeding removal job
+func NewDoneSeedingJob(
+	name string,
+	cfg *config.RemoveDoneSeedingConfig,
+	manager *jobs.Manager,
+	logger *slog.Logger,
+	testRun bool,
+) *DoneSeedingJob {
+	return &DoneSeedingJob{
+		base: newBase(name, cfg.Enabled, cfg.Interval, cfg.Order, manager, logger, testRun),
+		cfg:  cfg,
+	}
+}
+
+// Run executes the done seeding removal job
+func (j *DoneSeedingJob) Run(ctx context.Context) error {
+	j.logger.Debug("starting done seeding removal job",
+		"test_run", j.testRun,
+		"target_tags", j.cfg.TargetTags,
+		"target_categories", j.cfg.TargetCategories)
+
+	// Get all download clients
+	downloadClients := j.manager.GetAllDownloadClients()
+	if len(downloadClients) == 0 {
+		j.logger.Warn("no download clients registered, skipping done seeding check")
+		return nil
+	}
+
+	foundCount := 0
+	removedCount := 0
+
+	for clientName, client := range downloadClients {
+		if !jobs.InInstances(j.cfg.Instances, clientName) {
+			continue
+		}
+
+		// Seeding goals only apply to clients whose downloads seed, so
+		// usenet clients such as SABnzbd and NZBGet are skipped
+		if seeder, ok := client.(downloadclient.SeedingClient); !ok || !seeder.SeedingCapable() {
+			j.logger.Debug("skipping client that doesn't seed",
+				"client", clientName,
+				"type", client.Name())
+			continue
+		}
+
+		torrents, err := client.GetTorrents(ctx)
+		if err != nil {
+			j.logger.Error("failed to get torrents from client",
+				"client", clientName,
+				"error", err)
+			continue
+		}
+
+		j.logger.Debug("retrieved torrents from download client",
+			"client", clientName,
+			"count", len(torrents))
+
+		var candidates []downloadclient.Torrent
+		var hashes []string
+		for _, torrent := range torrents {
+			// Check if torrent matches target categories or tags
+			if !j.matchesTarget(&torrent) {
+				continue
+			}
+
+			// Exclusions win over targets, so favourites are never removed
+			if j.isExcluded(&torrent) {
+				j.logger.Debug("torrent excluded from done seeding removal",
+					"client", clientName,
+					"hash", torrent.Hash,
+					"name", torrent.Name,
+					"category", torrent.Category,
+					"tags", torrent.Tags)
+				continue
+			}
+
+			// Check if torrent is in a completed seeding state
+			// We need to check against qBittorrent's raw states
+			if !j.isCompletedState(&torrent) {
+				continue
+			}
+
+			candidates = append(candidates, torrent)
+			hashes = append(hashes, torrent.Hash)
+		}
+
+		// Get torrent properties to check seeding limits. Clients that don't
+		// report properties have no limits of their own, so only the
+		// configured goals apply.
+		var properties map[string]downloadclient.PropertiesResult
+		if propsClient, ok := client.(downloadclient.PropertiesClient); ok {
+			properties = downloadclient.FetchProperties(ctx, propsClient, hashes, j.manager.GetConfig().General.PropertyWorkers)
+		}
+
+		for _, torrent := range candidates {
+			props := &downloadclient.TorrentProperties{}
+			if properties != nil {
+				result := properties[torrent.Hash]
+				switch {
+				case errors.Is(result.Err, downloadclient.ErrUnsupported):
+					// No limits of its own, as if it weren't a PropertiesClient
+				case result.Err != nil:
+					j.logger.Warn("failed to get torrent properties, skipping",
+						"hash", torrent.Hash,
+						"name", torrent.Name,
+						"error", result.Err)
+					continue
+				default:
+					props = result.Properties
+				}
+			}
+
+			// Check if seeding goals are met
+			if !j.seedingGoalsMet(&torrent, props) {
+				continue
+			}
+
+			foundCount++
+			j.logger.Debug("found torrent that completed seeding",
+				"client", clientName,
+				"hash", torrent.Hash,
+				"name", torrent.Name,
+				"ratio", torrent.Ratio,
+				"ratio_limit", props.RatioLimit,
+				"seed_time", torrent.SeedTime,
+				"seed_time_limit", time.Duration(props.SeedingTimeLimit)*time.Second)
+
+			// Remove from download client if not in test run mode
+			if !j.testRun {
+				if err := j.manager.DeleteClientTorrent(ctx, clientName, client, &torrent, j.cfg.DeleteFiles != nil && *j.cfg.DeleteFiles); err != nil {
+					j.logger.Error("failed to remove torrent",
+						"hash", torrent.Hash,
+						"error", err)
+					continue
+				}
+
+				j.logger.Info("removed torrent that completed seeding",
+					"hash", torrent.Hash,
+					"name", torrent.Name,
+					"ratio", torrent.Ratio,
+					"seed_time", torrent.SeedTime)
+				j.manager.RecordRemoval(jobs.RemovedDownload{
+					Job:        j.name,
+					Source:     clientName,
+					DownloadID: torrent.Hash,
+					Name:       torrent.Name,
+					Reason:     "done seeding",
+				})
+
+				removedCount++
+			} else {
+				j.logger.Info("[TEST RUN] would remove torrent that completed seeding",
+					"hash", torrent.Hash,
+					"name", torrent.Name,
+					"ratio", torrent.Ratio,
+					"seed_time", torrent.SeedTime)
+				removedCount++
+			}
+		}
+	}
+
+	j.logger.Debug("done seeding removal job completed",
+		"found", foundCount,
+		"removed", removedCount,
+		"test_run", j.testRun)
+
+	j.lastFound = foundCount
+	j.lastActed = removedCount
+
+	return nil
+}
+
+// matchesTarget checks if torrent matches target categories or tags
+func (j *DoneSeedingJob) matchesTarget(torrent *downloadclient.Torrent) bool {
+	// Check if category matches
+	if len(j.cfg.TargetCategories) > 0 {
+		for _, category := range j.cfg.TargetCategories {
+			if torrent.Category == category {
+				return true
+			}
+		}
+	}
+
+	// Check if any tag matches
+	if len(j.cfg.TargetTags) > 0 {
+		for _, targetTag := range j.cfg.TargetTags {
+			for _, torrentTag := range torrent.Tags {
+				if torrentTag == targetTag {
+					return true
+				}
+			}
+		}
+	}
+
+	// If no target categories or tags configured, match all
+	if len(j.cfg.TargetCategories) == 0 && len(j.cfg.TargetTags) == 0 {
+		return true
+	}
+
+	return false
+}
+
+// isExcluded checks if the torrent has an excluded category or tag
+func (j *DoneSeedingJob) isExcluded(torrent *downloadclient.Torrent) bool {
+	for _, category := range j.cfg.ExcludeCategories {
+		if strings.EqualFold(torrent.Category, category) {
+			return true
+		}
+	}
+
+	for _, excludeTag := range j.cfg.ExcludeTags {
+		for _, torrentTag := range torrent.Tags {
+			if strings.EqualFold(torrentTag, excludeTag) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isCompletedState checks if the torrent is in a completed seeding state
+// For qBittorrent, completed states are "stoppedUP" and "pausedUP"
+// Since we only have the mapped state, we check if it's paused/seeding
+func (j *DoneSeedingJob) isCompletedState(torrent *downloadclient.Torrent) bool {
+	// A torrent is considered "done" if it's fully downloaded (progress = 1.0)
+	// and in a paused or seeding state
+	if torrent.Progress < 1.0 {
+		return false
+	}
+
+	// Check if in paused or seeding state (mapped from stoppedUP/pausedUP)
+	return torrent.State == downloadclient.StatePaused || torrent.State == downloadclient.StateSeeding
+}
+
+// seedingGoalsMet checks if the torrent has met its seeding goals. The
+// client's own limits take precedence; when it sets none, the configured goal
+// for the torrent's category or tags is used instead.
+func (j *DoneSeedingJob) seedingGoalsMet(torrent *downloadclient.Torrent, props *downloadclient.TorrentProperties) bool {
+	// Limits must be > 0 to be active
+	if props.RatioLimit <= 0 && props.SeedingTimeLimit <= 0 {
+		goal, key, ok := j.goalFor(torrent)
+		if !ok {
+			return false
+		}
+		j.logger.Debug("client has no seeding limits, using configured goal",
+			"hash", torrent.Hash,
+			"goal", key,
+			"ratio_goal", goal.Ratio,
+			"seed_time_goal", goal.SeedTime)
+		return j.limitsMet(torrent, goal.Ratio, goal.SeedTime)
+	}
+
+	return j.limitsMet(torrent, props.RatioLimit, time.Duration(props.SeedingTimeLimit)*time.Second)
+}
+
+// limitsMet reports whether the torrent has reached either limit. A limit of
+// 0 or less is not active.
+func (j *DoneSeedingJob) limitsMet(torrent *downloadclient.Torrent, ratioLimit float64, seedTimeLimit time.Duration) bool {
+	if ratioLimit > 0 && torrent.Ratio >= ratioLimit {
+		j.logger.Debug("ratio limit met",
+			"hash", torrent.Hash,
+			"ratio", torrent.Ratio,
+			"limit", ratioLimit)
+		return true
+	}
+
+	if seedTimeLimit > 0 && torrent.SeedTime >= seedTimeLimit {
+		j.logger.Debug("seeding time limit met",
+			"hash", torrent.Hash,
+			"seed_time", torrent.SeedTime,
+			"limit", seedTimeLimit)
+		return true
+	}
+
+	return false
+}
+
+// goalFor returns the configured seeding goal for the torrent's category, or
+// failing that its first tag with a goal. Keys are matched case-insensitively
+// since the config loader lowercases map keys.
+func (j *DoneSeedingJob) goalFor(torrent *downloadclient.Torrent) (config.SeedingGoal, string, bool) {
+	if len(j.cfg.Goals) == 0 {
+		return config.SeedingGoal{}, "", false
+	}
+
+	candidates := append([]string{torrent.Category}, torrent.Tags...)
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		for key, goal := range j.cfg.Goals {
+			if strings.EqualFold(key, candidate) {
+				return goal, key, true
+			}
+		}
+	}
+
+	return config.SeedingGoal{}, "", false
+}
@@ -0,0 +1,258 @@
+package manage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+)
+
+func TestDoneSeedingGoals(t *testing.T) {
+	seeding := func(hash string, ratio float64, seedTime time.Duration, category string, tags ...string) downloadclient.Torrent {
+		return downloadclient.Torrent{
+			Hash:     hash,
+			State:    downloadclient.StateSeeding,
+			Progress: 1,
+			Ratio:    ratio,
+			SeedTime: seedTime,
+			Category: category,
+			Tags:     tags,
+		}
+	}
+
+	client := newFakeDownloadClient(
+		// public goal is ratio 1.0
+		seeding("public-done", 1.2, time.Hour, "", "public"),
+		seeding("public-seeding", 0.5, 30*24*time.Hour, "", "public"),
+		// private goal is 14 days seeding
+		seeding("private-done", 0.1, 15*24*time.Hour, "", "Private"),
+		seeding("private-seeding", 5, 24*time.Hour, "", "private"),
+		// category goal applies ahead of tags
+		seeding("category-done", 0.6, time.Hour, "tv", "private"),
+		// no goal and no client limit: kept
+		seeding("untagged", 10, 100*24*time.Hour, ""),
+		// client limit takes precedence over the tag goal
+		seeding("client-limit", 1.5, time.Hour, "", "public"),
+	)
+	client.props["client-limit"] = &downloadclient.TorrentProperties{RatioLimit: 2}
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, nil)
+	m.RegisterDownloadClient("qbittorrent", client)
+
+	jobCfg := &config.RemoveDoneSeedingConfig{
+		Enabled: true,
+		Goals: map[string]config.SeedingGoal{
+			"public":  {Ratio: 1},
+			"private": {SeedTime: 14 * 24 * time.Hour},
+			"tv":      {Ratio: 0.5},
+		},
+	}
+	job := NewDoneSeedingJob("remove_done_seeding", jobCfg, m, testLogger(), false)
+
+	require.NoError(t, job.Run(context.Background()))
+
+	for _, hash := range []string{"public-done", "private-done", "category-done"} {
+		_, ok := client.wasDeleted(hash)
+		assert.True(t, ok, "%s should have met its goal", hash)
+	}
+	for _, hash := range []string{"public-seeding", "private-seeding", "untagged", "client-limit"} {
+		_, ok := client.wasDeleted(hash)
+		assert.False(t, ok, "%s should still be seeding", hash)
+	}
+	assert.Equal(t, 3, job.Stats().Removed)
+}
+
+func TestDoneSeedingSeedingCapableClients(t *testing.T) {
+	done := downloadclient.Torrent{
+		Hash:     "done",
+		State:    downloadclient.StateSeeding,
+		Progress: 1,
+		Ratio:    2,
+		Tags:     []string{"public"},
+	}
+
+	// Any client that seeds is processed, whatever it is called
+	deluge := newFakeDownloadClient(done)
+	deluge.name = "Deluge"
+	// Usenet clients never seed
+	sabnzbd := newFakeDownloadClient(done)
+	sabnzbd.name = "SABnzbd"
+	sabnzbd.seeds = false
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, nil)
+	m.RegisterDownloadClient("deluge", deluge)
+	m.RegisterDownloadClient("sabnzbd", sabnzbd)
+
+	jobCfg := &config.RemoveDoneSeedingConfig{
+		Enabled: true,
+		Goals:   map[string]config.SeedingGoal{"public": {Ratio: 1}},
+	}
+	job := NewDoneSeedingJob("remove_done_seeding", jobCfg, m, testLogger(), false)
+	require.NoError(t, job.Run(context.Background()))
+
+	_, ok := deluge.wasDeleted("done")
+	assert.True(t, ok, "seeding-capable client should be processed")
+	_, ok = sabnzbd.wasDeleted("done")
+	assert.False(t, ok, "usenet client should be skipped")
+}
+
+func TestDoneSeedingInstances(t *testing.T) {
+	done := downloadclient.Torrent{
+		Hash:     "done",
+		State:    downloadclient.StateSeeding,
+		Progress: 1,
+		Ratio:    2,
+		Tags:     []string{"public"},
+	}
+
+	scoped := newFakeDownloadClient(done)
+	other := newFakeDownloadClient(done)
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, nil)
+	m.RegisterDownloadClient("qbittorrent", scoped)
+	m.RegisterDownloadClient("qbittorrent-4k", other)
+
+	jobCfg := &config.RemoveDoneSeedingConfig{
+		Enabled:   true,
+		Goals:     map[string]config.SeedingGoal{"public": {Ratio: 1}},
+		Instances: []string{"QBittorrent"},
+	}
+	job := NewDoneSeedingJob("remove_done_seeding", jobCfg, m, testLogger(), false)
+	require.NoError(t, job.Run(context.Background()))
+
+	_, ok := scoped.wasDeleted("done")
+	assert.True(t, ok, "client in scope should be processed")
+	_, ok = other.wasDeleted("done")
+	assert.False(t, ok, "client out of scope should be skipped")
+}
+
+func TestDoneSeedingCapabilities(t *testing.T) {
+	done := downloadclient.Torrent{
+		Hash:     "done",
+		State:    downloadclient.StateSeeding,
+		Progress: 1,
+		Ratio:    2,
+		Tags:     []string{"public"},
+	}
+
+	// Without the seeding capability the client is skipped
+	bare := newFakeDownloadClient(done)
+	// Without properties only the configured goals apply
+	seedingOnly := newFakeDownloadClient(done)
+	unsupported := newFakeDownloadClient(done)
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, nil)
+	m.RegisterDownloadClient("bare", bareClient{bare})
+	m.RegisterDownloadClient("seeding", seedingOnlyClient{seedingOnly, seedingOnly})
+	m.RegisterDownloadClient("unsupported", unsupportedPropsClient{unsupported})
+
+	jobCfg := &config.RemoveDoneSeedingConfig{
+		Enabled: true,
+		Goals:   map[string]config.SeedingGoal{"public": {Ratio: 1}},
+	}
+	job := NewDoneSeedingJob("remove_done_seeding", jobCfg, m, testLogger(), false)
+	require.NoError(t, job.Run(context.Background()))
+
+	_, ok := bare.wasDeleted("done")
+	assert.False(t, ok, "client without the seeding capability should be skipped")
+	_, ok = seedingOnly.wasDeleted("done")
+	assert.True(t, ok, "client without properties should be held to the configured goals")
+	_, ok = unsupported.wasDeleted("done")
+	assert.True(t, ok, "unsupported properties should be treated as no limits, not a failure")
+}
+
+// unsupportedPropsClient is a fake whose properties are unsupported
+type unsupportedPropsClient struct {
+	*fakeDownloadClient
+}
+
+func (c unsupportedPropsClient) GetTorrentProperties(ctx context.Context, hash string) (*downloadclient.TorrentProperties, error) {
+	return nil, downloadclient.ErrUnsupported
+}
+
+func TestDoneSeedingExclusions(t *testing.T) {
+	done := func(hash, category string, tags ...string) downloadclient.Torrent {
+		return downloadclient.Torrent{
+			Hash:     hash,
+			State:    downloadclient.StateSeeding,
+			Progress: 1,
+			Ratio:    2,
+			Category: category,
+			Tags:     tags,
+		}
+	}
+
+	client := newFakeDownloadClient(
+		done("targeted", "tv-sonarr", "completed"),
+		// Targeted by both category and tag, but also excluded
+		done("favorite", "tv-sonarr", "completed", "Favorites"),
+		done("kept-category", "keep", "completed"),
+	)
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, nil)
+	m.RegisterDownloadClient("qbittorrent", client)
+
+	jobCfg := &config.RemoveDoneSeedingConfig{
+		Enabled:           true,
+		TargetTags:        []string{"completed"},
+		TargetCategories:  []string{"tv-sonarr"},
+		ExcludeTags:       []string{"favorites"},
+		ExcludeCategories: []string{"keep"},
+		Goals:             map[string]config.SeedingGoal{"completed": {Ratio: 1}},
+	}
+	job := NewDoneSeedingJob("remove_done_seeding", jobCfg, m, testLogger(), false)
+	require.NoError(t, job.Run(context.Background()))
+
+	_, ok := client.wasDeleted("targeted")
+	assert.True(t, ok, "targeted torrent that met its goal should be removed")
+	for _, hash := range []string{"favorite", "kept-category"} {
+		_, ok := client.wasDeleted(hash)
+		assert.False(t, ok, "%s is excluded and should be kept", hash)
+	}
+}
+
+func TestDoneSeedingDeleteFilesPolicy(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		deleteFiles *bool
+		want        bool
+	}{
+		{name: "default keeps files", deleteFiles: nil, want: false},
+		{name: "delete files", deleteFiles: boolPtr(true), want: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeDownloadClient(downloadclient.Torrent{
+				Hash:     "done",
+				State:    downloadclient.StateSeeding,
+				Progress: 1,
+				Ratio:    2,
+				Category: "tv-sonarr",
+			})
+			m := newTestManager(t, testConfig(), nil)
+			m.RegisterDownloadClient("qbittorrent", client)
+
+			jobCfg := &config.RemoveDoneSeedingConfig{
+				Enabled:          true,
+				TargetCategories: []string{"tv-sonarr"},
+				Goals:            map[string]config.SeedingGoal{"tv-sonarr": {Ratio: 1}},
+				DeleteFiles:      tt.deleteFiles,
+			}
+			job := NewDoneSeedingJob("remove_done_seeding", jobCfg, m, testLogger(), false)
+			require.NoError(t, job.Run(context.Background()))
+
+			deleteFiles, ok := client.wasDeleted("done")
+			require.True(t, ok)
+			assert.Equal(t, tt.want, deleteFiles)
+		})
+	}
+}
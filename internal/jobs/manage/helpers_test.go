@@ -0,0 +1,229 @@
+package manage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+// testLogger returns a logger that discards all output
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// boolPtr returns a pointer to b
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// fakeArr is an httptest-backed *arr instance serving a fixed queue and
+// counting queue deletions, which manage jobs must never make
+type fakeArr struct {
+	server  *httptest.Server
+	mu      sync.Mutex
+	queue   []arrapi.QueueItem
+	deletes int
+}
+
+// newFakeArr starts a fake *arr server with the given queue
+func newFakeArr(t *testing.T, queue []arrapi.QueueItem) *fakeArr {
+	t.Helper()
+
+	f := &fakeArr{queue: queue}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+func (f *fakeArr) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v3/queue":
+		f.mu.Lock()
+		resp := arrapi.QueueResponse{
+			Page:         1,
+			PageSize:     1000,
+			TotalRecords: len(f.queue),
+			Records:      f.queue,
+		}
+		f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(resp)
+	case r.Method == http.MethodDelete:
+		f.mu.Lock()
+		f.deletes++
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// deleteCount returns the number of queue items deleted
+func (f *fakeArr) deleteCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.deletes
+}
+
+// fakeDownloadClient is an in-memory download client
+type fakeDownloadClient struct {
+	mu       sync.Mutex
+	name     string
+	torrents []downloadclient.Torrent
+	props    map[string]*downloadclient.TorrentProperties
+	deleted  map[string]bool // hash -> deleteFiles
+	paused   map[string]bool
+	resumed  map[string]bool
+	seeds    bool // whether the client reports itself seeding capable
+}
+
+// newFakeDownloadClient creates a fake qBittorrent-like client with the given torrents
+func newFakeDownloadClient(torrents ...downloadclient.Torrent) *fakeDownloadClient {
+	return &fakeDownloadClient{
+		name:     "qBittorrent",
+		torrents: torrents,
+		props:    make(map[string]*downloadclient.TorrentProperties),
+		deleted:  make(map[string]bool),
+		paused:   make(map[string]bool),
+		resumed:  make(map[string]bool),
+		seeds:    true,
+	}
+}
+
+// bareClient exposes only the core Client methods of a fake, hiding its
+// optional capabilities
+type bareClient struct {
+	downloadclient.Client
+}
+
+// seedingOnlyClient is a bare client that can seed, but has no properties or tags
+type seedingOnlyClient struct {
+	downloadclient.Client
+	downloadclient.SeedingClient
+}
+
+func (c *fakeDownloadClient) Name() string {
+	return c.name
+}
+
+func (c *fakeDownloadClient) SeedingCapable() bool {
+	return c.seeds
+}
+
+func (c *fakeDownloadClient) GetTorrents(ctx context.Context) ([]downloadclient.Torrent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := make([]downloadclient.Torrent, len(c.torrents))
+	copy(result, c.torrents)
+	return result, nil
+}
+
+func (c *fakeDownloadClient) GetTorrent(ctx context.Context, hash string) (*downloadclient.Torrent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range c.torrents {
+		if t.Hash == hash {
+			torrent := t
+			return &torrent, nil
+		}
+	}
+	return nil, fmt.Errorf("torrent not found: %s", hash)
+}
+
+func (c *fakeDownloadClient) DeleteTorrent(ctx context.Context, hash string, deleteFiles bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deleted[hash] = deleteFiles
+	return nil
+}
+
+func (c *fakeDownloadClient) PauseTorrent(ctx context.Context, hash string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused[hash] = true
+	return nil
+}
+
+func (c *fakeDownloadClient) ResumeTorrent(ctx context.Context, hash string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resumed[hash] = true
+	return nil
+}
+
+func (c *fakeDownloadClient) GetTorrentProperties(ctx context.Context, hash string) (*downloadclient.TorrentProperties, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if props, ok := c.props[hash]; ok {
+		return props, nil
+	}
+	return &downloadclient.TorrentProperties{}, nil
+}
+
+func (c *fakeDownloadClient) SetCategory(ctx context.Context, hash string, category string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.torrents {
+		if c.torrents[i].Hash == hash {
+			c.torrents[i].Category = category
+			return nil
+		}
+	}
+	return fmt.Errorf("torrent not found: %s", hash)
+}
+
+func (c *fakeDownloadClient) IsPrivateTracker(ctx context.Context, hash string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if props, ok := c.props[hash]; ok {
+		return props.IsPrivate, nil
+	}
+	return false, nil
+}
+
+// wasDeleted reports whether a torrent was deleted and with which deleteFiles flag
+func (c *fakeDownloadClient) wasDeleted(hash string) (deleteFiles bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	deleteFiles, ok = c.deleted[hash]
+	return deleteFiles, ok
+}
+
+// testConfig returns a minimal valid config for job tests
+func testConfig() *config.Config {
+	return &config.Config{
+		General: config.GeneralConfig{
+			PrivateTrackerHandling: "remove",
+			PublicTrackerHandling:  "remove",
+		},
+	}
+}
+
+// newTestManager creates a manager with the given arr instances registered
+func newTestManager(t *testing.T, cfg *config.Config, arrs map[string]*fakeArr) *jobs.Manager {
+	t.Helper()
+
+	m := jobs.NewManager(cfg, testLogger(), "")
+	for name, arr := range arrs {
+		m.RegisterArrClient(name, arrapi.NewClient(arrapi.ClientConfig{
+			Name:    name,
+			BaseURL: arr.server.URL,
+			APIKey:  "test",
+			Logger:  testLogger(),
+		}))
+	}
+	t.Cleanup(m.Close)
+
+	return m
+}
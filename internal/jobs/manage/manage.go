@@ -0,0 +1,84 @@
+// Package manage holds the jobs that manage downloads without removing them
+// from the *arr queues: enforcing seeding goals, pausing downloads when the
+// disk fills up and throttling the number of active downloads
+package manage
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+// base implements the parts of jobs.StatsJob, jobs.ScheduledJob and
+// jobs.OrderedJob every manage job shares
+type base struct {
+	name      string
+	enabled   bool
+	interval  time.Duration
+	order     *int
+	manager   *jobs.Manager
+	logger    *slog.Logger
+	testRun   bool
+	lastFound int
+	lastActed int // downloads removed, paused or otherwise acted on
+}
+
+// newBase creates the shared state of a manage job
+func newBase(name string, enabled bool, interval time.Duration, order *int, manager *jobs.Manager, logger *slog.Logger, testRun bool) base {
+	return base{
+		name:     name,
+		enabled:  enabled,
+		interval: interval,
+		order:    order,
+		manager:  manager,
+		logger:   logger.With("job", name),
+		testRun:  testRun,
+	}
+}
+
+// Name returns the job identifier
+func (b *base) Name() string {
+	return b.name
+}
+
+// Enabled returns whether the job is enabled
+func (b *base) Enabled() bool {
+	return b.enabled
+}
+
+// SetTestRun switches the job in or out of test-run mode
+func (b *base) SetTestRun(testRun bool) {
+	b.testRun = testRun
+}
+
+// Interval returns how often the job runs, or 0 to run every cycle
+func (b *base) Interval() time.Duration {
+	return b.interval
+}
+
+// Order returns the job's position in the cycle, defaulting to running with
+// the removal jobs before any searches
+func (b *base) Order() int {
+	if b.order != nil {
+		return *b.order
+	}
+	return jobs.RemovalJobOrder
+}
+
+// Stats returns the statistics from the last job run
+func (b *base) Stats() jobs.JobStats {
+	return jobs.JobStats{
+		Found:   b.lastFound,
+		Removed: b.lastActed,
+	}
+}
+
+// jobInterval returns how often a job should run, or 0 to run every cycle
+func jobInterval(cfg *config.JobConfig) time.Duration {
+	if cfg.Interval != nil {
+		return *cfg.Interval
+	}
+	return 0
+}
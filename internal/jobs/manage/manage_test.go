@@ -0,0 +1,60 @@
+package manage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+// manageJob is every interface the manager checks a job for
+type manageJob interface {
+	jobs.StatsJob
+	jobs.ScheduledJob
+	jobs.OrderedJob
+}
+
+var (
+	_ manageJob = (*ActiveDownloadsJob)(nil)
+	_ manageJob = (*DiskSpaceJob)(nil)
+	_ manageJob = (*DoneSeedingJob)(nil)
+)
+
+func TestJobsRegisterWithManager(t *testing.T) {
+	cfg := testConfig()
+	cfg.JobDefaults.MaxActiveDownloads = 1
+	m := newTestManager(t, cfg, map[string]*fakeArr{"radarr": newFakeArr(t, nil)})
+	m.RegisterDownloadClient("qbittorrent", newFakeDownloadClient(
+		downloadclient.Torrent{Hash: "first", State: downloadclient.StateDownloading},
+		downloadclient.Torrent{Hash: "second", State: downloadclient.StateDownloading},
+	))
+
+	order := 5
+	registered := []manageJob{
+		NewActiveDownloadsJob("limit_active_downloads", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), true),
+		NewDiskSpaceJob("pause_no_space", &config.JobConfig{Enabled: true, Order: &order}, m, testLogger(), true),
+		NewDoneSeedingJob("remove_done_seeding", &config.RemoveDoneSeedingConfig{Enabled: true}, m, testLogger(), true),
+	}
+	for _, job := range registered {
+		assert.True(t, job.Enabled(), job.Name())
+		assert.Zero(t, job.Interval(), job.Name())
+		m.RegisterJob(job)
+	}
+	assert.Equal(t, 5, registered[1].Order(), "a configured order should be kept")
+	assert.Equal(t, jobs.RemovalJobOrder, registered[0].Order(), "manage jobs run with the removal jobs by default")
+
+	require.NoError(t, m.RunAll(context.Background()))
+
+	stats := m.GetLastStats()
+	require.NotNil(t, stats)
+	assert.Equal(t, len(registered), stats.JobsRun)
+	for _, job := range registered {
+		assert.Contains(t, stats.ItemsFound, job.Name(), "%s should have run and reported its stats", job.Name())
+	}
+	assert.Equal(t, 1, stats.ItemsFound["limit_active_downloads"], "one download over the limit")
+}
@@ -2,15 +2,23 @@ package jobs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
 	"github.com/jmylchreest/go-decluttarr/internal/config"
 	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+	"github.com/jmylchreest/go-decluttarr/internal/processed"
+	"github.com/jmylchreest/go-decluttarr/internal/searches"
 	"github.com/jmylchreest/go-decluttarr/internal/strikes"
+	"github.com/jmylchreest/go-decluttarr/internal/tombstones"
 )
 
 // CycleStats tracks statistics for a single execution cycle
@@ -24,8 +32,37 @@ type CycleStats struct {
 	ItemsRemoved map[string]int // job name -> count removed
 	StrikesAdded int
 	StrikesReset int
-	TotalStrikes int
-	Errors       []string
+	// StrikesRecovered counts resets for downloads that recovered on their own
+	StrikesRecovered int
+	TotalStrikes     int
+	// Struck lists the downloads struck this cycle, with their strike limit
+	Struck []StruckDownload
+	// Removed lists the downloads removed this cycle, in removal order
+	Removed []RemovedDownload
+	Errors  []string
+}
+
+// RemovedDownload is a download removed during a cycle
+type RemovedDownload struct {
+	Job        string
+	Source     string // *arr instance or download client it was removed from
+	DownloadID string
+	Name       string
+	Reason     string
+}
+
+// StruckDownload is a download struck during a cycle
+type StruckDownload struct {
+	DownloadID string
+	Name       string
+	Job        string
+	Strikes    int
+	MaxStrikes int
+}
+
+// StrikesLeft returns the strikes remaining before the download is removed
+func (d StruckDownload) StrikesLeft() int {
+	return d.MaxStrikes - d.Strikes
 }
 
 // Manager coordinates job execution across multiple *arr instances and download clients
@@ -36,16 +73,61 @@ type Manager struct {
 	arrClients      map[string]*arrapi.Client // keyed by instance name
 	downloadClients map[string]downloadclient.Client
 	strikes         *strikes.Handler
+	searches        *searches.History
+	tombstones      *tombstones.Set
 	mu              sync.RWMutex
 	lastStats       *CycleStats
+	history         []*CycleStats // oldest first, capped at historySize
+	historySize     int
+	queuesComplete  bool                      // whether the last GetAllQueues reached every instance
+	lastRun         map[string]time.Time      // job name -> start of the cycle it last ran in
+	indexerFailures map[string]int            // "instance/indexer" -> removals since it was last disabled
+	removeFailures  map[string]int            // download ID -> failed *arr removals, for force_delete_after
+	processed       *processed.Set            // library entries removals tagged with processed_tag
+	clientsOffline  map[string]bool           // instances whose health showed no reachable download client this cycle
+	unreliable      map[string]bool           // instances whose data this cycle is known to be incomplete
+	struck          map[string]StruckDownload // download ID -> strikes checked this cycle
+	removed         []RemovedDownload         // downloads removed this cycle
+	clockOffsets    map[string]time.Duration  // instance -> how far its clock is ahead of ours
+	now             func() time.Time
 }
 
-// NewManager creates a new job manager with the given configuration
+// DefaultStatsHistorySize is the number of cycles retained when not configured
+const DefaultStatsHistorySize = 50
+
+// clockSkewTolerance is the clock offset below which an instance's
+// timestamps aren't corrected, as the Date header it's measured from has
+// one-second resolution
+const clockSkewTolerance = 2 * time.Second
+
+// DefaultProcessedTagTTL is how long the downloads of a library entry tagged
+// with general.processed_tag are skipped, when processed_tag_ttl isn't set
+const DefaultProcessedTagTTL = 24 * time.Hour
+
+// scheduleSlack allows for timer jitter when deciding whether a scheduled job is due
+const scheduleSlack = time.Second
+
+// NewManager creates a new job manager with the given configuration. The
+// search history, the tombstones of removed downloads and the library entries
+// tagged as processed are persisted as searches.json, tombstones.json and
+// processed.json alongside the strikes file.
 func NewManager(cfg *config.Config, logger *slog.Logger, strikesPath string) *Manager {
 	if logger == nil {
 		logger = slog.Default()
 	}
 
+	var searchesPath, tombstonesPath, processedPath string
+	if strikesPath != "" {
+		searchesPath = filepath.Join(filepath.Dir(strikesPath), "searches.json")
+		tombstonesPath = filepath.Join(filepath.Dir(strikesPath), "tombstones.json")
+		processedPath = filepath.Join(filepath.Dir(strikesPath), "processed.json")
+	}
+
+	historySize := cfg.General.StatsHistorySize
+	if historySize <= 0 {
+		historySize = DefaultStatsHistorySize
+	}
+
 	return &Manager{
 		cfg:             cfg,
 		logger:          logger.With("component", "job_manager"),
@@ -53,6 +135,18 @@ func NewManager(cfg *config.Config, logger *slog.Logger, strikesPath string) *Ma
 		arrClients:      make(map[string]*arrapi.Client),
 		downloadClients: make(map[string]downloadclient.Client),
 		strikes:         strikes.NewHandler(strikesPath, logger),
+		searches:        searches.NewHistory(searchesPath, logger),
+		tombstones:      tombstones.NewSet(tombstonesPath, logger),
+		historySize:     historySize,
+		lastRun:         make(map[string]time.Time),
+		indexerFailures: make(map[string]int),
+		removeFailures:  make(map[string]int),
+		processed:       processed.NewSet(processedPath, logger),
+		clientsOffline:  make(map[string]bool),
+		unreliable:      make(map[string]bool),
+		struck:          make(map[string]StruckDownload),
+		clockOffsets:    make(map[string]time.Duration),
+		now:             time.Now,
 	}
 }
 
@@ -62,23 +156,78 @@ func (m *Manager) RegisterJob(job Job) {
 	defer m.mu.Unlock()
 
 	m.jobs = append(m.jobs, job)
-	m.logger.Debug("registered job", "job", job.Name())
+	sort.SliceStable(m.jobs, func(a, b int) bool {
+		return jobOrder(m.jobs[a]) < jobOrder(m.jobs[b])
+	})
+	m.logger.Debug("registered job", "job", job.Name(), "order", jobOrder(job))
+}
+
+// jobOrder returns a job's position in the cycle, or 0 if it has none
+func jobOrder(job Job) int {
+	if oj, ok := job.(OrderedJob); ok {
+		return oj.Order()
+	}
+	return 0
+}
+
+// ClearJobs removes all registered jobs
+func (m *Manager) ClearJobs() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.jobs = make([]Job, 0)
+}
+
+// SetTestRun switches every registered job that supports it in or out of
+// test-run mode. Call it between cycles, not while one is running.
+func (m *Manager) SetTestRun(testRun bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, job := range m.jobs {
+		if tj, ok := job.(TestRunJob); ok {
+			tj.SetTestRun(testRun)
+		}
+	}
 }
 
+// ErrOutsideActiveHours is returned by RunAll when the cycle was skipped
+// because it fell outside general.active_hours
+var ErrOutsideActiveHours = errors.New("outside active hours")
+
 // RunAll executes all enabled jobs - GRACEFUL: continues on error
 func (m *Manager) RunAll(ctx context.Context) error {
+	if hours := m.cfg.General.ActiveHours; !hours.Contains(m.now()) {
+		m.logger.Debug("outside active hours, skipping cycle",
+			"start", hours.Start,
+			"end", hours.End,
+			"days", hours.Days)
+		return ErrOutsideActiveHours
+	}
+
 	m.mu.RLock()
 	jobs := m.jobs
 	m.mu.RUnlock()
 
 	// Initialize cycle stats
 	stats := &CycleStats{
-		StartTime:    time.Now(),
+		StartTime:    m.now(),
 		ItemsFound:   make(map[string]int),
 		ItemsRemoved: make(map[string]int),
 		Errors:       make([]string, 0),
 	}
 
+	m.mu.Lock()
+	m.unreliable = make(map[string]bool)
+	m.struck = make(map[string]StruckDownload)
+	m.removed = nil
+	m.mu.Unlock()
+
+	m.checkHealth(ctx)
+	if m.cfg.General.CorrectClockSkew {
+		m.measureClockSkew(ctx)
+	}
+
 	var errs []error
 	var failedJobs []string
 
@@ -88,6 +237,11 @@ func (m *Manager) RunAll(ctx context.Context) error {
 			continue
 		}
 
+		if !m.isDue(job, stats.StartTime) {
+			m.logger.Debug("skipping job, interval not elapsed", "job", job.Name())
+			continue
+		}
+
 		m.logger.Debug("running job", "job", job.Name())
 		stats.JobsRun++
 
@@ -110,12 +264,18 @@ func (m *Manager) RunAll(ctx context.Context) error {
 		}
 	}
 
+	m.clearRemovedExternally(ctx)
+
 	// Get strike stats and reset cycle counters
-	stats.StrikesAdded, stats.StrikesReset = m.strikes.ResetCycleCounters()
+	stats.StrikesAdded, stats.StrikesReset, stats.StrikesRecovered = m.strikes.ResetCycleCounters()
 	stats.TotalStrikes = m.strikes.Count()
+	stats.Struck = m.struckThisCycle()
+	m.mu.RLock()
+	stats.Removed = m.removed
+	m.mu.RUnlock()
 
 	// Finalize timing
-	stats.EndTime = time.Now()
+	stats.EndTime = m.now()
 	stats.Duration = stats.EndTime.Sub(stats.StartTime)
 
 	// Save strikes to disk
@@ -123,12 +283,32 @@ func (m *Manager) RunAll(ctx context.Context) error {
 		m.logger.Error("failed to save strikes", "error", err)
 	}
 
-	// Cleanup stale strikes (older than 7 days)
-	m.strikes.Cleanup(7 * 24 * time.Hour)
+	// Cleanup stale strikes
+	m.strikes.Cleanup(strikes.DefaultMaxAge)
+
+	if err := m.searches.Save(); err != nil {
+		m.logger.Error("failed to save search history", "error", err)
+	}
+	m.searches.Cleanup(searches.DefaultMaxAge)
+
+	if err := m.tombstones.Save(); err != nil {
+		m.logger.Error("failed to save tombstones", "error", err)
+	}
+	m.tombstones.Cleanup(tombstones.DefaultMaxAge)
+
+	m.cleanupProcessed(ctx)
+	if err := m.processed.Save(); err != nil {
+		m.logger.Error("failed to save processed entries", "error", err)
+	}
 
 	// Store stats for later access
 	m.mu.Lock()
 	m.lastStats = stats
+	m.history = append(m.history, stats)
+	if len(m.history) > m.historySize {
+		// Drop the oldest entries, copying so the backing array doesn't grow unbounded
+		m.history = append([]*CycleStats(nil), m.history[len(m.history)-m.historySize:]...)
+	}
 	m.mu.Unlock()
 
 	// Log cycle summary
@@ -141,12 +321,146 @@ func (m *Manager) RunAll(ctx context.Context) error {
 	return nil
 }
 
+// checkHealth logs each *arr instance's active health warnings and records
+// the instances that can't reach a download client, whose queues are skipped
+// this cycle so a queue that only looks broken isn't removed
+func (m *Manager) checkHealth(ctx context.Context) {
+	offline := make(map[string]bool)
+
+	for name, client := range m.GetAllArrClients() {
+		checks, err := client.GetHealth(ctx)
+		if err != nil {
+			m.logger.Debug("failed to get instance health", "instance", name, "error", err)
+			continue
+		}
+
+		for _, check := range checks {
+			if check.Type != arrapi.HealthWarning && check.Type != arrapi.HealthError {
+				continue
+			}
+			m.logger.Warn("instance health warning",
+				"instance", name,
+				"source", check.Source,
+				"type", check.Type,
+				"message", check.Message)
+		}
+
+		if arrapi.DownloadClientOffline(checks) {
+			m.logger.Warn("instance reports its download client is offline, skipping its queue this cycle",
+				"instance", name)
+			offline[name] = true
+		}
+	}
+
+	m.mu.Lock()
+	m.clientsOffline = offline
+	m.mu.Unlock()
+}
+
+// measureClockSkew records how far each *arr instance's clock is ahead of
+// ours, so GetAllQueues can correct the timestamps in its queue. An instance
+// whose clock can't be read is left uncorrected.
+func (m *Manager) measureClockSkew(ctx context.Context) {
+	m.mu.RLock()
+	clients := m.arrClients
+	m.mu.RUnlock()
+
+	offsets := make(map[string]time.Duration)
+	for name, client := range clients {
+		offset, err := client.ClockOffset(ctx)
+		if err != nil {
+			m.logger.Debug("failed to read instance clock, not correcting its timestamps",
+				"instance", name,
+				"error", err)
+			continue
+		}
+		if offset > -clockSkewTolerance && offset < clockSkewTolerance {
+			continue
+		}
+
+		m.logger.Warn("instance clock differs from local clock, correcting its queue timestamps",
+			"instance", name,
+			"offset", offset.Round(time.Second))
+		offsets[name] = offset
+	}
+
+	m.mu.Lock()
+	m.clockOffsets = offsets
+	m.mu.Unlock()
+}
+
+// withQueueDetails fills in each queue item's status and error messages from
+// queue/details, where it reports more of them than the paged queue
+func withQueueDetails(queue []arrapi.QueueItem, details []arrapi.QueueItem) {
+	byID := make(map[int]*arrapi.QueueItem, len(details))
+	for i := range details {
+		byID[details[i].ID] = &details[i]
+	}
+
+	for i := range queue {
+		detail, ok := byID[queue[i].ID]
+		if !ok {
+			continue
+		}
+		if messageCount(detail.StatusMessages) > messageCount(queue[i].StatusMessages) {
+			queue[i].StatusMessages = detail.StatusMessages
+		}
+		if queue[i].ErrorMessage == "" {
+			queue[i].ErrorMessage = detail.ErrorMessage
+		}
+	}
+}
+
+// messageCount counts the titles and messages in status messages
+func messageCount(statusMessages []arrapi.StatusMessage) int {
+	n := 0
+	for _, sm := range statusMessages {
+		n += 1 + len(sm.Messages)
+	}
+	return n
+}
+
+// correctClockSkew shifts a queue's timestamps from the instance's clock to
+// ours, given how far the instance's clock is ahead
+func correctClockSkew(queue []arrapi.QueueItem, offset time.Duration) {
+	if offset == 0 {
+		return
+	}
+	for i := range queue {
+		if !queue[i].Added.IsZero() {
+			queue[i].Added = queue[i].Added.Add(-offset)
+		}
+		if eta := queue[i].EstimatedCompletionTime; eta != nil {
+			corrected := eta.Add(-offset)
+			queue[i].EstimatedCompletionTime = &corrected
+		}
+	}
+}
+
 // JobResult represents the result of a single job for structured logging
 type JobResult struct {
 	Found   int `json:"found"`
 	Removed int `json:"removed"`
 }
 
+// isDue reports whether a job should run in the cycle starting at now, and
+// records the run if so. Jobs without an interval are always due.
+func (m *Manager) isDue(job Job, now time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if sj, ok := job.(ScheduledJob); ok {
+		if interval := sj.Interval(); interval > 0 {
+			if last, ran := m.lastRun[job.Name()]; ran && now.Sub(last) < interval-scheduleSlack {
+				return false
+			}
+		}
+	}
+
+	m.lastRun[job.Name()] = now
+	return true
+}
+
 // logCycleSummary outputs a summary of the execution cycle as structured log
 func (m *Manager) logCycleSummary(stats *CycleStats) {
 	// Calculate totals
@@ -182,6 +496,7 @@ func (m *Manager) logCycleSummary(stats *CycleStats) {
 		slog.Group("strikes",
 			slog.Int("added", stats.StrikesAdded),
 			slog.Int("cleared", stats.StrikesReset),
+			slog.Int("recovered", stats.StrikesRecovered),
 			slog.Int("tracked", stats.TotalStrikes),
 		),
 		slog.Any("jobs", jobResults),
@@ -203,34 +518,150 @@ func (m *Manager) GetLastStats() *CycleStats {
 	return m.lastStats
 }
 
+// GetStatsHistory returns the statistics of recent cycles, oldest first
+func (m *Manager) GetStatsHistory() []*CycleStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	history := make([]*CycleStats, len(m.history))
+	copy(history, m.history)
+	return history
+}
+
 // GetAllQueues fetches queue from all configured arr instances
 func (m *Manager) GetAllQueues(ctx context.Context) (map[string][]arrapi.QueueItem, error) {
 	m.mu.RLock()
 	clients := m.arrClients
+	offline := m.clientsOffline
+	offsets := m.clockOffsets
 	m.mu.RUnlock()
 
 	result := make(map[string][]arrapi.QueueItem)
 	var errs []error
 
 	for name, client := range clients {
+		if offline[name] {
+			m.logger.Debug("skipping queue, instance's download client is offline", "instance", name)
+			continue
+		}
+
 		queue, err := client.GetQueue(ctx)
 		if err != nil {
-			m.logger.Error("failed to get queue", "instance", name, "error", err)
+			switch {
+			case arrapi.IsAuthError(err):
+				m.logger.Error("failed to get queue: api key rejected, check the instance's api_key", "instance", name, "error", err)
+			case arrapi.IsTransient(err):
+				m.logger.Warn("failed to get queue, will retry next cycle", "instance", name, "error", err)
+				m.MarkUnreliable(name, err)
+			default:
+				m.logger.Error("failed to get queue", "instance", name, "error", err)
+			}
 			errs = append(errs, fmt.Errorf("%s: %w", name, err))
 			continue
 		}
 
+		if m.cfg.General.QueueDetails {
+			if details, err := client.GetQueueDetails(ctx); err != nil {
+				m.logger.Debug("queue details unavailable, using the standard queue", "instance", name, "error", err)
+			} else {
+				withQueueDetails(queue, details)
+			}
+		}
+
+		correctClockSkew(queue, offsets[name])
 		result[name] = queue
 		m.logger.Debug("retrieved queue", "instance", name, "items", len(queue))
 	}
 
+	m.mu.Lock()
+	m.queuesComplete = len(errs) == 0 && len(offline) == 0
+	m.mu.Unlock()
+
 	if len(errs) > 0 {
-		return result, fmt.Errorf("errors retrieving queues: %v", errs)
+		return result, fmt.Errorf("errors retrieving queues: %w", errors.Join(errs...))
 	}
 
 	return result, nil
 }
 
+// MarkUnreliable records that an instance's data this cycle is incomplete
+// after a transient API error, so jobs don't strike its items on the strength
+// of it. The flag clears at the start of the next cycle.
+func (m *Manager) MarkUnreliable(instanceName string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.unreliable[instanceName] {
+		m.logger.Warn("instance data incomplete this cycle, not adding strikes for its items",
+			"instance", instanceName,
+			"error", err)
+	}
+	m.unreliable[instanceName] = true
+}
+
+// DataReliable reports whether no transient API error has left an instance's
+// data incomplete this cycle
+func (m *Manager) DataReliable(instanceName string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return !m.unreliable[instanceName]
+}
+
+// StrikesExceeded reports whether a download has reached maxStrikes, and
+// records its strikes for the cycle's notifications
+func (m *Manager) StrikesExceeded(downloadID string, maxStrikes int) bool {
+	record, ok := m.strikes.GetRecord(downloadID)
+	if !ok {
+		return false
+	}
+
+	m.mu.Lock()
+	m.struck[downloadID] = StruckDownload{
+		DownloadID: downloadID,
+		Name:       record.Name,
+		Job:        record.Job,
+		Strikes:    record.Count,
+		MaxStrikes: maxStrikes,
+	}
+	m.mu.Unlock()
+
+	return record.Count >= maxStrikes
+}
+
+// RecordRemoval records a download removed this cycle for the cycle summary.
+// Queue items removed with RemoveQueueItem are recorded automatically.
+func (m *Manager) RecordRemoval(removed RemovedDownload) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.removed = append(m.removed, removed)
+}
+
+// struckThisCycle returns the downloads struck this cycle, ordered by name
+func (m *Manager) struckThisCycle() []StruckDownload {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	struck := make([]StruckDownload, 0, len(m.struck))
+	for _, d := range m.struck {
+		struck = append(struck, d)
+	}
+	sort.Slice(struck, func(a, b int) bool {
+		if struck[a].Name != struck[b].Name {
+			return struck[a].Name < struck[b].Name
+		}
+		return struck[a].DownloadID < struck[b].DownloadID
+	})
+	return struck
+}
+
+// AllQueuesFetched reports whether the last GetAllQueues call retrieved the
+// queue of every registered *arr instance
+func (m *Manager) AllQueuesFetched() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.queuesComplete
+}
+
 // RegisterArrClient adds an *arr client to the manager
 func (m *Manager) RegisterArrClient(name string, client *arrapi.Client) {
 	m.mu.Lock()
@@ -240,6 +671,65 @@ func (m *Manager) RegisterArrClient(name string, client *arrapi.Client) {
 	m.logger.Debug("registered arr client", "instance", name)
 }
 
+// ArrClientFactory builds the client for an *arr instance of the given kind
+// (sonarr, radarr, lidarr, readarr or whisparr)
+type ArrClientFactory func(kind string, inst config.InstanceConfig) *arrapi.Client
+
+// Reconfigure applies a reloaded configuration's API keys. The client of each
+// existing instance whose API key changed is rebuilt with newClient and the
+// old one closed; strikes and search history are untouched. Other changes
+// need a restart. The client map is replaced rather than modified, as readers
+// iterate it outside the lock. Returns the names of the rebuilt instances.
+func (m *Manager) Reconfigure(cfg *config.Config, newClient ArrClientFactory) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clients := make(map[string]*arrapi.Client, len(m.arrClients))
+	for name, client := range m.arrClients {
+		clients[name] = client
+	}
+	var closed []*arrapi.Client
+
+	kinds := []struct {
+		kind     string
+		old, new []config.InstanceConfig
+	}{
+		{"sonarr", m.cfg.Instances.Sonarr, cfg.Instances.Sonarr},
+		{"radarr", m.cfg.Instances.Radarr, cfg.Instances.Radarr},
+		{"lidarr", m.cfg.Instances.Lidarr, cfg.Instances.Lidarr},
+		{"readarr", m.cfg.Instances.Readarr, cfg.Instances.Readarr},
+		{"whisparr", m.cfg.Instances.Whisparr, cfg.Instances.Whisparr},
+	}
+
+	var rebuilt []string
+	for _, k := range kinds {
+		for i := range k.old {
+			current := &k.old[i]
+			for _, inst := range k.new {
+				if inst.Name != current.Name || inst.APIKey == current.APIKey {
+					continue
+				}
+
+				// Update the live config in place, as jobs hold pointers into it
+				current.APIKey = inst.APIKey
+				if old, ok := clients[inst.Name]; ok {
+					closed = append(closed, old)
+				}
+				clients[inst.Name] = newClient(k.kind, *current)
+				rebuilt = append(rebuilt, inst.Name)
+				m.logger.Info("rebuilt arr client with rotated API key", "instance", inst.Name)
+			}
+		}
+	}
+
+	m.arrClients = clients
+	for _, old := range closed {
+		old.Close()
+	}
+
+	return rebuilt
+}
+
 // RegisterDownloadClient adds a download client to the manager
 func (m *Manager) RegisterDownloadClient(name string, client downloadclient.Client) {
 	m.mu.Lock()
@@ -272,6 +762,11 @@ func (m *Manager) GetStrikesHandler() *strikes.Handler {
 	return m.strikes
 }
 
+// GetSearchHistory returns the history of searches triggered by search jobs
+func (m *Manager) GetSearchHistory() *searches.History {
+	return m.searches
+}
+
 // GetConfig returns the configuration
 func (m *Manager) GetConfig() *config.Config {
 	return m.cfg
@@ -303,7 +798,8 @@ func (m *Manager) GetAllArrClients() map[string]*arrapi.Client {
 	return result
 }
 
-// GetRemovalAction determines what action to take for a download based on tracker type and protected tags.
+// GetRemovalAction determines what action to take for a download based on tracker type, protected tags
+// and protected categories.
 // Returns: "remove", "tag", or "skip"
 func (m *Manager) GetRemovalAction(ctx context.Context, downloadHash string) string {
 	// Step 1: Check if protected tag exists on the torrent
@@ -313,27 +809,24 @@ func (m *Manager) GetRemovalAction(ctx context.Context, downloadHash string) str
 		return "remove" // Default to remove if torrent not found
 	}
 
-	// Check for protected tag
-	if m.cfg.General.ProtectedTag != "" {
-		for _, tag := range torrent.Tags {
-			if tag == m.cfg.General.ProtectedTag {
-				m.logger.Debug("torrent has protected tag, skipping removal",
-					"hash", downloadHash,
-					"tag", m.cfg.General.ProtectedTag)
-				return "skip"
-			}
-		}
+	// Check for protected tag or category
+	if m.isProtected(torrent) {
+		m.logger.Debug("torrent is protected, skipping removal",
+			"hash", downloadHash,
+			"tags", torrent.Tags,
+			"category", torrent.Category,
+			"state", torrent.ClientState)
+		return "skip"
 	}
 
-	// Step 2: Check tracker type (private vs public)
-	isPrivate, err := client.IsPrivateTracker(ctx, downloadHash)
-	if err != nil {
-		m.logger.Warn("failed to determine tracker type, defaulting to public handling",
-			"hash", downloadHash,
-			"error", err)
-		isPrivate = false
+	// Usenet downloads have no trackers, so tracker handling doesn't apply
+	if isUsenetClient(client) {
+		return "remove"
 	}
 
+	// Step 2: Check tracker type (private vs public)
+	isPrivate := m.isPrivateTorrent(ctx, client, torrent)
+
 	// Step 3: Apply configured handling based on tracker type
 	var handling string
 	if isPrivate {
@@ -363,6 +856,550 @@ func (m *Manager) GetRemovalAction(ctx context.Context, downloadHash string) str
 	}
 }
 
+// IsLastActiveInCategory reports whether a torrent is the only active
+// download in its category in its download client, so removing it would
+// leave the category with nothing downloading
+func (m *Manager) IsLastActiveInCategory(ctx context.Context, downloadHash string) bool {
+	torrent, client := m.findTorrentByHash(ctx, downloadHash)
+	if torrent == nil || !downloadclient.UsesDownloadSlot(*torrent) {
+		return false
+	}
+
+	torrents, err := client.GetTorrents(ctx)
+	if err != nil {
+		m.logger.Debug("failed to list torrents for active download check",
+			"hash", downloadHash,
+			"error", err)
+		return false
+	}
+
+	for _, other := range torrents {
+		if strings.EqualFold(other.Hash, torrent.Hash) || !strings.EqualFold(other.Category, torrent.Category) {
+			continue
+		}
+		if downloadclient.UsesDownloadSlot(other) {
+			return false
+		}
+	}
+	return true
+}
+
+// isProtected reports whether a torrent has the protected tag, is in a
+// protected category, or is in a protected state, i.e. mid-operation in the
+// client. States match the client's own name or the normalized state.
+func (m *Manager) isProtected(torrent *downloadclient.Torrent) bool {
+	if m.cfg.General.ProtectedTag != "" && downloadclient.HasTag(torrent, m.cfg.General.ProtectedTag) {
+		return true
+	}
+
+	for _, category := range m.cfg.General.ProtectedCategories {
+		if torrent.Category != "" && strings.EqualFold(torrent.Category, category) {
+			return true
+		}
+	}
+
+	return m.InProtectedState(torrent)
+}
+
+// InProtectedState reports whether a torrent is in one of
+// general.protected_states, e.g. checking or moving its files. Removal jobs
+// don't strike such torrents, as the state they'd judge is only transient.
+func (m *Manager) InProtectedState(torrent *downloadclient.Torrent) bool {
+	for _, state := range m.cfg.General.ProtectedStates {
+		if (torrent.ClientState != "" && strings.EqualFold(torrent.ClientState, state)) || strings.EqualFold(string(torrent.State), state) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteAllowed reports whether removal jobs may delete from an *arr instance,
+// or must treat it as a test run because it hasn't opted in to deletion
+func (m *Manager) DeleteAllowed(instanceName string) bool {
+	if m.cfg.DeleteAllowed(instanceName) {
+		return true
+	}
+
+	m.logger.Debug("instance has not opted in to deletion, treating as test run",
+		"instance", instanceName)
+	return false
+}
+
+// RemoveQueueItem removes an item from an *arr instance's queue. If
+// reviewCategory is set and the download resolves to a torrent, the torrent is
+// moved to that category and kept in the client rather than deleted. The
+// blocklist choice in opts is overridden by the configured per-tracker-type
+// blocklist settings when the download resolves to a torrent. A torrent that
+// shares its content with another (e.g. added by a cross-seeding tool) is
+// always kept in the client, so its files stay in place. If reasonTag is
+// set, a torrent kept in the client is tagged with it first, leaving an audit
+// trail of why it was removed from the *arr. Unless deleteFiles is set, a
+// torrent removed from the client is deleted by decluttarr after the *arr
+// removal rather than by the *arr, keeping its files on disk. A download
+// removed general.blocklist_regrabs_after times recently has its release
+// blocklisted, so the *arr stops grabbing it again. If
+// general.pre_remove_hook is set it runs first, and ErrRemovalDenied is
+// returned if it refuses. With general.processed_tag set, the item's series,
+// movie, artist or author is tagged in the *arr afterwards.
+func (m *Manager) RemoveQueueItem(ctx context.Context, instanceName string, item arrapi.QueueItem, opts arrapi.DeleteOptions, reviewCategory, reasonTag string, deleteFiles bool) error {
+	arrClient, ok := m.GetArrClient(instanceName)
+	if !ok {
+		return fmt.Errorf("arr client not found: %s", instanceName)
+	}
+
+	if err := m.runPreRemoveHook(ctx, instanceName, item); err != nil {
+		return err
+	}
+
+	// The torrent to delete from the client keeping its files, once the *arr
+	// has dropped it
+	var keepFilesTorrent *downloadclient.Torrent
+	var keepFilesClient downloadclient.Client
+
+	needsTorrent := opts.RemoveFromClient ||
+		reasonTag != "" ||
+		m.cfg.General.BlocklistPublic != nil ||
+		m.cfg.General.BlocklistPrivate != nil
+
+	if needsTorrent && item.DownloadID != "" {
+		if torrent, client := m.findTorrentByHash(ctx, item.DownloadID); torrent != nil {
+			if blocklist := m.blocklistFor(ctx, client, torrent); blocklist != nil {
+				opts.Blocklist = *blocklist
+			}
+
+			if opts.RemoveFromClient {
+				if other := m.crossSeedOf(ctx, client, torrent); other != nil {
+					opts.RemoveFromClient = false
+
+					m.logger.Info("download is cross-seeded, removing only the arr queue entry",
+						"instance", instanceName,
+						"download_id", item.DownloadID,
+						"title", item.Title,
+						"cross_seed_hash", other.Hash,
+						"content_path", torrentContentPath(torrent))
+				}
+			}
+
+			if reviewCategory != "" && opts.RemoveFromClient {
+				if err := client.SetCategory(ctx, torrent.Hash, reviewCategory); err != nil {
+					return fmt.Errorf("failed to move download to review category: %w", err)
+				}
+				opts.RemoveFromClient = false
+
+				m.logger.Info("moved download to review category",
+					"instance", instanceName,
+					"download_id", item.DownloadID,
+					"title", item.Title,
+					"category", reviewCategory)
+			}
+
+			if reasonTag != "" && !opts.RemoveFromClient {
+				// The tag is only an audit trail, so failing to apply it
+				// doesn't stop the removal
+				if tagger, ok := client.(downloadclient.TaggableClient); !ok {
+					m.logger.Debug("download client doesn't support tags, not tagging removal reason",
+						"instance", instanceName,
+						"download_id", item.DownloadID,
+						"client", client.Name())
+				} else if err := tagger.AddTags(ctx, torrent.Hash, []string{reasonTag}); err != nil {
+					m.logger.Warn("failed to tag download with removal reason",
+						"instance", instanceName,
+						"download_id", item.DownloadID,
+						"tag", reasonTag,
+						"error", err)
+				}
+			}
+
+			if opts.RemoveFromClient && !deleteFiles {
+				opts.RemoveFromClient = false
+				keepFilesTorrent, keepFilesClient = torrent, client
+			}
+		}
+	}
+
+	regrabThreshold := m.cfg.General.BlocklistRegrabsAfter
+	if regrabThreshold > 0 && item.DownloadID != "" && !opts.Blocklist {
+		if removals := m.tombstones.Removals(item.DownloadID); removals >= regrabThreshold {
+			opts.Blocklist = true
+
+			m.logger.Info("download was grabbed again after being removed, blocklisting the release",
+				"instance", instanceName,
+				"download_id", item.DownloadID,
+				"title", item.Title,
+				"removals", removals)
+		}
+	}
+
+	removeFromClient := opts.RemoveFromClient || keepFilesTorrent != nil
+	if err := arrClient.DeleteQueueItem(ctx, item.ID, opts); err != nil {
+		if !m.forceDelete(ctx, instanceName, item, removeFromClient, deleteFiles, err) {
+			return err
+		}
+	} else {
+		m.mu.Lock()
+		delete(m.removeFailures, item.DownloadID)
+		m.mu.Unlock()
+
+		if keepFilesTorrent != nil {
+			// The *arr entry is already gone, so a failure here leaves an
+			// orphan for remove_orphans rather than failing the removal
+			if err := keepFilesClient.DeleteTorrent(ctx, keepFilesTorrent.Hash, false); err != nil {
+				m.logger.Warn("failed to remove download from download client",
+					"instance", instanceName,
+					"download_id", item.DownloadID,
+					"error", err)
+			} else {
+				m.logger.Debug("removed download from download client, keeping its files",
+					"instance", instanceName,
+					"download_id", item.DownloadID,
+					"title", item.Title)
+			}
+		}
+	}
+
+	if regrabThreshold > 0 && item.DownloadID != "" {
+		if opts.Blocklist {
+			// A blocklisted release can't be grabbed again
+			m.tombstones.Forget(item.DownloadID)
+		} else {
+			m.tombstones.Record(item.DownloadID, item.Title, m.now())
+		}
+	}
+
+	m.tagProcessed(ctx, instanceName, arrClient, item)
+
+	removed := RemovedDownload{Source: instanceName, DownloadID: item.DownloadID, Name: item.Title}
+	if record, ok := m.strikes.GetRecord(item.DownloadID); ok {
+		removed.Job = record.Job
+		removed.Reason = record.LastReason
+	}
+	m.RecordRemoval(removed)
+
+	return nil
+}
+
+// DeleteClientTorrent deletes a torrent from a download client directly, for
+// jobs acting on the client rather than an *arr queue. If
+// general.pre_remove_hook is set it runs first, and ErrRemovalDenied is
+// returned if it refuses.
+func (m *Manager) DeleteClientTorrent(ctx context.Context, clientName string, client downloadclient.Client, torrent *downloadclient.Torrent, deleteFiles bool) error {
+	protocol := "torrent"
+	if isUsenetClient(client) {
+		protocol = "usenet"
+	}
+	item := arrapi.QueueItem{DownloadID: torrent.Hash, Title: torrent.Name, Protocol: protocol}
+	if err := m.runPreRemoveHook(ctx, clientName, item); err != nil {
+		return err
+	}
+	return client.DeleteTorrent(ctx, torrent.Hash, deleteFiles)
+}
+
+// processedTagTTL returns how long a library entry tagged as processed is skipped
+func (m *Manager) processedTagTTL() time.Duration {
+	if m.cfg.General.ProcessedTagTTL > 0 {
+		return m.cfg.General.ProcessedTagTTL
+	}
+	return DefaultProcessedTagTTL
+}
+
+// tagProcessed applies general.processed_tag to the library entry a removed
+// queue item was grabbed for, and remembers when, so removal jobs skip the
+// entry's downloads for processed_tag_ttl. The tag only marks the entry in
+// the *arr, so failing to apply it doesn't fail the removal.
+func (m *Manager) tagProcessed(ctx context.Context, instanceName string, arrClient *arrapi.Client, item arrapi.QueueItem) {
+	label := m.cfg.General.ProcessedTag
+	if label == "" {
+		return
+	}
+	kind, id, ok := MediaEntity(item)
+	if !ok {
+		return
+	}
+
+	m.processed.Record(instanceName, kind, id, label, m.now())
+
+	if err := arrClient.AddEntityTag(ctx, kind, id, label); err != nil {
+		m.logger.Warn("failed to tag library entry as processed",
+			"instance", instanceName,
+			"type", kind,
+			"id", id,
+			"tag", label,
+			"error", err)
+		return
+	}
+
+	m.logger.Debug("tagged library entry as processed",
+		"instance", instanceName,
+		"type", kind,
+		"id", id,
+		"tag", label)
+}
+
+// RecentlyProcessed reports whether a removal tagged the library entry of a
+// queue item with general.processed_tag within processed_tag_ttl. Removal
+// jobs skip such items rather than evaluating the entry's downloads again.
+func (m *Manager) RecentlyProcessed(instanceName string, item arrapi.QueueItem) bool {
+	if m.cfg.General.ProcessedTag == "" {
+		return false
+	}
+	kind, id, ok := MediaEntity(item)
+	if !ok {
+		return false
+	}
+
+	tagged, ok := m.processed.Tagged(instanceName, kind, id)
+	return ok && m.now().Sub(tagged) < m.processedTagTTL()
+}
+
+// cleanupProcessed takes the processed tag off library entries tagged longer
+// than processed_tag_ttl ago and forgets them. Entries whose tag couldn't be
+// removed are retried next cycle, unless their instance is no longer configured.
+func (m *Manager) cleanupProcessed(ctx context.Context) {
+	for _, entry := range m.processed.Expired(m.now().Add(-m.processedTagTTL())) {
+		arrClient, ok := m.GetArrClient(entry.Instance)
+		if ok {
+			if err := arrClient.RemoveEntityTag(ctx, entry.Kind, entry.ID, entry.Tag); err != nil {
+				m.logger.Warn("failed to remove processed tag from library entry",
+					"instance", entry.Instance,
+					"type", entry.Kind,
+					"id", entry.ID,
+					"tag", entry.Tag,
+					"error", err)
+				continue
+			}
+		}
+		m.processed.Forget(entry.Instance, entry.Kind, entry.ID)
+	}
+}
+
+// forceDelete counts a failed *arr removal of a download and, once
+// general.force_delete_after removals have failed, deletes its torrent from
+// the download client directly, bypassing the *arr. It reports whether the
+// torrent was deleted. Downloads the removal would have kept in the client
+// are never force-deleted.
+func (m *Manager) forceDelete(ctx context.Context, instanceName string, item arrapi.QueueItem, removeFromClient, deleteFiles bool, deleteErr error) bool {
+	threshold := m.cfg.General.ForceDeleteAfter
+	if threshold <= 0 || item.DownloadID == "" || !removeFromClient {
+		return false
+	}
+
+	m.mu.Lock()
+	m.removeFailures[item.DownloadID]++
+	failures := m.removeFailures[item.DownloadID]
+	m.mu.Unlock()
+
+	if failures < threshold {
+		m.logger.Debug("arr removal failed, counting towards force delete",
+			"instance", instanceName,
+			"download_id", item.DownloadID,
+			"failures", failures,
+			"force_delete_after", threshold)
+		return false
+	}
+
+	torrent, client := m.findTorrentByHash(ctx, item.DownloadID)
+	if torrent == nil {
+		m.logger.Warn("cannot force delete download, not found in any download client",
+			"instance", instanceName,
+			"download_id", item.DownloadID,
+			"title", item.Title)
+		return false
+	}
+	if err := client.DeleteTorrent(ctx, torrent.Hash, deleteFiles); err != nil {
+		m.logger.Error("failed to force delete download from download client",
+			"instance", instanceName,
+			"download_id", item.DownloadID,
+			"error", err)
+		return false
+	}
+
+	m.mu.Lock()
+	delete(m.removeFailures, item.DownloadID)
+	m.mu.Unlock()
+
+	m.logger.Warn("force deleted download from download client after repeated arr removal failures",
+		"instance", instanceName,
+		"download_id", item.DownloadID,
+		"title", item.Title,
+		"failures", failures,
+		"arr_error", deleteErr)
+	return true
+}
+
+// crossSeedOf returns another torrent in the client with the same content as
+// torrent, as cross-seeding tools add under a different hash or category, or
+// nil if it has none or the client's torrents can't be listed
+func (m *Manager) crossSeedOf(ctx context.Context, client downloadclient.Client, torrent *downloadclient.Torrent) *downloadclient.Torrent {
+	contentPath := torrentContentPath(torrent)
+	if contentPath == "" {
+		return nil
+	}
+
+	torrents, err := client.GetTorrents(ctx)
+	if err != nil {
+		m.logger.Debug("failed to list torrents for cross-seed check",
+			"hash", torrent.Hash,
+			"error", err)
+		return nil
+	}
+
+	for i := range torrents {
+		if strings.EqualFold(torrents[i].Hash, torrent.Hash) {
+			continue
+		}
+		if torrentContentPath(&torrents[i]) == contentPath {
+			return &torrents[i]
+		}
+	}
+	return nil
+}
+
+// torrentContentPath returns the path of a torrent's content, derived from its
+// save path and name when the client doesn't report it, or "" if unknown
+func torrentContentPath(torrent *downloadclient.Torrent) string {
+	if torrent.ContentPath != "" {
+		return path.Clean(torrent.ContentPath)
+	}
+	if torrent.SavePath == "" || torrent.Name == "" {
+		return ""
+	}
+	return path.Join(torrent.SavePath, torrent.Name)
+}
+
+// RecordIndexerFailure counts a removal traced to an indexer, disabling the
+// indexer in the *arr once threshold removals have been recorded. The indexer
+// name comes from the queue item, which the *arr fills in from the grab
+// history. A threshold of 0 or less disables tracking.
+func (m *Manager) RecordIndexerFailure(ctx context.Context, instanceName, indexerName string, threshold int) {
+	if threshold <= 0 || indexerName == "" {
+		return
+	}
+
+	key := instanceName + "/" + strings.ToLower(indexerName)
+
+	m.mu.Lock()
+	m.indexerFailures[key]++
+	failures := m.indexerFailures[key]
+	m.mu.Unlock()
+
+	if failures < threshold {
+		m.logger.Debug("recorded indexer failure",
+			"instance", instanceName,
+			"indexer", indexerName,
+			"failures", failures,
+			"threshold", threshold)
+		return
+	}
+
+	if err := m.disableIndexer(ctx, instanceName, indexerName); err != nil {
+		m.logger.Error("failed to disable indexer",
+			"instance", instanceName,
+			"indexer", indexerName,
+			"failures", failures,
+			"error", err)
+		return
+	}
+
+	m.mu.Lock()
+	delete(m.indexerFailures, key)
+	m.mu.Unlock()
+
+	m.logger.Warn("DISABLED INDEXER after repeated failed downloads; re-enable it in the *arr once fixed",
+		"instance", instanceName,
+		"indexer", indexerName,
+		"failures", failures)
+}
+
+// disableIndexer looks up an indexer by name and disables it
+func (m *Manager) disableIndexer(ctx context.Context, instanceName, indexerName string) error {
+	arrClient, ok := m.GetArrClient(instanceName)
+	if !ok {
+		return fmt.Errorf("arr client not found: %s", instanceName)
+	}
+
+	indexers, err := arrClient.GetIndexers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, indexer := range indexers {
+		if strings.EqualFold(indexer.Name, indexerName) {
+			return arrClient.DisableIndexer(ctx, indexer.ID)
+		}
+	}
+
+	return fmt.Errorf("indexer not found: %s", indexerName)
+}
+
+// blocklistFor returns the configured blocklist setting for a torrent's
+// tracker type, or nil if none is configured
+func (m *Manager) blocklistFor(ctx context.Context, client downloadclient.Client, torrent *downloadclient.Torrent) *bool {
+	if m.cfg.General.BlocklistPublic == nil && m.cfg.General.BlocklistPrivate == nil {
+		return nil
+	}
+	if isUsenetClient(client) {
+		return nil
+	}
+
+	if m.isPrivateTorrent(ctx, client, torrent) {
+		return m.cfg.General.BlocklistPrivate
+	}
+	return m.cfg.General.BlocklistPublic
+}
+
+// MaxStrikesFor returns the strikes a download needs before action is taken:
+// private or public for torrents on that tracker type when set, otherwise
+// maxStrikes. Downloads not found in a client use maxStrikes.
+func (m *Manager) MaxStrikesFor(ctx context.Context, downloadHash string, maxStrikes, private, public int) int {
+	if private <= 0 && public <= 0 {
+		return maxStrikes
+	}
+
+	torrent, client := m.findTorrentByHash(ctx, downloadHash)
+	if torrent == nil || isUsenetClient(client) {
+		return maxStrikes
+	}
+
+	limit := public
+	if m.isPrivateTorrent(ctx, client, torrent) {
+		limit = private
+	}
+	if limit <= 0 {
+		return maxStrikes
+	}
+	return limit
+}
+
+// isUsenetClient reports whether a client's downloads are usenet downloads,
+// which have no trackers
+func isUsenetClient(client downloadclient.Client) bool {
+	seeder, ok := client.(downloadclient.SeedingClient)
+	return ok && !seeder.SeedingCapable()
+}
+
+// isPrivateTorrent reports whether a torrent is from a private tracker,
+// defaulting to public if it can't be determined
+func (m *Manager) isPrivateTorrent(ctx context.Context, client downloadclient.Client, torrent *downloadclient.Torrent) bool {
+	isPrivate, err := trackerPrivate(ctx, client, torrent)
+	if err != nil {
+		m.logger.Warn("failed to determine tracker type, defaulting to public handling",
+			"hash", torrent.Hash,
+			"error", err)
+		return false
+	}
+	return isPrivate
+}
+
+// trackerPrivate reports whether a torrent is from a private tracker. A
+// torrent the client already flagged as private, e.g. from qBittorrent's
+// tracker fallback, needs no further request.
+func trackerPrivate(ctx context.Context, client downloadclient.Client, torrent *downloadclient.Torrent) (bool, error) {
+	if torrent.IsPrivate {
+		return true, nil
+	}
+	return client.IsPrivateTracker(ctx, torrent.Hash)
+}
+
 // ApplyObsoleteTag adds the obsolete tag to a torrent
 func (m *Manager) ApplyObsoleteTag(ctx context.Context, downloadHash string) error {
 	if m.cfg.General.ObsoleteTag == "" {
@@ -373,6 +1410,10 @@ func (m *Manager) ApplyObsoleteTag(ctx context.Context, downloadHash string) err
 	if torrent == nil {
 		return fmt.Errorf("torrent not found: %s", downloadHash)
 	}
+	tagger, ok := client.(downloadclient.TaggableClient)
+	if !ok {
+		return fmt.Errorf("%s client doesn't support tags", client.Name())
+	}
 
 	// Check if tag already exists
 	for _, tag := range torrent.Tags {
@@ -383,7 +1424,7 @@ func (m *Manager) ApplyObsoleteTag(ctx context.Context, downloadHash string) err
 	}
 
 	// Add the tag
-	if err := client.AddTags(ctx, downloadHash, []string{m.cfg.General.ObsoleteTag}); err != nil {
+	if err := tagger.AddTags(ctx, torrent.Hash, []string{m.cfg.General.ObsoleteTag}); err != nil {
 		return fmt.Errorf("failed to add obsolete tag: %w", err)
 	}
 
@@ -394,8 +1435,222 @@ func (m *Manager) ApplyObsoleteTag(ctx context.Context, downloadHash string) err
 	return nil
 }
 
-// findTorrentByHash finds a torrent across all download clients
+// PauseDownload pauses a torrent in whichever download client holds it. Torrents
+// that are already paused are left alone.
+func (m *Manager) PauseDownload(ctx context.Context, downloadHash string) error {
+	torrent, client := m.findTorrentByHash(ctx, downloadHash)
+	if torrent == nil {
+		return fmt.Errorf("torrent not found: %s", downloadHash)
+	}
+
+	if torrent.State == downloadclient.StatePaused {
+		m.logger.Debug("torrent already paused", "hash", downloadHash)
+		return nil
+	}
+
+	if err := client.PauseTorrent(ctx, torrent.Hash); err != nil {
+		return fmt.Errorf("failed to pause torrent: %w", err)
+	}
+
+	m.logger.Debug("paused torrent", "hash", downloadHash)
+
+	return nil
+}
+
+// ApplyObsoleteTagBulk adds the obsolete tag to many torrents on a single client,
+// using one request when the client supports bulk tagging
+func (m *Manager) ApplyObsoleteTagBulk(ctx context.Context, client downloadclient.Client, hashes []string) error {
+	if m.cfg.General.ObsoleteTag == "" {
+		return fmt.Errorf("obsolete tag not configured")
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	tags := []string{m.cfg.General.ObsoleteTag}
+
+	if bulk, ok := client.(downloadclient.BulkTagger); ok {
+		if err := bulk.AddTagsBulk(ctx, hashes, tags); err != nil {
+			return fmt.Errorf("failed to add obsolete tag: %w", err)
+		}
+	} else if tagger, ok := client.(downloadclient.TaggableClient); !ok {
+		return fmt.Errorf("%s client doesn't support tags", client.Name())
+	} else {
+		for _, hash := range hashes {
+			if err := tagger.AddTags(ctx, hash, tags); err != nil {
+				return fmt.Errorf("failed to add obsolete tag to %s: %w", hash, err)
+			}
+		}
+	}
+
+	m.logger.Debug("added obsolete tag to torrents",
+		"count", len(hashes),
+		"tag", m.cfg.General.ObsoleteTag)
+
+	return nil
+}
+
+// ObsoleteTagBatch collects downloads to tag as obsolete, so that each
+// download client is sent one request for all of its downloads
+type ObsoleteTagBatch struct {
+	manager *Manager
+	clients []downloadclient.Client
+	pending map[downloadclient.Client][]string // client -> hashes to tag
+	tagged  []string                           // download IDs already tagged
+	ids     map[string]string                  // client hash -> download ID as added
+}
+
+// NewObsoleteTagBatch creates an empty batch of obsolete tags
+func (m *Manager) NewObsoleteTagBatch() *ObsoleteTagBatch {
+	return &ObsoleteTagBatch{
+		manager: m,
+		pending: make(map[downloadclient.Client][]string),
+		ids:     make(map[string]string),
+	}
+}
+
+// Add queues a download to be tagged as obsolete, returning an error if it
+// can't be tagged. A download that already has the tag needs no request.
+func (b *ObsoleteTagBatch) Add(ctx context.Context, downloadHash string) error {
+	if b.manager.cfg.General.ObsoleteTag == "" {
+		return fmt.Errorf("obsolete tag not configured")
+	}
+
+	torrent, client := b.manager.findTorrentByHash(ctx, downloadHash)
+	if torrent == nil {
+		return fmt.Errorf("torrent not found: %s", downloadHash)
+	}
+	if downloadclient.HasTag(torrent, b.manager.cfg.General.ObsoleteTag) {
+		b.tagged = append(b.tagged, downloadHash)
+		return nil
+	}
+
+	if _, ok := b.pending[client]; !ok {
+		b.clients = append(b.clients, client)
+	}
+	b.pending[client] = append(b.pending[client], torrent.Hash)
+	b.ids[torrent.Hash] = downloadHash
+	return nil
+}
+
+// Apply tags the queued downloads with one request per download client. It
+// returns the download IDs tagged, including those that already had the tag,
+// and the error for each download whose client failed.
+func (b *ObsoleteTagBatch) Apply(ctx context.Context) (tagged []string, failed map[string]error) {
+	tagged = append(tagged, b.tagged...)
+	failed = make(map[string]error)
+
+	for _, client := range b.clients {
+		hashes := b.pending[client]
+		err := b.manager.ApplyObsoleteTagBulk(ctx, client, hashes)
+		for _, hash := range hashes {
+			if err != nil {
+				failed[b.ids[hash]] = err
+			} else {
+				tagged = append(tagged, b.ids[hash])
+			}
+		}
+	}
+
+	return tagged, failed
+}
+
+// GetAllTorrents fetches the torrents from every download client, keyed by
+// lowercase hash. Clients that fail are logged and skipped.
+func (m *Manager) GetAllTorrents(ctx context.Context) map[string]downloadclient.Torrent {
+	torrents, _ := m.fetchAllTorrents(ctx)
+	return torrents
+}
+
+// fetchAllTorrents is GetAllTorrents, also reporting whether every download
+// client's torrents were fetched
+func (m *Manager) fetchAllTorrents(ctx context.Context) (map[string]downloadclient.Torrent, bool) {
+	torrents := make(map[string]downloadclient.Torrent)
+	complete := true
+
+	for name, client := range m.GetAllDownloadClients() {
+		clientTorrents, err := client.GetTorrents(ctx)
+		if err != nil {
+			m.logger.Warn("failed to get torrents from download client",
+				"client", name,
+				"error", err)
+			complete = false
+			continue
+		}
+		for _, torrent := range clientTorrents {
+			torrents[strings.ToLower(torrent.Hash)] = torrent
+		}
+	}
+
+	return torrents, complete
+}
+
+// clearRemovedExternally clears the strikes and force delete counts of
+// downloads no longer in any *arr queue or download client, e.g. because they
+// were removed by hand, so a reused download ID doesn't inherit them. Nothing
+// is cleared unless every queue and client was fetched, as a download missing
+// from a partial view may just be out of sight.
+func (m *Manager) clearRemovedExternally(ctx context.Context) {
+	records := m.strikes.GetAllRecords()
+
+	m.mu.RLock()
+	failures := len(m.removeFailures)
+	sources := len(m.arrClients) + len(m.downloadClients)
+	m.mu.RUnlock()
+	if (len(records) == 0 && failures == 0) || sources == 0 || ctx.Err() != nil {
+		return
+	}
+
+	queues, err := m.GetAllQueues(ctx)
+	if err != nil || !m.AllQueuesFetched() {
+		m.logger.Debug("not every queue was fetched, keeping strikes of missing downloads", "error", err)
+		return
+	}
+	torrents, complete := m.fetchAllTorrents(ctx)
+	if !complete {
+		m.logger.Debug("not every download client was reached, keeping strikes of missing downloads")
+		return
+	}
+
+	present := make(map[string]bool, len(torrents))
+	for hash := range torrents {
+		present[hash] = true
+	}
+	for _, queue := range queues {
+		for _, item := range queue {
+			if item.DownloadID != "" {
+				present[strings.ToLower(item.DownloadID)] = true
+			}
+		}
+	}
+
+	for downloadID, record := range records {
+		if present[strings.ToLower(downloadID)] {
+			continue
+		}
+		m.strikes.Reset(downloadID)
+		m.logger.Debug("download removed externally, strikes cleared",
+			"download_id", downloadID,
+			"name", record.Name,
+			"strikes", record.Count,
+			"job", record.Job)
+	}
+
+	m.mu.Lock()
+	for downloadID := range m.removeFailures {
+		if !present[strings.ToLower(downloadID)] {
+			delete(m.removeFailures, downloadID)
+		}
+	}
+	m.mu.Unlock()
+}
+
+// findTorrentByHash finds a torrent across all download clients. The *arr
+// reports torrent hashes in uppercase while qBittorrent uses lowercase, so
+// the hash is normalized to lowercase and clients match it case-insensitively.
 func (m *Manager) findTorrentByHash(ctx context.Context, hash string) (*downloadclient.Torrent, downloadclient.Client) {
+	hash = strings.ToLower(hash)
+
 	m.mu.RLock()
 	clients := m.downloadClients
 	m.mu.RUnlock()
@@ -422,6 +1677,15 @@ func (m *Manager) Close() {
 	if err := m.strikes.Save(); err != nil {
 		m.logger.Error("failed to save strikes on close", "error", err)
 	}
+	if err := m.searches.Save(); err != nil {
+		m.logger.Error("failed to save search history on close", "error", err)
+	}
+	if err := m.tombstones.Save(); err != nil {
+		m.logger.Error("failed to save tombstones on close", "error", err)
+	}
+	if err := m.processed.Save(); err != nil {
+		m.logger.Error("failed to save processed entries on close", "error", err)
+	}
 
 	// Close all arr clients
 	for name, client := range m.arrClients {
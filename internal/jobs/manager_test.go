@@ -0,0 +1,431 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// countingJob reports how many times it has run as its found count
+type countingJob struct {
+	runs int
+}
+
+func (j *countingJob) Name() string                  { return "counting" }
+func (j *countingJob) Enabled() bool                 { return true }
+func (j *countingJob) Run(ctx context.Context) error { j.runs++; return nil }
+func (j *countingJob) Stats() JobStats               { return JobStats{Found: j.runs} }
+
+func TestStatsHistory(t *testing.T) {
+	cfg := &config.Config{General: config.GeneralConfig{StatsHistorySize: 3}}
+	m := NewManager(cfg, testLogger(), "")
+	defer m.Close()
+
+	job := &countingJob{}
+	m.RegisterJob(job)
+
+	assert.Empty(t, m.GetStatsHistory())
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, m.RunAll(context.Background()))
+	}
+
+	history := m.GetStatsHistory()
+	require.Len(t, history, 3, "history should be capped")
+
+	// Oldest first, holding the most recent cycles
+	for i, stats := range history {
+		assert.Equal(t, i+3, stats.ItemsFound["counting"])
+	}
+	assert.Same(t, m.GetLastStats(), history[len(history)-1])
+}
+
+func TestStatsHistoryDefaultSize(t *testing.T) {
+	m := NewManager(&config.Config{}, testLogger(), "")
+	defer m.Close()
+
+	for i := 0; i < DefaultStatsHistorySize+5; i++ {
+		require.NoError(t, m.RunAll(context.Background()))
+	}
+
+	assert.Len(t, m.GetStatsHistory(), DefaultStatsHistorySize)
+}
+
+// scheduledJob is a countingJob with a run interval
+type scheduledJob struct {
+	countingJob
+	name     string
+	interval time.Duration
+}
+
+func (j *scheduledJob) Name() string            { return j.name }
+func (j *scheduledJob) Interval() time.Duration { return j.interval }
+
+func TestJobIntervals(t *testing.T) {
+	m := NewManager(&config.Config{}, testLogger(), "")
+	defer m.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	hourly := &scheduledJob{name: "hourly", interval: time.Hour}
+	everyCycle := &scheduledJob{name: "every_cycle"}
+	m.RegisterJob(hourly)
+	m.RegisterJob(everyCycle)
+
+	// Cycles every 10 minutes for just under an hour
+	for i := 0; i < 6; i++ {
+		require.NoError(t, m.RunAll(context.Background()))
+		now = now.Add(10 * time.Minute)
+	}
+
+	assert.Equal(t, 1, hourly.runs, "hourly job should only run on the first cycle")
+	assert.Equal(t, 6, everyCycle.runs, "job without interval should run every cycle")
+	assert.Equal(t, 1, m.GetLastStats().JobsRun, "skipped jobs should not count as run")
+
+	// An hour after its last run the job is due again
+	require.NoError(t, m.RunAll(context.Background()))
+	assert.Equal(t, 2, hourly.runs)
+	assert.Equal(t, 7, everyCycle.runs)
+}
+
+func TestActiveHoursSkipsCycles(t *testing.T) {
+	cfg := &config.Config{General: config.GeneralConfig{
+		ActiveHours: config.ActiveHours{Start: "22:00", End: "06:00"},
+	}}
+	m := NewManager(cfg, testLogger(), "")
+	defer m.Close()
+
+	now := time.Date(2024, 1, 5, 12, 0, 0, 0, time.Local)
+	m.now = func() time.Time { return now }
+
+	job := &countingJob{}
+	m.RegisterJob(job)
+
+	assert.ErrorIs(t, m.RunAll(context.Background()), ErrOutsideActiveHours)
+	assert.Zero(t, job.runs, "no jobs should run outside active hours")
+	assert.Nil(t, m.GetLastStats(), "a skipped cycle records no stats")
+
+	// Inside the window, on both sides of midnight
+	for _, at := range []time.Time{
+		time.Date(2024, 1, 5, 23, 0, 0, 0, time.Local),
+		time.Date(2024, 1, 6, 2, 0, 0, 0, time.Local),
+	} {
+		now = at
+		require.NoError(t, m.RunAll(context.Background()))
+	}
+	assert.Equal(t, 2, job.runs)
+}
+
+// queueJob records the queues it receives from the manager
+type queueJob struct {
+	m        *Manager
+	queues   map[string][]arrapi.QueueItem
+	complete bool
+}
+
+func (j *queueJob) Name() string  { return "queue" }
+func (j *queueJob) Enabled() bool { return true }
+func (j *queueJob) Run(ctx context.Context) error {
+	queues, err := j.m.GetAllQueues(ctx)
+	j.queues = queues
+	j.complete = j.m.AllQueuesFetched()
+	return err
+}
+
+// healthServer serves a one-item queue and the given health checks
+func healthServer(t *testing.T, checks []arrapi.HealthCheck) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/health":
+			_ = json.NewEncoder(w).Encode(checks)
+		case "/api/v3/queue":
+			_ = json.NewEncoder(w).Encode(arrapi.QueueResponse{
+				Page:         1,
+				PageSize:     1000,
+				TotalRecords: 1,
+				Records:      []arrapi.QueueItem{{ID: 1, Title: "Queued.Item"}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestHealthSkipsInstancesWithOfflineDownloadClient(t *testing.T) {
+	healthy := healthServer(t, []arrapi.HealthCheck{
+		{Source: "IndexerStatusCheck", Type: "warning", Message: "Indexers unavailable due to failures: Example"},
+	})
+	offline := healthServer(t, []arrapi.HealthCheck{
+		{Source: "DownloadClientStatusCheck", Type: "error", Message: "All download clients are unavailable due to failures"},
+	})
+
+	var logs bytes.Buffer
+	m := NewManager(&config.Config{}, slog.New(slog.NewTextHandler(&logs, nil)), "")
+	defer m.Close()
+
+	for name, server := range map[string]*httptest.Server{"healthy": healthy, "offline": offline} {
+		m.RegisterArrClient(name, arrapi.NewClient(arrapi.ClientConfig{
+			Name:    name,
+			BaseURL: server.URL,
+			APIKey:  "test",
+			Logger:  testLogger(),
+		}))
+	}
+
+	job := &queueJob{m: m}
+	m.RegisterJob(job)
+
+	require.NoError(t, m.RunAll(context.Background()))
+
+	assert.Len(t, job.queues["healthy"], 1)
+	assert.NotContains(t, job.queues, "offline", "queue of an instance without a download client should be skipped")
+	assert.False(t, job.complete, "skipped instance means not every queue was fetched")
+	assert.Contains(t, logs.String(), "Indexers unavailable due to failures")
+	assert.Contains(t, logs.String(), "download client is offline")
+}
+
+// orderedJob appends its name to a shared log when run
+type orderedJob struct {
+	name  string
+	order int
+	ran   *[]string
+}
+
+func (j *orderedJob) Name() string                  { return j.name }
+func (j *orderedJob) Enabled() bool                 { return true }
+func (j *orderedJob) Order() int                    { return j.order }
+func (j *orderedJob) Run(ctx context.Context) error { *j.ran = append(*j.ran, j.name); return nil }
+
+func TestJobOrder(t *testing.T) {
+	m := NewManager(&config.Config{}, testLogger(), "")
+	defer m.Close()
+
+	var ran []string
+	m.RegisterJob(&orderedJob{name: "search_missing", order: SearchJobOrder, ran: &ran})
+	m.RegisterJob(&orderedJob{name: "remove_stalled", order: RemovalJobOrder, ran: &ran})
+	m.RegisterJob(&orderedJob{name: "search_first", order: 10, ran: &ran})
+	m.RegisterJob(&orderedJob{name: "remove_slow", order: RemovalJobOrder, ran: &ran})
+	m.RegisterJob(&countingJob{})
+
+	require.NoError(t, m.RunAll(context.Background()))
+
+	// Ascending order, with ties kept in registration order
+	assert.Equal(t, []string{"search_first", "remove_stalled", "remove_slow", "search_missing"}, ran)
+
+	m.mu.RLock()
+	first := m.jobs[0].Name()
+	m.mu.RUnlock()
+	assert.Equal(t, "counting", first, "jobs without an order sort as 0")
+}
+
+// testRunJob is a countingJob that records its test-run mode
+type testRunJob struct {
+	countingJob
+	testRun bool
+}
+
+func (j *testRunJob) SetTestRun(testRun bool) { j.testRun = testRun }
+
+func TestSetTestRun(t *testing.T) {
+	m := NewManager(&config.Config{}, testLogger(), "")
+	defer m.Close()
+
+	job := &testRunJob{testRun: true}
+	m.RegisterJob(job)
+	m.RegisterJob(&countingJob{})
+	require.NoError(t, m.RunAll(context.Background()))
+
+	m.SetTestRun(false)
+	assert.False(t, job.testRun)
+
+	// The same job instance stays registered, keeping its state
+	require.NoError(t, m.RunAll(context.Background()))
+	assert.Equal(t, 2, job.runs)
+}
+
+func TestReconfigureRotatesAPIKey(t *testing.T) {
+	var gotKey atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey.Store(r.Header.Get("X-Api-Key"))
+		_ = json.NewEncoder(w).Encode(arrapi.QueueResponse{Page: 1, PageSize: 1000})
+	}))
+	defer server.Close()
+
+	instances := func(apiKey string) config.InstancesConfig {
+		return config.InstancesConfig{
+			Sonarr: []config.InstanceConfig{{Name: "sonarr", URL: server.URL, APIKey: apiKey}},
+			Radarr: []config.InstanceConfig{{Name: "radarr", URL: server.URL, APIKey: "unchanged"}},
+		}
+	}
+	newClient := func(kind string, inst config.InstanceConfig) *arrapi.Client {
+		return arrapi.NewClient(arrapi.ClientConfig{
+			Name:    inst.Name,
+			BaseURL: inst.URL,
+			APIKey:  inst.APIKey,
+			Logger:  testLogger(),
+		})
+	}
+
+	cfg := &config.Config{Instances: instances("old-key")}
+	m := NewManager(cfg, testLogger(), "")
+	defer m.Close()
+	for _, inst := range append(cfg.Instances.Sonarr, cfg.Instances.Radarr...) {
+		m.RegisterArrClient(inst.Name, newClient("", inst))
+	}
+	m.GetStrikesHandler().Add("abc", "remove_stalled", "Struck.Item", "download stalled")
+
+	client, _ := m.GetArrClient("sonarr")
+	_, err := client.GetQueue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "old-key", gotKey.Load())
+
+	rebuilt := m.Reconfigure(&config.Config{Instances: instances("new-key")}, newClient)
+	assert.Equal(t, []string{"sonarr"}, rebuilt)
+	assert.Equal(t, "new-key", cfg.Instances.Sonarr[0].APIKey)
+
+	client, _ = m.GetArrClient("sonarr")
+	_, err = client.GetQueue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "new-key", gotKey.Load())
+	assert.Equal(t, 1, m.GetStrikesHandler().Get("abc"), "strikes should survive a reconfigure")
+
+	assert.Empty(t, m.Reconfigure(&config.Config{Instances: instances("new-key")}, newClient))
+}
+
+func TestReconfigureWhileFetchingQueues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(arrapi.QueueResponse{Page: 1, PageSize: 1000})
+	}))
+	defer server.Close()
+
+	instances := func(apiKey string) config.InstancesConfig {
+		return config.InstancesConfig{
+			Sonarr: []config.InstanceConfig{{Name: "sonarr", URL: server.URL, APIKey: apiKey}},
+		}
+	}
+	newClient := func(kind string, inst config.InstanceConfig) *arrapi.Client {
+		return arrapi.NewClient(arrapi.ClientConfig{Name: inst.Name, BaseURL: inst.URL, APIKey: inst.APIKey, Logger: testLogger()})
+	}
+
+	cfg := &config.Config{Instances: instances("key-0")}
+	m := NewManager(cfg, testLogger(), "")
+	defer m.Close()
+	m.RegisterArrClient("sonarr", newClient("sonarr", cfg.Instances.Sonarr[0]))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			_, _ = m.GetAllQueues(context.Background())
+		}
+	}()
+
+	// Run with -race: readers iterate the client map while keys rotate
+rotate:
+	for i := 1; ; i++ {
+		select {
+		case <-done:
+			break rotate
+		default:
+		}
+		m.Reconfigure(&config.Config{Instances: instances(fmt.Sprintf("key-%d", i))}, newClient)
+	}
+
+	client, ok := m.GetArrClient("sonarr")
+	require.True(t, ok)
+	_, err := client.GetQueue(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestRecentlyProcessedExpires(t *testing.T) {
+	var mu sync.Mutex
+	movieTags := `[]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/tag":
+			_, _ = w.Write([]byte(`[{"id": 1, "label": "processed"}]`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/movie/9":
+			_, _ = w.Write([]byte(`{"id": 9, "tags": ` + movieTags + `}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v3/movie/9":
+			var movie struct {
+				Tags []int `json:"tags"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&movie)
+			tags, _ := json.Marshal(movie.Tags)
+			movieTags = string(tags)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	strikesPath := filepath.Join(t.TempDir(), "strikes.json")
+	cfg := &config.Config{General: config.GeneralConfig{ProcessedTag: "processed", ProcessedTagTTL: time.Hour}}
+	newManager := func() *Manager {
+		m := NewManager(cfg, testLogger(), strikesPath)
+		m.RegisterArrClient("radarr", arrapi.NewClient(arrapi.ClientConfig{Name: "radarr", BaseURL: server.URL, APIKey: "test", Logger: testLogger()}))
+		return m
+	}
+	m := newManager()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	movieID := 9
+	item := arrapi.QueueItem{ID: 1, Title: "Movie", DownloadID: "abc", MovieID: &movieID}
+	unlinked := arrapi.QueueItem{ID: 2, Title: "Unknown", DownloadID: "def"}
+
+	require.NoError(t, m.RemoveQueueItem(context.Background(), "radarr", item, arrapi.DeleteOptions{}, "", "", true))
+	require.NoError(t, m.RemoveQueueItem(context.Background(), "radarr", unlinked, arrapi.DeleteOptions{}, "", "", true))
+
+	assert.True(t, m.RecentlyProcessed("radarr", item))
+	assert.False(t, m.RecentlyProcessed("other-radarr", item), "processing is per instance")
+	assert.False(t, m.RecentlyProcessed("radarr", unlinked), "items not linked to the library aren't tracked")
+	mu.Lock()
+	assert.Equal(t, `[1]`, movieTags)
+	mu.Unlock()
+
+	// A restart keeps skipping the entry
+	m.Close()
+	m = newManager()
+	defer m.Close()
+	m.now = func() time.Time { return now }
+	assert.True(t, m.RecentlyProcessed("radarr", item), "processed entries should survive a restart")
+
+	now = now.Add(time.Hour)
+	assert.False(t, m.RecentlyProcessed("radarr", item), "the entry should be evaluated again after the TTL")
+
+	m.cleanupProcessed(context.Background())
+	assert.Equal(t, 0, m.processed.Count())
+	mu.Lock()
+	assert.Equal(t, `[]`, movieTags, "the tag should be removed after the TTL")
+	mu.Unlock()
+}
@@ -0,0 +1,346 @@
+package removal
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+	"github.com/jmylchreest/go-decluttarr/internal/strikes"
+)
+
+// reviewCategory returns the category removed downloads are moved to instead
+// of being deleted, or "" to delete them
+func reviewCategory(cfg *config.JobConfig, defaults *config.JobDefaultsConfig) string {
+	if cfg.ReviewCategory != nil {
+		return *cfg.ReviewCategory
+	}
+	return defaults.ReviewCategory
+}
+
+// reasonTagPrefix prefixes the tags recording why a download was removed
+const reasonTagPrefix = "decluttarr:"
+
+// reasonTag returns the tag applied to torrents the job removes from the *arr
+// but keeps in the client, or "" if reason tags are disabled
+func reasonTag(cfg *config.JobConfig, defaults *config.JobDefaultsConfig, reason string) string {
+	enabled := defaults.ReasonTags
+	if cfg.ReasonTags != nil {
+		enabled = *cfg.ReasonTags
+	}
+	if !enabled {
+		return ""
+	}
+	return reasonTagPrefix + reason
+}
+
+// deleteFiles returns whether a job's removals delete the torrent's files from
+// disk, or fallback if the job doesn't set delete_files. Jobs removing *arr
+// queue items pass true, matching the *arr's own removals. Jobs that delete
+// from the download client directly pass false, so files are kept.
+func deleteFiles(setting *bool, fallback bool) bool {
+	if setting != nil {
+		return *setting
+	}
+	return fallback
+}
+
+// minTimeLeft returns how close to completion a download must be estimated to
+// be for it to be exempt from slow and stalled removal, or 0 for no exemption
+func minTimeLeft(cfg *config.JobConfig, defaults *config.JobDefaultsConfig) time.Duration {
+	if cfg.MinTimeLeft != nil {
+		return *cfg.MinTimeLeft
+	}
+	return defaults.MinTimeLeft
+}
+
+// completesWithin reports whether the *arr estimates the item will finish
+// within timeLeft. Estimates already in the past are stale and don't count.
+func completesWithin(item arrapi.QueueItem, timeLeft time.Duration) bool {
+	if timeLeft <= 0 || item.EstimatedCompletionTime == nil {
+		return false
+	}
+	remaining := time.Until(*item.EstimatedCompletionTime)
+	return remaining >= 0 && remaining <= timeLeft
+}
+
+// maxStrikesFor returns the strikes a download needs before action is taken,
+// using max_strikes_private or max_strikes_public for its tracker type
+func maxStrikesFor(ctx context.Context, manager *jobs.Manager, cfg *config.JobConfig, defaults *config.JobDefaultsConfig, maxStrikes int, downloadID string) int {
+	private, public := defaults.MaxStrikesPrivate, defaults.MaxStrikesPublic
+	if cfg.MaxStrikesPrivate != nil {
+		private = *cfg.MaxStrikesPrivate
+	}
+	if cfg.MaxStrikesPublic != nil {
+		public = *cfg.MaxStrikesPublic
+	}
+	return manager.MaxStrikesFor(ctx, downloadID, maxStrikes, private, public)
+}
+
+// deferLastActive returns whether removal of the last active download in a
+// category is deferred until another download in the category is active
+func deferLastActive(cfg *config.JobConfig, defaults *config.JobDefaultsConfig) bool {
+	if cfg.DeferLastActive != nil {
+		return *cfg.DeferLastActive
+	}
+	return defaults.DeferLastActive
+}
+
+// minGrabAge returns how long after the *arr grabbed a download it is exempt
+// from removal, or 0 for no exemption
+func minGrabAge(cfg *config.JobConfig, defaults *config.JobDefaultsConfig) time.Duration {
+	if cfg.MinGrabAge != nil {
+		return *cfg.MinGrabAge
+	}
+	return defaults.MinGrabAge
+}
+
+// withoutFreshGrabs drops the queue items the *arr grabbed less than minAge
+// ago. A fresh grab can briefly look failed or stalled before the download
+// client reports any progress. Items without a grab time are kept.
+func withoutFreshGrabs(queue []arrapi.QueueItem, minAge time.Duration, logger *slog.Logger) []arrapi.QueueItem {
+	if minAge <= 0 {
+		return queue
+	}
+
+	var kept []arrapi.QueueItem
+	for _, item := range queue {
+		if !item.Added.IsZero() && time.Since(item.Added) < minAge {
+			logger.Debug("skipping recently grabbed download",
+				"title", item.Title,
+				"download_id", item.DownloadID,
+				"grabbed", item.Added,
+				"min_grab_age", minAge,
+			)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+// withoutProcessed drops the queue items of library entries a removal was
+// recently tagged on with general.processed_tag, so their downloads aren't
+// evaluated again until processed_tag_ttl has passed
+func withoutProcessed(queue []arrapi.QueueItem, manager *jobs.Manager, instanceName string, logger *slog.Logger) []arrapi.QueueItem {
+	var kept []arrapi.QueueItem
+	for _, item := range queue {
+		if manager.RecentlyProcessed(instanceName, item) {
+			logger.Debug("skipping download of a recently processed library entry",
+				"title", item.Title,
+				"download_id", item.DownloadID,
+				"instance", instanceName,
+			)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+// protectNewest returns how many of the most recent grabs for each series,
+// movie, artist or author are exempt from removal, or 0 for no exemption
+func protectNewest(cfg *config.JobConfig, defaults *config.JobDefaultsConfig) int {
+	if cfg.ProtectNewest != nil {
+		return *cfg.ProtectNewest
+	}
+	return defaults.ProtectNewest
+}
+
+// mediaKey returns the library entry a queue item was grabbed for, or "" if
+// it isn't linked to one
+func mediaKey(item arrapi.QueueItem) string {
+	kind, id, ok := jobs.MediaEntity(item)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s/%d", kind, id)
+}
+
+// withoutNewestGrabs drops the queue items of the n most recent grabs for
+// each series, movie, artist or author, so the download the *arr is counting
+// on, such as a season pack, isn't removed while it catches up. A grab is a
+// download ID, which a season pack shares across its episodes' queue items.
+// Items not linked to the library or without a grab time aren't protected.
+func withoutNewestGrabs(queue []arrapi.QueueItem, n int, logger *slog.Logger) []arrapi.QueueItem {
+	if n <= 0 {
+		return queue
+	}
+
+	// Each grab's download ID and time, by library entry
+	type grab struct {
+		downloadID string
+		added      time.Time
+	}
+	grabs := make(map[string][]grab)
+	seen := make(map[string]bool)
+	for _, item := range queue {
+		key := mediaKey(item)
+		if key == "" || item.Added.IsZero() || item.DownloadID == "" || seen[key+"|"+item.DownloadID] {
+			continue
+		}
+		seen[key+"|"+item.DownloadID] = true
+		grabs[key] = append(grabs[key], grab{downloadID: item.DownloadID, added: item.Added})
+	}
+
+	protected := make(map[string]bool)
+	for _, entryGrabs := range grabs {
+		sort.SliceStable(entryGrabs, func(a, b int) bool {
+			return entryGrabs[a].added.After(entryGrabs[b].added)
+		})
+		for _, g := range entryGrabs[:min(n, len(entryGrabs))] {
+			protected[g.downloadID] = true
+		}
+	}
+
+	var kept []arrapi.QueueItem
+	for _, item := range queue {
+		if protected[item.DownloadID] {
+			logger.Debug("skipping one of the newest grabs for its library entry",
+				"title", item.Title,
+				"download_id", item.DownloadID,
+				"grabbed", item.Added,
+				"protect_newest", n,
+			)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+// grabAge returns how long ago a queue item was grabbed. ok is false when
+// the grab time is in the future, which means the *arr's clock is ahead of
+// ours and the item can't be timed.
+func grabAge(item arrapi.QueueItem) (age time.Duration, ok bool) {
+	age = time.Since(item.Added)
+	return age, age >= 0
+}
+
+// dataReliable reports whether an instance's data this cycle can be trusted
+// for striking, logging when its queue is skipped
+func dataReliable(manager *jobs.Manager, instanceName string, logger *slog.Logger) bool {
+	if manager.DataReliable(instanceName) {
+		return true
+	}
+	logger.Debug("skipping queue, instance data incomplete this cycle", "instance", instanceName)
+	return false
+}
+
+// obsoleteTagger batches a removal job's obsolete tags, tagging the downloads
+// of each download client in one request once the job has checked every
+// queue. Each download's log line is written once its tag is applied.
+type obsoleteTagger struct {
+	batch   *jobs.ObsoleteTagBatch
+	logger  *slog.Logger
+	pending map[string]pendingTag // download ID -> its log line
+	titles  map[string]string     // download ID -> title, for errors
+}
+
+// pendingTag is the log line of a download queued for tagging
+type pendingTag struct {
+	msg   string
+	attrs []any
+}
+
+func newObsoleteTagger(manager *jobs.Manager, logger *slog.Logger) *obsoleteTagger {
+	return &obsoleteTagger{
+		batch:   manager.NewObsoleteTagBatch(),
+		logger:  logger,
+		pending: make(map[string]pendingTag),
+		titles:  make(map[string]string),
+	}
+}
+
+// add queues a queue item's download to be tagged as obsolete, logging msg
+// with attrs once it is. Downloads that can't be tagged are logged and skipped.
+func (t *obsoleteTagger) add(ctx context.Context, item arrapi.QueueItem, msg string, attrs ...any) {
+	if _, ok := t.pending[item.DownloadID]; ok {
+		return
+	}
+	if err := t.batch.Add(ctx, item.DownloadID); err != nil {
+		t.logger.Error("failed to tag as obsolete",
+			"title", item.Title,
+			"download_id", item.DownloadID,
+			"error", err,
+		)
+		return
+	}
+	t.pending[item.DownloadID] = pendingTag{msg: msg, attrs: attrs}
+	t.titles[item.DownloadID] = item.Title
+}
+
+// apply tags the queued downloads, resetting the strikes of each one tagged,
+// and returns how many were tagged
+func (t *obsoleteTagger) apply(ctx context.Context, strikesHandler *strikes.Handler) int {
+	if len(t.pending) == 0 {
+		return 0
+	}
+
+	tagged, failed := t.batch.Apply(ctx)
+	for downloadID, err := range failed {
+		t.logger.Error("failed to tag as obsolete",
+			"title", t.titles[downloadID],
+			"download_id", downloadID,
+			"error", err,
+		)
+	}
+	for _, downloadID := range tagged {
+		line := t.pending[downloadID]
+		t.logger.Info(line.msg, line.attrs...)
+		strikesHandler.Reset(downloadID)
+	}
+	return len(tagged)
+}
+
+// runStrikes strikes each download at most once per job run. The same
+// download can be tracked by several *arr instances sharing a download
+// client, and shouldn't be struck once for each of them.
+type runStrikes struct {
+	handler *strikes.Handler
+	struck  map[string]bool // download IDs struck this run
+}
+
+func newRunStrikes(handler *strikes.Handler) *runStrikes {
+	return &runStrikes{handler: handler, struck: make(map[string]bool)}
+}
+
+// add strikes a download unless it was already struck this run, returning
+// its strike count
+func (r *runStrikes) add(downloadID, job, name, reason string) int {
+	if r.struck[downloadID] {
+		return r.handler.Get(downloadID)
+	}
+	r.struck[downloadID] = true
+	return r.handler.Add(downloadID, job, name, reason)
+}
+
+// jobInterval returns how often a job should run, or 0 to run every cycle
+func jobInterval(cfg *config.JobConfig) time.Duration {
+	if cfg.Interval != nil {
+		return *cfg.Interval
+	}
+	return 0
+}
+
+// jobOrder returns the job's position in the cycle, defaulting to running
+// with the other removal jobs before any searches
+func jobOrder(order *int) int {
+	if order != nil {
+		return *order
+	}
+	return jobs.RemovalJobOrder
+}
+
+// disableIndexerAfter returns the number of removals traced to one indexer
+// before it's disabled, or 0 if the job doesn't disable indexers
+func disableIndexerAfter(cfg *config.JobConfig) int {
+	if cfg.DisableIndexerAfter == nil {
+		return 0
+	}
+	return *cfg.DisableIndexerAfter
+}
@@ -0,0 +1,559 @@
+package removal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+// queueJobTests builds each queue-based removal job with a queue item it should remove
+var queueJobTests = []struct {
+	name string
+	item arrapi.QueueItem
+	job  func(cfg *config.Config, jobCfg *config.JobConfig, m *jobs.Manager) jobs.StatsJob
+}{
+	{
+		name: "failed downloads",
+		item: arrapi.QueueItem{ID: 1, Title: "Failed.Item", DownloadID: "good", TrackedDownloadStatus: "error"},
+		job: func(cfg *config.Config, jobCfg *config.JobConfig, m *jobs.Manager) jobs.StatsJob {
+			return NewFailedDownloadsJob("remove_failed_downloads", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+		},
+	},
+	{
+		name: "failed imports",
+		item: arrapi.QueueItem{ID: 1, Title: "Import.Item", DownloadID: "good", TrackedDownloadState: "importFailed"},
+		job: func(cfg *config.Config, jobCfg *config.JobConfig, m *jobs.Manager) jobs.StatsJob {
+			return NewFailedImportsJob("remove_failed_imports", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+		},
+	},
+	{
+		name: "stalled",
+		item: arrapi.QueueItem{ID: 1, Title: "Stalled.Item", DownloadID: "good", TrackedDownloadState: "importPending", TrackedDownloadStatus: "warning"},
+		job: func(cfg *config.Config, jobCfg *config.JobConfig, m *jobs.Manager) jobs.StatsJob {
+			return NewStalledJob("remove_stalled", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+		},
+	},
+	{
+		name: "not started",
+		item: arrapi.QueueItem{ID: 1, Title: "NotStarted.Item", DownloadID: "good", Status: "stalled", Size: 1024, Sizeleft: 1024, Added: time.Now().Add(-24 * time.Hour)},
+		job: func(cfg *config.Config, jobCfg *config.JobConfig, m *jobs.Manager) jobs.StatsJob {
+			return NewNotStartedJob("remove_not_started", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+		},
+	},
+}
+
+func TestJobsReportQueueFetchErrors(t *testing.T) {
+	for _, tt := range queueJobTests {
+		t.Run(tt.name, func(t *testing.T) {
+			good := newFakeArr(t, []arrapi.QueueItem{tt.item})
+			bad := newFakeArr(t, nil)
+			bad.handle("/api/v3/queue", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+
+			cfg := testConfig()
+			m := newTestManager(t, cfg, map[string]*fakeArr{"good": good, "bad": bad})
+
+			job := tt.job(cfg, &config.JobConfig{Enabled: true}, m)
+			err := job.Run(context.Background())
+
+			require.Error(t, err, "failed instance should be reported")
+			assert.Contains(t, err.Error(), "bad")
+
+			_, ok := good.deleted(1)
+			assert.True(t, ok, "healthy instance should still be processed")
+			assert.Equal(t, 1, job.Stats().Removed)
+		})
+	}
+}
+
+func TestJobsSkipProtectedCategories(t *testing.T) {
+	for _, tt := range queueJobTests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := newFakeArr(t, []arrapi.QueueItem{tt.item})
+
+			cfg := testConfig()
+			cfg.General.ProtectedCategories = []string{"permaseed"}
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+			client := newFakeDownloadClient(downloadclient.Torrent{
+				Hash:     tt.item.DownloadID,
+				Name:     tt.item.Title,
+				Category: "PermaSeed",
+				State:    downloadclient.StateStalled,
+			})
+			m.RegisterDownloadClient("qbittorrent", client)
+
+			job := tt.job(cfg, &config.JobConfig{Enabled: true}, m)
+			require.NoError(t, job.Run(context.Background()))
+
+			assert.Equal(t, 0, arr.deleteCount(), "torrent in protected category should not be removed")
+			_, deleted := client.wasDeleted(tt.item.DownloadID)
+			assert.False(t, deleted)
+		})
+	}
+}
+
+func TestJobsFilterProtocols(t *testing.T) {
+	for _, tt := range queueJobTests {
+		t.Run(tt.name, func(t *testing.T) {
+			torrent := tt.item
+			torrent.Protocol = "torrent"
+			usenet := tt.item
+			usenet.ID = 2
+			usenet.DownloadID = "usenet"
+			usenet.Protocol = "usenet"
+
+			arr := newFakeArr(t, []arrapi.QueueItem{torrent, usenet})
+			cfg := testConfig()
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+			job := tt.job(cfg, &config.JobConfig{Enabled: true, Protocols: []string{"Torrent"}}, m)
+			require.NoError(t, job.Run(context.Background()))
+
+			_, ok := arr.deleted(1)
+			assert.True(t, ok, "torrent item should be removed")
+			_, ok = arr.deleted(2)
+			assert.False(t, ok, "usenet item should be ignored by a torrent-only job")
+			assert.Equal(t, 1, job.Stats().Found)
+		})
+	}
+}
+
+func TestJobsCustomMessagePatterns(t *testing.T) {
+	type finder interface {
+		FindAffected(queue []arrapi.QueueItem) []arrapi.QueueItem
+	}
+
+	tests := []struct {
+		name    string
+		item    arrapi.QueueItem
+		pattern string
+		job     func(cfg *config.Config, jobCfg *config.JobConfig, m *jobs.Manager) finder
+	}{
+		{
+			name:    "failed downloads",
+			item:    arrapi.QueueItem{ID: 1, Title: "Unpack.Item", ErrorMessage: "Unpacking failed, write error or disk is full?"},
+			pattern: "*Unpacking failed*",
+			job: func(cfg *config.Config, jobCfg *config.JobConfig, m *jobs.Manager) finder {
+				return NewFailedDownloadsJob("remove_failed_downloads", jobCfg, &cfg.JobDefaults, m, testLogger(), true)
+			},
+		},
+		{
+			name: "bad files",
+			item: arrapi.QueueItem{ID: 1, Title: "Password.Item", StatusMessages: []arrapi.StatusMessage{{
+				Title:    "Password.Item",
+				Messages: []string{"Archive is password protected"},
+			}}},
+			pattern: "*password protected*",
+			job: func(cfg *config.Config, jobCfg *config.JobConfig, m *jobs.Manager) finder {
+				return NewBadFilesJob("remove_bad_files", jobCfg, &cfg.JobDefaults, m, testLogger(), true)
+			},
+		},
+		{
+			name: "metadata missing",
+			item: arrapi.QueueItem{ID: 1, Title: "Ambiguous.Item", TrackedDownloadStatus: "warning", StatusMessages: []arrapi.StatusMessage{{
+				Title:    "Ambiguous.Item",
+				Messages: []string{"Release title is ambiguous"},
+			}}},
+			pattern: "*ambiguous*",
+			job: func(cfg *config.Config, jobCfg *config.JobConfig, m *jobs.Manager) finder {
+				return NewMetadataMissingJob("remove_metadata_failed", jobCfg, &cfg.JobDefaults, m, testLogger(), true)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			m := newTestManager(t, cfg, nil)
+
+			job := tt.job(cfg, &config.JobConfig{Enabled: true}, m)
+			assert.Empty(t, job.FindAffected([]arrapi.QueueItem{tt.item}), "built-in keywords should not match")
+
+			job = tt.job(cfg, &config.JobConfig{Enabled: true, MessagePatterns: []string{tt.pattern}}, m)
+			assert.Len(t, job.FindAffected([]arrapi.QueueItem{tt.item}), 1, "custom pattern should match")
+		})
+	}
+}
+
+func TestJobsRequireDeleteOptin(t *testing.T) {
+	for _, tt := range queueJobTests {
+		t.Run(tt.name, func(t *testing.T) {
+			staged := newFakeArr(t, []arrapi.QueueItem{tt.item})
+			trusted := newFakeArr(t, []arrapi.QueueItem{tt.item})
+
+			cfg := testConfig()
+			cfg.General.RequireDeleteOptin = true
+			cfg.Instances.Sonarr = []config.InstanceConfig{
+				{Name: "staged", Enabled: true},
+				{Name: "trusted", Enabled: true, AllowDelete: true},
+			}
+			m := newTestManager(t, cfg, map[string]*fakeArr{"staged": staged, "trusted": trusted})
+
+			job := tt.job(cfg, &config.JobConfig{Enabled: true}, m)
+			require.NoError(t, job.Run(context.Background()))
+
+			assert.Equal(t, 0, staged.deleteCount(), "instance without allow_delete should be treated as a test run")
+			_, ok := trusted.deleted(1)
+			assert.True(t, ok, "instance with allow_delete should be removed from")
+		})
+	}
+
+	t.Run("logs would remove", func(t *testing.T) {
+		staged := newFakeArr(t, []arrapi.QueueItem{queueJobTests[0].item})
+
+		cfg := testConfig()
+		cfg.General.RequireDeleteOptin = true
+		cfg.Instances.Radarr = []config.InstanceConfig{{Name: "staged", Enabled: true}}
+		m := newTestManager(t, cfg, map[string]*fakeArr{"staged": staged})
+
+		var logs bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&logs, nil))
+		job := NewFailedDownloadsJob("remove_failed_downloads", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, logger, false)
+		require.NoError(t, job.Run(context.Background()))
+
+		assert.Equal(t, 0, staged.deleteCount())
+		assert.Contains(t, logs.String(), "would remove failed download")
+	})
+}
+
+func TestJobsKeepCrossSeededFiles(t *testing.T) {
+	tests := []struct {
+		name       string
+		torrents   []downloadclient.Torrent
+		wantClient bool
+	}{
+		{
+			name: "shared save path and name is cross-seeded",
+			torrents: []downloadclient.Torrent{
+				{Hash: "good", Name: "Shared.Release", SavePath: "/data/torrents", Category: "tv-sonarr"},
+				{Hash: "seed", Name: "Shared.Release", SavePath: "/data/torrents/", Category: "cross-seed"},
+			},
+		},
+		{
+			name: "shared content path is cross-seeded",
+			torrents: []downloadclient.Torrent{
+				{Hash: "good", Name: "Shared.Release", ContentPath: "/data/torrents/Shared.Release"},
+				{Hash: "seed", Name: "Shared Release", ContentPath: "/data/torrents/Shared.Release"},
+			},
+		},
+		{
+			name: "same save path with different content is removed",
+			torrents: []downloadclient.Torrent{
+				{Hash: "good", Name: "Shared.Release", SavePath: "/data/torrents"},
+				{Hash: "other", Name: "Other.Release", SavePath: "/data/torrents"},
+			},
+			wantClient: true,
+		},
+	}
+
+	for _, job := range queueJobTests {
+		for _, tt := range tests {
+			t.Run(job.name+"/"+tt.name, func(t *testing.T) {
+				arr := newFakeArr(t, []arrapi.QueueItem{job.item})
+
+				cfg := testConfig()
+				m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+				m.RegisterDownloadClient("qbittorrent", newFakeDownloadClient(tt.torrents...))
+
+				require.NoError(t, job.job(cfg, &config.JobConfig{Enabled: true}, m).Run(context.Background()))
+
+				params, ok := arr.deleted(1)
+				require.True(t, ok, "queue item should be removed from the arr")
+				if tt.wantClient {
+					assert.Equal(t, "true", params["removeFromClient"])
+				} else {
+					assert.Empty(t, params["removeFromClient"], "cross-seeded files must be kept")
+				}
+			})
+		}
+	}
+}
+
+func TestJobsHandleSparseQueueItems(t *testing.T) {
+	payloads := map[string]string{
+		"minimal": `{"id": 1}`,
+		"nulls": `{
+			"id": 1,
+			"title": null,
+			"status": null,
+			"trackedDownloadStatus": null,
+			"trackedDownloadState": null,
+			"statusMessages": null,
+			"errorMessage": null,
+			"downloadId": null,
+			"added": null,
+			"estimatedCompletionTime": null,
+			"seriesId": null,
+			"movieId": null,
+			"artistId": null,
+			"authorId": null
+		}`,
+		"null entries": `{"id": 1, "statusMessages": [null, {"title": null, "messages": [null]}]}`,
+	}
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, nil)
+	jobCfg := &config.JobConfig{Enabled: true}
+	finders := map[string]interface {
+		FindAffected([]arrapi.QueueItem) []arrapi.QueueItem
+	}{
+		"bad files":        NewBadFilesJob("remove_bad_files", jobCfg, &cfg.JobDefaults, m, testLogger(), true),
+		"failed downloads": NewFailedDownloadsJob("remove_failed_downloads", jobCfg, &cfg.JobDefaults, m, testLogger(), true),
+		"failed imports":   NewFailedImportsJob("remove_failed_imports", jobCfg, &cfg.JobDefaults, m, testLogger(), true),
+		"metadata missing": NewMetadataMissingJob("remove_metadata_failed", jobCfg, &cfg.JobDefaults, m, testLogger(), true),
+		"missing files":    NewMissingFilesJob("remove_missing_files", jobCfg, &cfg.JobDefaults, m, testLogger(), true),
+		"not started":      NewNotStartedJob("remove_not_started", jobCfg, &cfg.JobDefaults, m, testLogger(), true),
+		"slow":             NewSlowDownloadJob("remove_slow", jobCfg, &cfg.JobDefaults, m, testLogger(), true),
+		"stalled":          NewStalledJob("remove_stalled", jobCfg, &cfg.JobDefaults, m, testLogger(), true),
+	}
+	unmonitored := NewUnmonitoredJob("remove_unmonitored", jobCfg, &cfg.JobDefaults, m, testLogger(), true)
+
+	for name, payload := range payloads {
+		t.Run(name, func(t *testing.T) {
+			var item arrapi.QueueItem
+			require.NoError(t, json.Unmarshal([]byte(payload), &item))
+			assert.Equal(t, 1, item.ID)
+
+			for job, finder := range finders {
+				assert.NotPanics(t, func() {
+					assert.Empty(t, finder.FindAffected([]arrapi.QueueItem{item}), "%s should not match a sparse item", job)
+				}, job)
+			}
+
+			for _, app := range []string{"Sonarr", "Radarr", "Lidarr", "Readarr"} {
+				// Without an entity ID the arr is never asked, so no client is needed
+				isUnmonitored, err := unmonitored.checkUnmonitored(context.Background(), nil, app, &item)
+				require.NoError(t, err)
+				assert.False(t, isUnmonitored, app)
+			}
+		})
+	}
+}
+
+func TestObsoleteTagCapabilities(t *testing.T) {
+	torrent := downloadclient.Torrent{Hash: "abc", Name: "Some.Download"}
+
+	t.Run("taggable client is tagged", func(t *testing.T) {
+		client := newFakeDownloadClient(torrent)
+		m := newTestManager(t, testConfig(), nil)
+		m.RegisterDownloadClient("qbittorrent", client)
+
+		require.NoError(t, m.ApplyObsoleteTag(context.Background(), "abc"))
+		require.NoError(t, m.ApplyObsoleteTagBulk(context.Background(), client, []string{"abc"}))
+		assert.Equal(t, []string{"Obsolete", "Obsolete"}, client.tags["abc"])
+	})
+
+	t.Run("client without tags reports it", func(t *testing.T) {
+		client := newFakeDownloadClient(torrent)
+		m := newTestManager(t, testConfig(), nil)
+		m.RegisterDownloadClient("bare", bareClient{client})
+
+		err := m.ApplyObsoleteTag(context.Background(), "abc")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "doesn't support tags")
+		err = m.ApplyObsoleteTagBulk(context.Background(), bareClient{client}, []string{"abc"})
+		require.Error(t, err)
+		assert.Empty(t, client.tags)
+	})
+}
+
+func TestUsenetDownloadsIgnoreTrackerHandling(t *testing.T) {
+	cfg := testConfig()
+	cfg.General.PublicTrackerHandling = "obsolete_tag"
+	cfg.General.PrivateTrackerHandling = "skip"
+	m := newTestManager(t, cfg, nil)
+
+	usenet := newFakeDownloadClient(downloadclient.Torrent{Hash: "sabnzbd_nzo_1", Name: "Usenet.Item"})
+	usenet.seeds = false
+	m.RegisterDownloadClient("sabnzbd", usenet)
+	m.RegisterDownloadClient("qbittorrent", newFakeDownloadClient(downloadclient.Torrent{Hash: "abc", Name: "Torrent.Item"}))
+
+	assert.Equal(t, "remove", m.GetRemovalAction(context.Background(), "sabnzbd_nzo_1"), "usenet downloads have no trackers")
+	assert.Equal(t, "tag", m.GetRemovalAction(context.Background(), "abc"))
+}
+
+func TestQueueDetailsDriveDetection(t *testing.T) {
+	// The paged queue only reports a warning, while queue/details has the reason
+	summary := arrapi.QueueItem{
+		ID:                    1,
+		Title:                 "Bad.Download",
+		DownloadID:            "abc",
+		Status:                "completed",
+		TrackedDownloadStatus: "warning",
+		StatusMessages:        []arrapi.StatusMessage{{Title: "Bad.Download"}},
+	}
+	detailed := summary
+	detailed.StatusMessages = []arrapi.StatusMessage{
+		{Title: "Bad.Download", Messages: []string{"File is corrupt and can't be imported"}},
+	}
+
+	tests := []struct {
+		name        string
+		details     bool
+		serve       bool
+		wantRemoved bool
+	}{
+		{name: "summary queue misses the reason", details: false, serve: true},
+		{name: "details reveal the reason", details: true, serve: true, wantRemoved: true},
+		{name: "falls back to the summary queue without details", details: true, serve: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := newFakeArr(t, []arrapi.QueueItem{summary})
+			if tt.serve {
+				arr.handle("/api/v3/queue/details", func(w http.ResponseWriter, r *http.Request) {
+					_ = json.NewEncoder(w).Encode([]arrapi.QueueItem{detailed})
+				})
+			}
+
+			cfg := testConfig()
+			cfg.General.QueueDetails = tt.details
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+			job := NewBadFilesJob("remove_bad_files", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+			require.NoError(t, job.Run(context.Background()))
+
+			_, removed := arr.deleted(1)
+			assert.Equal(t, tt.wantRemoved, removed)
+		})
+	}
+}
+
+// unreachableClient is a download client whose torrents can't be fetched
+type unreachableClient struct {
+	*fakeDownloadClient
+}
+
+func (c unreachableClient) GetTorrents(ctx context.Context) ([]downloadclient.Torrent, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestRunAllClearsStrikesOfExternallyRemovedDownloads(t *testing.T) {
+	seed := func(m *jobs.Manager) {
+		handler := m.GetStrikesHandler()
+		handler.Add("QUEUEDHASH", "remove_stalled", "Queued.Item", "download stalled")
+		handler.Add("seedinghash", "remove_orphans", "Seeding.Item", "not tracked by any arr instance")
+		handler.Add("GONEHASH", "remove_stalled", "Gone.Item", "download stalled")
+		handler.ResetCycleCounters()
+	}
+
+	t.Run("missing everywhere", func(t *testing.T) {
+		arr := newFakeArr(t, []arrapi.QueueItem{{ID: 1, Title: "Queued.Item", DownloadID: "QUEUEDHASH"}})
+		m := newTestManager(t, testConfig(), map[string]*fakeArr{"sonarr": arr})
+		m.RegisterDownloadClient("qbit", newFakeDownloadClient(downloadclient.Torrent{Hash: "queuedhash"}, downloadclient.Torrent{Hash: "SeedingHash"}))
+		seed(m)
+
+		require.NoError(t, m.RunAll(context.Background()))
+
+		records := m.GetStrikesHandler().GetAllRecords()
+		assert.Contains(t, records, "QUEUEDHASH")
+		assert.Contains(t, records, "seedinghash", "a torrent only in the download client is still present")
+		assert.NotContains(t, records, "GONEHASH", "a download in no queue or client was removed externally")
+		assert.Equal(t, 1, m.GetLastStats().StrikesReset)
+		assert.Equal(t, 2, m.GetLastStats().TotalStrikes)
+	})
+
+	t.Run("queue unavailable", func(t *testing.T) {
+		arr := newFakeArr(t, nil)
+		arr.handle("/api/v3/queue", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		m := newTestManager(t, testConfig(), map[string]*fakeArr{"sonarr": arr})
+		m.RegisterDownloadClient("qbit", newFakeDownloadClient())
+		seed(m)
+
+		require.NoError(t, m.RunAll(context.Background()))
+		assert.Equal(t, 3, m.GetStrikesHandler().Count(), "strikes are kept when a queue couldn't be fetched")
+	})
+
+	t.Run("download client unreachable", func(t *testing.T) {
+		arr := newFakeArr(t, nil)
+		m := newTestManager(t, testConfig(), map[string]*fakeArr{"sonarr": arr})
+		m.RegisterDownloadClient("qbit", unreachableClient{newFakeDownloadClient()})
+		seed(m)
+
+		require.NoError(t, m.RunAll(context.Background()))
+		assert.Equal(t, 3, m.GetStrikesHandler().Count(), "strikes are kept when a download client couldn't be reached")
+	})
+}
+
+func TestJobsDeleteFilesPolicy(t *testing.T) {
+	for _, tt := range queueJobTests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, policy := range []struct {
+				name        string
+				deleteFiles *bool
+			}{
+				{name: "default", deleteFiles: nil},
+				{name: "delete", deleteFiles: boolPtr(true)},
+				{name: "keep", deleteFiles: boolPtr(false)},
+			} {
+				t.Run(policy.name, func(t *testing.T) {
+					arr := newFakeArr(t, []arrapi.QueueItem{tt.item})
+					cfg := testConfig()
+					m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+					client := newFakeDownloadClient(downloadclient.Torrent{
+						Hash:  tt.item.DownloadID,
+						Name:  tt.item.Title,
+						State: downloadclient.StateStalled,
+					})
+					m.RegisterDownloadClient("qbittorrent", client)
+
+					job := tt.job(cfg, &config.JobConfig{Enabled: true, DeleteFiles: policy.deleteFiles}, m)
+					require.NoError(t, job.Run(context.Background()))
+
+					params, ok := arr.deleted(1)
+					require.True(t, ok, "item should be removed from the arr")
+					deleteFiles, deletedByUs := client.wasDeleted(tt.item.DownloadID)
+
+					if policy.deleteFiles == nil || *policy.deleteFiles {
+						assert.Equal(t, "true", params["removeFromClient"], "the arr removes the download and its files")
+						assert.False(t, deletedByUs)
+						return
+					}
+					assert.Empty(t, params["removeFromClient"], "the arr shouldn't delete the files")
+					require.True(t, deletedByUs, "the torrent should still be removed from the client")
+					assert.False(t, deleteFiles, "the torrent's files should be kept")
+				})
+			}
+		})
+	}
+}
+
+func TestJobsResolveUppercaseDownloadIDs(t *testing.T) {
+	// The *arr reports torrent hashes in uppercase, qBittorrent in lowercase
+	arr := newFakeArr(t, []arrapi.QueueItem{{ID: 1, Title: "Protected.Item", DownloadID: "ABCDEF0123", Status: "stalled"}})
+
+	cfg := testConfig()
+	cfg.General.ProtectedCategories = []string{"permaseed"}
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+	client := newFakeDownloadClient(downloadclient.Torrent{
+		Hash:     "abcdef0123",
+		Name:     "Protected.Item",
+		Category: "permaseed",
+		State:    downloadclient.StateStalled,
+	})
+	m.RegisterDownloadClient("qbittorrent", client)
+
+	job := NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+	require.NoError(t, job.Run(context.Background()))
+	assert.Zero(t, arr.deleteCount(), "the uppercase download ID should resolve to the protected torrent")
+
+	require.NoError(t, m.PauseDownload(context.Background(), "ABCDEF0123"))
+	assert.Equal(t, map[string]bool{"abcdef0123": true}, client.paused, "the client's own hash should be paused")
+}
@@ -2,12 +2,13 @@ package removal
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
 	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
 	"github.com/jmylchreest/go-decluttarr/internal/jobs"
 )
 
@@ -61,6 +62,21 @@ func (j *FailedDownloadsJob) Enabled() bool {
 	return j.enabled
 }
 
+// SetTestRun switches the job in or out of test-run mode
+func (j *FailedDownloadsJob) SetTestRun(testRun bool) {
+	j.testRun = testRun
+}
+
+// Interval returns how often the job runs, or 0 to run every cycle
+func (j *FailedDownloadsJob) Interval() time.Duration {
+	return jobInterval(j.cfg)
+}
+
+// Order returns the job's position in the cycle
+func (j *FailedDownloadsJob) Order() int {
+	return jobOrder(j.cfg.Order)
+}
+
 // FindAffected identifies failed download items in the queue
 func (j *FailedDownloadsJob) FindAffected(queue []arrapi.QueueItem) []arrapi.QueueItem {
 	var affected []arrapi.QueueItem
@@ -74,8 +90,32 @@ func (j *FailedDownloadsJob) FindAffected(queue []arrapi.QueueItem) []arrapi.Que
 	return affected
 }
 
+// filterQueue applies the job's scope and filters to an instance's queue,
+// leaving the items detection is run on
+func (j *FailedDownloadsJob) filterQueue(instanceName string, queue []arrapi.QueueItem) []arrapi.QueueItem {
+	if !jobs.InInstances(j.cfg.Instances, instanceName) {
+		return nil
+	}
+	queue = jobs.FilterProtocols(queue, j.cfg.Protocols)
+	queue = withoutFreshGrabs(queue, minGrabAge(j.cfg, j.defaults), j.logger)
+	queue = withoutProcessed(queue, j.manager, instanceName, j.logger)
+	return queue
+}
+
+// Detect returns the items of an instance's queue a run would strike, given
+// the download clients' torrents keyed by lowercase hash
+func (j *FailedDownloadsJob) Detect(instanceName string, queue []arrapi.QueueItem, _ map[string]downloadclient.Torrent) []arrapi.QueueItem {
+	queue = j.filterQueue(instanceName, queue)
+	return j.FindAffected(queue)
+}
+
 // isFailedDownload determines if a queue item is a failed download
 func (j *FailedDownloadsJob) isFailedDownload(item arrapi.QueueItem) bool {
+	// Items left behind by a removed/renamed download client are handled separately
+	if j.isStaleClientItem(item) {
+		return true
+	}
+
 	// Check TrackedDownloadStatus for error/warning
 	if item.TrackedDownloadStatus == "error" || item.TrackedDownloadStatus == "warning" {
 		// Verify it's a download failure (not import failure)
@@ -90,17 +130,17 @@ func (j *FailedDownloadsJob) isFailedDownload(item arrapi.QueueItem) bool {
 
 		// Common download failure indicators
 		if strings.Contains(title, "download") &&
-		   (strings.Contains(title, "failed") ||
-		    strings.Contains(title, "error") ||
-		    strings.Contains(title, "missing") ||
-		    strings.Contains(title, "corrupt")) {
+			(strings.Contains(title, "failed") ||
+				strings.Contains(title, "error") ||
+				strings.Contains(title, "missing") ||
+				strings.Contains(title, "corrupt")) {
 			return true
 		}
 
 		// Specific download failure messages
 		if msg.Title == "Download client unavailable" ||
-		   msg.Title == "No files found are eligible for import" ||
-		   msg.Title == "Unable to determine if file is a sample" {
+			msg.Title == "No files found are eligible for import" ||
+			msg.Title == "Unable to determine if file is a sample" {
 			return true
 		}
 	}
@@ -113,6 +153,98 @@ func (j *FailedDownloadsJob) isFailedDownload(item arrapi.QueueItem) bool {
 		}
 	}
 
+	// User-defined patterns augment the built-in checks
+	return jobs.MatchesMessages(item, j.cfg.MessagePatterns)
+}
+
+// isStaleClientItem determines if a queue item references a download client that
+// is unavailable in the arr and not registered with go-decluttarr. These entries
+// linger forever once a client has been removed or renamed.
+func (j *FailedDownloadsJob) isStaleClientItem(item arrapi.QueueItem) bool {
+	if item.DownloadClient == "" || j.isKnownDownloadClient(item.DownloadClient) {
+		return false
+	}
+
+	for _, msg := range item.StatusMessages {
+		if strings.Contains(strings.ToLower(msg.Title), "download client unavailable") {
+			return true
+		}
+		for _, message := range msg.Messages {
+			if strings.Contains(strings.ToLower(message), "download client unavailable") {
+				return true
+			}
+		}
+	}
+
+	return strings.Contains(strings.ToLower(item.ErrorMessage), "download client unavailable")
+}
+
+// failureClass is why a download failed, which decides how it's removed
+type failureClass string
+
+const (
+	failureGeneric           failureClass = "generic"            // blocklist and don't search again
+	failureIndexer           failureClass = "indexer"            // the release or its indexer is at fault: blocklist and search again
+	failureClientUnavailable failureClass = "client_unavailable" // the download client is unreachable: not struck or removed, only retried next cycle
+	failureStaleClient       failureClass = "stale_client"       // the download client no longer exists: only clear the queue entry
+)
+
+// deleteOptions returns how a download that failed this way is removed
+func (c failureClass) deleteOptions() arrapi.DeleteOptions {
+	switch c {
+	case failureIndexer:
+		return arrapi.DeleteOptions{RemoveFromClient: true, Blocklist: true, SkipRedownload: false}
+	case failureStaleClient:
+		return arrapi.DeleteOptions{RemoveFromClient: false, Blocklist: false, SkipRedownload: true}
+	default:
+		return arrapi.DeleteOptions{RemoveFromClient: true, Blocklist: true, SkipRedownload: true}
+	}
+}
+
+// indexerFailureTitles are the status message titles of a release the
+// indexer served broken. Titles are matched whole, as many unrelated messages
+// mention the indexer or the grab history.
+var indexerFailureTitles = []string{
+	"Indexer returned an invalid torrent",
+	"Invalid torrent file",
+	"Not a valid torrent file",
+	"Unable to retrieve release from indexer",
+}
+
+// classifyFailure works out why a download failed from its status message
+// titles and error message
+func (j *FailedDownloadsJob) classifyFailure(item arrapi.QueueItem) failureClass {
+	if j.isStaleClientItem(item) {
+		return failureStaleClient
+	}
+
+	texts := []string{item.ErrorMessage}
+	for _, msg := range item.StatusMessages {
+		texts = append(texts, msg.Title)
+	}
+
+	for _, text := range texts {
+		if strings.Contains(strings.ToLower(text), "download client unavailable") {
+			return failureClientUnavailable
+		}
+	}
+	for _, text := range texts {
+		for _, title := range indexerFailureTitles {
+			if strings.EqualFold(strings.TrimSpace(text), title) {
+				return failureIndexer
+			}
+		}
+	}
+	return failureGeneric
+}
+
+// isKnownDownloadClient checks if a download client name is registered with the manager
+func (j *FailedDownloadsJob) isKnownDownloadClient(name string) bool {
+	for clientName := range j.manager.GetAllDownloadClients() {
+		if strings.EqualFold(clientName, name) {
+			return true
+		}
+	}
 	return false
 }
 
@@ -120,17 +252,23 @@ func (j *FailedDownloadsJob) isFailedDownload(item arrapi.QueueItem) bool {
 func (j *FailedDownloadsJob) Run(ctx context.Context) error {
 	j.logger.Debug("starting failed downloads removal job", "test_run", j.testRun, "max_strikes", j.maxStrikes)
 
-	queues, err := j.manager.GetAllQueues(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get queues: %w", err)
-	}
+	queues, queueErr := j.manager.GetAllQueues(ctx)
+	queues = jobs.ScopeQueues(queues, j.cfg.Instances)
 
 	strikesHandler := j.manager.GetStrikesHandler()
+	struck := newRunStrikes(strikesHandler)
+	trace := newDecisionTracer(j.manager, j.logger, j.maxStrikes, j.testRun)
 	totalProcessed := 0
 	totalRemoved := 0
+	tags := newObsoleteTagger(j.manager, j.logger)
 
 	for instanceName, queue := range queues {
+		if !dataReliable(j.manager, instanceName, j.logger) {
+			continue
+		}
+		queue = j.filterQueue(instanceName, queue)
 		affected := j.FindAffected(queue)
+		trace.unmatched(instanceName, queue, affected)
 		j.logger.Debug("found failed downloads",
 			"instance", instanceName,
 			"count", len(affected),
@@ -139,8 +277,22 @@ func (j *FailedDownloadsJob) Run(ctx context.Context) error {
 		for _, item := range affected {
 			totalProcessed++
 
+			class := j.classifyFailure(item)
+			if class == failureClientUnavailable {
+				// Nothing is wrong with the download itself, so it's left for
+				// the client to come back
+				j.logger.Info("download client unavailable, will retry next cycle",
+					"title", item.Title,
+					"download_id", item.DownloadID,
+					"download_client", item.DownloadClient,
+					"instance", instanceName,
+				)
+				continue
+			}
+
 			// Add strike for this download
-			currentStrikes := strikesHandler.Add(item.DownloadID, j.name, item.Title)
+			reason := jobs.QueueItemReason(item, "download failed")
+			currentStrikes := struck.add(item.DownloadID, j.name, item.Title, reason)
 			j.logger.Debug("added strike to failed download",
 				"title", item.Title,
 				"download_id", item.DownloadID,
@@ -151,8 +303,11 @@ func (j *FailedDownloadsJob) Run(ctx context.Context) error {
 				"instance", instanceName,
 			)
 
+			maxStrikes := maxStrikesFor(ctx, j.manager, j.cfg, j.defaults, j.maxStrikes, item.DownloadID)
+			trace.struck(ctx, instanceName, item, reason, currentStrikes, maxStrikes)
+
 			// Check if max strikes exceeded
-			if strikesHandler.HasExceeded(item.DownloadID, j.maxStrikes) {
+			if j.manager.StrikesExceeded(item.DownloadID, maxStrikes) {
 				// Determine removal action based on tracker type and protected tags
 				action := j.manager.GetRemovalAction(ctx, item.DownloadID)
 
@@ -169,20 +324,13 @@ func (j *FailedDownloadsJob) Run(ctx context.Context) error {
 							"instance", instanceName,
 						)
 					} else {
-						if err := j.manager.ApplyObsoleteTag(ctx, item.DownloadID); err != nil {
-							j.logger.Error("failed to tag as obsolete",
-								"title", item.Title,
-								"download_id", item.DownloadID,
-								"error", err,
-							)
-							continue
-						}
-						j.logger.Info("tagged failed download as obsolete",
+						tags.add(ctx, item, "tagged failed download as obsolete",
 							"title", item.Title,
 							"download_id", item.DownloadID,
 							"strikes", currentStrikes,
 							"instance", instanceName,
 						)
+						continue
 					}
 					strikesHandler.Reset(item.DownloadID)
 					totalRemoved++ // Count as handled
@@ -191,17 +339,18 @@ func (j *FailedDownloadsJob) Run(ctx context.Context) error {
 					// Proceed with removal
 				}
 
-				if j.testRun {
+				if j.testRun || !j.manager.DeleteAllowed(instanceName) {
 					j.logger.Info("[TEST RUN] would remove failed download",
 						"title", item.Title,
 						"download_id", item.DownloadID,
 						"strikes", currentStrikes,
 						"status", item.TrackedDownloadStatus,
 						"error", item.ErrorMessage,
+						"failure_class", class,
 						"instance", instanceName,
 					)
 				} else {
-					if err := j.removeItem(ctx, instanceName, item); err != nil {
+					if err := j.removeItem(ctx, instanceName, item, class); err != nil {
 						j.logger.Error("failed to remove failed download",
 							"title", item.Title,
 							"download_id", item.DownloadID,
@@ -219,13 +368,21 @@ func (j *FailedDownloadsJob) Run(ctx context.Context) error {
 						"title", item.Title,
 						"download_id", item.DownloadID,
 						"strikes", currentStrikes,
+						"failure_class", class,
 						"instance", instanceName,
 					)
+
+					// A vanished download client says nothing about the indexer
+					if class != failureStaleClient {
+						j.manager.RecordIndexerFailure(ctx, instanceName, item.Indexer, disableIndexerAfter(j.cfg))
+					}
 				}
 			}
 		}
 	}
 
+	totalRemoved += tags.apply(ctx, strikesHandler)
+
 	j.logger.Debug("failed downloads removal job completed",
 		"processed", totalProcessed,
 		"removed", totalRemoved,
@@ -235,23 +392,12 @@ func (j *FailedDownloadsJob) Run(ctx context.Context) error {
 	j.lastFound = totalProcessed
 	j.lastRemoved = totalRemoved
 
-	return nil
+	return jobs.QueueError(queueErr)
 }
 
-// removeItem removes a queue item from the arr instance
-func (j *FailedDownloadsJob) removeItem(ctx context.Context, instanceName string, item arrapi.QueueItem) error {
-	client, ok := j.manager.GetArrClient(instanceName)
-	if !ok {
-		return fmt.Errorf("arr client not found: %s", instanceName)
-	}
-
-	opts := arrapi.DeleteOptions{
-		RemoveFromClient: true,
-		Blocklist:        true, // Blocklist failed downloads to prevent re-download
-		SkipRedownload:   true,
-	}
-
-	return client.DeleteQueueItem(ctx, item.ID, opts)
+// removeItem removes a queue item from the arr instance, as suits why it failed
+func (j *FailedDownloadsJob) removeItem(ctx context.Context, instanceName string, item arrapi.QueueItem, class failureClass) error {
+	return j.manager.RemoveQueueItem(ctx, instanceName, item, class.deleteOptions(), reviewCategory(j.cfg, j.defaults), reasonTag(j.cfg, j.defaults, "failed-download"), deleteFiles(j.cfg.DeleteFiles, true))
 }
 
 // Stats returns the statistics from the last job run
@@ -0,0 +1,332 @@
+package removal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+)
+
+func TestFailedDownloadsStaleClient(t *testing.T) {
+	unavailable := []arrapi.StatusMessage{{
+		Title:    "Download client unavailable",
+		Messages: []string{"Download client is unavailable"},
+	}}
+
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		{
+			ID:                    1,
+			Title:                 "Stale.Item",
+			DownloadID:            "stale",
+			DownloadClient:        "old-qbittorrent",
+			TrackedDownloadStatus: "warning",
+			StatusMessages:        unavailable,
+		},
+		{
+			ID:                    2,
+			Title:                 "Failed.Item",
+			DownloadID:            "failed",
+			DownloadClient:        "qbittorrent",
+			TrackedDownloadStatus: "error",
+		},
+	})
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+	m.RegisterDownloadClient("qbittorrent", newFakeDownloadClient())
+
+	jobCfg := &config.JobConfig{Enabled: true}
+	job := NewFailedDownloadsJob("remove_failed_downloads", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+
+	require.NoError(t, job.Run(context.Background()))
+
+	params, ok := arr.deleted(1)
+	require.True(t, ok, "stale client item should be removed")
+	assert.Empty(t, params["removeFromClient"], "stale client item must not be removed from client")
+	assert.Empty(t, params["blocklist"])
+
+	params, ok = arr.deleted(2)
+	require.True(t, ok, "failed download should be removed")
+	assert.Equal(t, "true", params["removeFromClient"])
+	assert.Equal(t, "true", params["blocklist"])
+}
+
+func TestFailedDownloadsIsStaleClientItem(t *testing.T) {
+	cfg := testConfig()
+	m := newTestManager(t, cfg, nil)
+	m.RegisterDownloadClient("qbittorrent", newFakeDownloadClient())
+
+	job := NewFailedDownloadsJob("remove_failed_downloads", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), true)
+
+	unavailable := []arrapi.StatusMessage{{Title: "Download client unavailable"}}
+
+	tests := []struct {
+		name string
+		item arrapi.QueueItem
+		want bool
+	}{
+		{
+			name: "unknown client with unavailable warning",
+			item: arrapi.QueueItem{DownloadClient: "removed-client", StatusMessages: unavailable},
+			want: true,
+		},
+		{
+			name: "registered client matched case-insensitively",
+			item: arrapi.QueueItem{DownloadClient: "qBittorrent", StatusMessages: unavailable},
+			want: false,
+		},
+		{
+			name: "unknown client without warning",
+			item: arrapi.QueueItem{DownloadClient: "removed-client"},
+			want: false,
+		},
+		{
+			name: "no client reported",
+			item: arrapi.QueueItem{StatusMessages: unavailable},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, job.isStaleClientItem(tt.item))
+		})
+	}
+}
+
+func TestFailedDownloadsBlocklistByTrackerType(t *testing.T) {
+	tests := []struct {
+		name             string
+		blocklistPublic  *bool
+		blocklistPrivate *bool
+		wantPublic       string
+		wantPrivate      string
+	}{
+		{
+			name:        "job default blocklists both",
+			wantPublic:  "true",
+			wantPrivate: "true",
+		},
+		{
+			name:             "private releases are not blocklisted",
+			blocklistPrivate: boolPtr(false),
+			wantPublic:       "true",
+			wantPrivate:      "",
+		},
+		{
+			name:             "per-type settings override the job",
+			blocklistPublic:  boolPtr(false),
+			blocklistPrivate: boolPtr(true),
+			wantPublic:       "",
+			wantPrivate:      "true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := newFakeArr(t, []arrapi.QueueItem{
+				{ID: 1, Title: "Public.Item", DownloadID: "public", DownloadClient: "qbittorrent", TrackedDownloadStatus: "error"},
+				{ID: 2, Title: "Private.Item", DownloadID: "private", DownloadClient: "qbittorrent", TrackedDownloadStatus: "error"},
+			})
+
+			cfg := testConfig()
+			cfg.General.BlocklistPublic = tt.blocklistPublic
+			cfg.General.BlocklistPrivate = tt.blocklistPrivate
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+			client := newFakeDownloadClient(
+				downloadclient.Torrent{Hash: "public", Name: "Public.Item"},
+				downloadclient.Torrent{Hash: "private", Name: "Private.Item"},
+			)
+			client.props["private"] = &downloadclient.TorrentProperties{IsPrivate: true}
+			m.RegisterDownloadClient("qbittorrent", client)
+
+			job := NewFailedDownloadsJob("remove_failed_downloads", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+			require.NoError(t, job.Run(context.Background()))
+
+			params, ok := arr.deleted(1)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantPublic, params["blocklist"])
+
+			params, ok = arr.deleted(2)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantPrivate, params["blocklist"])
+		})
+	}
+}
+
+func TestFailedDownloadsMinGrabAge(t *testing.T) {
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		{ID: 1, Title: "Fresh.Grab", DownloadID: "fresh", TrackedDownloadStatus: "error", Added: time.Now().Add(-30 * time.Second)},
+		{ID: 2, Title: "Old.Grab", DownloadID: "old", TrackedDownloadStatus: "error", Added: time.Now().Add(-time.Hour)},
+		{ID: 3, Title: "Unknown.Grab", DownloadID: "unknown", TrackedDownloadStatus: "error"},
+	})
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+	m.RegisterDownloadClient("qbittorrent", newFakeDownloadClient())
+
+	grabAge := 5 * time.Minute
+	jobCfg := &config.JobConfig{Enabled: true, MinGrabAge: &grabAge}
+	job := NewFailedDownloadsJob("remove_failed_downloads", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+
+	require.NoError(t, job.Run(context.Background()))
+
+	_, ok := arr.deleted(1)
+	assert.False(t, ok, "freshly grabbed item should be exempt")
+	assert.Zero(t, m.GetStrikesHandler().Get("fresh"), "freshly grabbed item should not be struck")
+
+	_, ok = arr.deleted(2)
+	assert.True(t, ok, "item grabbed before min_grab_age should be removed")
+	_, ok = arr.deleted(3)
+	assert.True(t, ok, "item without a grab time should be removed")
+}
+
+func TestFailedDownloadsSkipsProtectedStates(t *testing.T) {
+	tests := []struct {
+		name        string
+		states      []string
+		wantDeleted bool
+	}{
+		{
+			name:   "checking torrent is skipped",
+			states: []string{"checkingDL", "moving"},
+		},
+		{
+			name:   "normalized state names match too",
+			states: []string{"queued"},
+		},
+		{
+			name:        "empty list protects no states",
+			wantDeleted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := newFakeArr(t, []arrapi.QueueItem{
+				{ID: 1, Title: "Checking.Item", DownloadID: "abc", TrackedDownloadStatus: "error"},
+			})
+
+			cfg := testConfig()
+			cfg.General.ProtectedStates = tt.states
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+			// qBittorrent reports checkingDL, normalized to queued
+			m.RegisterDownloadClient("qbittorrent", newFakeDownloadClient(downloadclient.Torrent{
+				Hash:        "abc",
+				Name:        "Checking.Item",
+				State:       downloadclient.StateQueued,
+				ClientState: "checkingDL",
+			}))
+
+			job := NewFailedDownloadsJob("remove_failed_downloads", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+			require.NoError(t, job.Run(context.Background()))
+
+			_, deleted := arr.deleted(1)
+			assert.Equal(t, tt.wantDeleted, deleted)
+		})
+	}
+}
+
+func TestFailedDownloadsFailureClasses(t *testing.T) {
+	tests := []struct {
+		name               string
+		item               arrapi.QueueItem
+		wantClass          failureClass
+		wantFromClient     string
+		wantBlocklist      string
+		wantSkipRedownload string
+		wantKept           bool // neither struck nor removed
+	}{
+		{
+			name: "indexer failure is blocklisted and searched again",
+			item: arrapi.QueueItem{
+				DownloadClient:        "qbittorrent",
+				TrackedDownloadStatus: "warning",
+				StatusMessages:        []arrapi.StatusMessage{{Title: "Indexer returned an invalid torrent"}},
+			},
+			wantClass:      failureIndexer,
+			wantFromClient: "true",
+			wantBlocklist:  "true",
+		},
+		{
+			name: "unavailable client is only retried",
+			item: arrapi.QueueItem{
+				DownloadClient:        "qbittorrent",
+				TrackedDownloadStatus: "warning",
+				StatusMessages:        []arrapi.StatusMessage{{Title: "Download client unavailable"}},
+			},
+			wantClass: failureClientUnavailable,
+			wantKept:  true,
+		},
+		{
+			name: "grab history message isn't an indexer failure",
+			item: arrapi.QueueItem{
+				DownloadClient:        "qbittorrent",
+				TrackedDownloadStatus: "warning",
+				StatusMessages:        []arrapi.StatusMessage{{Title: "Found matching series via grab history, but release was matched to series by ID"}},
+			},
+			wantClass:          failureGeneric,
+			wantFromClient:     "true",
+			wantBlocklist:      "true",
+			wantSkipRedownload: "true",
+		},
+		{
+			name: "vanished client only clears the queue entry",
+			item: arrapi.QueueItem{
+				DownloadClient:        "old-qbittorrent",
+				TrackedDownloadStatus: "warning",
+				StatusMessages:        []arrapi.StatusMessage{{Title: "Download client unavailable"}},
+			},
+			wantClass:          failureStaleClient,
+			wantSkipRedownload: "true",
+		},
+		{
+			name: "other failures are blocklisted without searching again",
+			item: arrapi.QueueItem{
+				DownloadClient:        "qbittorrent",
+				TrackedDownloadStatus: "error",
+				ErrorMessage:          "Download failed",
+			},
+			wantClass:          failureGeneric,
+			wantFromClient:     "true",
+			wantBlocklist:      "true",
+			wantSkipRedownload: "true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := tt.item
+			item.ID, item.Title, item.DownloadID = 1, "Failed.Item", "failed"
+			arr := newFakeArr(t, []arrapi.QueueItem{item})
+
+			cfg := testConfig()
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+			m.RegisterDownloadClient("qbittorrent", newFakeDownloadClient())
+
+			job := NewFailedDownloadsJob("remove_failed_downloads", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+			assert.Equal(t, tt.wantClass, job.classifyFailure(item))
+
+			require.NoError(t, job.Run(context.Background()))
+
+			params, ok := arr.deleted(1)
+			if tt.wantKept {
+				assert.False(t, ok, "the download should be retried, not removed")
+				assert.Zero(t, m.GetStrikesHandler().Get("failed"), "the download should not be struck")
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tt.wantFromClient, params["removeFromClient"])
+			assert.Equal(t, tt.wantBlocklist, params["blocklist"])
+			assert.Equal(t, tt.wantSkipRedownload, params["skipRedownload"])
+		})
+	}
+}
@@ -2,13 +2,13 @@ package removal
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
-	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
 	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
 	"github.com/jmylchreest/go-decluttarr/internal/jobs"
 )
 
@@ -24,6 +24,18 @@ type FailedImportsJob struct {
 	maxStrikes  int
 	lastFound   int
 	lastRemoved int
+
+	// importBlocked also treats importBlocked items as failed imports
+	importBlocked bool
+
+	// importPendingAfter treats completed downloads grabbed longer ago that
+	// are still pending import as failed imports, after one import attempt
+	importPendingAfter time.Duration
+
+	// manualImport attempts a manual import before striking; each download
+	// is only attempted once while it stays in the queue
+	manualImport    bool
+	importAttempted map[string]bool
 }
 
 // NewFailedImportsJob creates a new failed imports removal job
@@ -40,15 +52,34 @@ func NewFailedImportsJob(
 		maxStrikes = *cfg.MaxStrikes
 	}
 
+	manualImport := false
+	if cfg.ManualImport != nil {
+		manualImport = *cfg.ManualImport
+	}
+
+	importBlocked := false
+	if cfg.ImportBlocked != nil {
+		importBlocked = *cfg.ImportBlocked
+	}
+
+	var importPendingAfter time.Duration
+	if cfg.ImportPendingAfter != nil {
+		importPendingAfter = *cfg.ImportPendingAfter
+	}
+
 	return &FailedImportsJob{
-		name:       name,
-		enabled:    cfg.Enabled,
-		cfg:        cfg,
-		defaults:   defaults,
-		manager:    manager,
-		logger:     logger.With("job", "remove_failed_imports"),
-		testRun:    testRun,
-		maxStrikes: maxStrikes,
+		name:               name,
+		enabled:            cfg.Enabled,
+		cfg:                cfg,
+		defaults:           defaults,
+		manager:            manager,
+		logger:             logger.With("job", "remove_failed_imports"),
+		testRun:            testRun,
+		maxStrikes:         maxStrikes,
+		importBlocked:      importBlocked,
+		importPendingAfter: importPendingAfter,
+		manualImport:       manualImport,
+		importAttempted:    make(map[string]bool),
 	}
 }
 
@@ -62,6 +93,21 @@ func (j *FailedImportsJob) Enabled() bool {
 	return j.enabled
 }
 
+// SetTestRun switches the job in or out of test-run mode
+func (j *FailedImportsJob) SetTestRun(testRun bool) {
+	j.testRun = testRun
+}
+
+// Interval returns how often the job runs, or 0 to run every cycle
+func (j *FailedImportsJob) Interval() time.Duration {
+	return jobInterval(j.cfg)
+}
+
+// Order returns the job's position in the cycle
+func (j *FailedImportsJob) Order() int {
+	return jobOrder(j.cfg.Order)
+}
+
 // FindAffected identifies failed import items in the queue
 func (j *FailedImportsJob) FindAffected(queue []arrapi.QueueItem) []arrapi.QueueItem {
 	var affected []arrapi.QueueItem
@@ -75,12 +121,43 @@ func (j *FailedImportsJob) FindAffected(queue []arrapi.QueueItem) []arrapi.Queue
 	return affected
 }
 
+// filterQueue applies the job's scope and filters to an instance's queue,
+// leaving the items detection is run on
+func (j *FailedImportsJob) filterQueue(instanceName string, queue []arrapi.QueueItem) []arrapi.QueueItem {
+	if !jobs.InInstances(j.cfg.Instances, instanceName) {
+		return nil
+	}
+	queue = jobs.FilterProtocols(queue, j.cfg.Protocols)
+	queue = withoutFreshGrabs(queue, minGrabAge(j.cfg, j.defaults), j.logger)
+	queue = withoutProcessed(queue, j.manager, instanceName, j.logger)
+	return queue
+}
+
+// Detect returns the items of an instance's queue a run would strike, given
+// the download clients' torrents keyed by lowercase hash
+func (j *FailedImportsJob) Detect(instanceName string, queue []arrapi.QueueItem, _ map[string]downloadclient.Torrent) []arrapi.QueueItem {
+	queue = j.filterQueue(instanceName, queue)
+	return j.FindAffected(queue)
+}
+
 // isFailedImport determines if a queue item is a failed import
 func (j *FailedImportsJob) isFailedImport(item arrapi.QueueItem) bool {
+	// Blocked imports (e.g. waiting on a manual decision) are often transient,
+	// so they're only handled when enabled and use their own patterns
+	if item.TrackedDownloadState == "importBlocked" {
+		return j.importBlocked && matchesMessagePatterns(item, j.cfg.BlockedMessagePatterns)
+	}
+
+	// A completed download can sit pending import indefinitely when no
+	// import is ever triggered
+	if item.TrackedDownloadState == "importPending" {
+		return j.stuckImportPending(item)
+	}
+
 	// Primary indicator: TrackedDownloadState == "importFailed"
 	// This means the download completed successfully but import failed
 	if item.TrackedDownloadState == "importFailed" {
-		return j.matchesMessagePatterns(item)
+		return matchesMessagePatterns(item, j.cfg.MessagePatterns)
 	}
 
 	// Check for import-specific failures in status messages
@@ -92,7 +169,7 @@ func (j *FailedImportsJob) isFailedImport(item arrapi.QueueItem) bool {
 			(strings.Contains(title, "failed") ||
 				strings.Contains(title, "error") ||
 				strings.Contains(title, "unable")) {
-			return j.matchesMessagePatterns(item)
+			return matchesMessagePatterns(item, j.cfg.MessagePatterns)
 		}
 
 		// Specific import failure messages
@@ -101,7 +178,7 @@ func (j *FailedImportsJob) isFailedImport(item arrapi.QueueItem) bool {
 			msg.Title == "Not a valid video file" ||
 			msg.Title == "Not an upgrade for existing file" ||
 			msg.Title == "Sample" {
-			return j.matchesMessagePatterns(item)
+			return matchesMessagePatterns(item, j.cfg.MessagePatterns)
 		}
 	}
 
@@ -109,126 +186,53 @@ func (j *FailedImportsJob) isFailedImport(item arrapi.QueueItem) bool {
 	if item.ErrorMessage != "" {
 		errorLower := strings.ToLower(item.ErrorMessage)
 		if strings.Contains(errorLower, "import") && strings.Contains(errorLower, "failed") {
-			return j.matchesMessagePatterns(item)
+			return matchesMessagePatterns(item, j.cfg.MessagePatterns)
 		}
 	}
 
 	return false
 }
 
-// matchesMessagePatterns checks if the item's messages match configured patterns
-// If no patterns are configured, returns true (matches everything)
-// If patterns are configured, returns true only if at least one pattern matches
-func (j *FailedImportsJob) matchesMessagePatterns(item arrapi.QueueItem) bool {
-	// If no patterns configured, match everything (backward compatible)
-	if len(j.cfg.MessagePatterns) == 0 {
-		return true
-	}
-
-	// Check all status messages
-	for _, statusMsg := range item.StatusMessages {
-		if matchesPattern(statusMsg.Title, j.cfg.MessagePatterns) {
-			return true
-		}
-		for _, msg := range statusMsg.Messages {
-			if matchesPattern(msg, j.cfg.MessagePatterns) {
-				return true
-			}
-		}
-	}
-
-	// Check error message
-	if item.ErrorMessage != "" {
-		if matchesPattern(item.ErrorMessage, j.cfg.MessagePatterns) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// matchesPattern checks if a message matches any of the configured patterns
-// Supports glob/wildcard matching using filepath.Match
-func matchesPattern(message string, patterns []string) bool {
-	if len(patterns) == 0 {
+// stuckImportPending reports whether a completed download pending import was
+// grabbed longer ago than importPendingAfter
+func (j *FailedImportsJob) stuckImportPending(item arrapi.QueueItem) bool {
+	if j.importPendingAfter <= 0 || item.Status != "completed" || item.Added.IsZero() {
 		return false
 	}
-
-	messageLower := strings.ToLower(message)
-
-	for _, pattern := range patterns {
-		patternLower := strings.ToLower(pattern)
-
-		// Try filepath.Match for standard glob patterns
-		if matched, err := filepath.Match(patternLower, messageLower); err == nil && matched {
-			return true
-		}
-
-		// Fallback to simple wildcard matching for patterns with *
-		if strings.Contains(patternLower, "*") {
-			if wildcardMatch(messageLower, patternLower) {
-				return true
-			}
-		}
-
-		// Exact match fallback
-		if messageLower == patternLower {
-			return true
-		}
-	}
-
-	return false
+	age, ok := grabAge(item)
+	return ok && age >= j.importPendingAfter
 }
 
-// wildcardMatch performs simple wildcard matching
-// Supports patterns like "*text*", "prefix*", "*suffix"
-func wildcardMatch(text, pattern string) bool {
-	parts := strings.Split(pattern, "*")
-	if len(parts) == 1 {
-		return text == pattern
-	}
-
-	// Check prefix
-	if parts[0] != "" && !strings.HasPrefix(text, parts[0]) {
-		return false
-	}
-
-	// Check suffix
-	if parts[len(parts)-1] != "" && !strings.HasSuffix(text, parts[len(parts)-1]) {
-		return false
-	}
-
-	// Check middle parts
-	pos := len(parts[0])
-	for i := 1; i < len(parts)-1; i++ {
-		if parts[i] == "" {
-			continue
-		}
-		idx := strings.Index(text[pos:], parts[i])
-		if idx == -1 {
-			return false
-		}
-		pos += idx + len(parts[i])
-	}
-
-	return true
+// matchesMessagePatterns checks if the item's messages match the given patterns
+// If no patterns are configured, returns true (matches everything)
+// If patterns are configured, returns true only if at least one pattern matches
+func matchesMessagePatterns(item arrapi.QueueItem, patterns []string) bool {
+	// If no patterns configured, match everything (backward compatible)
+	return len(patterns) == 0 || jobs.MatchesMessages(item, patterns)
 }
 
 // Run executes the failed imports removal job
 func (j *FailedImportsJob) Run(ctx context.Context) error {
 	j.logger.Debug("starting failed imports removal job", "test_run", j.testRun, "max_strikes", j.maxStrikes)
 
-	queues, err := j.manager.GetAllQueues(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get queues: %w", err)
-	}
+	queues, queueErr := j.manager.GetAllQueues(ctx)
+	queues = jobs.ScopeQueues(queues, j.cfg.Instances)
 
 	strikesHandler := j.manager.GetStrikesHandler()
+	struck := newRunStrikes(strikesHandler)
+	trace := newDecisionTracer(j.manager, j.logger, j.maxStrikes, j.testRun)
 	totalProcessed := 0
 	totalRemoved := 0
+	tags := newObsoleteTagger(j.manager, j.logger)
+	seen := make(map[string]bool)
 
 	for instanceName, queue := range queues {
+		if !dataReliable(j.manager, instanceName, j.logger) {
+			continue
+		}
+		queue = j.filterQueue(instanceName, queue)
 		affected := j.FindAffected(queue)
+		trace.unmatched(instanceName, queue, affected)
 		j.logger.Debug("found failed imports",
 			"instance", instanceName,
 			"count", len(affected),
@@ -236,9 +240,25 @@ func (j *FailedImportsJob) Run(ctx context.Context) error {
 
 		for _, item := range affected {
 			totalProcessed++
+			seen[item.DownloadID] = true
+
+			// Give the import one more chance before striking. A stuck pending
+			// import always gets one, as nothing may have tried importing it.
+			tryImport := j.manualImport || item.TrackedDownloadState == "importPending"
+			if tryImport && !j.importAttempted[item.DownloadID] {
+				j.importAttempted[item.DownloadID] = true
+				if j.tryManualImport(ctx, instanceName, item) {
+					continue
+				}
+			}
 
 			// Add strike for this download
-			currentStrikes := strikesHandler.Add(item.DownloadID, j.name, item.Title)
+			fallback := "import failed"
+			if item.TrackedDownloadState == "importPending" {
+				fallback = "import pending"
+			}
+			reason := jobs.QueueItemReason(item, fallback)
+			currentStrikes := struck.add(item.DownloadID, j.name, item.Title, reason)
 			j.logger.Debug("added strike to failed import",
 				"title", item.Title,
 				"download_id", item.DownloadID,
@@ -249,8 +269,11 @@ func (j *FailedImportsJob) Run(ctx context.Context) error {
 				"instance", instanceName,
 			)
 
+			maxStrikes := maxStrikesFor(ctx, j.manager, j.cfg, j.defaults, j.maxStrikes, item.DownloadID)
+			trace.struck(ctx, instanceName, item, reason, currentStrikes, maxStrikes)
+
 			// Check if max strikes exceeded
-			if strikesHandler.HasExceeded(item.DownloadID, j.maxStrikes) {
+			if j.manager.StrikesExceeded(item.DownloadID, maxStrikes) {
 				// Determine removal action based on tracker type and protected tags
 				action := j.manager.GetRemovalAction(ctx, item.DownloadID)
 
@@ -267,20 +290,13 @@ func (j *FailedImportsJob) Run(ctx context.Context) error {
 							"instance", instanceName,
 						)
 					} else {
-						if err := j.manager.ApplyObsoleteTag(ctx, item.DownloadID); err != nil {
-							j.logger.Error("failed to tag as obsolete",
-								"title", item.Title,
-								"download_id", item.DownloadID,
-								"error", err,
-							)
-							continue
-						}
-						j.logger.Info("tagged failed import as obsolete",
+						tags.add(ctx, item, "tagged failed import as obsolete",
 							"title", item.Title,
 							"download_id", item.DownloadID,
 							"strikes", currentStrikes,
 							"instance", instanceName,
 						)
+						continue
 					}
 					strikesHandler.Reset(item.DownloadID)
 					totalRemoved++ // Count as handled
@@ -289,7 +305,7 @@ func (j *FailedImportsJob) Run(ctx context.Context) error {
 					// Proceed with removal
 				}
 
-				if j.testRun {
+				if j.testRun || !j.manager.DeleteAllowed(instanceName) {
 					j.logger.Info("[TEST RUN] would remove failed import",
 						"title", item.Title,
 						"download_id", item.DownloadID,
@@ -325,6 +341,15 @@ func (j *FailedImportsJob) Run(ctx context.Context) error {
 		}
 	}
 
+	// Forget attempts for downloads that have left the queue
+	for downloadID := range j.importAttempted {
+		if !seen[downloadID] {
+			delete(j.importAttempted, downloadID)
+		}
+	}
+
+	totalRemoved += tags.apply(ctx, strikesHandler)
+
 	j.logger.Debug("failed imports removal job completed",
 		"processed", totalProcessed,
 		"removed", totalRemoved,
@@ -334,23 +359,84 @@ func (j *FailedImportsJob) Run(ctx context.Context) error {
 	j.lastFound = totalProcessed
 	j.lastRemoved = totalRemoved
 
-	return nil
+	return jobs.QueueError(queueErr)
 }
 
-// removeItem removes a queue item from the arr instance
-func (j *FailedImportsJob) removeItem(ctx context.Context, instanceName string, item arrapi.QueueItem) error {
+// tryManualImport asks the arr instance to manually import the download's files.
+// Returns true if an import was triggered.
+func (j *FailedImportsJob) tryManualImport(ctx context.Context, instanceName string, item arrapi.QueueItem) bool {
 	client, ok := j.manager.GetArrClient(instanceName)
 	if !ok {
-		return fmt.Errorf("arr client not found: %s", instanceName)
+		return false
 	}
 
+	candidates, err := client.GetManualImportCandidates(ctx, item.DownloadID)
+	if err != nil {
+		j.logger.Warn("failed to get manual import candidates",
+			"title", item.Title,
+			"download_id", item.DownloadID,
+			"error", err,
+			"instance", instanceName,
+		)
+		return false
+	}
+
+	var files []arrapi.ManualImportFile
+	for _, candidate := range candidates {
+		if file, ok := candidate.ImportFile(); ok {
+			files = append(files, file)
+		}
+	}
+
+	if len(files) == 0 {
+		j.logger.Debug("no importable files for failed import",
+			"title", item.Title,
+			"download_id", item.DownloadID,
+			"candidates", len(candidates),
+			"instance", instanceName,
+		)
+		return false
+	}
+
+	if j.testRun {
+		j.logger.Info("[TEST RUN] would attempt manual import",
+			"title", item.Title,
+			"download_id", item.DownloadID,
+			"files", len(files),
+			"instance", instanceName,
+		)
+		return false
+	}
+
+	if err := client.TriggerManualImport(ctx, files); err != nil {
+		j.logger.Error("failed to trigger manual import",
+			"title", item.Title,
+			"download_id", item.DownloadID,
+			"error", err,
+			"instance", instanceName,
+		)
+		return false
+	}
+
+	j.logger.Info("triggered manual import for failed import",
+		"title", item.Title,
+		"download_id", item.DownloadID,
+		"files", len(files),
+		"instance", instanceName,
+	)
+
+	return true
+}
+
+// removeItem removes a queue item from the arr instance
+func (j *FailedImportsJob) removeItem(ctx context.Context, instanceName string, item arrapi.QueueItem) error {
 	opts := arrapi.DeleteOptions{
 		RemoveFromClient: true,  // Remove from download client since download succeeded
 		Blocklist:        false, // Don't blocklist - download was successful
 		SkipRedownload:   true,  // Skip redownload since import failed (likely quality/format issue)
 	}
 
-	return client.DeleteQueueItem(ctx, item.ID, opts)
+	return j.manager.RemoveQueueItem(ctx, instanceName, item, opts, reviewCategory(j.cfg, j.defaults), reasonTag(j.cfg, j.defaults, "failed-import"), deleteFiles(j.cfg.DeleteFiles, true))
 }
 
 // Stats returns the statistics from the last job run
@@ -0,0 +1,269 @@
+package removal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+)
+
+func TestFailedImportsManualImport(t *testing.T) {
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		{ID: 1, Title: "Importable.Item", DownloadID: "importable", TrackedDownloadState: "importFailed"},
+		{ID: 2, Title: "Rejected.Item", DownloadID: "rejected", TrackedDownloadState: "importFailed"},
+	})
+
+	arr.handle("/api/v3/manualimport", func(w http.ResponseWriter, r *http.Request) {
+		candidates := map[string][]arrapi.ManualImportCandidate{
+			"importable": {{
+				Path:       "/downloads/Importable.Item.mkv",
+				DownloadID: "importable",
+				Movie:      &arrapi.ManualImportEntity{ID: 7},
+			}},
+			"rejected": {{
+				Path:       "/downloads/Rejected.Item.mkv",
+				DownloadID: "rejected",
+				Movie:      &arrapi.ManualImportEntity{ID: 8},
+				Rejections: []arrapi.ManualImportRejection{{Reason: "Not an upgrade"}},
+			}},
+		}
+		_ = json.NewEncoder(w).Encode(candidates[r.URL.Query().Get("downloadId")])
+	})
+
+	var mu sync.Mutex
+	var imported []arrapi.ManualImportFile
+	arr.handle("/api/v3/command", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Name  string                    `json:"name"`
+			Files []arrapi.ManualImportFile `json:"files"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "ManualImport", body.Name)
+		mu.Lock()
+		imported = append(imported, body.Files...)
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, map[string]*fakeArr{"radarr": arr})
+
+	jobCfg := &config.JobConfig{Enabled: true, ManualImport: boolPtr(true)}
+	job := NewFailedImportsJob("remove_failed_imports", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+
+	// First cycle: the importable item gets a manual import instead of a strike
+	require.NoError(t, job.Run(context.Background()))
+
+	mu.Lock()
+	require.Len(t, imported, 1)
+	assert.Equal(t, "/downloads/Importable.Item.mkv", imported[0].Path)
+	require.NotNil(t, imported[0].MovieID)
+	assert.Equal(t, 7, *imported[0].MovieID)
+	mu.Unlock()
+
+	_, ok := arr.deleted(1)
+	assert.False(t, ok, "item should not be removed while a manual import is attempted")
+	_, ok = arr.deleted(2)
+	assert.True(t, ok, "item without importable files should be struck and removed")
+
+	// Second cycle: the import didn't clear the item, so it is struck as usual
+	require.NoError(t, job.Run(context.Background()))
+
+	mu.Lock()
+	assert.Len(t, imported, 1, "manual import should only be attempted once")
+	mu.Unlock()
+
+	_, ok = arr.deleted(1)
+	assert.True(t, ok, "item should be removed after the manual import attempt")
+}
+
+func TestFailedImportsImportPending(t *testing.T) {
+	grabbed := time.Now().Add(-2 * time.Hour)
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		{ID: 1, Title: "Stuck.Item", DownloadID: "stuck", Status: "completed", TrackedDownloadState: "importPending", Added: grabbed},
+		{ID: 2, Title: "Recent.Item", DownloadID: "recent", Status: "completed", TrackedDownloadState: "importPending", Added: time.Now().Add(-10 * time.Minute)},
+		{ID: 3, Title: "Downloading.Item", DownloadID: "downloading", Status: "downloading", TrackedDownloadState: "importPending", Added: grabbed},
+	})
+
+	arr.handle("/api/v3/manualimport", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "stuck", r.URL.Query().Get("downloadId"))
+		_ = json.NewEncoder(w).Encode([]arrapi.ManualImportCandidate{{
+			Path:       "/downloads/Stuck.Item.mkv",
+			DownloadID: "stuck",
+			Movie:      &arrapi.ManualImportEntity{ID: 7},
+		}})
+	})
+
+	var mu sync.Mutex
+	imports := 0
+	arr.handle("/api/v3/command", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		imports++
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, map[string]*fakeArr{"radarr": arr})
+
+	after := time.Hour
+	jobCfg := &config.JobConfig{Enabled: true, ImportPendingAfter: &after}
+	job := NewFailedImportsJob("remove_failed_imports", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+
+	// First cycle: the stuck item gets an import instead of a strike
+	require.NoError(t, job.Run(context.Background()))
+
+	mu.Lock()
+	assert.Equal(t, 1, imports, "an import should be triggered for the stuck item")
+	mu.Unlock()
+	assert.Zero(t, arr.deleteCount(), "nothing is removed while the import is attempted")
+
+	// Second cycle: still pending, so the item is struck and removed
+	require.NoError(t, job.Run(context.Background()))
+
+	mu.Lock()
+	assert.Equal(t, 1, imports, "the import should only be attempted once")
+	mu.Unlock()
+
+	_, ok := arr.deleted(1)
+	assert.True(t, ok, "stuck item should be removed once the import didn't clear it")
+	assert.Equal(t, 1, arr.deleteCount(), "recent and incomplete downloads pending import are left alone")
+}
+
+func TestFailedImportsImportBlocked(t *testing.T) {
+	blocked := arrapi.QueueItem{
+		ID:                   1,
+		Title:                "Blocked.Item",
+		DownloadID:           "blocked",
+		TrackedDownloadState: "importBlocked",
+		StatusMessages: []arrapi.StatusMessage{{
+			Title:    "Blocked.Item",
+			Messages: []string{"Found matching series via grab history, but release was matched to series by ID. Automatic import is not possible."},
+		}},
+	}
+
+	tests := []struct {
+		name   string
+		jobCfg config.JobConfig
+		want   bool
+	}{
+		{
+			name:   "ignored by default",
+			jobCfg: config.JobConfig{Enabled: true},
+			want:   false,
+		},
+		{
+			name:   "detected when enabled",
+			jobCfg: config.JobConfig{Enabled: true, ImportBlocked: boolPtr(true)},
+			want:   true,
+		},
+		{
+			name: "blocked patterns must match",
+			jobCfg: config.JobConfig{
+				Enabled:                true,
+				ImportBlocked:          boolPtr(true),
+				BlockedMessagePatterns: []string{"*Sample*"},
+			},
+			want: false,
+		},
+		{
+			name: "matching blocked pattern",
+			jobCfg: config.JobConfig{
+				Enabled:                true,
+				ImportBlocked:          boolPtr(true),
+				BlockedMessagePatterns: []string{"*automatic import is not possible*"},
+			},
+			want: true,
+		},
+		{
+			name: "failed-import patterns don't apply to blocked imports",
+			jobCfg: config.JobConfig{
+				Enabled:         true,
+				ImportBlocked:   boolPtr(true),
+				MessagePatterns: []string{"*Sample*"},
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			m := newTestManager(t, cfg, nil)
+
+			jobCfg := tt.jobCfg
+			job := NewFailedImportsJob("remove_failed_imports", &jobCfg, &cfg.JobDefaults, m, testLogger(), true)
+
+			affected := job.FindAffected([]arrapi.QueueItem{blocked})
+			assert.Equal(t, tt.want, len(affected) == 1)
+		})
+	}
+}
+
+func TestFailedImportsReasonTag(t *testing.T) {
+	tests := []struct {
+		name           string
+		reasonTags     bool
+		override       *bool
+		reviewCategory string
+		wantTags       []string
+	}{
+		{
+			name:           "kept torrent is tagged with the reason",
+			reasonTags:     true,
+			reviewCategory: "manual",
+			wantTags:       []string{"decluttarr:failed-import"},
+		},
+		{
+			name:           "disabled by default",
+			reviewCategory: "manual",
+		},
+		{
+			name:           "job override enables reason tags",
+			override:       boolPtr(true),
+			reviewCategory: "manual",
+			wantTags:       []string{"decluttarr:failed-import"},
+		},
+		{
+			name:       "deleted torrent isn't tagged",
+			reasonTags: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := newFakeArr(t, []arrapi.QueueItem{
+				{ID: 1, Title: "Failed.Import", DownloadID: "abc", TrackedDownloadState: "importFailed"},
+			})
+
+			cfg := testConfig()
+			cfg.JobDefaults.ReasonTags = tt.reasonTags
+			cfg.JobDefaults.ReviewCategory = tt.reviewCategory
+			m := newTestManager(t, cfg, map[string]*fakeArr{"radarr": arr})
+
+			client := newFakeDownloadClient(downloadclient.Torrent{Hash: "abc", Name: "Failed.Import", Category: "radarr"})
+			m.RegisterDownloadClient("qbittorrent", client)
+
+			jobCfg := &config.JobConfig{Enabled: true, ReasonTags: tt.override}
+			job := NewFailedImportsJob("remove_failed_imports", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+
+			require.NoError(t, job.Run(context.Background()))
+
+			_, ok := arr.deleted(1)
+			require.True(t, ok, "queue item should be removed from the arr")
+
+			client.mu.Lock()
+			defer client.mu.Unlock()
+			assert.Equal(t, tt.wantTags, client.tags["abc"])
+		})
+	}
+}
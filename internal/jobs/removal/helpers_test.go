@@ -0,0 +1,326 @@
+package removal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+// testLogger returns a logger that discards all output
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// stringPtr returns a pointer to s
+func stringPtr(s string) *string {
+	return &s
+}
+
+// boolPtr returns a pointer to b
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// fakeArr is an httptest-backed *arr instance serving a fixed queue and
+// recording queue deletions
+type fakeArr struct {
+	server   *httptest.Server
+	mu       sync.Mutex
+	queue    []arrapi.QueueItem
+	deletes  map[int]map[string]string // queue id -> query params
+	handlers map[string]http.HandlerFunc
+}
+
+// newFakeArr starts a fake *arr server with the given queue
+func newFakeArr(t *testing.T, queue []arrapi.QueueItem) *fakeArr {
+	t.Helper()
+
+	f := &fakeArr{
+		queue:    queue,
+		deletes:  make(map[int]map[string]string),
+		handlers: make(map[string]http.HandlerFunc),
+	}
+
+	f.server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+// handle registers a custom handler for a path (e.g. "/api/v3/system/status")
+func (f *fakeArr) handle(path string, h http.HandlerFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[path] = h
+}
+
+func (f *fakeArr) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	h, ok := f.handlers[r.URL.Path]
+	f.mu.Unlock()
+	if ok {
+		h(w, r)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v3/queue":
+		f.mu.Lock()
+		resp := arrapi.QueueResponse{
+			Page:         1,
+			PageSize:     1000,
+			TotalRecords: len(f.queue),
+			Records:      f.queue,
+		}
+		f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(resp)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/v3/queue/"):
+		var id int
+		if _, err := fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/api/v3/queue/"), "%d", &id); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		params := make(map[string]string)
+		for k := range r.URL.Query() {
+			params[k] = r.URL.Query().Get(k)
+		}
+		f.mu.Lock()
+		f.deletes[id] = params
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// deleted returns the query params used to delete a queue item, if it was deleted
+func (f *fakeArr) deleted(id int) (map[string]string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	params, ok := f.deletes[id]
+	return params, ok
+}
+
+// deleteCount returns the number of queue items deleted
+func (f *fakeArr) deleteCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.deletes)
+}
+
+// fakeDownloadClient is an in-memory download client
+type fakeDownloadClient struct {
+	mu       sync.Mutex
+	name     string
+	torrents []downloadclient.Torrent
+	props    map[string]*downloadclient.TorrentProperties
+	deleted  map[string]bool // hash -> deleteFiles
+	tags     map[string][]string
+	tagCalls int // tag requests made
+	paused   map[string]bool
+	resumed  map[string]bool
+	seeds    bool // whether the client reports itself seeding capable
+}
+
+// newFakeDownloadClient creates a fake qBittorrent-like client with the given torrents
+func newFakeDownloadClient(torrents ...downloadclient.Torrent) *fakeDownloadClient {
+	return &fakeDownloadClient{
+		name:     "qBittorrent",
+		torrents: torrents,
+		props:    make(map[string]*downloadclient.TorrentProperties),
+		deleted:  make(map[string]bool),
+		tags:     make(map[string][]string),
+		paused:   make(map[string]bool),
+		resumed:  make(map[string]bool),
+		seeds:    true,
+	}
+}
+
+// bareClient exposes only the core Client methods of a fake, hiding its
+// optional capabilities
+type bareClient struct {
+	downloadclient.Client
+}
+
+func (c *fakeDownloadClient) Name() string {
+	return c.name
+}
+
+func (c *fakeDownloadClient) SeedingCapable() bool {
+	return c.seeds
+}
+
+func (c *fakeDownloadClient) GetTorrents(ctx context.Context) ([]downloadclient.Torrent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := make([]downloadclient.Torrent, len(c.torrents))
+	copy(result, c.torrents)
+	return result, nil
+}
+
+func (c *fakeDownloadClient) GetTorrent(ctx context.Context, hash string) (*downloadclient.Torrent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range c.torrents {
+		if t.Hash == hash {
+			torrent := t
+			return &torrent, nil
+		}
+	}
+	return nil, fmt.Errorf("torrent not found: %s", hash)
+}
+
+func (c *fakeDownloadClient) DeleteTorrent(ctx context.Context, hash string, deleteFiles bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deleted[hash] = deleteFiles
+	return nil
+}
+
+func (c *fakeDownloadClient) PauseTorrent(ctx context.Context, hash string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused[hash] = true
+	return nil
+}
+
+func (c *fakeDownloadClient) ResumeTorrent(ctx context.Context, hash string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resumed[hash] = true
+	return nil
+}
+
+func (c *fakeDownloadClient) GetTorrentProperties(ctx context.Context, hash string) (*downloadclient.TorrentProperties, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if props, ok := c.props[hash]; ok {
+		return props, nil
+	}
+	return &downloadclient.TorrentProperties{}, nil
+}
+
+func (c *fakeDownloadClient) AddTags(ctx context.Context, hash string, tags []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tagCalls++
+	c.tags[hash] = append(c.tags[hash], tags...)
+	return nil
+}
+
+func (c *fakeDownloadClient) AddTagsBulk(ctx context.Context, hashes []string, tags []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tagCalls++
+	for _, hash := range hashes {
+		c.tags[hash] = append(c.tags[hash], tags...)
+	}
+	return nil
+}
+
+func (c *fakeDownloadClient) RemoveTags(ctx context.Context, hash string, tags []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.torrents {
+		if c.torrents[i].Hash != hash {
+			continue
+		}
+		var kept []string
+		for _, tag := range c.torrents[i].Tags {
+			if !slices.Contains(tags, tag) {
+				kept = append(kept, tag)
+			}
+		}
+		c.torrents[i].Tags = kept
+		return nil
+	}
+	return fmt.Errorf("torrent not found: %s", hash)
+}
+
+func (c *fakeDownloadClient) SetCategory(ctx context.Context, hash string, category string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.torrents {
+		if c.torrents[i].Hash == hash {
+			c.torrents[i].Category = category
+			return nil
+		}
+	}
+	return fmt.Errorf("torrent not found: %s", hash)
+}
+
+func (c *fakeDownloadClient) IsPrivateTracker(ctx context.Context, hash string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if props, ok := c.props[hash]; ok {
+		return props.IsPrivate, nil
+	}
+	return false, nil
+}
+
+// wasDeleted reports whether a torrent was deleted and with which deleteFiles flag
+func (c *fakeDownloadClient) wasDeleted(hash string) (deleteFiles bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	deleteFiles, ok = c.deleted[hash]
+	return deleteFiles, ok
+}
+
+// category returns the current category of a torrent
+func (c *fakeDownloadClient) category(hash string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range c.torrents {
+		if t.Hash == hash {
+			return t.Category
+		}
+	}
+	return ""
+}
+
+// testConfig returns a minimal valid config for job tests
+func testConfig() *config.Config {
+	return &config.Config{
+		General: config.GeneralConfig{
+			PrivateTrackerHandling: "remove",
+			PublicTrackerHandling:  "remove",
+			ObsoleteTag:            "Obsolete",
+			ProtectedTag:           "Keep",
+		},
+		JobDefaults: config.JobDefaultsConfig{
+			MaxStrikes:       1,
+			MinDownloadSpeed: 100,
+		},
+	}
+}
+
+// newTestManager creates a manager with the given arr instances registered
+func newTestManager(t *testing.T, cfg *config.Config, arrs map[string]*fakeArr) *jobs.Manager {
+	t.Helper()
+
+	m := jobs.NewManager(cfg, testLogger(), "")
+	for name, arr := range arrs {
+		m.RegisterArrClient(name, arrapi.NewClient(arrapi.ClientConfig{
+			Name:    name,
+			BaseURL: arr.server.URL,
+			APIKey:  "test",
+			Logger:  testLogger(),
+		}))
+	}
+	t.Cleanup(m.Close)
+
+	return m
+}
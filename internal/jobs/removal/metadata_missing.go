@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
 	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
 	"github.com/jmylchreest/go-decluttarr/internal/jobs"
 )
 
@@ -61,6 +63,21 @@ func (j *MetadataMissingJob) Enabled() bool {
 	return j.enabled
 }
 
+// SetTestRun switches the job in or out of test-run mode
+func (j *MetadataMissingJob) SetTestRun(testRun bool) {
+	j.testRun = testRun
+}
+
+// Interval returns how often the job runs, or 0 to run every cycle
+func (j *MetadataMissingJob) Interval() time.Duration {
+	return jobInterval(j.cfg)
+}
+
+// Order returns the job's position in the cycle
+func (j *MetadataMissingJob) Order() int {
+	return jobOrder(j.cfg.Order)
+}
+
 // metadataKeywords contains keywords indicating metadata/parsing issues
 var metadataKeywords = []string{
 	"unable to parse",
@@ -90,6 +107,25 @@ func (j *MetadataMissingJob) FindAffected(queue []arrapi.QueueItem) []arrapi.Que
 	return affected
 }
 
+// filterQueue applies the job's scope and filters to an instance's queue,
+// leaving the items detection is run on
+func (j *MetadataMissingJob) filterQueue(instanceName string, queue []arrapi.QueueItem) []arrapi.QueueItem {
+	if !jobs.InInstances(j.cfg.Instances, instanceName) {
+		return nil
+	}
+	queue = jobs.FilterProtocols(queue, j.cfg.Protocols)
+	queue = withoutFreshGrabs(queue, minGrabAge(j.cfg, j.defaults), j.logger)
+	queue = withoutProcessed(queue, j.manager, instanceName, j.logger)
+	return queue
+}
+
+// Detect returns the items of an instance's queue a run would strike, given
+// the download clients' torrents keyed by lowercase hash
+func (j *MetadataMissingJob) Detect(instanceName string, queue []arrapi.QueueItem, _ map[string]downloadclient.Torrent) []arrapi.QueueItem {
+	queue = j.filterQueue(instanceName, queue)
+	return j.FindAffected(queue)
+}
+
 // hasMetadataIssue checks if a queue item has metadata issues
 func (j *MetadataMissingJob) hasMetadataIssue(item *arrapi.QueueItem) bool {
 	// Check if item has library ID based on type
@@ -139,7 +175,8 @@ func (j *MetadataMissingJob) hasMetadataIssue(item *arrapi.QueueItem) bool {
 		}
 	}
 
-	return false
+	// User-defined patterns augment the built-in keywords
+	return jobs.MatchesMessages(*item, j.cfg.MessagePatterns)
 }
 
 // Run executes the metadata missing removal job
@@ -148,21 +185,27 @@ func (j *MetadataMissingJob) Run(ctx context.Context) error {
 		"test_run", j.testRun,
 		"max_strikes", j.maxStrikes)
 
-	queues, err := j.manager.GetAllQueues(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get queues: %w", err)
-	}
+	queues, queueErr := j.manager.GetAllQueues(ctx)
+	queues = jobs.ScopeQueues(queues, j.cfg.Instances)
 
 	strikesHandler := j.manager.GetStrikesHandler()
+	struck := newRunStrikes(strikesHandler)
+	trace := newDecisionTracer(j.manager, j.logger, j.maxStrikes, j.testRun)
 	totalProcessed := 0
 	totalRemoved := 0
+	tags := newObsoleteTagger(j.manager, j.logger)
 
 	for instanceName, queue := range queues {
+		if !dataReliable(j.manager, instanceName, j.logger) {
+			continue
+		}
+		queue = j.filterQueue(instanceName, queue)
 		j.logger.Debug("checking queue items for metadata issues",
 			"instance", instanceName,
 			"count", len(queue))
 
 		affected := j.FindAffected(queue)
+		trace.unmatched(instanceName, queue, affected)
 		j.logger.Debug("found items with metadata issues",
 			"instance", instanceName,
 			"count", len(affected),
@@ -175,7 +218,7 @@ func (j *MetadataMissingJob) Run(ctx context.Context) error {
 			reason := j.getMetadataIssueReason(&item)
 
 			// Add strike for this download
-			currentStrikes := strikesHandler.Add(item.DownloadID, j.name, item.Title)
+			currentStrikes := struck.add(item.DownloadID, j.name, item.Title, reason)
 			j.logger.Debug("added strike to metadata-failed download",
 				"title", item.Title,
 				"download_id", item.DownloadID,
@@ -185,8 +228,11 @@ func (j *MetadataMissingJob) Run(ctx context.Context) error {
 				"instance", instanceName,
 			)
 
+			maxStrikes := maxStrikesFor(ctx, j.manager, j.cfg, j.defaults, j.maxStrikes, item.DownloadID)
+			trace.struck(ctx, instanceName, item, reason, currentStrikes, maxStrikes)
+
 			// Check if max strikes exceeded
-			if strikesHandler.HasExceeded(item.DownloadID, j.maxStrikes) {
+			if j.manager.StrikesExceeded(item.DownloadID, maxStrikes) {
 				// Determine removal action based on tracker type and protected tags
 				action := j.manager.GetRemovalAction(ctx, item.DownloadID)
 
@@ -204,21 +250,14 @@ func (j *MetadataMissingJob) Run(ctx context.Context) error {
 							"instance", instanceName,
 						)
 					} else {
-						if err := j.manager.ApplyObsoleteTag(ctx, item.DownloadID); err != nil {
-							j.logger.Error("failed to tag as obsolete",
-								"title", item.Title,
-								"download_id", item.DownloadID,
-								"error", err,
-							)
-							continue
-						}
-						j.logger.Info("tagged metadata-failed download as obsolete",
+						tags.add(ctx, item, "tagged metadata-failed download as obsolete",
 							"title", item.Title,
 							"download_id", item.DownloadID,
 							"strikes", currentStrikes,
 							"reason", reason,
 							"instance", instanceName,
 						)
+						continue
 					}
 					strikesHandler.Reset(item.DownloadID)
 					totalRemoved++ // Count as handled
@@ -227,7 +266,7 @@ func (j *MetadataMissingJob) Run(ctx context.Context) error {
 					// Proceed with removal
 				}
 
-				if j.testRun {
+				if j.testRun || !j.manager.DeleteAllowed(instanceName) {
 					j.logger.Info("[TEST RUN] would remove metadata-failed download",
 						"title", item.Title,
 						"download_id", item.DownloadID,
@@ -261,6 +300,8 @@ func (j *MetadataMissingJob) Run(ctx context.Context) error {
 		}
 	}
 
+	totalRemoved += tags.apply(ctx, strikesHandler)
+
 	j.logger.Debug("metadata missing removal job completed",
 		"processed", totalProcessed,
 		"removed", totalRemoved,
@@ -269,7 +310,7 @@ func (j *MetadataMissingJob) Run(ctx context.Context) error {
 	j.lastFound = totalProcessed
 	j.lastRemoved = totalRemoved
 
-	return nil
+	return jobs.QueueError(queueErr)
 }
 
 // getMetadataIssueReason returns the reason for the metadata issue
@@ -296,23 +337,22 @@ func (j *MetadataMissingJob) getMetadataIssueReason(item *arrapi.QueueItem) stri
 		}
 	}
 
+	if jobs.MatchesMessages(*item, j.cfg.MessagePatterns) {
+		return jobs.QueueItemReason(*item, "matched message_patterns")
+	}
+
 	return "unknown"
 }
 
 // removeItem removes a queue item from the arr instance
 func (j *MetadataMissingJob) removeItem(ctx context.Context, instanceName string, item arrapi.QueueItem) error {
-	client, ok := j.manager.GetArrClient(instanceName)
-	if !ok {
-		return fmt.Errorf("arr client not found: %s", instanceName)
-	}
-
 	opts := arrapi.DeleteOptions{
 		RemoveFromClient: true,
 		Blocklist:        false, // Don't blocklist, might be parseable later
 		SkipRedownload:   true,  // Skip redownload since we can't match it
 	}
 
-	return client.DeleteQueueItem(ctx, item.ID, opts)
+	return j.manager.RemoveQueueItem(ctx, instanceName, item, opts, reviewCategory(j.cfg, j.defaults), reasonTag(j.cfg, j.defaults, "metadata-missing"), deleteFiles(j.cfg.DeleteFiles, true))
 }
 
 // Stats returns the statistics from the last job run
@@ -2,12 +2,13 @@ package removal
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
 	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
 	"github.com/jmylchreest/go-decluttarr/internal/jobs"
 )
 
@@ -61,6 +62,21 @@ func (j *MissingFilesJob) Enabled() bool {
 	return j.enabled
 }
 
+// SetTestRun switches the job in or out of test-run mode
+func (j *MissingFilesJob) SetTestRun(testRun bool) {
+	j.testRun = testRun
+}
+
+// Interval returns how often the job runs, or 0 to run every cycle
+func (j *MissingFilesJob) Interval() time.Duration {
+	return jobInterval(j.cfg)
+}
+
+// Order returns the job's position in the cycle
+func (j *MissingFilesJob) Order() int {
+	return jobOrder(j.cfg.Order)
+}
+
 // FindAffected identifies items with missing files in the queue
 func (j *MissingFilesJob) FindAffected(queue []arrapi.QueueItem) []arrapi.QueueItem {
 	var affected []arrapi.QueueItem
@@ -74,6 +90,25 @@ func (j *MissingFilesJob) FindAffected(queue []arrapi.QueueItem) []arrapi.QueueI
 	return affected
 }
 
+// filterQueue applies the job's scope and filters to an instance's queue,
+// leaving the items detection is run on
+func (j *MissingFilesJob) filterQueue(instanceName string, queue []arrapi.QueueItem) []arrapi.QueueItem {
+	if !jobs.InInstances(j.cfg.Instances, instanceName) {
+		return nil
+	}
+	queue = jobs.FilterProtocols(queue, j.cfg.Protocols)
+	queue = withoutFreshGrabs(queue, minGrabAge(j.cfg, j.defaults), j.logger)
+	queue = withoutProcessed(queue, j.manager, instanceName, j.logger)
+	return queue
+}
+
+// Detect returns the items of an instance's queue a run would strike, given
+// the download clients' torrents keyed by lowercase hash
+func (j *MissingFilesJob) Detect(instanceName string, queue []arrapi.QueueItem, _ map[string]downloadclient.Torrent) []arrapi.QueueItem {
+	queue = j.filterQueue(instanceName, queue)
+	return j.FindAffected(queue)
+}
+
 // hasMissingFiles checks if a queue item has missing files based on status messages
 func (j *MissingFilesJob) hasMissingFiles(item *arrapi.QueueItem) bool {
 	// Check status messages for missing file indicators
@@ -111,17 +146,23 @@ func (j *MissingFilesJob) Run(ctx context.Context) error {
 		"test_run", j.testRun,
 		"max_strikes", j.maxStrikes)
 
-	queues, err := j.manager.GetAllQueues(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get queues: %w", err)
-	}
+	queues, queueErr := j.manager.GetAllQueues(ctx)
+	queues = jobs.ScopeQueues(queues, j.cfg.Instances)
 
 	strikesHandler := j.manager.GetStrikesHandler()
+	struck := newRunStrikes(strikesHandler)
+	trace := newDecisionTracer(j.manager, j.logger, j.maxStrikes, j.testRun)
 	totalProcessed := 0
 	totalRemoved := 0
+	tags := newObsoleteTagger(j.manager, j.logger)
 
 	for instanceName, queue := range queues {
+		if !dataReliable(j.manager, instanceName, j.logger) {
+			continue
+		}
+		queue = j.filterQueue(instanceName, queue)
 		affected := j.FindAffected(queue)
+		trace.unmatched(instanceName, queue, affected)
 		j.logger.Debug("found items with missing files",
 			"instance", instanceName,
 			"count", len(affected),
@@ -131,7 +172,8 @@ func (j *MissingFilesJob) Run(ctx context.Context) error {
 			totalProcessed++
 
 			// Add strike for this download
-			currentStrikes := strikesHandler.Add(item.DownloadID, j.name, item.Title)
+			reason := jobs.QueueItemReason(item, "files missing")
+			currentStrikes := struck.add(item.DownloadID, j.name, item.Title, reason)
 			j.logger.Debug("added strike to item with missing files",
 				"title", item.Title,
 				"download_id", item.DownloadID,
@@ -140,8 +182,11 @@ func (j *MissingFilesJob) Run(ctx context.Context) error {
 				"instance", instanceName,
 			)
 
+			maxStrikes := maxStrikesFor(ctx, j.manager, j.cfg, j.defaults, j.maxStrikes, item.DownloadID)
+			trace.struck(ctx, instanceName, item, reason, currentStrikes, maxStrikes)
+
 			// Check if max strikes exceeded
-			if strikesHandler.HasExceeded(item.DownloadID, j.maxStrikes) {
+			if j.manager.StrikesExceeded(item.DownloadID, maxStrikes) {
 				// Determine removal action based on tracker type and protected tags
 				action := j.manager.GetRemovalAction(ctx, item.DownloadID)
 
@@ -158,20 +203,13 @@ func (j *MissingFilesJob) Run(ctx context.Context) error {
 							"instance", instanceName,
 						)
 					} else {
-						if err := j.manager.ApplyObsoleteTag(ctx, item.DownloadID); err != nil {
-							j.logger.Error("failed to tag as obsolete",
-								"title", item.Title,
-								"download_id", item.DownloadID,
-								"error", err,
-							)
-							continue
-						}
-						j.logger.Info("tagged item with missing files as obsolete",
+						tags.add(ctx, item, "tagged item with missing files as obsolete",
 							"title", item.Title,
 							"download_id", item.DownloadID,
 							"strikes", currentStrikes,
 							"instance", instanceName,
 						)
+						continue
 					}
 					strikesHandler.Reset(item.DownloadID)
 					totalRemoved++ // Count as handled
@@ -180,7 +218,7 @@ func (j *MissingFilesJob) Run(ctx context.Context) error {
 					// Proceed with removal
 				}
 
-				if j.testRun {
+				if j.testRun || !j.manager.DeleteAllowed(instanceName) {
 					j.logger.Info("[TEST RUN] would remove item with missing files",
 						"title", item.Title,
 						"download_id", item.DownloadID,
@@ -213,6 +251,8 @@ func (j *MissingFilesJob) Run(ctx context.Context) error {
 		}
 	}
 
+	totalRemoved += tags.apply(ctx, strikesHandler)
+
 	j.logger.Debug("missing files removal job completed",
 		"processed", totalProcessed,
 		"removed", totalRemoved,
@@ -221,23 +261,18 @@ func (j *MissingFilesJob) Run(ctx context.Context) error {
 	j.lastFound = totalProcessed
 	j.lastRemoved = totalRemoved
 
-	return nil
+	return jobs.QueueError(queueErr)
 }
 
 // removeItem removes a queue item from the arr instance
 func (j *MissingFilesJob) removeItem(ctx context.Context, instanceName string, item arrapi.QueueItem) error {
-	client, ok := j.manager.GetArrClient(instanceName)
-	if !ok {
-		return fmt.Errorf("arr client not found: %s", instanceName)
-	}
-
 	opts := arrapi.DeleteOptions{
 		RemoveFromClient: true,
 		Blocklist:        false,
 		SkipRedownload:   true,
 	}
 
-	return client.DeleteQueueItem(ctx, item.ID, opts)
+	return j.manager.RemoveQueueItem(ctx, instanceName, item, opts, reviewCategory(j.cfg, j.defaults), reasonTag(j.cfg, j.defaults, "missing-files"), deleteFiles(j.cfg.DeleteFiles, true))
 }
 
 // Stats returns the statistics from the last job run
@@ -0,0 +1,321 @@
+package removal
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+// DefaultMaxGrabToDownload is how long a grabbed download may sit at 0%
+// before remove_not_started removes it, when max_grab_to_download isn't set
+const DefaultMaxGrabToDownload = 6 * time.Hour
+
+// NotStartedJob removes downloads that never started: grabbed longer than
+// max_grab_to_download ago and still at 0%, such as dead torrents with no
+// seeders. Unlike remove_stalled, which judges downloads by their current
+// state, it only acts on downloads that have made no progress at all.
+type NotStartedJob struct {
+	name              string
+	enabled           bool
+	cfg               *config.JobConfig
+	defaults          *config.JobDefaultsConfig
+	manager           *jobs.Manager
+	logger            *slog.Logger
+	testRun           bool
+	maxStrikes        int
+	maxGrabToDownload time.Duration
+	lastFound         int
+	lastRemoved       int
+}
+
+// NewNotStartedJob creates a new not started removal job
+func NewNotStartedJob(
+	name string,
+	cfg *config.JobConfig,
+	defaults *config.JobDefaultsConfig,
+	manager *jobs.Manager,
+	logger *slog.Logger,
+	testRun bool,
+) *NotStartedJob {
+	maxStrikes := defaults.MaxStrikes
+	if cfg.MaxStrikes != nil {
+		maxStrikes = *cfg.MaxStrikes
+	}
+
+	maxGrabToDownload := DefaultMaxGrabToDownload
+	if cfg.MaxGrabToDownload != nil && *cfg.MaxGrabToDownload > 0 {
+		maxGrabToDownload = *cfg.MaxGrabToDownload
+	}
+
+	return &NotStartedJob{
+		name:              name,
+		enabled:           cfg.Enabled,
+		cfg:               cfg,
+		defaults:          defaults,
+		manager:           manager,
+		logger:            logger.With("job", "remove_not_started"),
+		testRun:           testRun,
+		maxStrikes:        maxStrikes,
+		maxGrabToDownload: maxGrabToDownload,
+	}
+}
+
+// Name returns the job identifier
+func (j *NotStartedJob) Name() string {
+	return j.name
+}
+
+// Enabled returns whether this job is enabled
+func (j *NotStartedJob) Enabled() bool {
+	return j.enabled
+}
+
+// SetTestRun switches the job in or out of test-run mode
+func (j *NotStartedJob) SetTestRun(testRun bool) {
+	j.testRun = testRun
+}
+
+// Interval returns how often the job runs, or 0 to run every cycle
+func (j *NotStartedJob) Interval() time.Duration {
+	return jobInterval(j.cfg)
+}
+
+// Order returns the job's position in the cycle
+func (j *NotStartedJob) Order() int {
+	return jobOrder(j.cfg.Order)
+}
+
+// notStartedStatuses are the *arr queue statuses of a download that isn't
+// downloading. Queued and paused downloads are held back by the client or
+// the user rather than dead, so they aren't included.
+var notStartedStatuses = []string{
+	"warning",
+	"stalled",
+}
+
+// FindAffected identifies queue items that never started downloading
+func (j *NotStartedJob) FindAffected(queue []arrapi.QueueItem) []arrapi.QueueItem {
+	return j.findNotStarted(queue, nil)
+}
+
+// filterQueue applies the job's scope and filters to an instance's queue,
+// leaving the items detection is run on
+func (j *NotStartedJob) filterQueue(instanceName string, queue []arrapi.QueueItem) []arrapi.QueueItem {
+	if !jobs.InInstances(j.cfg.Instances, instanceName) {
+		return nil
+	}
+	queue = jobs.FilterProtocols(queue, j.cfg.Protocols)
+	queue = withoutProcessed(queue, j.manager, instanceName, j.logger)
+	return queue
+}
+
+// Detect returns the items of an instance's queue a run would strike, given
+// the download clients' torrents keyed by lowercase hash
+func (j *NotStartedJob) Detect(instanceName string, queue []arrapi.QueueItem, torrents map[string]downloadclient.Torrent) []arrapi.QueueItem {
+	queue = j.filterQueue(instanceName, queue)
+	return j.findNotStarted(queue, torrents)
+}
+
+// findNotStarted identifies queue items grabbed longer than
+// max_grab_to_download ago that are still at 0%. Items that resolve to a
+// torrent use the client's reported state and progress; others (e.g. usenet)
+// fall back to the *arr's status and size left.
+func (j *NotStartedJob) findNotStarted(queue []arrapi.QueueItem, torrents map[string]downloadclient.Torrent) []arrapi.QueueItem {
+	var affected []arrapi.QueueItem
+
+	for _, item := range queue {
+		// Items without a download ID haven't been sent to a client yet,
+		// e.g. ones held by a delay profile
+		if item.DownloadID == "" || item.Added.IsZero() {
+			continue
+		}
+		if age, ok := grabAge(item); !ok || age < j.maxGrabToDownload {
+			continue
+		}
+
+		if torrent, ok := torrents[strings.ToLower(item.DownloadID)]; ok {
+			if j.manager.InProtectedState(&torrent) {
+				j.logger.Debug("skipping torrent in a protected state",
+					"title", item.Title,
+					"download_id", item.DownloadID,
+					"state", torrent.ClientState,
+				)
+				continue
+			}
+			if torrent.Progress == 0 && torrent.State != downloadclient.StatePaused && torrent.State != downloadclient.StateQueued {
+				affected = append(affected, item)
+			}
+			continue
+		}
+
+		noProgress := item.Size == 0 || item.Sizeleft >= item.Size
+		for _, status := range notStartedStatuses {
+			if noProgress && strings.EqualFold(item.Status, status) {
+				affected = append(affected, item)
+				break
+			}
+		}
+	}
+
+	return affected
+}
+
+// Run executes the not started removal job
+func (j *NotStartedJob) Run(ctx context.Context) error {
+	j.logger.Debug("starting not started removal job",
+		"test_run", j.testRun,
+		"max_strikes", j.maxStrikes,
+		"max_grab_to_download", j.maxGrabToDownload)
+
+	queues, queueErr := j.manager.GetAllQueues(ctx)
+	queues = jobs.ScopeQueues(queues, j.cfg.Instances)
+
+	// Torrent states reported by the download clients take precedence
+	torrents := j.manager.GetAllTorrents(ctx)
+
+	strikesHandler := j.manager.GetStrikesHandler()
+	struck := newRunStrikes(strikesHandler)
+	trace := newDecisionTracer(j.manager, j.logger, j.maxStrikes, j.testRun)
+	totalProcessed := 0
+	totalRemoved := 0
+	tags := newObsoleteTagger(j.manager, j.logger)
+
+	for instanceName, queue := range queues {
+		if !dataReliable(j.manager, instanceName, j.logger) {
+			continue
+		}
+		queue = j.filterQueue(instanceName, queue)
+		j.logger.Debug("checking queue items for downloads that never started",
+			"instance", instanceName,
+			"count", len(queue))
+
+		affected := j.findNotStarted(queue, torrents)
+		trace.unmatched(instanceName, queue, affected)
+		j.logger.Debug("found downloads that never started",
+			"instance", instanceName,
+			"count", len(affected),
+		)
+
+		for _, item := range affected {
+			totalProcessed++
+
+			reason := "never started downloading"
+
+			// Add strike for this download
+			currentStrikes := struck.add(item.DownloadID, j.name, item.Title, reason)
+			j.logger.Debug("added strike to download that never started",
+				"title", item.Title,
+				"download_id", item.DownloadID,
+				"strikes", currentStrikes,
+				"max_strikes", j.maxStrikes,
+				"added", item.Added,
+				"instance", instanceName,
+			)
+
+			maxStrikes := maxStrikesFor(ctx, j.manager, j.cfg, j.defaults, j.maxStrikes, item.DownloadID)
+			trace.struck(ctx, instanceName, item, reason, currentStrikes, maxStrikes)
+
+			// Check if max strikes exceeded
+			if !j.manager.StrikesExceeded(item.DownloadID, maxStrikes) {
+				continue
+			}
+
+			// Determine removal action based on tracker type and protected tags
+			switch j.manager.GetRemovalAction(ctx, item.DownloadID) {
+			case "skip":
+				j.logger.Debug("skipping protected item", "title", item.Title, "download_id", item.DownloadID)
+				continue
+			case "tag":
+				if j.testRun {
+					j.logger.Info("[TEST RUN] would tag download that never started as obsolete",
+						"title", item.Title,
+						"download_id", item.DownloadID,
+						"strikes", currentStrikes,
+						"instance", instanceName,
+					)
+				} else {
+					tags.add(ctx, item, "tagged download that never started as obsolete",
+						"title", item.Title,
+						"download_id", item.DownloadID,
+						"strikes", currentStrikes,
+						"instance", instanceName,
+					)
+					continue
+				}
+				strikesHandler.Reset(item.DownloadID)
+				totalRemoved++ // Count as handled
+				continue
+			}
+
+			if j.testRun || !j.manager.DeleteAllowed(instanceName) {
+				j.logger.Info("[TEST RUN] would remove download that never started",
+					"title", item.Title,
+					"download_id", item.DownloadID,
+					"strikes", currentStrikes,
+					"added", item.Added,
+					"instance", instanceName,
+				)
+				continue
+			}
+
+			if err := j.removeItem(ctx, instanceName, item); err != nil {
+				j.logger.Error("failed to remove download that never started",
+					"title", item.Title,
+					"download_id", item.DownloadID,
+					"error", err,
+					"instance", instanceName,
+				)
+				continue
+			}
+
+			// Reset strikes after successful removal
+			strikesHandler.Reset(item.DownloadID)
+			totalRemoved++
+
+			j.logger.Info("removed download that never started",
+				"title", item.Title,
+				"download_id", item.DownloadID,
+				"added", item.Added,
+				"instance", instanceName,
+			)
+		}
+	}
+
+	totalRemoved += tags.apply(ctx, strikesHandler)
+
+	j.logger.Debug("not started removal job completed",
+		"processed", totalProcessed,
+		"removed", totalRemoved,
+		"test_run", j.testRun)
+
+	j.lastFound = totalProcessed
+	j.lastRemoved = totalRemoved
+
+	return jobs.QueueError(queueErr)
+}
+
+// removeItem removes a queue item from the arr instance, blocklisting the
+// dead release so the arr searches for another
+func (j *NotStartedJob) removeItem(ctx context.Context, instanceName string, item arrapi.QueueItem) error {
+	opts := arrapi.DeleteOptions{
+		RemoveFromClient: true,
+		Blocklist:        true,
+		SkipRedownload:   false,
+	}
+
+	return j.manager.RemoveQueueItem(ctx, instanceName, item, opts, reviewCategory(j.cfg, j.defaults), reasonTag(j.cfg, j.defaults, "not-started"), deleteFiles(j.cfg.DeleteFiles, true))
+}
+
+// Stats returns the statistics from the last job run
+func (j *NotStartedJob) Stats() jobs.JobStats {
+	return jobs.JobStats{
+		Found:   j.lastFound,
+		Removed: j.lastRemoved,
+	}
+}
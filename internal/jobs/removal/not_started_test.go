@@ -0,0 +1,85 @@
+package removal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+)
+
+func TestNotStartedRemovesDeadGrabsWithBlocklist(t *testing.T) {
+	now := time.Now()
+	item := func(id int, downloadID, status string, sizeleft int64, age time.Duration) arrapi.QueueItem {
+		return arrapi.QueueItem{
+			ID:         id,
+			Title:      downloadID,
+			DownloadID: downloadID,
+			Status:     status,
+			Protocol:   "usenet",
+			Size:       1024,
+			Sizeleft:   sizeleft,
+			Added:      now.Add(-age),
+		}
+	}
+
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		item(1, "dead", "warning", 1024, 48*time.Hour),
+		// Some progress: remove_stalled's to judge
+		item(2, "started", "warning", 512, 48*time.Hour),
+		item(3, "recent", "warning", 1024, time.Hour),
+		// Held back by the client or the user, not dead
+		item(4, "queued", "queued", 1024, 48*time.Hour),
+		item(5, "paused", "paused", 1024, 48*time.Hour),
+	})
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+	maxGrab := 24 * time.Hour
+	job := NewNotStartedJob("remove_not_started", &config.JobConfig{Enabled: true, MaxGrabToDownload: &maxGrab}, &cfg.JobDefaults, m, testLogger(), false)
+	require.NoError(t, job.Run(context.Background()))
+
+	params, ok := arr.deleted(1)
+	require.True(t, ok, "a download grabbed long ago still at 0% should be removed")
+	assert.Equal(t, "true", params["blocklist"], "the dead release should be blocklisted")
+	assert.NotEqual(t, "true", params["skipRedownload"], "the arr should search again")
+	assert.Equal(t, 1, arr.deleteCount())
+	assert.Equal(t, 1, job.Stats().Removed)
+}
+
+func TestNotStartedUsesClientState(t *testing.T) {
+	added := time.Now().Add(-48 * time.Hour)
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		{ID: 1, Title: "Dead", DownloadID: "DEAD", Status: "downloading", Added: added},
+		{ID: 2, Title: "Queued", DownloadID: "QUEUED", Status: "warning", Added: added},
+		{ID: 3, Title: "Started", DownloadID: "STARTED", Status: "warning", Added: added},
+		{ID: 4, Title: "Fetching", DownloadID: "FETCHING", Status: "downloading", Added: added},
+	})
+	client := newFakeDownloadClient(
+		// Searching for metadata for two days
+		downloadclient.Torrent{Hash: "dead", State: downloadclient.StateDownloading},
+		downloadclient.Torrent{Hash: "queued", State: downloadclient.StateQueued},
+		downloadclient.Torrent{Hash: "started", State: downloadclient.StateStalled, Progress: 0.1},
+		// Still fetching metadata, which the user protects
+		downloadclient.Torrent{Hash: "fetching", State: downloadclient.StateDownloading, ClientState: "metaDL"},
+	)
+
+	cfg := testConfig()
+	cfg.General.ProtectedStates = []string{"metaDL"}
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+	m.RegisterDownloadClient("qbittorrent", client)
+
+	job := NewNotStartedJob("remove_not_started", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+	require.NoError(t, job.Run(context.Background()))
+
+	_, removed := arr.deleted(1)
+	assert.True(t, removed, "a torrent at 0% past the default timeout should be removed")
+	assert.Equal(t, 1, arr.deleteCount(), "queued, started and protected torrents should be kept")
+	assert.Equal(t, 0, m.GetStrikesHandler().Get("FETCHING"), "torrents in a protected state shouldn't be struck")
+}
@@ -0,0 +1,197 @@
+package removal
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+// ObsoleteTagsJob removes the obsolete tag from torrents whose problem has
+// since been resolved, e.g. a stalled download the user got going again. A
+// tagged torrent counts as resolved once its *arr queue item is healthy and it
+// has no strikes. Torrents not in any queue keep the tag, since orphans are
+// tagged too.
+type ObsoleteTagsJob struct {
+	name        string
+	enabled     bool
+	cfg         *config.JobConfig
+	manager     *jobs.Manager
+	logger      *slog.Logger
+	testRun     bool
+	lastFound   int
+	lastRemoved int
+}
+
+// NewObsoleteTagsJob creates a new obsolete tag cleanup job
+func NewObsoleteTagsJob(
+	name string,
+	cfg *config.JobConfig,
+	manager *jobs.Manager,
+	logger *slog.Logger,
+	testRun bool,
+) *ObsoleteTagsJob {
+	return &ObsoleteTagsJob{
+		name:    name,
+		enabled: cfg.Enabled,
+		cfg:     cfg,
+		manager: manager,
+		logger:  logger.With("job", "clean_obsolete_tags"),
+		testRun: testRun,
+	}
+}
+
+// Name returns the job identifier
+func (j *ObsoleteTagsJob) Name() string {
+	return j.name
+}
+
+// Enabled returns whether this job is enabled
+func (j *ObsoleteTagsJob) Enabled() bool {
+	return j.enabled
+}
+
+// SetTestRun switches the job in or out of test-run mode
+func (j *ObsoleteTagsJob) SetTestRun(testRun bool) {
+	j.testRun = testRun
+}
+
+// Interval returns how often the job runs, or 0 to run every cycle
+func (j *ObsoleteTagsJob) Interval() time.Duration {
+	return jobInterval(j.cfg)
+}
+
+// Order returns the job's position in the cycle
+func (j *ObsoleteTagsJob) Order() int {
+	return jobOrder(j.cfg.Order)
+}
+
+// isHealthyQueueItem reports whether the *arr reports nothing wrong with a
+// queue item
+func isHealthyQueueItem(item arrapi.QueueItem) bool {
+	if item.TrackedDownloadStatus != "" && item.TrackedDownloadStatus != "ok" {
+		return false
+	}
+	return item.ErrorMessage == "" && len(item.StatusMessages) == 0
+}
+
+// Run executes the obsolete tag cleanup job
+func (j *ObsoleteTagsJob) Run(ctx context.Context) error {
+	j.logger.Debug("starting obsolete tag cleanup job", "test_run", j.testRun)
+
+	obsoleteTag := j.manager.GetConfig().General.ObsoleteTag
+	if obsoleteTag == "" {
+		j.logger.Debug("obsolete tag not configured, skipping")
+		return nil
+	}
+
+	queues, queueErr := j.manager.GetAllQueues(ctx)
+
+	// A download that's still unhealthy in an unreachable instance's queue
+	// would look resolved, so only act when every queue was fetched.
+	if queueErr != nil || !j.manager.AllQueuesFetched() {
+		j.logger.Warn("not all arr queues could be fetched, skipping obsolete tag cleanup this cycle", "error", queueErr)
+		j.lastFound = 0
+		j.lastRemoved = 0
+		return jobs.QueueError(queueErr)
+	}
+
+	// A download is resolved only if every queue it appears in reports it healthy
+	strikesHandler := j.manager.GetStrikesHandler()
+	resolved := make(map[string]bool) // lowercase download ID -> resolved
+	for _, queue := range queues {
+		for _, item := range queue {
+			if item.DownloadID == "" {
+				continue
+			}
+			id := strings.ToLower(item.DownloadID)
+			healthy := isHealthyQueueItem(item) && strikesHandler.Get(item.DownloadID) == 0
+			if ok, seen := resolved[id]; seen {
+				healthy = healthy && ok
+			}
+			resolved[id] = healthy
+		}
+	}
+
+	totalFound := 0
+	totalRemoved := 0
+
+	for clientName, client := range j.manager.GetAllDownloadClients() {
+		if !jobs.InInstances(j.cfg.Instances, clientName) {
+			continue
+		}
+		remover, ok := client.(downloadclient.TagRemover)
+		if !ok {
+			continue
+		}
+
+		torrents, err := client.GetTorrents(ctx)
+		if err != nil {
+			j.logger.Error("failed to get torrents from client",
+				"client", clientName,
+				"error", err)
+			continue
+		}
+
+		for _, torrent := range torrents {
+			if !downloadclient.HasTag(&torrent, obsoleteTag) || !resolved[strings.ToLower(torrent.Hash)] {
+				continue
+			}
+			if torrent.State == downloadclient.StateStalled || torrent.State == downloadclient.StateError {
+				continue
+			}
+
+			totalFound++
+
+			if j.testRun {
+				j.logger.Info("[TEST RUN] would remove obsolete tag from resolved torrent",
+					"hash", torrent.Hash,
+					"name", torrent.Name,
+					"client", clientName,
+				)
+				totalRemoved++
+				continue
+			}
+
+			if err := remover.RemoveTags(ctx, torrent.Hash, []string{obsoleteTag}); err != nil {
+				j.logger.Error("failed to remove obsolete tag",
+					"hash", torrent.Hash,
+					"name", torrent.Name,
+					"client", clientName,
+					"error", err,
+				)
+				continue
+			}
+
+			totalRemoved++
+			j.logger.Info("removed obsolete tag from resolved torrent",
+				"hash", torrent.Hash,
+				"name", torrent.Name,
+				"client", clientName,
+			)
+		}
+	}
+
+	j.logger.Debug("obsolete tag cleanup job completed",
+		"found", totalFound,
+		"removed", totalRemoved,
+		"test_run", j.testRun)
+
+	j.lastFound = totalFound
+	j.lastRemoved = totalRemoved
+
+	return jobs.QueueError(queueErr)
+}
+
+// Stats returns the statistics from the last job run
+func (j *ObsoleteTagsJob) Stats() jobs.JobStats {
+	return jobs.JobStats{
+		Found:   j.lastFound,
+		Removed: j.lastRemoved,
+	}
+}
@@ -0,0 +1,102 @@
+package removal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+)
+
+func TestObsoleteTagsRemovedOnceResolved(t *testing.T) {
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		{ID: 1, Title: "Recovered.Item", DownloadID: "RECOVERED", TrackedDownloadStatus: "ok"},
+		{
+			ID:                    2,
+			Title:                 "Still.Stalled",
+			DownloadID:            "stalled",
+			TrackedDownloadStatus: "warning",
+			StatusMessages:        []arrapi.StatusMessage{{Title: "Download stalled"}},
+		},
+		{ID: 3, Title: "Struck.Item", DownloadID: "struck", TrackedDownloadStatus: "ok"},
+	})
+
+	client := newFakeDownloadClient(
+		downloadclient.Torrent{Hash: "recovered", State: downloadclient.StateDownloading, Tags: []string{"Obsolete", "Other"}},
+		downloadclient.Torrent{Hash: "stalled", State: downloadclient.StateStalled, Tags: []string{"Obsolete"}},
+		downloadclient.Torrent{Hash: "struck", State: downloadclient.StateDownloading, Tags: []string{"Obsolete"}},
+		downloadclient.Torrent{Hash: "orphan", State: downloadclient.StateSeeding, Tags: []string{"Obsolete"}},
+	)
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+	m.RegisterDownloadClient("qbittorrent", client)
+	m.GetStrikesHandler().Add("struck", "remove_slow", "Struck.Item", "download too slow")
+
+	tags := func(hash string) []string {
+		torrent, err := client.GetTorrent(context.Background(), hash)
+		require.NoError(t, err)
+		return torrent.Tags
+	}
+
+	t.Run("test run leaves tags", func(t *testing.T) {
+		job := NewObsoleteTagsJob("clean_obsolete_tags", &config.JobConfig{Enabled: true}, m, testLogger(), true)
+		require.NoError(t, job.Run(context.Background()))
+		assert.Equal(t, []string{"Obsolete", "Other"}, tags("recovered"))
+		assert.Equal(t, 1, job.Stats().Removed)
+	})
+
+	t.Run("removes tag from healthy torrent", func(t *testing.T) {
+		job := NewObsoleteTagsJob("clean_obsolete_tags", &config.JobConfig{Enabled: true}, m, testLogger(), false)
+		require.NoError(t, job.Run(context.Background()))
+
+		assert.Equal(t, []string{"Other"}, tags("recovered"))
+		assert.Equal(t, []string{"Obsolete"}, tags("stalled"), "still affected")
+		assert.Equal(t, []string{"Obsolete"}, tags("struck"), "still has strikes")
+		assert.Equal(t, []string{"Obsolete"}, tags("orphan"), "not in any queue")
+		assert.Equal(t, 1, job.Stats().Removed)
+	})
+}
+
+func TestObsoleteTagsSkippedWhenQueueFetchFails(t *testing.T) {
+	good := newFakeArr(t, []arrapi.QueueItem{{ID: 1, Title: "Healthy.Item", DownloadID: "shared", TrackedDownloadStatus: "ok"}})
+	bad := newFakeArr(t, nil)
+	bad.handle("/api/v3/queue", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	client := newFakeDownloadClient(
+		downloadclient.Torrent{Hash: "shared", State: downloadclient.StateDownloading, Tags: []string{"Obsolete"}},
+	)
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": good, "radarr": bad})
+	m.RegisterDownloadClient("qbittorrent", client)
+
+	tags := func() []string {
+		torrent, err := client.GetTorrent(context.Background(), "shared")
+		require.NoError(t, err)
+		return torrent.Tags
+	}
+
+	job := NewObsoleteTagsJob("clean_obsolete_tags", &config.JobConfig{Enabled: true}, m, testLogger(), false)
+
+	require.Error(t, job.Run(context.Background()))
+	assert.Equal(t, []string{"Obsolete"}, tags(), "the unreachable instance may still report it unhealthy")
+	assert.Equal(t, 0, job.Stats().Removed)
+
+	// Once every instance responds, the tag is cleaned up again
+	bad.handle("/api/v3/queue", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(arrapi.QueueResponse{})
+	})
+
+	require.NoError(t, job.Run(context.Background()))
+	assert.Empty(t, tags())
+	assert.Equal(t, 1, job.Stats().Removed)
+}
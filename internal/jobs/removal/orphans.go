@@ -3,8 +3,14 @@ package removal
 import (
 	"context"
 	"log/slog"
+	"path"
+	"strings"
+	"time"
+	"unicode"
 
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
 	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
 	"github.com/jmylchreest/go-decluttarr/internal/jobs"
 )
 
@@ -58,25 +64,53 @@ func (j *OrphansJob) Enabled() bool {
 	return j.enabled
 }
 
+// SetTestRun switches the job in or out of test-run mode
+func (j *OrphansJob) SetTestRun(testRun bool) {
+	j.testRun = testRun
+}
+
+// Interval returns how often the job runs, or 0 to run every cycle
+func (j *OrphansJob) Interval() time.Duration {
+	return jobInterval(j.cfg)
+}
+
+// Order returns the job's position in the cycle
+func (j *OrphansJob) Order() int {
+	return jobOrder(j.cfg.Order)
+}
+
 // Run executes the orphans removal job
 func (j *OrphansJob) Run(ctx context.Context) error {
 	j.logger.Debug("starting orphans removal job",
 		"test_run", j.testRun,
-		"max_strikes", j.maxStrikes)
+		"max_strikes", j.maxStrikes,
+		"target_categories", j.cfg.TargetCategories,
+		"ignore_categories", j.cfg.IgnoreCategories)
 
-	// Build a map of all download IDs tracked by *arr instances
-	trackedDownloads := make(map[string]bool)
+	queues, queueErr := j.manager.GetAllQueues(ctx)
 
-	queues, err := j.manager.GetAllQueues(ctx)
-	if err != nil {
-		j.logger.Warn("error getting some queues, continuing with available data", "error", err)
+	// A download tracked by an unreachable instance would look orphaned, so
+	// only act when every queue was fetched. Instances skipped because their
+	// download client is offline return no error but have no queue either.
+	queuesMissing := queueErr != nil
+	for instanceName := range j.manager.GetAllArrClients() {
+		if _, ok := queues[instanceName]; !ok {
+			queuesMissing = true
+		}
+	}
+	if queuesMissing {
+		j.logger.Warn("not all arr queues could be fetched, skipping orphan removal this cycle", "error", queueErr)
+		j.lastFound = 0
+		j.lastRemoved = 0
+		return jobs.QueueError(queueErr)
 	}
 
+	// Collect the downloads tracked by *arr instances. Every queue counts,
+	// even when the job is scoped to some download clients.
+	tracked := newTrackedDownloads()
 	for instanceName, queue := range queues {
 		for _, item := range queue {
-			if item.DownloadID != "" {
-				trackedDownloads[item.DownloadID] = true
-			}
+			tracked.add(item)
 		}
 		j.logger.Debug("retrieved queue from arr instance",
 			"instance", instanceName,
@@ -84,13 +118,13 @@ func (j *OrphansJob) Run(ctx context.Context) error {
 	}
 
 	j.logger.Debug("total tracked downloads across all arr instances",
-		"count", len(trackedDownloads))
+		"count", len(tracked.ids))
 
 	// Get all download clients and their torrents
 	downloadClients := j.manager.GetAllDownloadClients()
 	if len(downloadClients) == 0 {
 		j.logger.Warn("no download clients registered, skipping orphan check")
-		return nil
+		return jobs.QueueError(queueErr)
 	}
 
 	strikesHandler := j.manager.GetStrikesHandler()
@@ -98,6 +132,13 @@ func (j *OrphansJob) Run(ctx context.Context) error {
 	removedCount := 0
 
 	for clientName, client := range downloadClients {
+		if !jobs.InInstances(j.cfg.Instances, clientName) {
+			continue
+		}
+
+		// Orphans to tag as obsolete are batched per client
+		var toTag []string
+
 		torrents, err := client.GetTorrents(ctx)
 		if err != nil {
 			j.logger.Error("failed to get torrents from client",
@@ -112,8 +153,22 @@ func (j *OrphansJob) Run(ctx context.Context) error {
 
 		for _, torrent := range torrents {
 			// Check if torrent is tracked by any *arr instance
-			if trackedDownloads[torrent.Hash] {
-				// Torrent is tracked, skip
+			if matchedBy := tracked.match(&torrent); matchedBy != "" {
+				if matchedBy != trackedByID {
+					j.logger.Debug("torrent matched a tracked download without an exact hash match",
+						"hash", torrent.Hash,
+						"name", torrent.Name,
+						"matched_by", matchedBy)
+				}
+				continue
+			}
+
+			// Only consider torrents in arr-managed categories
+			if !j.matchesCategory(&torrent) {
+				j.logger.Debug("skipping torrent outside orphan categories",
+					"hash", torrent.Hash,
+					"name", torrent.Name,
+					"category", torrent.Category)
 				continue
 			}
 
@@ -125,14 +180,14 @@ func (j *OrphansJob) Run(ctx context.Context) error {
 				"state", torrent.State)
 
 			// Increment strikes
-			currentStrikes := strikesHandler.Add(torrent.Hash, j.name, torrent.Name)
+			currentStrikes := strikesHandler.Add(torrent.Hash, j.name, torrent.Name, "not tracked by any arr instance")
 			j.logger.Debug("incremented strikes for orphaned torrent",
 				"hash", torrent.Hash,
 				"current_strikes", currentStrikes,
 				"max_strikes", j.maxStrikes)
 
 			// Check if strikes exceeded
-			if !strikesHandler.HasExceeded(torrent.Hash, j.maxStrikes) {
+			if !j.manager.StrikesExceeded(torrent.Hash, maxStrikesFor(ctx, j.manager, j.cfg, j.defaults, j.maxStrikes, torrent.Hash)) {
 				j.logger.Debug("orphaned torrent has not exceeded max strikes yet",
 					"hash", torrent.Hash,
 					"current_strikes", currentStrikes,
@@ -153,25 +208,17 @@ func (j *OrphansJob) Run(ctx context.Context) error {
 				j.logger.Debug("skipping protected orphaned torrent", "name", torrent.Name, "hash", torrent.Hash)
 				continue
 			case "tag":
-				if j.testRun {
+				if downloadclient.HasTag(&torrent, j.manager.GetConfig().General.ObsoleteTag) {
+					j.logger.Debug("obsolete tag already exists", "hash", torrent.Hash)
+				} else if j.testRun {
 					j.logger.Info("[TEST RUN] would tag orphaned torrent as obsolete",
 						"hash", torrent.Hash,
 						"name", torrent.Name,
 						"strikes", currentStrikes,
 					)
 				} else {
-					if err := j.manager.ApplyObsoleteTag(ctx, torrent.Hash); err != nil {
-						j.logger.Error("failed to tag orphaned torrent as obsolete",
-							"hash", torrent.Hash,
-							"error", err,
-						)
-						continue
-					}
-					j.logger.Info("tagged orphaned torrent as obsolete",
-						"hash", torrent.Hash,
-						"name", torrent.Name,
-						"strikes", currentStrikes,
-					)
+					toTag = append(toTag, torrent.Hash)
+					continue
 				}
 				strikesHandler.Reset(torrent.Hash)
 				removedCount++ // Count as handled
@@ -182,7 +229,7 @@ func (j *OrphansJob) Run(ctx context.Context) error {
 
 			// Remove from download client if not in test run mode
 			if !j.testRun {
-				if err := client.DeleteTorrent(ctx, torrent.Hash, false); err != nil {
+				if err := j.manager.DeleteClientTorrent(ctx, clientName, client, &torrent, deleteFiles(j.cfg.DeleteFiles, false)); err != nil {
 					j.logger.Error("failed to remove orphaned torrent",
 						"hash", torrent.Hash,
 						"error", err)
@@ -193,6 +240,13 @@ func (j *OrphansJob) Run(ctx context.Context) error {
 					"hash", torrent.Hash,
 					"name", torrent.Name,
 					"strikes", currentStrikes)
+				j.manager.RecordRemoval(jobs.RemovedDownload{
+					Job:        j.name,
+					Source:     clientName,
+					DownloadID: torrent.Hash,
+					Name:       torrent.Name,
+					Reason:     "orphaned",
+				})
 
 				// Reset strikes after successful removal
 				strikesHandler.Reset(torrent.Hash)
@@ -204,6 +258,29 @@ func (j *OrphansJob) Run(ctx context.Context) error {
 				removedCount++
 			}
 		}
+
+		if len(toTag) == 0 {
+			continue
+		}
+
+		if err := j.manager.ApplyObsoleteTagBulk(ctx, client, toTag); err != nil {
+			j.logger.Error("failed to tag orphaned torrents as obsolete",
+				"client", clientName,
+				"count", len(toTag),
+				"error", err,
+			)
+			continue
+		}
+
+		for _, hash := range toTag {
+			strikesHandler.Reset(hash)
+		}
+		removedCount += len(toTag) // Count as handled
+
+		j.logger.Info("tagged orphaned torrents as obsolete",
+			"client", clientName,
+			"count", len(toTag),
+		)
 	}
 
 	j.logger.Debug("orphans removal job completed",
@@ -214,7 +291,7 @@ func (j *OrphansJob) Run(ctx context.Context) error {
 	j.lastFound = orphanCount
 	j.lastRemoved = removedCount
 
-	return nil
+	return jobs.QueueError(queueErr)
 }
 
 // Stats returns the statistics from the last job run
@@ -224,3 +301,87 @@ func (j *OrphansJob) Stats() jobs.JobStats {
 		Removed: j.lastRemoved,
 	}
 }
+
+// How a torrent was matched to a download tracked by an *arr
+const (
+	trackedByID   = "id"
+	trackedByName = "name"
+)
+
+// trackedDownloads holds the downloads in the *arr queues, normalized so
+// torrents aren't taken for orphans when the *arr and the download client
+// disagree on hash casing, or report different IDs for the same download
+type trackedDownloads struct {
+	ids   map[string]bool // lowercased download IDs
+	names map[string]bool // normalized titles and output folder names
+}
+
+func newTrackedDownloads() *trackedDownloads {
+	return &trackedDownloads{
+		ids:   make(map[string]bool),
+		names: make(map[string]bool),
+	}
+}
+
+// add records a queue item as tracked
+func (t *trackedDownloads) add(item arrapi.QueueItem) {
+	if item.DownloadID != "" {
+		t.ids[strings.ToLower(item.DownloadID)] = true
+	}
+	if name := normalizeDownloadName(item.Title); name != "" {
+		t.names[name] = true
+	}
+	if item.OutputPath != "" {
+		if name := normalizeDownloadName(path.Base(strings.ReplaceAll(item.OutputPath, `\`, "/"))); name != "" {
+			t.names[name] = true
+		}
+	}
+}
+
+// match returns how the torrent matches a tracked download, or "" if it is
+// untracked. The hash is compared case-insensitively, falling back to the
+// torrent's name against the queue titles and output folders.
+func (t *trackedDownloads) match(torrent *downloadclient.Torrent) string {
+	if t.ids[strings.ToLower(torrent.Hash)] {
+		return trackedByID
+	}
+	if name := normalizeDownloadName(torrent.Name); name != "" && t.names[name] {
+		return trackedByName
+	}
+	return ""
+}
+
+// normalizeDownloadName lowercases a release name and drops separators, so
+// "Some.Show.S01" and "Some Show S01" compare equal
+func normalizeDownloadName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// matchesCategory checks if a torrent's category is eligible for orphan detection.
+// Ignored categories always win; if target categories are set, the torrent must
+// be in one of them.
+func (j *OrphansJob) matchesCategory(torrent *downloadclient.Torrent) bool {
+	for _, category := range j.cfg.IgnoreCategories {
+		if strings.EqualFold(torrent.Category, category) {
+			return false
+		}
+	}
+
+	if len(j.cfg.TargetCategories) == 0 {
+		return true
+	}
+
+	for _, category := range j.cfg.TargetCategories {
+		if strings.EqualFold(torrent.Category, category) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,267 @@
+package removal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+)
+
+func TestOrphansCategoryFiltering(t *testing.T) {
+	tests := []struct {
+		name        string
+		jobCfg      config.JobConfig
+		wantRemoved []string
+		wantKept    []string
+	}{
+		{
+			name:        "no filters considers all categories",
+			jobCfg:      config.JobConfig{Enabled: true},
+			wantRemoved: []string{"orphan-tv", "orphan-manual"},
+			wantKept:    []string{"tracked"},
+		},
+		{
+			name:        "target categories excludes manual torrents",
+			jobCfg:      config.JobConfig{Enabled: true, TargetCategories: []string{"tv-sonarr"}},
+			wantRemoved: []string{"orphan-tv"},
+			wantKept:    []string{"tracked", "orphan-manual"},
+		},
+		{
+			name:        "ignore categories excludes manual torrents",
+			jobCfg:      config.JobConfig{Enabled: true, IgnoreCategories: []string{"Manual"}},
+			wantRemoved: []string{"orphan-tv"},
+			wantKept:    []string{"tracked", "orphan-manual"},
+		},
+		{
+			name: "ignore wins over target",
+			jobCfg: config.JobConfig{
+				Enabled:          true,
+				TargetCategories: []string{"tv-sonarr", "manual"},
+				IgnoreCategories: []string{"manual"},
+			},
+			wantRemoved: []string{"orphan-tv"},
+			wantKept:    []string{"tracked", "orphan-manual"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := newFakeArr(t, []arrapi.QueueItem{
+				{ID: 1, Title: "Tracked", DownloadID: "tracked"},
+			})
+
+			cfg := testConfig()
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+			client := newFakeDownloadClient(
+				downloadclient.Torrent{Hash: "tracked", Name: "Tracked", Category: "tv-sonarr"},
+				downloadclient.Torrent{Hash: "orphan-tv", Name: "Orphan TV", Category: "tv-sonarr"},
+				downloadclient.Torrent{Hash: "orphan-manual", Name: "Manual", Category: "manual"},
+			)
+			m.RegisterDownloadClient("qbittorrent", client)
+
+			jobCfg := tt.jobCfg
+			job := NewOrphansJob("remove_orphans", &jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+
+			require.NoError(t, job.Run(context.Background()))
+
+			for _, hash := range tt.wantRemoved {
+				_, ok := client.wasDeleted(hash)
+				assert.True(t, ok, "expected %s to be removed", hash)
+			}
+			for _, hash := range tt.wantKept {
+				_, ok := client.wasDeleted(hash)
+				assert.False(t, ok, "expected %s to be kept", hash)
+			}
+		})
+	}
+}
+
+func TestOrphansDeleteFilesPolicy(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		deleteFiles *bool
+		want        bool
+	}{
+		{name: "default keeps files", deleteFiles: nil, want: false},
+		{name: "delete files", deleteFiles: boolPtr(true), want: true},
+		{name: "keep files", deleteFiles: boolPtr(false), want: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := newFakeArr(t, nil)
+			cfg := testConfig()
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+			client := newFakeDownloadClient(downloadclient.Torrent{Hash: "orphan", Name: "Orphan", Category: "tv-sonarr"})
+			m.RegisterDownloadClient("qbittorrent", client)
+
+			job := NewOrphansJob("remove_orphans", &config.JobConfig{Enabled: true, DeleteFiles: tt.deleteFiles}, &cfg.JobDefaults, m, testLogger(), false)
+			require.NoError(t, job.Run(context.Background()))
+
+			deleteFiles, ok := client.wasDeleted("orphan")
+			require.True(t, ok)
+			assert.Equal(t, tt.want, deleteFiles)
+		})
+	}
+}
+
+func TestOrphansRunPreRemoveHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need a POSIX shell")
+	}
+
+	tests := []struct {
+		name     string
+		client   string
+		usenet   bool
+		wantArgs string
+	}{
+		{name: "torrent client", client: "qbittorrent", wantArgs: "qbittorrent  orphan Orphan torrent\n"},
+		{name: "usenet client", client: "sabnzbd", usenet: true, wantArgs: "sabnzbd  orphan Orphan usenet\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			hook := filepath.Join(dir, "hook.sh")
+			outFile := filepath.Join(dir, "args")
+			script := "#!/bin/sh\necho \"$@\" \"$DECLUTTARR_PROTOCOL\" > " + outFile + "\nexit 1\n"
+			require.NoError(t, os.WriteFile(hook, []byte(script), 0o755))
+
+			arr := newFakeArr(t, nil)
+			cfg := testConfig()
+			cfg.General.PreRemoveHook = hook
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+			client := newFakeDownloadClient(downloadclient.Torrent{Hash: "orphan", Name: "Orphan", Category: "tv-sonarr"})
+			client.seeds = !tt.usenet
+			m.RegisterDownloadClient(tt.client, client)
+
+			job := NewOrphansJob("remove_orphans", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+			require.NoError(t, job.Run(context.Background()))
+
+			_, ok := client.wasDeleted("orphan")
+			assert.False(t, ok, "a removal the hook denies must not reach the client")
+			args, err := os.ReadFile(outFile)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, string(args), "client removals have no queue ID")
+		})
+	}
+}
+
+func TestOrphansSkippedWhenQueueFetchFails(t *testing.T) {
+	good := newFakeArr(t, []arrapi.QueueItem{{ID: 1, Title: "Tracked", DownloadID: "tracked"}})
+	bad := newFakeArr(t, nil)
+	bad.handle("/api/v3/queue", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": good, "radarr": bad})
+
+	client := newFakeDownloadClient(
+		downloadclient.Torrent{Hash: "tracked", Name: "Tracked", Category: "tv-sonarr"},
+		downloadclient.Torrent{Hash: "radarr-download", Name: "Movie", Category: "radarr"},
+	)
+	m.RegisterDownloadClient("qbittorrent", client)
+
+	job := NewOrphansJob("remove_orphans", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+
+	require.Error(t, job.Run(context.Background()))
+	assert.False(t, m.AllQueuesFetched())
+
+	_, ok := client.wasDeleted("radarr-download")
+	assert.False(t, ok, "torrent from unreachable instance must not be treated as an orphan")
+	assert.Equal(t, 0, m.GetStrikesHandler().Get("radarr-download"), "no strikes should be added")
+	assert.Equal(t, 0, job.Stats().Removed)
+
+	// Once every instance responds, orphans are handled again
+	bad.handle("/api/v3/queue", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(arrapi.QueueResponse{})
+	})
+
+	require.NoError(t, job.Run(context.Background()))
+	assert.True(t, m.AllQueuesFetched())
+
+	_, ok = client.wasDeleted("radarr-download")
+	assert.True(t, ok)
+}
+
+func TestOrphansFuzzyMatching(t *testing.T) {
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		{ID: 1, Title: "Upper.Case.Hash", DownloadID: "ABCDEF0123"},
+		{ID: 2, Title: "Some.Show.S01E01.1080p", DownloadID: "SABnzbd_nzo_1", Protocol: "usenet"},
+		{ID: 3, Title: "Renamed.Title", DownloadID: "other-id", OutputPath: "/downloads/tv/Original Folder Name"},
+	})
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+	client := newFakeDownloadClient(
+		downloadclient.Torrent{Hash: "abcdef0123", Name: "Unrelated", Category: "tv-sonarr"},
+		downloadclient.Torrent{Hash: "111", Name: "Some Show S01E01 1080p", Category: "tv-sonarr"},
+		downloadclient.Torrent{Hash: "222", Name: "Original.Folder.Name", Category: "tv-sonarr"},
+		downloadclient.Torrent{Hash: "333", Name: "Real.Orphan", Category: "tv-sonarr"},
+	)
+	m.RegisterDownloadClient("qbittorrent", client)
+
+	job := NewOrphansJob("remove_orphans", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+	require.NoError(t, job.Run(context.Background()))
+
+	for _, hash := range []string{"abcdef0123", "111", "222"} {
+		_, ok := client.wasDeleted(hash)
+		assert.False(t, ok, "expected %s to be matched to a tracked download", hash)
+	}
+	_, ok := client.wasDeleted("333")
+	assert.True(t, ok, "expected the untracked torrent to be removed")
+	assert.Equal(t, 1, job.Stats().Found)
+}
+
+func TestOrphansTagHandlingCountsEveryBranch(t *testing.T) {
+	tests := []struct {
+		name     string
+		testRun  bool
+		wantTags []string // hashes tagged
+	}{
+		{name: "tagged in one request", wantTags: []string{"orphan-a", "orphan-b"}},
+		{name: "test run", testRun: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := newFakeArr(t, nil)
+			client := newFakeDownloadClient(
+				downloadclient.Torrent{Hash: "orphan-a", Name: "Orphan.A"},
+				downloadclient.Torrent{Hash: "orphan-b", Name: "Orphan.B"},
+				downloadclient.Torrent{Hash: "orphan-tagged", Name: "Orphan.Tagged", Tags: []string{"Obsolete"}},
+			)
+
+			cfg := testConfig()
+			cfg.General.PublicTrackerHandling = "obsolete_tag"
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+			m.RegisterDownloadClient("qbittorrent", client)
+
+			job := NewOrphansJob("remove_orphans", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), tt.testRun)
+			require.NoError(t, job.Run(context.Background()))
+
+			var tagged []string
+			for hash := range client.tags {
+				tagged = append(tagged, hash)
+			}
+			assert.ElementsMatch(t, tt.wantTags, tagged)
+			assert.LessOrEqual(t, client.tagCalls, 1)
+			assert.Equal(t, 3, job.Stats().Removed, "tagged, already tagged and test-run orphans count as handled")
+			for _, hash := range []string{"orphan-a", "orphan-b", "orphan-tagged"} {
+				assert.Equal(t, 0, m.GetStrikesHandler().Get(hash), "strikes reset for %s", hash)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
 	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
 	"github.com/jmylchreest/go-decluttarr/internal/jobs"
 )
 
@@ -22,6 +23,9 @@ type SlowDownloadJob struct {
 	testRun          bool
 	maxStrikes       int
 	minDownloadSpeed float64
+	deferLastActive  bool
+	minTimeLeft      time.Duration
+	minProgress      float64 // downloads at least this fraction complete are exempt, 0 = none
 	lastFound        int
 	lastRemoved      int
 }
@@ -45,6 +49,11 @@ func NewSlowDownloadJob(
 		minDownloadSpeed = *cfg.MinDownloadSpeed
 	}
 
+	var minProgress float64
+	if cfg.SlowMinProgressExempt != nil {
+		minProgress = *cfg.SlowMinProgressExempt
+	}
+
 	return &SlowDownloadJob{
 		name:             name,
 		enabled:          cfg.Enabled,
@@ -55,6 +64,9 @@ func NewSlowDownloadJob(
 		testRun:          testRun,
 		maxStrikes:       maxStrikes,
 		minDownloadSpeed: minDownloadSpeed,
+		deferLastActive:  deferLastActive(cfg, defaults),
+		minTimeLeft:      minTimeLeft(cfg, defaults),
+		minProgress:      minProgress,
 	}
 }
 
@@ -68,6 +80,35 @@ func (j *SlowDownloadJob) Enabled() bool {
 	return j.enabled
 }
 
+// SetTestRun switches the job in or out of test-run mode
+func (j *SlowDownloadJob) SetTestRun(testRun bool) {
+	j.testRun = testRun
+}
+
+// Interval returns how often the job runs, or 0 to run every cycle
+func (j *SlowDownloadJob) Interval() time.Duration {
+	return jobInterval(j.cfg)
+}
+
+// Order returns the job's position in the cycle
+func (j *SlowDownloadJob) Order() int {
+	return jobOrder(j.cfg.Order)
+}
+
+// nearlyDownloaded reports whether a download has reached the progress at
+// which it's exempt from slow strikes
+func (j *SlowDownloadJob) nearlyDownloaded(item arrapi.QueueItem) bool {
+	return j.minProgress > 0 && queueProgress(item) >= j.minProgress
+}
+
+// queueProgress returns the fraction of a queue item that has downloaded
+func queueProgress(item arrapi.QueueItem) float64 {
+	if item.Size <= 0 {
+		return 0
+	}
+	return float64(item.Size-item.Sizeleft) / float64(item.Size)
+}
+
 // FindAffected identifies slow download items in the queue
 func (j *SlowDownloadJob) FindAffected(queue []arrapi.QueueItem) []arrapi.QueueItem {
 	var affected []arrapi.QueueItem
@@ -82,8 +123,17 @@ func (j *SlowDownloadJob) FindAffected(queue []arrapi.QueueItem) []arrapi.QueueI
 			continue
 		}
 
+		// A download about to finish isn't worth replacing
+		if completesWithin(item, j.minTimeLeft) || j.nearlyDownloaded(item) {
+			continue
+		}
+
 		// Calculate download speed (bytes per second)
-		elapsed := time.Since(item.Added).Seconds()
+		age, ok := grabAge(item)
+		if !ok {
+			continue
+		}
+		elapsed := age.Seconds()
 		if elapsed < 60 { // Wait at least 1 minute before checking speed
 			continue
 		}
@@ -99,6 +149,27 @@ func (j *SlowDownloadJob) FindAffected(queue []arrapi.QueueItem) []arrapi.QueueI
 	return affected
 }
 
+// filterQueue applies the job's scope and filters to an instance's queue,
+// leaving the items detection is run on
+func (j *SlowDownloadJob) filterQueue(instanceName string, queue []arrapi.QueueItem) []arrapi.QueueItem {
+	if !jobs.InInstances(j.cfg.Instances, instanceName) {
+		return nil
+	}
+	// The newest grabs are picked from the whole queue, before it's filtered
+	queue = withoutNewestGrabs(queue, protectNewest(j.cfg, j.defaults), j.logger)
+	queue = jobs.FilterProtocols(queue, j.cfg.Protocols)
+	queue = withoutFreshGrabs(queue, minGrabAge(j.cfg, j.defaults), j.logger)
+	queue = withoutProcessed(queue, j.manager, instanceName, j.logger)
+	return queue
+}
+
+// Detect returns the items of an instance's queue a run would strike, given
+// the download clients' torrents keyed by lowercase hash
+func (j *SlowDownloadJob) Detect(instanceName string, queue []arrapi.QueueItem, _ map[string]downloadclient.Torrent) []arrapi.QueueItem {
+	queue = j.filterQueue(instanceName, queue)
+	return j.FindAffected(queue)
+}
+
 // Run executes the slow download removal job
 func (j *SlowDownloadJob) Run(ctx context.Context) error {
 	j.logger.Debug("starting slow download removal job",
@@ -111,16 +182,21 @@ func (j *SlowDownloadJob) Run(ctx context.Context) error {
 		return nil
 	}
 
-	queues, err := j.manager.GetAllQueues(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get queues: %w", err)
-	}
+	queues, queueErr := j.manager.GetAllQueues(ctx)
+	queues = jobs.ScopeQueues(queues, j.cfg.Instances)
 
 	strikesHandler := j.manager.GetStrikesHandler()
+	struck := newRunStrikes(strikesHandler)
+	trace := newDecisionTracer(j.manager, j.logger, j.maxStrikes, j.testRun)
 	totalProcessed := 0
 	totalRemoved := 0
+	tags := newObsoleteTagger(j.manager, j.logger)
 
 	for instanceName, queue := range queues {
+		if !dataReliable(j.manager, instanceName, j.logger) {
+			continue
+		}
+		queue = j.filterQueue(instanceName, queue)
 		j.logger.Debug("checking queue items for slow downloads",
 			"instance", instanceName,
 			"count", len(queue))
@@ -128,21 +204,56 @@ func (j *SlowDownloadJob) Run(ctx context.Context) error {
 		for _, item := range queue {
 			// Skip if not downloading
 			if item.Status != "downloading" {
+				trace.skipped(instanceName, item, "not downloading")
+				continue
+			}
+
+			// A download about to finish isn't worth replacing
+			if completesWithin(item, j.minTimeLeft) {
+				j.logger.Debug("download nearly complete, skipping speed check",
+					"title", item.Title,
+					"estimated_completion", item.EstimatedCompletionTime,
+					"min_time_left", j.minTimeLeft)
+				trace.skipped(instanceName, item, "estimated to complete within min_time_left")
+				continue
+			}
+
+			if j.nearlyDownloaded(item) {
+				j.logger.Debug("download nearly complete, skipping speed check",
+					"title", item.Title,
+					"progress", queueProgress(item),
+					"slow_min_progress_exempt", j.minProgress)
+				trace.skipped(instanceName, item, "progress at or above slow_min_progress_exempt")
 				continue
 			}
 
 			// Calculate download speed (bytes per second)
-			elapsed := time.Since(item.Added).Seconds()
+			age, ok := grabAge(item)
+			if !ok {
+				j.logger.Warn("download grabbed in the future, the instance's clock may be ahead; skipping speed check",
+					"title", item.Title,
+					"download_id", item.DownloadID,
+					"grabbed", item.Added,
+					"instance", instanceName)
+				trace.skipped(instanceName, item, "grab time in the future (clock skew)")
+				continue
+			}
+			elapsed := age.Seconds()
 			if elapsed < 60 { // Wait at least 1 minute before checking speed
 				j.logger.Debug("download too recent, skipping speed check",
 					"title", item.Title,
 					"elapsed_seconds", elapsed)
+				trace.skipped(instanceName, item, "download too recent to measure speed")
 				continue
 			}
 
 			downloaded := float64(item.Size - item.Sizeleft)
 			speed := downloaded / elapsed // bytes per second
 
+			if speed >= j.minDownloadSpeed {
+				trace.skipped(instanceName, item, "download speed at or above minimum")
+			}
+
 			if speed < j.minDownloadSpeed {
 				totalProcessed++
 				j.logger.Debug("download is slow",
@@ -151,7 +262,8 @@ func (j *SlowDownloadJob) Run(ctx context.Context) error {
 					"min_speed_bps", j.minDownloadSpeed)
 
 				// Increment strikes
-				currentStrikes := strikesHandler.Add(item.DownloadID, j.name, item.Title)
+				reason := fmt.Sprintf("download speed %.0f B/s below minimum %.0f B/s", speed, j.minDownloadSpeed)
+				currentStrikes := struck.add(item.DownloadID, j.name, item.Title, reason)
 				j.logger.Debug("added strike to slow download",
 					"title", item.Title,
 					"download_id", item.DownloadID,
@@ -161,7 +273,10 @@ func (j *SlowDownloadJob) Run(ctx context.Context) error {
 					"instance", instanceName,
 				)
 
-				if strikesHandler.HasExceeded(item.DownloadID, j.maxStrikes) {
+				maxStrikes := maxStrikesFor(ctx, j.manager, j.cfg, j.defaults, j.maxStrikes, item.DownloadID)
+				trace.struck(ctx, instanceName, item, reason, currentStrikes, maxStrikes)
+
+				if j.manager.StrikesExceeded(item.DownloadID, maxStrikes) {
 					// Determine removal action based on tracker type and protected tags
 					action := j.manager.GetRemovalAction(ctx, item.DownloadID)
 
@@ -178,20 +293,13 @@ func (j *SlowDownloadJob) Run(ctx context.Context) error {
 								"instance", instanceName,
 							)
 						} else {
-							if err := j.manager.ApplyObsoleteTag(ctx, item.DownloadID); err != nil {
-								j.logger.Error("failed to tag as obsolete",
-									"title", item.Title,
-									"download_id", item.DownloadID,
-									"error", err,
-								)
-								continue
-							}
-							j.logger.Info("tagged slow download as obsolete",
+							tags.add(ctx, item, "tagged slow download as obsolete",
 								"title", item.Title,
 								"download_id", item.DownloadID,
 								"speed_bps", speed,
 								"instance", instanceName,
 							)
+							continue
 						}
 						strikesHandler.Reset(item.DownloadID)
 						totalRemoved++ // Count as handled
@@ -200,7 +308,16 @@ func (j *SlowDownloadJob) Run(ctx context.Context) error {
 						// Proceed with removal
 					}
 
-					if j.testRun {
+					if j.deferLastActive && j.manager.IsLastActiveInCategory(ctx, item.DownloadID) {
+						j.logger.Info("deferring removal of the last active download in its category",
+							"title", item.Title,
+							"download_id", item.DownloadID,
+							"instance", instanceName,
+						)
+						continue
+					}
+
+					if j.testRun || !j.manager.DeleteAllowed(instanceName) {
 						j.logger.Info("[TEST RUN] would remove slow download",
 							"title", item.Title,
 							"download_id", item.DownloadID,
@@ -236,12 +353,14 @@ func (j *SlowDownloadJob) Run(ctx context.Context) error {
 					j.logger.Debug("download speed recovered, clearing strikes",
 						"title", item.Title,
 						"download_id", item.DownloadID)
-					strikesHandler.Reset(item.DownloadID)
+					strikesHandler.Recover(item.DownloadID)
 				}
 			}
 		}
 	}
 
+	totalRemoved += tags.apply(ctx, strikesHandler)
+
 	j.logger.Debug("slow download removal job completed",
 		"processed", totalProcessed,
 		"removed", totalRemoved,
@@ -250,23 +369,18 @@ func (j *SlowDownloadJob) Run(ctx context.Context) error {
 	j.lastFound = totalProcessed
 	j.lastRemoved = totalRemoved
 
-	return nil
+	return jobs.QueueError(queueErr)
 }
 
 // removeItem removes a queue item from the arr instance
 func (j *SlowDownloadJob) removeItem(ctx context.Context, instanceName string, item arrapi.QueueItem) error {
-	client, ok := j.manager.GetArrClient(instanceName)
-	if !ok {
-		return fmt.Errorf("arr client not found: %s", instanceName)
-	}
-
 	opts := arrapi.DeleteOptions{
 		RemoveFromClient: true,
 		Blocklist:        false,
 		SkipRedownload:   false,
 	}
 
-	return client.DeleteQueueItem(ctx, item.ID, opts)
+	return j.manager.RemoveQueueItem(ctx, instanceName, item, opts, reviewCategory(j.cfg, j.defaults), reasonTag(j.cfg, j.defaults, "slow"), deleteFiles(j.cfg.DeleteFiles, true))
 }
 
 // Stats returns the statistics from the last job run
@@ -0,0 +1,165 @@
+package removal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+)
+
+func TestSlowMinTimeLeft(t *testing.T) {
+	soon := time.Now().Add(10 * time.Minute)
+	later := time.Now().Add(12 * time.Hour)
+	added := time.Now().Add(-time.Hour)
+
+	slowItem := func(id int, downloadID string, eta *time.Time) arrapi.QueueItem {
+		return arrapi.QueueItem{
+			ID:                      id,
+			Title:                   downloadID,
+			DownloadID:              downloadID,
+			Status:                  "downloading",
+			Size:                    1000,
+			Sizeleft:                900,
+			Added:                   added,
+			EstimatedCompletionTime: eta,
+		}
+	}
+
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		slowItem(1, "soon", &soon),
+		slowItem(2, "later", &later),
+		slowItem(3, "no-estimate", nil),
+	})
+
+	cfg := testConfig()
+	cfg.JobDefaults.MaxStrikes = 3
+	m := newTestManager(t, cfg, map[string]*fakeArr{"radarr": arr})
+
+	timeLeft := 30 * time.Minute
+	jobCfg := &config.JobConfig{Enabled: true, MinTimeLeft: &timeLeft}
+	job := NewSlowDownloadJob("remove_slow", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+
+	require.NoError(t, job.Run(context.Background()))
+
+	strikes := m.GetStrikesHandler()
+	assert.Equal(t, 0, strikes.Get("soon"), "download completing within min_time_left is exempt")
+	assert.Equal(t, 1, strikes.Get("later"))
+	assert.Equal(t, 1, strikes.Get("no-estimate"))
+	assert.Len(t, job.FindAffected([]arrapi.QueueItem{slowItem(1, "soon", &soon), slowItem(2, "later", &later)}), 1)
+}
+
+func TestSlowProtectNewest(t *testing.T) {
+	now := time.Now()
+	movieID := 7
+	slowItem := func(id int, downloadID string, age time.Duration) arrapi.QueueItem {
+		return arrapi.QueueItem{
+			ID:         id,
+			Title:      downloadID,
+			DownloadID: downloadID,
+			Status:     "downloading",
+			Size:       1000,
+			Sizeleft:   900,
+			MovieID:    &movieID,
+			Added:      now.Add(-age),
+		}
+	}
+
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		slowItem(1, "older", 3*time.Hour),
+		slowItem(2, "newest", time.Hour),
+	})
+
+	cfg := testConfig()
+	cfg.JobDefaults.MaxStrikes = 3
+	cfg.JobDefaults.ProtectNewest = 1
+	m := newTestManager(t, cfg, map[string]*fakeArr{"radarr": arr})
+
+	job := NewSlowDownloadJob("remove_slow", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+	require.NoError(t, job.Run(context.Background()))
+
+	strikes := m.GetStrikesHandler()
+	assert.Equal(t, 0, strikes.Get("newest"), "the movie's newest grab is exempt")
+	assert.Equal(t, 1, strikes.Get("older"))
+}
+
+func TestSlowMinProgressExempt(t *testing.T) {
+	added := time.Now().Add(-time.Hour)
+	slowItem := func(id int, downloadID string, sizeleft int64) arrapi.QueueItem {
+		return arrapi.QueueItem{
+			ID:         id,
+			Title:      downloadID,
+			DownloadID: downloadID,
+			Status:     "downloading",
+			Size:       1000,
+			Sizeleft:   sizeleft,
+			Added:      added,
+		}
+	}
+	nearlyDone := slowItem(1, "nearly-done", 10)
+	barelyStarted := slowItem(2, "barely-started", 900)
+
+	arr := newFakeArr(t, []arrapi.QueueItem{nearlyDone, barelyStarted})
+
+	cfg := testConfig()
+	cfg.JobDefaults.MaxStrikes = 3
+	m := newTestManager(t, cfg, map[string]*fakeArr{"radarr": arr})
+
+	exempt := 0.95
+	jobCfg := &config.JobConfig{Enabled: true, SlowMinProgressExempt: &exempt}
+	job := NewSlowDownloadJob("remove_slow", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+
+	require.NoError(t, job.Run(context.Background()))
+
+	strikes := m.GetStrikesHandler()
+	assert.Equal(t, 0, strikes.Get("nearly-done"), "a 99% complete download is exempt")
+	assert.Equal(t, 1, strikes.Get("barely-started"), "a 10% complete download is struck")
+	assert.Equal(t, []arrapi.QueueItem{barelyStarted}, job.FindAffected([]arrapi.QueueItem{nearlyDone, barelyStarted}))
+}
+
+func TestSlowClockSkew(t *testing.T) {
+	tests := []struct {
+		name        string
+		correct     bool
+		wantStrikes int
+	}{
+		{name: "grab time in the future is skipped, not struck"},
+		{name: "corrected by the instance's clock offset", correct: true, wantStrikes: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The instance's clock runs two hours ahead, so a download it
+			// grabbed an hour ago looks grabbed an hour in the future
+			ahead := 2 * time.Hour
+			arr := newFakeArr(t, []arrapi.QueueItem{{
+				ID:         1,
+				Title:      "Skewed.Item",
+				DownloadID: "skewed",
+				Status:     "downloading",
+				Size:       1000,
+				Sizeleft:   900,
+				Added:      time.Now().Add(ahead - time.Hour),
+			}})
+			arr.handle("/api/v3/system/status", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Date", time.Now().Add(ahead).UTC().Format(http.TimeFormat))
+				_ = json.NewEncoder(w).Encode(arrapi.SystemStatus{AppName: "Radarr"})
+			})
+
+			cfg := testConfig()
+			cfg.JobDefaults.MaxStrikes = 3
+			cfg.General.CorrectClockSkew = tt.correct
+			m := newTestManager(t, cfg, map[string]*fakeArr{"radarr": arr})
+			m.RegisterJob(NewSlowDownloadJob("remove_slow", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false))
+
+			require.NoError(t, m.RunAll(context.Background()))
+			assert.Equal(t, tt.wantStrikes, m.GetStrikesHandler().Get("skewed"))
+		})
+	}
+}
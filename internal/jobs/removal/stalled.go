@@ -2,26 +2,32 @@ package removal
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
 	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
 	"github.com/jmylchreest/go-decluttarr/internal/jobs"
 )
 
 // StalledJob removes stalled downloads from the queue
 type StalledJob struct {
-	name        string
-	enabled     bool
-	cfg         *config.JobConfig
-	defaults    *config.JobDefaultsConfig
-	manager     *jobs.Manager
-	logger      *slog.Logger
-	testRun     bool
-	maxStrikes  int
-	lastFound   int
-	lastRemoved int
+	name                string
+	enabled             bool
+	cfg                 *config.JobConfig
+	defaults            *config.JobDefaultsConfig
+	manager             *jobs.Manager
+	logger              *slog.Logger
+	testRun             bool
+	maxStrikes          int
+	blocklistRedownload bool
+	stalledGrace        time.Duration
+	deferLastActive     bool
+	minTimeLeft         time.Duration
+	lastFound           int
+	lastRemoved         int
 }
 
 // NewStalledJob creates a new stalled removal job
@@ -38,15 +44,29 @@ func NewStalledJob(
 		maxStrikes = *cfg.MaxStrikes
 	}
 
+	blocklistRedownload := false
+	if cfg.BlocklistRedownload != nil {
+		blocklistRedownload = *cfg.BlocklistRedownload
+	}
+
+	var stalledGrace time.Duration
+	if cfg.StalledGrace != nil {
+		stalledGrace = *cfg.StalledGrace
+	}
+
 	return &StalledJob{
-		name:       name,
-		enabled:    cfg.Enabled,
-		cfg:        cfg,
-		defaults:   defaults,
-		manager:    manager,
-		logger:     logger.With("job", "remove_stalled"),
-		testRun:    testRun,
-		maxStrikes: maxStrikes,
+		name:                name,
+		enabled:             cfg.Enabled,
+		cfg:                 cfg,
+		defaults:            defaults,
+		manager:             manager,
+		logger:              logger.With("job", "remove_stalled"),
+		testRun:             testRun,
+		maxStrikes:          maxStrikes,
+		blocklistRedownload: blocklistRedownload,
+		stalledGrace:        stalledGrace,
+		deferLastActive:     deferLastActive(cfg, defaults),
+		minTimeLeft:         minTimeLeft(cfg, defaults),
 	}
 }
 
@@ -60,11 +80,93 @@ func (j *StalledJob) Enabled() bool {
 	return j.enabled
 }
 
+// SetTestRun switches the job in or out of test-run mode
+func (j *StalledJob) SetTestRun(testRun bool) {
+	j.testRun = testRun
+}
+
+// Interval returns how often the job runs, or 0 to run every cycle
+func (j *StalledJob) Interval() time.Duration {
+	return jobInterval(j.cfg)
+}
+
+// Order returns the job's position in the cycle
+func (j *StalledJob) Order() int {
+	return jobOrder(j.cfg.Order)
+}
+
 // FindAffected identifies stalled items in the queue
 func (j *StalledJob) FindAffected(queue []arrapi.QueueItem) []arrapi.QueueItem {
+	return j.findStalled(queue, nil)
+}
+
+// filterQueue applies the job's scope and filters to an instance's queue,
+// leaving the items detection is run on
+func (j *StalledJob) filterQueue(instanceName string, queue []arrapi.QueueItem) []arrapi.QueueItem {
+	if !jobs.InInstances(j.cfg.Instances, instanceName) {
+		return nil
+	}
+	// The newest grabs are picked from the whole queue, before it's filtered
+	queue = withoutNewestGrabs(queue, protectNewest(j.cfg, j.defaults), j.logger)
+	queue = jobs.FilterProtocols(queue, j.cfg.Protocols)
+	queue = withoutFreshGrabs(queue, minGrabAge(j.cfg, j.defaults), j.logger)
+	queue = withoutProcessed(queue, j.manager, instanceName, j.logger)
+	return queue
+}
+
+// Detect returns the items of an instance's queue a run would strike, given
+// the download clients' torrents keyed by lowercase hash
+func (j *StalledJob) Detect(instanceName string, queue []arrapi.QueueItem, torrents map[string]downloadclient.Torrent) []arrapi.QueueItem {
+	queue = j.filterQueue(instanceName, queue)
+	return j.findStalled(queue, torrents)
+}
+
+// findStalled identifies stalled items in the queue. Items that resolve to a
+// torrent use the client's reported state; others (e.g. usenet) fall back to
+// the arr status heuristics.
+func (j *StalledJob) findStalled(queue []arrapi.QueueItem, torrents map[string]downloadclient.Torrent) []arrapi.QueueItem {
 	var affected []arrapi.QueueItem
 
 	for _, item := range queue {
+		// A download about to finish isn't worth replacing
+		if completesWithin(item, j.minTimeLeft) {
+			j.logger.Debug("skipping download estimated to complete soon",
+				"title", item.Title,
+				"download_id", item.DownloadID,
+				"estimated_completion", item.EstimatedCompletionTime,
+				"min_time_left", j.minTimeLeft,
+			)
+			continue
+		}
+
+		if torrent, ok := torrents[strings.ToLower(item.DownloadID)]; ok && item.DownloadID != "" {
+			if j.manager.InProtectedState(&torrent) {
+				j.logger.Debug("skipping torrent in a protected state",
+					"title", item.Title,
+					"download_id", item.DownloadID,
+					"state", torrent.ClientState,
+				)
+				continue
+			}
+
+			// Freshly added torrents may still be searching for peers
+			if j.inGracePeriod(torrent) {
+				j.logger.Debug("skipping recently added torrent",
+					"title", item.Title,
+					"download_id", item.DownloadID,
+					"added_on", torrent.AddedOn,
+					"stalled_grace", j.stalledGrace,
+				)
+				continue
+			}
+
+			// The client reports stalls directly, so there's nothing to estimate
+			if torrent.State == downloadclient.StateStalled || item.TrackedDownloadState == "importPending" {
+				affected = append(affected, item)
+			}
+			continue
+		}
+
 		if j.isStalledItem(item) {
 			affected = append(affected, item)
 		}
@@ -73,6 +175,14 @@ func (j *StalledJob) FindAffected(queue []arrapi.QueueItem) []arrapi.QueueItem {
 	return affected
 }
 
+// inGracePeriod reports whether a torrent was added within the stalled grace window
+func (j *StalledJob) inGracePeriod(torrent downloadclient.Torrent) bool {
+	if j.stalledGrace <= 0 || torrent.AddedOn.IsZero() {
+		return false
+	}
+	return time.Since(torrent.AddedOn) < j.stalledGrace
+}
+
 // isStalledItem determines if a queue item is stalled
 func (j *StalledJob) isStalledItem(item arrapi.QueueItem) bool {
 	// Check if TrackedDownloadState indicates stalled condition
@@ -106,17 +216,26 @@ func (j *StalledJob) isStalledItem(item arrapi.QueueItem) bool {
 func (j *StalledJob) Run(ctx context.Context) error {
 	j.logger.Debug("starting stalled removal job", "test_run", j.testRun, "max_strikes", j.maxStrikes)
 
-	queues, err := j.manager.GetAllQueues(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get queues: %w", err)
-	}
+	queues, queueErr := j.manager.GetAllQueues(ctx)
+	queues = jobs.ScopeQueues(queues, j.cfg.Instances)
+
+	// Torrent states reported by the download clients take precedence
+	torrents := j.manager.GetAllTorrents(ctx)
 
 	strikesHandler := j.manager.GetStrikesHandler()
+	struck := newRunStrikes(strikesHandler)
+	trace := newDecisionTracer(j.manager, j.logger, j.maxStrikes, j.testRun)
 	totalProcessed := 0
 	totalRemoved := 0
+	tags := newObsoleteTagger(j.manager, j.logger)
 
 	for instanceName, queue := range queues {
-		affected := j.FindAffected(queue)
+		if !dataReliable(j.manager, instanceName, j.logger) {
+			continue
+		}
+		queue = j.filterQueue(instanceName, queue)
+		affected := j.findStalled(queue, torrents)
+		trace.unmatched(instanceName, queue, affected)
 		j.logger.Debug("found stalled items",
 			"instance", instanceName,
 			"count", len(affected),
@@ -126,7 +245,8 @@ func (j *StalledJob) Run(ctx context.Context) error {
 			totalProcessed++
 
 			// Add strike for this download
-			currentStrikes := strikesHandler.Add(item.DownloadID, j.name, item.Title)
+			reason := jobs.QueueItemReason(item, "download stalled")
+			currentStrikes := struck.add(item.DownloadID, j.name, item.Title, reason)
 			j.logger.Debug("added strike to stalled download",
 				"title", item.Title,
 				"download_id", item.DownloadID,
@@ -135,8 +255,11 @@ func (j *StalledJob) Run(ctx context.Context) error {
 				"instance", instanceName,
 			)
 
+			maxStrikes := maxStrikesFor(ctx, j.manager, j.cfg, j.defaults, j.maxStrikes, item.DownloadID)
+			trace.struck(ctx, instanceName, item, reason, currentStrikes, maxStrikes)
+
 			// Check if max strikes exceeded
-			if strikesHandler.HasExceeded(item.DownloadID, j.maxStrikes) {
+			if j.manager.StrikesExceeded(item.DownloadID, maxStrikes) {
 				// Determine removal action based on tracker type and protected tags
 				action := j.manager.GetRemovalAction(ctx, item.DownloadID)
 
@@ -153,20 +276,13 @@ func (j *StalledJob) Run(ctx context.Context) error {
 							"instance", instanceName,
 						)
 					} else {
-						if err := j.manager.ApplyObsoleteTag(ctx, item.DownloadID); err != nil {
-							j.logger.Error("failed to tag as obsolete",
-								"title", item.Title,
-								"download_id", item.DownloadID,
-								"error", err,
-							)
-							continue
-						}
-						j.logger.Info("tagged stalled download as obsolete",
+						tags.add(ctx, item, "tagged stalled download as obsolete",
 							"title", item.Title,
 							"download_id", item.DownloadID,
 							"strikes", currentStrikes,
 							"instance", instanceName,
 						)
+						continue
 					}
 					strikesHandler.Reset(item.DownloadID)
 					totalRemoved++ // Count as handled
@@ -175,7 +291,16 @@ func (j *StalledJob) Run(ctx context.Context) error {
 					// Proceed with removal
 				}
 
-				if j.testRun {
+				if j.deferLastActive && j.manager.IsLastActiveInCategory(ctx, item.DownloadID) {
+					j.logger.Info("deferring removal of the last active download in its category",
+						"title", item.Title,
+						"download_id", item.DownloadID,
+						"instance", instanceName,
+					)
+					continue
+				}
+
+				if j.testRun || !j.manager.DeleteAllowed(instanceName) {
 					j.logger.Info("[TEST RUN] would remove stalled download",
 						"title", item.Title,
 						"download_id", item.DownloadID,
@@ -205,11 +330,15 @@ func (j *StalledJob) Run(ctx context.Context) error {
 						"strikes", currentStrikes,
 						"instance", instanceName,
 					)
+
+					j.manager.RecordIndexerFailure(ctx, instanceName, item.Indexer, disableIndexerAfter(j.cfg))
 				}
 			}
 		}
 	}
 
+	totalRemoved += tags.apply(ctx, strikesHandler)
+
 	j.logger.Debug("stalled removal job completed",
 		"processed", totalProcessed,
 		"removed", totalRemoved,
@@ -219,23 +348,24 @@ func (j *StalledJob) Run(ctx context.Context) error {
 	j.lastFound = totalProcessed
 	j.lastRemoved = totalRemoved
 
-	return nil
+	return jobs.QueueError(queueErr)
 }
 
 // removeItem removes a queue item from the arr instance
 func (j *StalledJob) removeItem(ctx context.Context, instanceName string, item arrapi.QueueItem) error {
-	client, ok := j.manager.GetArrClient(instanceName)
-	if !ok {
-		return fmt.Errorf("arr client not found: %s", instanceName)
-	}
-
 	opts := arrapi.DeleteOptions{
 		RemoveFromClient: true,
 		Blocklist:        false,
 		SkipRedownload:   true,
 	}
 
-	return client.DeleteQueueItem(ctx, item.ID, opts)
+	// Blocklist the dead release so the arr grabs an alternative
+	if j.blocklistRedownload {
+		opts.Blocklist = true
+		opts.SkipRedownload = false
+	}
+
+	return j.manager.RemoveQueueItem(ctx, instanceName, item, opts, reviewCategory(j.cfg, j.defaults), reasonTag(j.cfg, j.defaults, "stalled"), deleteFiles(j.cfg.DeleteFiles, true))
 }
 
 // Stats returns the statistics from the last job run
@@ -0,0 +1,748 @@
+package removal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+func TestStalledReviewCategory(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaults     string
+		override     *string
+		wantCategory string
+		wantDeleted  bool
+	}{
+		{
+			name:         "no review category deletes the torrent",
+			wantCategory: "tv-sonarr",
+			wantDeleted:  true,
+		},
+		{
+			name:         "default review category moves the torrent",
+			defaults:     "manual",
+			wantCategory: "manual",
+		},
+		{
+			name:         "job override takes precedence",
+			defaults:     "manual",
+			override:     stringPtr("review"),
+			wantCategory: "review",
+		},
+		{
+			name:         "job override can disable review",
+			defaults:     "manual",
+			override:     stringPtr(""),
+			wantCategory: "tv-sonarr",
+			wantDeleted:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := newFakeArr(t, []arrapi.QueueItem{
+				{ID: 1, Title: "Stalled.Item", DownloadID: "abc", Status: "stalled"},
+			})
+
+			cfg := testConfig()
+			cfg.JobDefaults.ReviewCategory = tt.defaults
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+			client := newFakeDownloadClient(downloadclient.Torrent{
+				Hash:     "abc",
+				Name:     "Stalled.Item",
+				State:    downloadclient.StateStalled,
+				Category: "tv-sonarr",
+			})
+			m.RegisterDownloadClient("qbittorrent", client)
+
+			jobCfg := &config.JobConfig{Enabled: true, ReviewCategory: tt.override}
+			job := NewStalledJob("remove_stalled", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+
+			require.NoError(t, job.Run(context.Background()))
+
+			params, ok := arr.deleted(1)
+			require.True(t, ok, "queue item should always be removed from the arr")
+			if tt.wantDeleted {
+				assert.Equal(t, "true", params["removeFromClient"])
+			} else {
+				assert.Empty(t, params["removeFromClient"], "torrent must be kept in the client")
+			}
+
+			assert.Equal(t, tt.wantCategory, client.category("abc"))
+		})
+	}
+}
+
+func TestStalledUsesClientState(t *testing.T) {
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		{
+			// Just added and reported as downloading by the arr, but stalled in qBittorrent
+			ID:         1,
+			Title:      "Client.Stalled",
+			DownloadID: "ABC",
+			Protocol:   "torrent",
+			Status:     "downloading",
+			Added:      time.Now(),
+		},
+		{
+			// Arr warns, but the client says it is downloading fine
+			ID:         2,
+			Title:      "Client.Downloading",
+			DownloadID: "DEF",
+			Protocol:   "torrent",
+			Status:     "warning",
+		},
+		{
+			// Usenet has no client state, so arr heuristics apply
+			ID:         3,
+			Title:      "Usenet.Stalled",
+			DownloadID: "sabnzbd_nzo_1",
+			Protocol:   "usenet",
+			Status:     "warning",
+		},
+	})
+
+	cfg := testConfig()
+	cfg.JobDefaults.MaxStrikes = 3
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+	m.RegisterDownloadClient("qbittorrent", newFakeDownloadClient(
+		downloadclient.Torrent{Hash: "abc", Name: "Client.Stalled", State: downloadclient.StateStalled},
+		downloadclient.Torrent{Hash: "def", Name: "Client.Downloading", State: downloadclient.StateDownloading},
+	))
+
+	job := NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+	require.NoError(t, job.Run(context.Background()))
+
+	strikes := m.GetStrikesHandler()
+	assert.Equal(t, 1, strikes.Get("ABC"), "client-reported stall should be struck immediately")
+	assert.Equal(t, 0, strikes.Get("DEF"), "client state overrides arr warning")
+	assert.Equal(t, 1, strikes.Get("sabnzbd_nzo_1"), "usenet falls back to arr heuristics")
+	assert.Zero(t, arr.deleteCount())
+}
+
+func TestStalledSkipsProtectedStates(t *testing.T) {
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		// Waiting on import while the client moves the files into place
+		{ID: 1, Title: "Moving", DownloadID: "MOVING", Protocol: "torrent", TrackedDownloadState: "importPending"},
+		{ID: 2, Title: "Stuck", DownloadID: "STUCK", Protocol: "torrent", TrackedDownloadState: "importPending"},
+	})
+
+	cfg := testConfig()
+	cfg.General.ProtectedStates = []string{"moving"}
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+	m.RegisterDownloadClient("qbittorrent", newFakeDownloadClient(
+		downloadclient.Torrent{Hash: "moving", State: downloadclient.StateQueued, ClientState: "moving"},
+		downloadclient.Torrent{Hash: "stuck", State: downloadclient.StateSeeding, ClientState: "stalledUP"},
+	))
+
+	job := NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), true)
+	require.NoError(t, job.Run(context.Background()))
+
+	assert.Equal(t, 0, m.GetStrikesHandler().Get("MOVING"), "torrents in a protected state shouldn't be struck")
+	assert.Equal(t, 1, m.GetStrikesHandler().Get("STUCK"))
+}
+
+func TestStalledScopedToInstances(t *testing.T) {
+	sonarr := newFakeArr(t, []arrapi.QueueItem{
+		{ID: 1, Title: "Sonarr.Stalled", DownloadID: "SONARR1", Status: "stalled"},
+	})
+	radarr := newFakeArr(t, []arrapi.QueueItem{
+		{ID: 2, Title: "Radarr.Stalled", DownloadID: "RADARR1", Status: "stalled"},
+	})
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": sonarr, "radarr": radarr})
+
+	jobCfg := &config.JobConfig{Enabled: true, Instances: []string{"sonarr"}}
+	job := NewStalledJob("remove_stalled", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+	require.NoError(t, job.Run(context.Background()))
+
+	_, removed := sonarr.deleted(1)
+	assert.True(t, removed, "download in the scoped instance should be removed")
+	assert.Zero(t, radarr.deleteCount(), "other instances should be untouched")
+	assert.Equal(t, 0, m.GetStrikesHandler().Get("RADARR1"), "other instances shouldn't be struck")
+}
+
+func TestStalledBlocklistRedownload(t *testing.T) {
+	tests := []struct {
+		name               string
+		option             *bool
+		wantBlocklist      string
+		wantSkipRedownload string
+	}{
+		{
+			name:               "default removes without blocklist",
+			wantBlocklist:      "",
+			wantSkipRedownload: "true",
+		},
+		{
+			name:               "blocklist and redownload",
+			option:             boolPtr(true),
+			wantBlocklist:      "true",
+			wantSkipRedownload: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := newFakeArr(t, []arrapi.QueueItem{
+				{ID: 1, Title: "Stalled.Item", DownloadID: "abc", Status: "stalled"},
+			})
+
+			cfg := testConfig()
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+			jobCfg := &config.JobConfig{Enabled: true, BlocklistRedownload: tt.option}
+			job := NewStalledJob("remove_stalled", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+
+			require.NoError(t, job.Run(context.Background()))
+
+			params, ok := arr.deleted(1)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantBlocklist, params["blocklist"])
+			assert.Equal(t, tt.wantSkipRedownload, params["skipRedownload"])
+		})
+	}
+}
+
+func TestStalledGrace(t *testing.T) {
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		{ID: 1, Title: "New.Item", DownloadID: "new"},
+		{ID: 2, Title: "Old.Item", DownloadID: "old"},
+	})
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+	m.RegisterDownloadClient("qbittorrent", newFakeDownloadClient(
+		downloadclient.Torrent{Hash: "new", Name: "New.Item", State: downloadclient.StateStalled, AddedOn: time.Now().Add(-10 * time.Second)},
+		downloadclient.Torrent{Hash: "old", Name: "Old.Item", State: downloadclient.StateStalled, AddedOn: time.Now().Add(-2 * time.Hour)},
+	))
+
+	grace := 15 * time.Minute
+	jobCfg := &config.JobConfig{Enabled: true, StalledGrace: &grace}
+	job := NewStalledJob("remove_stalled", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+
+	require.NoError(t, job.Run(context.Background()))
+
+	_, ok := arr.deleted(1)
+	assert.False(t, ok, "recently added torrent should be exempt from stalled strikes")
+	_, struck := m.GetStrikesHandler().GetRecord("new")
+	assert.False(t, struck, "recently added torrent should not be struck")
+
+	_, ok = arr.deleted(2)
+	assert.True(t, ok, "old stalled torrent should be struck and removed")
+}
+
+func TestStalledDisablesIndexer(t *testing.T) {
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		{ID: 1, Title: "Dead.Item.1", DownloadID: "dead1", Status: "stalled", Indexer: "Dead Indexer"},
+		{ID: 2, Title: "Dead.Item.2", DownloadID: "dead2", Status: "stalled", Indexer: "dead indexer"},
+		{ID: 3, Title: "Good.Item", DownloadID: "good", Status: "stalled", Indexer: "Good Indexer"},
+	})
+
+	arr.handle("/api/v3/indexer", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]arrapi.Indexer{
+			{ID: 1, Name: "Good Indexer", EnableRss: true},
+			{ID: 2, Name: "Dead Indexer", EnableRss: true},
+		})
+	})
+
+	var mu sync.Mutex
+	var disabled []map[string]any
+	arr.handle("/api/v3/indexer/2", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"id": 2, "name": "Dead Indexer", "enableRss": true, "enableAutomaticSearch": true}`))
+		case http.MethodPut:
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			mu.Lock()
+			disabled = append(disabled, body)
+			mu.Unlock()
+			w.WriteHeader(http.StatusAccepted)
+		}
+	})
+	arr.handle("/api/v3/indexer/1", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("indexer below the threshold should not be touched: %s %s", r.Method, r.URL.Path)
+	})
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+	threshold := 2
+	jobCfg := &config.JobConfig{Enabled: true, DisableIndexerAfter: &threshold}
+	job := NewStalledJob("remove_stalled", jobCfg, &cfg.JobDefaults, m, testLogger(), false)
+
+	require.NoError(t, job.Run(context.Background()))
+	assert.Equal(t, 3, arr.deleteCount())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, disabled, 1, "indexer should be disabled once the threshold is crossed")
+	assert.Equal(t, false, disabled[0]["enableRss"])
+	assert.Equal(t, false, disabled[0]["enableAutomaticSearch"])
+}
+
+func TestStalledMinTimeLeft(t *testing.T) {
+	soon := time.Now().Add(5 * time.Minute)
+	later := time.Now().Add(6 * time.Hour)
+	stale := time.Now().Add(-time.Hour)
+
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		{ID: 1, Title: "Nearly.Done", DownloadID: "soon", Status: "stalled", EstimatedCompletionTime: &soon},
+		{ID: 2, Title: "Far.Off", DownloadID: "later", Status: "stalled", EstimatedCompletionTime: &later},
+		{ID: 3, Title: "Stale.Estimate", DownloadID: "stale", Status: "stalled", EstimatedCompletionTime: &stale},
+	})
+
+	cfg := testConfig()
+	cfg.JobDefaults.MaxStrikes = 3
+	cfg.JobDefaults.MinTimeLeft = time.Hour
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+	job := NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+	require.NoError(t, job.Run(context.Background()))
+
+	strikes := m.GetStrikesHandler()
+	assert.Equal(t, 0, strikes.Get("soon"), "download completing within min_time_left is exempt")
+	assert.Equal(t, 1, strikes.Get("later"))
+	assert.Equal(t, 1, strikes.Get("stale"), "estimates in the past don't exempt the download")
+}
+
+func TestStalledProtectNewest(t *testing.T) {
+	now := time.Now()
+	series := func(id int) *int { return &id }
+	item := func(id int, downloadID string, seriesID int, age time.Duration) arrapi.QueueItem {
+		return arrapi.QueueItem{
+			ID:         id,
+			Title:      downloadID,
+			DownloadID: downloadID,
+			Status:     "stalled",
+			SeriesID:   series(seriesID),
+			Added:      now.Add(-age),
+		}
+	}
+
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		// Series 1: a season pack's two episodes share the newest grab
+		item(1, "pack", 1, time.Hour),
+		item(2, "pack", 1, time.Hour),
+		item(3, "middle", 1, 2*time.Hour),
+		item(4, "oldest", 1, 3*time.Hour),
+		// Series 2 has its own newest grab
+		item(5, "other-series", 2, 5*time.Hour),
+		// Not linked to the library, so never protected
+		{ID: 6, Title: "Unknown", DownloadID: "unlinked", Status: "stalled", Added: now},
+	})
+
+	cfg := testConfig()
+	cfg.JobDefaults.MaxStrikes = 3
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+	protect := 2
+	job := NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true, ProtectNewest: &protect}, &cfg.JobDefaults, m, testLogger(), false)
+	require.NoError(t, job.Run(context.Background()))
+
+	strikes := m.GetStrikesHandler()
+	for _, id := range []string{"pack", "middle", "other-series"} {
+		assert.Equal(t, 0, strikes.Get(id), "%s is one of its series' newest grabs", id)
+	}
+	assert.Equal(t, 1, strikes.Get("oldest"), "older grabs of the series are struck")
+	assert.Equal(t, 1, strikes.Get("unlinked"))
+	assert.Equal(t, 2, job.Stats().Found)
+}
+
+func TestStalledStrikesSharedDownloadOnce(t *testing.T) {
+	// The same download tracked by two instances
+	sonarr := newFakeArr(t, []arrapi.QueueItem{{ID: 1, Title: "Shared.Item", DownloadID: "shared", Status: "stalled"}})
+	radarr := newFakeArr(t, []arrapi.QueueItem{{ID: 9, Title: "Shared.Item", DownloadID: "shared", Status: "stalled"}})
+
+	cfg := testConfig()
+	cfg.JobDefaults.MaxStrikes = 2
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": sonarr, "radarr": radarr})
+
+	job := NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+
+	require.NoError(t, job.Run(context.Background()))
+	assert.Equal(t, 1, m.GetStrikesHandler().Get("shared"), "a download reported by two instances should get one strike per run")
+	assert.Zero(t, sonarr.deleteCount()+radarr.deleteCount())
+
+	require.NoError(t, job.Run(context.Background()))
+	assert.Equal(t, 1, sonarr.deleteCount()+radarr.deleteCount(), "removed once max strikes is reached")
+}
+
+func TestStalledDeferLastActive(t *testing.T) {
+	tests := []struct {
+		name        string
+		deferLast   bool
+		others      []downloadclient.Torrent
+		wantDeleted bool
+	}{
+		{
+			name:      "last active download is deferred",
+			deferLast: true,
+		},
+		{
+			name:        "option off removes the last active download",
+			wantDeleted: true,
+		},
+		{
+			name:      "another active download in the category allows removal",
+			deferLast: true,
+			others: []downloadclient.Torrent{
+				{Hash: "other", State: downloadclient.StateDownloading, Progress: 0.5, Category: "tv-sonarr"},
+			},
+			wantDeleted: true,
+		},
+		{
+			name:      "active downloads in other categories don't count",
+			deferLast: true,
+			others: []downloadclient.Torrent{
+				{Hash: "movie", State: downloadclient.StateDownloading, Progress: 0.5, Category: "movies"},
+				{Hash: "done", State: downloadclient.StateSeeding, Progress: 1, Category: "tv-sonarr"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := newFakeArr(t, []arrapi.QueueItem{
+				{ID: 1, Title: "Stalled.Item", DownloadID: "abc", Status: "stalled"},
+			})
+
+			cfg := testConfig()
+			cfg.JobDefaults.DeferLastActive = tt.deferLast
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+			torrents := append([]downloadclient.Torrent{{
+				Hash:     "abc",
+				Name:     "Stalled.Item",
+				State:    downloadclient.StateStalled,
+				Progress: 0.2,
+				Category: "tv-sonarr",
+			}}, tt.others...)
+			m.RegisterDownloadClient("qbittorrent", newFakeDownloadClient(torrents...))
+
+			job := NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+			require.NoError(t, job.Run(context.Background()))
+
+			_, deleted := arr.deleted(1)
+			assert.Equal(t, tt.wantDeleted, deleted)
+			if !tt.wantDeleted {
+				assert.Equal(t, 1, m.GetStrikesHandler().Get("abc"), "strikes are kept while removal is deferred")
+			}
+		})
+	}
+}
+
+func TestStalledQueueNotFoundIsError(t *testing.T) {
+	sonarr := newFakeArr(t, []arrapi.QueueItem{{ID: 1, Title: "Stalled.Item", DownloadID: "abc", Status: "stalled"}})
+	readarr := newFakeArr(t, nil)
+	readarr.handle("/api/v3/queue", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	cfg := testConfig()
+	cfg.JobDefaults.MaxStrikes = 3
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": sonarr, "readarr": readarr})
+
+	job := NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+
+	assert.Error(t, job.Run(context.Background()), "every *arr has a queue, so a 404 is a misconfiguration")
+	assert.Equal(t, 1, m.GetStrikesHandler().Get("abc"), "other instances are still processed")
+	assert.False(t, m.AllQueuesFetched(), "the failed instance's queue is still unknown")
+}
+
+func TestStalledMaxStrikesByTrackerType(t *testing.T) {
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		{ID: 1, Title: "Private.Item", DownloadID: "private", Status: "stalled"},
+		{ID: 2, Title: "Public.Item", DownloadID: "public", Status: "stalled"},
+		{ID: 3, Title: "Flagged.Item", DownloadID: "flagged", Status: "stalled"},
+		{ID: 4, Title: "Usenet.Item", DownloadID: "sabnzbd_nzo_1", Protocol: "usenet", Status: "stalled"},
+	})
+
+	cfg := testConfig()
+	cfg.JobDefaults.MaxStrikes = 3
+	cfg.JobDefaults.MaxStrikesPrivate = 4
+	cfg.JobDefaults.MaxStrikesPublic = 2
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+	client := newFakeDownloadClient(
+		downloadclient.Torrent{Hash: "private", Name: "Private.Item", State: downloadclient.StateStalled},
+		downloadclient.Torrent{Hash: "public", Name: "Public.Item", State: downloadclient.StateStalled},
+		// Flagged private by the client itself, e.g. by the qBittorrent tracker fallback
+		downloadclient.Torrent{Hash: "flagged", Name: "Flagged.Item", State: downloadclient.StateStalled, IsPrivate: true},
+	)
+	client.props["private"] = &downloadclient.TorrentProperties{IsPrivate: true}
+	m.RegisterDownloadClient("qbittorrent", client)
+
+	usenet := newFakeDownloadClient(downloadclient.Torrent{Hash: "sabnzbd_nzo_1", Name: "Usenet.Item", State: downloadclient.StateStalled})
+	usenet.seeds = false
+	m.RegisterDownloadClient("sabnzbd", usenet)
+
+	job := NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+
+	for run := 1; run <= 2; run++ {
+		require.NoError(t, job.Run(context.Background()))
+	}
+	_, publicRemoved := arr.deleted(2)
+	_, privateRemoved := arr.deleted(1)
+	assert.True(t, publicRemoved, "public torrents use max_strikes_public")
+	assert.False(t, privateRemoved, "private torrents need max_strikes_private")
+	_, flaggedRemoved := arr.deleted(3)
+	assert.False(t, flaggedRemoved, "torrents the client flags as private need max_strikes_private")
+	_, usenetRemoved := arr.deleted(4)
+	assert.False(t, usenetRemoved, "usenet downloads have no tracker type")
+
+	require.NoError(t, job.Run(context.Background()))
+	_, usenetRemoved = arr.deleted(4)
+	assert.True(t, usenetRemoved, "usenet downloads use max_strikes")
+
+	require.NoError(t, job.Run(context.Background()))
+	_, privateRemoved = arr.deleted(1)
+	assert.True(t, privateRemoved, "removed once max_strikes_private is reached")
+	_, flaggedRemoved = arr.deleted(3)
+	assert.True(t, flaggedRemoved, "removed once max_strikes_private is reached")
+}
+
+func TestStalledRecordsStruckDownloads(t *testing.T) {
+	arr := newFakeArr(t, []arrapi.QueueItem{{ID: 1, Title: "Stalled.Item", DownloadID: "abc", Status: "stalled"}})
+
+	cfg := testConfig()
+	cfg.JobDefaults.MaxStrikes = 3
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+	m.RegisterJob(NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false))
+
+	for cycle := 1; cycle <= 2; cycle++ {
+		require.NoError(t, m.RunAll(context.Background()))
+
+		struck := m.GetLastStats().Struck
+		require.Len(t, struck, 1)
+		assert.Equal(t, jobs.StruckDownload{
+			DownloadID: "abc",
+			Name:       "Stalled.Item",
+			Job:        "remove_stalled",
+			Strikes:    cycle,
+			MaxStrikes: 3,
+		}, struck[0])
+	}
+	assert.Equal(t, 1, m.GetLastStats().Struck[0].StrikesLeft(), "one strike from removal after the second cycle")
+	assert.Zero(t, arr.deleteCount())
+}
+
+func TestStalledRecordsRemovedDownloads(t *testing.T) {
+	arr := newFakeArr(t, []arrapi.QueueItem{{ID: 1, Title: "Stalled.Item", DownloadID: "abc", Status: "stalled"}})
+
+	cfg := testConfig()
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+	m.RegisterJob(NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false))
+
+	require.NoError(t, m.RunAll(context.Background()))
+
+	assert.Equal(t, []jobs.RemovedDownload{{
+		Job:        "remove_stalled",
+		Source:     "sonarr",
+		DownloadID: "abc",
+		Name:       "Stalled.Item",
+		Reason:     "download stalled",
+	}}, m.GetLastStats().Removed)
+}
+
+func TestStalledForceDeleteAfterFailedRemovals(t *testing.T) {
+	tests := []struct {
+		name             string
+		forceDeleteAfter int
+		wantDeletedOnRun int // 0 = never
+	}{
+		{name: "disabled by default"},
+		{name: "deleted from the client at the threshold", forceDeleteAfter: 2, wantDeletedOnRun: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arr := newFakeArr(t, []arrapi.QueueItem{
+				{ID: 1, Title: "Stalled.Item", DownloadID: "ABC", Protocol: "torrent", Status: "stalled"},
+			})
+			arr.handle("/api/v3/queue/1", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+
+			cfg := testConfig()
+			cfg.General.ForceDeleteAfter = tt.forceDeleteAfter
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+			// qBittorrent reports lowercase hashes, the arr uppercase download IDs
+			client := newFakeDownloadClient(downloadclient.Torrent{Hash: "abc", Name: "Stalled.Item", State: downloadclient.StateStalled})
+			m.RegisterDownloadClient("qbittorrent", client)
+
+			job := NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+			for run := 1; run <= 3; run++ {
+				_ = job.Run(context.Background())
+
+				deleteFiles, deleted := client.wasDeleted("abc")
+				wantDeleted := tt.wantDeletedOnRun != 0 && run >= tt.wantDeletedOnRun
+				require.Equal(t, wantDeleted, deleted, "run %d", run)
+				if deleted {
+					assert.True(t, deleteFiles, "force delete should remove the files too")
+					assert.Equal(t, 0, m.GetStrikesHandler().Get("ABC"), "strikes reset once deleted")
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestRunAllPrunesForceDeleteCountsOfRemovedDownloads(t *testing.T) {
+	item := arrapi.QueueItem{ID: 1, Title: "Stalled.Item", DownloadID: "ABC", Protocol: "torrent", Status: "stalled"}
+	torrent := downloadclient.Torrent{Hash: "abc", Name: "Stalled.Item", State: downloadclient.StateStalled}
+
+	arr := newFakeArr(t, []arrapi.QueueItem{item})
+	arr.handle("/api/v3/queue/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	cfg := testConfig()
+	cfg.General.ForceDeleteAfter = 2
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+	client := newFakeDownloadClient(torrent)
+	m.RegisterDownloadClient("qbittorrent", client)
+	m.RegisterJob(NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false))
+
+	// One failed removal, then the download disappears from everywhere
+	require.NoError(t, m.RunAll(context.Background()))
+	setQueue := func(queue []arrapi.QueueItem, torrents []downloadclient.Torrent) {
+		arr.mu.Lock()
+		arr.queue = queue
+		arr.mu.Unlock()
+		client.mu.Lock()
+		client.torrents = torrents
+		client.mu.Unlock()
+	}
+	setQueue(nil, nil)
+	require.NoError(t, m.RunAll(context.Background()))
+
+	// A reused download ID starts counting from zero again
+	setQueue([]arrapi.QueueItem{item}, []downloadclient.Torrent{torrent})
+	require.NoError(t, m.RunAll(context.Background()))
+	_, deleted := client.wasDeleted("abc")
+	assert.False(t, deleted, "the failure before the download vanished shouldn't count")
+
+	require.NoError(t, m.RunAll(context.Background()))
+	_, deleted = client.wasDeleted("abc")
+	assert.True(t, deleted)
+}
+
+func TestStalledBlocklistsRegrabbedRelease(t *testing.T) {
+	arr := newFakeArr(t, []arrapi.QueueItem{{ID: 1, Title: "Bad.Release", DownloadID: "ABC123", Status: "stalled"}})
+
+	cfg := testConfig()
+	cfg.General.BlocklistRegrabsAfter = 1
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+	job := NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+
+	require.NoError(t, job.Run(context.Background()))
+	params, ok := arr.deleted(1)
+	require.True(t, ok)
+	assert.NotEqual(t, "true", params["blocklist"], "the first removal follows the job's blocklist setting")
+
+	// The *arr grabs the same release again under a new queue entry
+	arr.mu.Lock()
+	arr.queue = []arrapi.QueueItem{{ID: 2, Title: "Bad.Release", DownloadID: "abc123", Status: "stalled"}}
+	arr.mu.Unlock()
+
+	require.NoError(t, job.Run(context.Background()))
+	params, ok = arr.deleted(2)
+	require.True(t, ok)
+	assert.Equal(t, "true", params["blocklist"], "a release grabbed again after its removal should be blocklisted")
+}
+
+func TestStalledSkipsProcessedEntries(t *testing.T) {
+	series := func(id int) *int { return &id }
+	arr := newFakeArr(t, []arrapi.QueueItem{{ID: 1, Title: "Show.S01E01", DownloadID: "AAA", Status: "stalled", SeriesID: series(42)}})
+
+	arr.handle("/api/v3/tag", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			_, _ = w.Write([]byte(`{"id": 5, "label": "decluttarr-processed"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	})
+	var tagged []any
+	arr.handle("/api/v3/series/42", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			var series map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&series))
+			tagged, _ = series["tags"].([]any)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 42, "title": "Show", "tags": []}`))
+	})
+
+	cfg := testConfig()
+	cfg.General.ProcessedTag = "decluttarr-processed"
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+	job := NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+
+	require.NoError(t, job.Run(context.Background()))
+	_, ok := arr.deleted(1)
+	require.True(t, ok)
+	assert.Equal(t, []any{float64(5)}, tagged, "the series should be tagged as processed")
+
+	// The *arr grabs another release for the series, and one for another series
+	arr.mu.Lock()
+	arr.queue = []arrapi.QueueItem{
+		{ID: 2, Title: "Show.S01E01.Other", DownloadID: "BBB", Status: "stalled", SeriesID: series(42)},
+		{ID: 3, Title: "Other.Show.S01E01", DownloadID: "CCC", Status: "stalled", SeriesID: series(7)},
+	}
+	arr.mu.Unlock()
+
+	require.NoError(t, job.Run(context.Background()))
+	_, ok = arr.deleted(2)
+	assert.False(t, ok, "downloads of a processed series should be skipped within the TTL")
+	_, ok = arr.deleted(3)
+	assert.True(t, ok, "downloads of other series should still be removed")
+}
+
+func TestStalledTagsObsoleteDownloadsInOneRequest(t *testing.T) {
+	arr := newFakeArr(t, []arrapi.QueueItem{
+		{ID: 1, Title: "First", DownloadID: "AAA", Status: "stalled"},
+		{ID: 2, Title: "Second", DownloadID: "BBB", Status: "stalled"},
+		{ID: 3, Title: "Already.Tagged", DownloadID: "CCC", Status: "stalled"},
+	})
+	client := newFakeDownloadClient(
+		downloadclient.Torrent{Hash: "aaa", Name: "First", State: downloadclient.StateStalled},
+		downloadclient.Torrent{Hash: "bbb", Name: "Second", State: downloadclient.StateStalled},
+		downloadclient.Torrent{Hash: "ccc", Name: "Already.Tagged", State: downloadclient.StateStalled, Tags: []string{"Obsolete"}},
+	)
+
+	cfg := testConfig()
+	cfg.General.PublicTrackerHandling = "obsolete_tag"
+	m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+	m.RegisterDownloadClient("qbittorrent", client)
+
+	job := NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+	require.NoError(t, job.Run(context.Background()))
+
+	assert.Equal(t, 1, client.tagCalls, "the client's downloads should be tagged in one request")
+	assert.Equal(t, []string{"Obsolete"}, client.tags["aaa"])
+	assert.Equal(t, []string{"Obsolete"}, client.tags["bbb"])
+	assert.Empty(t, client.tags["ccc"], "a download already tagged needs no request")
+	assert.Equal(t, 0, arr.deleteCount())
+	assert.Equal(t, 3, job.Stats().Removed, "tagged downloads count as handled")
+	for _, id := range []string{"AAA", "BBB", "CCC"} {
+		assert.Equal(t, 0, m.GetStrikesHandler().Get(id), "strikes reset once %s is tagged", id)
+	}
+}
@@ -0,0 +1,93 @@
+package removal
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+	"github.com/jmylchreest/go-decluttarr/internal/logging"
+)
+
+// Traced actions, in addition to the "skip", "tag" and "remove" removal actions
+const (
+	traceActionNone   = "none"   // detection didn't match, nothing done
+	traceActionStrike = "strike" // struck, below max strikes
+)
+
+// decisionTracer logs one structured debug entry per queue item explaining a
+// removal job's decision. It is nil, and every method a no-op, unless
+// general.trace_decisions is set.
+type decisionTracer struct {
+	manager    *jobs.Manager
+	logger     *slog.Logger
+	maxStrikes int
+	testRun    bool
+}
+
+// newDecisionTracer returns a tracer for a job run, or nil if tracing is off
+func newDecisionTracer(manager *jobs.Manager, logger *slog.Logger, maxStrikes int, testRun bool) *decisionTracer {
+	if !manager.GetConfig().General.TraceDecisions {
+		return nil
+	}
+	return &decisionTracer{
+		manager:    manager,
+		logger:     logger,
+		maxStrikes: maxStrikes,
+		testRun:    testRun,
+	}
+}
+
+// unmatched traces the queue items that aren't in affected
+func (t *decisionTracer) unmatched(instanceName string, queue, affected []arrapi.QueueItem) {
+	if t == nil {
+		return
+	}
+
+	matched := make(map[int]bool, len(affected))
+	for _, item := range affected {
+		matched[item.ID] = true
+	}
+	for _, item := range queue {
+		if !matched[item.ID] {
+			t.skipped(instanceName, item, "no detection matched")
+		}
+	}
+}
+
+// skipped traces a queue item the job's detection didn't match, with why
+func (t *decisionTracer) skipped(instanceName string, item arrapi.QueueItem, reason string) {
+	if t == nil {
+		return
+	}
+	t.log(instanceName, item, false, reason, t.manager.GetStrikesHandler().Get(item.DownloadID), t.maxStrikes, traceActionNone)
+}
+
+// struck traces a queue item the job matched and struck, resolving the
+// action the job takes for it against the item's own max strikes, which
+// may differ from the job's for private or public trackers
+func (t *decisionTracer) struck(ctx context.Context, instanceName string, item arrapi.QueueItem, reason string, strikes, maxStrikes int) {
+	if t == nil {
+		return
+	}
+
+	action := traceActionStrike
+	if strikes >= maxStrikes {
+		action = t.manager.GetRemovalAction(ctx, item.DownloadID)
+	}
+	t.log(instanceName, item, true, reason, strikes, maxStrikes, action)
+}
+
+func (t *decisionTracer) log(instanceName string, item arrapi.QueueItem, matched bool, reason string, strikes, maxStrikes int, action string) {
+	t.logger.Debug(logging.TraceMessage,
+		"instance", instanceName,
+		"download_id", item.DownloadID,
+		"title", item.Title,
+		"matched", matched,
+		"reason", reason,
+		"strikes", strikes,
+		"max_strikes", maxStrikes,
+		"action", action,
+		"test_run", t.testRun || !t.manager.DeleteAllowed(instanceName),
+	)
+}
@@ -0,0 +1,62 @@
+package removal
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+)
+
+func TestDecisionTrace(t *testing.T) {
+	newJob := func(t *testing.T, trace bool) (*StalledJob, *bytes.Buffer) {
+		arr := newFakeArr(t, []arrapi.QueueItem{
+			{ID: 1, Title: "Stalled.Item", DownloadID: "stalled", Status: "stalled"},
+			{ID: 2, Title: "Healthy.Item", DownloadID: "healthy", Status: "downloading"},
+		})
+
+		cfg := testConfig()
+		cfg.General.TraceDecisions = trace
+		cfg.JobDefaults.MaxStrikes = 2
+		m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+		var logs bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		return NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, logger, true), &logs
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		job, logs := newJob(t, false)
+		require.NoError(t, job.Run(context.Background()))
+		assert.NotContains(t, logs.String(), "decision trace")
+	})
+
+	t.Run("traces every item", func(t *testing.T) {
+		job, logs := newJob(t, true)
+
+		require.NoError(t, job.Run(context.Background()))
+		out := logs.String()
+		assert.Contains(t, out, `msg="decision trace" job=remove_stalled instance=sonarr download_id=stalled title=Stalled.Item matched=true reason="download stalled" strikes=1 max_strikes=2 action=strike test_run=true`)
+		assert.Contains(t, out, `msg="decision trace" job=remove_stalled instance=sonarr download_id=healthy title=Healthy.Item matched=false reason="no detection matched" strikes=0 max_strikes=2 action=none test_run=true`)
+
+		logs.Reset()
+		require.NoError(t, job.Run(context.Background()))
+		assert.Contains(t, logs.String(), `download_id=stalled title=Stalled.Item matched=true reason="download stalled" strikes=2 max_strikes=2 action=remove`)
+	})
+
+	t.Run("resolves the item's own max strikes", func(t *testing.T) {
+		job, logs := newJob(t, true)
+		job.defaults.MaxStrikesPublic = 1
+		job.manager.RegisterDownloadClient("qbittorrent", newFakeDownloadClient(downloadclient.Torrent{Hash: "stalled", State: downloadclient.StateStalled}))
+
+		require.NoError(t, job.Run(context.Background()))
+		assert.Contains(t, logs.String(), `download_id=stalled title=Stalled.Item matched=true reason="download stalled" strikes=1 max_strikes=1 action=remove`)
+	})
+}
@@ -2,8 +2,8 @@ package removal
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
 	"github.com/jmylchreest/go-decluttarr/internal/config"
@@ -60,22 +60,44 @@ func (j *UnmonitoredJob) Enabled() bool {
 	return j.enabled
 }
 
+// SetTestRun switches the job in or out of test-run mode
+func (j *UnmonitoredJob) SetTestRun(testRun bool) {
+	j.testRun = testRun
+}
+
+// Interval returns how often the job runs, or 0 to run every cycle
+func (j *UnmonitoredJob) Interval() time.Duration {
+	return jobInterval(j.cfg)
+}
+
+// Order returns the job's position in the cycle
+func (j *UnmonitoredJob) Order() int {
+	return jobOrder(j.cfg.Order)
+}
+
 // Run executes the unmonitored removal job
 func (j *UnmonitoredJob) Run(ctx context.Context) error {
 	j.logger.Debug("starting unmonitored removal job",
 		"test_run", j.testRun,
 		"max_strikes", j.maxStrikes)
 
-	queues, err := j.manager.GetAllQueues(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get queues: %w", err)
-	}
+	queues, queueErr := j.manager.GetAllQueues(ctx)
+	queues = jobs.ScopeQueues(queues, j.cfg.Instances)
 
 	strikesHandler := j.manager.GetStrikesHandler()
+	struck := newRunStrikes(strikesHandler)
+	trace := newDecisionTracer(j.manager, j.logger, j.maxStrikes, j.testRun)
 	totalProcessed := 0
 	totalRemoved := 0
+	tags := newObsoleteTagger(j.manager, j.logger)
 
 	for instanceName, queue := range queues {
+		if !dataReliable(j.manager, instanceName, j.logger) {
+			continue
+		}
+		queue = jobs.FilterProtocols(queue, j.cfg.Protocols)
+		queue = withoutFreshGrabs(queue, minGrabAge(j.cfg, j.defaults), j.logger)
+		queue = withoutProcessed(queue, j.manager, instanceName, j.logger)
 		client, ok := j.manager.GetArrClient(instanceName)
 		if !ok {
 			j.logger.Error("arr client not found", "instance", instanceName)
@@ -88,6 +110,9 @@ func (j *UnmonitoredJob) Run(ctx context.Context) error {
 			j.logger.Error("failed to get system status",
 				"instance", instanceName,
 				"error", err)
+			if arrapi.IsTransient(err) {
+				j.manager.MarkUnreliable(instanceName, err)
+			}
 			continue
 		}
 
@@ -95,19 +120,38 @@ func (j *UnmonitoredJob) Run(ctx context.Context) error {
 			"instance", instanceName,
 			"app", systemStatus.AppName)
 
+		// Check every item before striking any, so a transient failure part
+		// way through doesn't leave the instance half struck
+		unmonitored := make(map[int]bool) // queue ID -> unmonitored, for items checked
 		for _, item := range queue {
-			totalProcessed++
-
 			isUnmonitored, err := j.checkUnmonitored(ctx, client, systemStatus.AppName, &item)
 			if err != nil {
 				j.logger.Error("failed to check monitored status",
 					"instance", instanceName,
 					"queue_id", item.ID,
 					"error", err)
+				if arrapi.IsTransient(err) {
+					j.manager.MarkUnreliable(instanceName, err)
+					break
+				}
+				continue
+			}
+			unmonitored[item.ID] = isUnmonitored
+		}
+		if !dataReliable(j.manager, instanceName, j.logger) {
+			continue
+		}
+
+		for _, item := range queue {
+			totalProcessed++
+
+			isUnmonitored, checked := unmonitored[item.ID]
+			if !checked {
 				continue
 			}
 
 			if !isUnmonitored {
+				trace.skipped(instanceName, item, "content is monitored")
 				continue
 			}
 
@@ -119,14 +163,18 @@ func (j *UnmonitoredJob) Run(ctx context.Context) error {
 				"title", item.Title)
 
 			// Increment strikes
-			currentStrikes := strikesHandler.Add(item.DownloadID, j.name, item.Title)
+			reason := "content is unmonitored"
+			currentStrikes := struck.add(item.DownloadID, j.name, item.Title, reason)
 			j.logger.Debug("incremented strikes for unmonitored item",
 				"download_id", item.DownloadID,
 				"current_strikes", currentStrikes,
 				"max_strikes", j.maxStrikes)
 
+			maxStrikes := maxStrikesFor(ctx, j.manager, j.cfg, j.defaults, j.maxStrikes, item.DownloadID)
+			trace.struck(ctx, instanceName, item, reason, currentStrikes, maxStrikes)
+
 			// Check if strikes exceeded
-			if !strikesHandler.HasExceeded(item.DownloadID, j.maxStrikes) {
+			if !j.manager.StrikesExceeded(item.DownloadID, maxStrikes) {
 				j.logger.Debug("unmonitored item has not exceeded max strikes yet",
 					"download_id", item.DownloadID,
 					"current_strikes", currentStrikes,
@@ -158,21 +206,14 @@ func (j *UnmonitoredJob) Run(ctx context.Context) error {
 						"strikes", currentStrikes,
 					)
 				} else {
-					if err := j.manager.ApplyObsoleteTag(ctx, item.DownloadID); err != nil {
-						j.logger.Error("failed to tag as obsolete",
-							"title", item.Title,
-							"download_id", item.DownloadID,
-							"error", err,
-						)
-						continue
-					}
-					j.logger.Info("tagged unmonitored item as obsolete",
+					tags.add(ctx, item, "tagged unmonitored item as obsolete",
 						"instance", instanceName,
 						"queue_id", item.ID,
 						"download_id", item.DownloadID,
 						"title", item.Title,
 						"strikes", currentStrikes,
 					)
+					continue
 				}
 				strikesHandler.Reset(item.DownloadID)
 				totalRemoved++ // Count as handled
@@ -181,15 +222,15 @@ func (j *UnmonitoredJob) Run(ctx context.Context) error {
 				// Proceed with removal
 			}
 
-			// Remove from queue if not in test run mode
-			if !j.testRun {
+			// Remove from queue unless in test run mode or the instance hasn't opted in to deletion
+			if !j.testRun && j.manager.DeleteAllowed(instanceName) {
 				opts := arrapi.DeleteOptions{
 					RemoveFromClient: true,
 					Blocklist:        false,
 					SkipRedownload:   true,
 				}
 
-				if err := client.DeleteQueueItem(ctx, item.ID, opts); err != nil {
+				if err := j.manager.RemoveQueueItem(ctx, instanceName, item, opts, reviewCategory(j.cfg, j.defaults), reasonTag(j.cfg, j.defaults, "unmonitored"), deleteFiles(j.cfg.DeleteFiles, true)); err != nil {
 					j.logger.Error("failed to remove queue item",
 						"instance", instanceName,
 						"queue_id", item.ID,
@@ -218,6 +259,8 @@ func (j *UnmonitoredJob) Run(ctx context.Context) error {
 		}
 	}
 
+	totalRemoved += tags.apply(ctx, strikesHandler)
+
 	j.logger.Debug("unmonitored removal job completed",
 		"processed", totalProcessed,
 		"removed", totalRemoved,
@@ -226,7 +269,7 @@ func (j *UnmonitoredJob) Run(ctx context.Context) error {
 	j.lastFound = totalProcessed
 	j.lastRemoved = totalRemoved
 
-	return nil
+	return jobs.QueueError(queueErr)
 }
 
 // checkUnmonitored determines if a queue item belongs to an unmonitored parent entity
@@ -254,10 +297,19 @@ func (j *UnmonitoredJob) checkUnmonitored(ctx context.Context, client *arrapi.Cl
 	}
 
 	// If no entity ID is present, item cannot be checked
-	if entityID == nil {
+	if entityID == nil || *entityID <= 0 {
 		return false, nil
 	}
 
+	// A season can be unmonitored within a monitored series
+	if appName == "Sonarr" && item.SeasonNumber != nil {
+		series, err := (&arrapi.SonarrClient{Client: client}).GetSeries(ctx, *entityID)
+		if err != nil {
+			return false, err
+		}
+		return !seasonMonitored(series, *item.SeasonNumber), nil
+	}
+
 	// Get monitored status using the helper method
 	monitored, err := client.GetMonitoredStatus(ctx, entityType, *entityID)
 	if err != nil {
@@ -268,6 +320,20 @@ func (j *UnmonitoredJob) checkUnmonitored(ctx context.Context, client *arrapi.Cl
 	return !monitored, nil
 }
 
+// seasonMonitored reports whether a series and its season are both
+// monitored. A season missing from the series' list counts as monitored.
+func seasonMonitored(series *arrapi.Series, seasonNumber int) bool {
+	if !series.Monitored {
+		return false
+	}
+	for _, season := range series.Seasons {
+		if season.SeasonNumber == seasonNumber {
+			return season.Monitored
+		}
+	}
+	return true
+}
+
 // Stats returns the statistics from the last job run
 func (j *UnmonitoredJob) Stats() jobs.JobStats {
 	return jobs.JobStats{
@@ -0,0 +1,117 @@
+package removal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+)
+
+func TestUnmonitoredStatusFailureAddsNoStrikes(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		wantUnmonitored int
+		wantOther       int
+	}{
+		{
+			name:   "transient status failure adds no strikes that cycle",
+			status: http.StatusServiceUnavailable,
+		},
+		{
+			name:            "successful status checks strike as usual",
+			status:          http.StatusOK,
+			wantUnmonitored: 2, // unmonitored and stalled
+			wantOther:       1, // stalled
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			one, two := 1, 2
+			arr := newFakeArr(t, []arrapi.QueueItem{
+				{ID: 1, Title: "Unmonitored.Show", DownloadID: "unmonitored", Status: "stalled", SeriesID: &one},
+				{ID: 2, Title: "Other.Show", DownloadID: "other", Status: "stalled", SeriesID: &two},
+			})
+			arr.handle("/api/v3/system/status", func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(arrapi.SystemStatus{AppName: "Sonarr"})
+			})
+			arr.handle("/api/v3/series/1", func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(map[string]bool{"monitored": false})
+			})
+			arr.handle("/api/v3/series/2", func(w http.ResponseWriter, r *http.Request) {
+				if tt.status != http.StatusOK {
+					w.WriteHeader(tt.status)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(map[string]bool{"monitored": true})
+			})
+
+			cfg := testConfig()
+			cfg.JobDefaults.MaxStrikes = 3
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+			first, second := 1, 2
+			m.RegisterJob(NewUnmonitoredJob("remove_unmonitored", &config.JobConfig{Enabled: true, Order: &first}, &cfg.JobDefaults, m, testLogger(), false))
+			m.RegisterJob(NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true, Order: &second}, &cfg.JobDefaults, m, testLogger(), false))
+
+			_ = m.RunAll(context.Background())
+
+			strikes := m.GetStrikesHandler()
+			assert.Equal(t, tt.wantUnmonitored, strikes.Get("unmonitored"))
+			assert.Equal(t, tt.wantOther, strikes.Get("other"), "later jobs don't strike an instance with incomplete data")
+			assert.Zero(t, arr.deleteCount())
+			require.Equal(t, tt.status == http.StatusOK, m.DataReliable("sonarr"))
+		})
+	}
+}
+
+func TestUnmonitoredSeason(t *testing.T) {
+	tests := []struct {
+		name            string
+		seriesMonitored bool
+		seasonMonitored bool
+		wantUnmonitored bool
+	}{
+		{name: "monitored series and season", seriesMonitored: true, seasonMonitored: true},
+		{name: "unmonitored season of a monitored series", seriesMonitored: true, wantUnmonitored: true},
+		{name: "unmonitored series", seasonMonitored: true, wantUnmonitored: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seriesID, season := 1, 2
+			arr := newFakeArr(t, []arrapi.QueueItem{
+				{ID: 1, Title: "Show.S02E01", DownloadID: "season", SeriesID: &seriesID, SeasonNumber: &season},
+			})
+			arr.handle("/api/v3/system/status", func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(arrapi.SystemStatus{AppName: "Sonarr"})
+			})
+			arr.handle("/api/v3/series/1", func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(arrapi.Series{
+					ID:        1,
+					Monitored: tt.seriesMonitored,
+					Seasons: []arrapi.Season{
+						{SeasonNumber: 1, Monitored: true},
+						{SeasonNumber: 2, Monitored: tt.seasonMonitored},
+					},
+				})
+			})
+
+			cfg := testConfig()
+			m := newTestManager(t, cfg, map[string]*fakeArr{"sonarr": arr})
+
+			job := NewUnmonitoredJob("remove_unmonitored", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), false)
+			require.NoError(t, job.Run(context.Background()))
+
+			_, removed := arr.deleted(1)
+			assert.Equal(t, tt.wantUnmonitored, removed)
+		})
+	}
+}
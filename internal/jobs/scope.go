@@ -0,0 +1,118 @@
+package jobs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/match"
+)
+
+// InInstances reports whether a job scoped to instances applies to the named
+// *arr instance or download client. An empty list applies to every one.
+func InInstances(instances []string, name string) bool {
+	if len(instances) == 0 {
+		return true
+	}
+	for _, instance := range instances {
+		if strings.EqualFold(instance, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeQueues returns the queues of the *arr instances a job is scoped to
+func ScopeQueues(queues map[string][]arrapi.QueueItem, instances []string) map[string][]arrapi.QueueItem {
+	if len(instances) == 0 {
+		return queues
+	}
+
+	scoped := make(map[string][]arrapi.QueueItem, len(queues))
+	for name, queue := range queues {
+		if InInstances(instances, name) {
+			scoped[name] = queue
+		}
+	}
+	return scoped
+}
+
+// FilterProtocols returns the queue items whose protocol (torrent/usenet) is
+// in protocols. An empty list keeps every item.
+func FilterProtocols(queue []arrapi.QueueItem, protocols []string) []arrapi.QueueItem {
+	if len(protocols) == 0 {
+		return queue
+	}
+
+	var filtered []arrapi.QueueItem
+	for _, item := range queue {
+		for _, protocol := range protocols {
+			if strings.EqualFold(item.Protocol, protocol) {
+				filtered = append(filtered, item)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// QueueError wraps a GetAllQueues error so a job can report instances it
+// couldn't fetch after processing the ones it could
+func QueueError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("failed to get queues: %w", err)
+}
+
+// QueueItemReason describes why a queue item was acted on, preferring the
+// *arr's own error or status message over the generic fallback
+func QueueItemReason(item arrapi.QueueItem, fallback string) string {
+	if item.ErrorMessage != "" {
+		return item.ErrorMessage
+	}
+	for _, msg := range item.StatusMessages {
+		if len(msg.Messages) > 0 && msg.Messages[0] != "" {
+			return msg.Messages[0]
+		}
+	}
+	return fallback
+}
+
+// MediaEntity returns the library entry a queue item was grabbed for, as its
+// API resource type (series, movie, artist or author) and ID. ok is false if
+// the item isn't linked to one.
+func MediaEntity(item arrapi.QueueItem) (kind string, id int, ok bool) {
+	switch {
+	case item.SeriesID != nil && *item.SeriesID > 0:
+		return "series", *item.SeriesID, true
+	case item.MovieID != nil && *item.MovieID > 0:
+		return "movie", *item.MovieID, true
+	case item.ArtistID != nil && *item.ArtistID > 0:
+		return "artist", *item.ArtistID, true
+	case item.AuthorID != nil && *item.AuthorID > 0:
+		return "author", *item.AuthorID, true
+	}
+	return "", 0, false
+}
+
+// MatchesMessages reports whether any of the item's status or error messages
+// match one of patterns. No patterns means no match.
+func MatchesMessages(item arrapi.QueueItem, patterns []string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+
+	for _, statusMsg := range item.StatusMessages {
+		if match.Any(statusMsg.Title, patterns) {
+			return true
+		}
+		for _, msg := range statusMsg.Messages {
+			if match.Any(msg, patterns) {
+				return true
+			}
+		}
+	}
+
+	return item.ErrorMessage != "" && match.Any(item.ErrorMessage, patterns)
+}
@@ -2,16 +2,23 @@ package search
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
 	"github.com/jmylchreest/go-decluttarr/internal/config"
 	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+	"github.com/jmylchreest/go-decluttarr/internal/searches"
 )
 
+// DefaultEpisodesPerSearch is the most episodes searched by one command when
+// search_missing.episodes_per_search isn't set
+const DefaultEpisodesPerSearch = 50
+
 // MissingJob searches for missing episodes/movies
 type MissingJob struct {
 	name                   string
@@ -22,6 +29,7 @@ type MissingJob struct {
 	testRun                bool
 	minDaysBetweenSearches int
 	maxConcurrentSearches  int
+	episodesPerSearch      int
 	lastFound              int
 	lastSearched           int
 	mu                     sync.RWMutex
@@ -35,6 +43,11 @@ func NewMissingJob(
 	logger *slog.Logger,
 	testRun bool,
 ) *MissingJob {
+	episodesPerSearch := cfg.EpisodesPerSearch
+	if episodesPerSearch <= 0 {
+		episodesPerSearch = DefaultEpisodesPerSearch
+	}
+
 	return &MissingJob{
 		name:                   name,
 		enabled:                cfg.Enabled,
@@ -44,6 +57,7 @@ func NewMissingJob(
 		testRun:                testRun,
 		minDaysBetweenSearches: cfg.MinDaysBetweenSearches,
 		maxConcurrentSearches:  cfg.MaxConcurrentSearches,
+		episodesPerSearch:      episodesPerSearch,
 	}
 }
 
@@ -57,6 +71,24 @@ func (j *MissingJob) Enabled() bool {
 	return j.enabled
 }
 
+// SetTestRun switches the job in or out of test-run mode
+func (j *MissingJob) SetTestRun(testRun bool) {
+	j.testRun = testRun
+}
+
+// Interval returns how often the job runs, or 0 to run every cycle
+func (j *MissingJob) Interval() time.Duration {
+	return j.cfg.Interval
+}
+
+// Order returns the job's position in the cycle, after removal jobs by default
+func (j *MissingJob) Order() int {
+	if j.cfg.Order != nil {
+		return *j.cfg.Order
+	}
+	return jobs.SearchJobOrder
+}
+
 // Stats returns job statistics
 func (j *MissingJob) Stats() jobs.JobStats {
 	j.mu.RLock()
@@ -73,6 +105,7 @@ func (j *MissingJob) Run(ctx context.Context) error {
 		"test_run", j.testRun,
 		"min_days_between_searches", j.minDaysBetweenSearches,
 		"max_concurrent_searches", j.maxConcurrentSearches,
+		"episodes_per_search", j.episodesPerSearch,
 	)
 
 	found := 0
@@ -144,90 +177,200 @@ func (j *MissingJob) Run(ctx context.Context) error {
 		"errors", len(errs))
 
 	if len(errs) > 0 {
-		return fmt.Errorf("encountered %d errors during search: %v", len(errs), errs)
+		return fmt.Errorf("encountered %d errors during search: %w", len(errs), errors.Join(errs...))
 	}
 
 	return nil
 }
 
+// acquireSearchSlot waits for a free slot in the search semaphore, giving up
+// if the context is cancelled first
+func acquireSearchSlot(ctx context.Context, searchSem chan struct{}) error {
+	select {
+	case searchSem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // searchMissingSonarr searches for missing episodes in a Sonarr instance
 func (j *MissingJob) searchMissingSonarr(ctx context.Context, instanceName string, client *arrapi.SonarrClient, searchSem chan struct{}) (found int, searched int, err error) {
 	logger := j.logger.With("instance", instanceName, "type", "sonarr")
 	logger.Debug("searching for missing episodes")
 
+	if j.queueFull(ctx, logger, client.Client) {
+		return 0, 0, nil
+	}
+
 	// Get all series
 	allSeries, err := client.GetAllSeries(ctx)
 	if err != nil {
+		if jobs.Unsupported(logger, instanceName, "series", err) {
+			return 0, 0, nil
+		}
 		return 0, 0, fmt.Errorf("failed to get series: %w", err)
 	}
 
 	logger.Debug("retrieved series", "count", len(allSeries))
 
+	// Series are searched in parallel, as many at a time as searches may run.
+	// The counts and the first error are shared with the goroutines under mu.
+	seriesSem := make(chan struct{}, max(j.maxConcurrentSearches, 1))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
 	for _, series := range allSeries {
 		if !series.Monitored {
 			continue
 		}
 
-		// Get episodes for this series
-		episodes, err := client.GetEpisodes(ctx, series.ID)
-		if err != nil {
-			logger.Error("failed to get episodes", "series", series.Title, "error", err)
-			continue
+		if slotErr := acquireSearchSlot(ctx, seriesSem); slotErr != nil {
+			mu.Lock()
+			if err == nil {
+				err = slotErr
+			}
+			mu.Unlock()
+			break
 		}
-
-		// Find missing episodes (monitored, no file, aired)
-		var missingEpisodes []arrapi.Episode
-		for _, ep := range episodes {
-			if ep.Monitored && !ep.HasFile && !ep.AirDateUTC.IsZero() && ep.AirDateUTC.Before(time.Now()) {
-				missingEpisodes = append(missingEpisodes, ep)
+		wg.Add(1)
+		go func(series arrapi.Series) {
+			defer wg.Done()
+			defer func() { <-seriesSem }()
+
+			f, s, seriesErr := j.searchMissingSeries(ctx, logger, instanceName, client, series, searchSem)
+			mu.Lock()
+			found += f
+			searched += s
+			if seriesErr != nil && err == nil {
+				err = seriesErr
 			}
+			mu.Unlock()
+		}(series)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	return found, searched, err
+}
+
+// searchMissingSeries searches for a series' missing episodes, in commands of
+// at most search_missing.episodes_per_search episodes each
+func (j *MissingJob) searchMissingSeries(ctx context.Context, logger *slog.Logger, instanceName string, client *arrapi.SonarrClient, series arrapi.Series, searchSem chan struct{}) (found int, searched int, err error) {
+	// Get episodes for this series
+	episodes, err := client.GetEpisodes(ctx, series.ID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, 0, ctx.Err()
 		}
+		logger.Error("failed to get episodes", "series", series.Title, "error", err)
+		return 0, 0, nil
+	}
 
-		// Filter out recently searched episodes
-		eligibleEpisodes := j.filterRecentlySearchedEpisodes(missingEpisodes)
+	// Find missing episodes (monitored, no file, aired)
+	var missingEpisodes []arrapi.Episode
+	now := time.Now()
+	for _, ep := range episodes {
+		if ep.Monitored && !ep.HasFile && j.hasAired(ep, now) {
+			missingEpisodes = append(missingEpisodes, ep)
+		}
+	}
 
-		// Extract episode IDs
-		var missingEpisodeIDs []int
-		for _, ep := range eligibleEpisodes {
-			missingEpisodeIDs = append(missingEpisodeIDs, ep.ID)
+	// Filter out recently searched episodes
+	eligibleEpisodes := j.filterRecentlySearchedEpisodes(instanceName, missingEpisodes)
+
+	// Extract episode IDs
+	var missingEpisodeIDs []int
+	for _, ep := range eligibleEpisodes {
+		missingEpisodeIDs = append(missingEpisodeIDs, ep.ID)
+	}
+
+	if len(missingEpisodeIDs) == 0 {
+		return 0, 0, nil
+	}
+
+	found = len(missingEpisodeIDs)
+	logger.Debug("found missing episodes",
+		"series", series.Title,
+		"count", found)
+
+	if j.testRun {
+		logger.Debug("test run: would trigger search",
+			"series", series.Title,
+			"episode_count", found)
+		return found, 0, nil
+	}
+
+	for batch := range slices.Chunk(missingEpisodeIDs, j.episodesPerSearch) {
+		// Acquire semaphore slot
+		if err := acquireSearchSlot(ctx, searchSem); err != nil {
+			return found, searched, err
 		}
+		err := client.SearchEpisodes(ctx, batch)
+		<-searchSem // Release slot
 
-		if len(missingEpisodeIDs) > 0 {
-			found += len(missingEpisodeIDs)
-			logger.Debug("found missing episodes",
+		if err != nil {
+			logger.Error("failed to trigger search",
 				"series", series.Title,
-				"count", len(missingEpisodeIDs))
+				"episode_count", len(batch),
+				"error", err)
+			continue
+		}
+		searched += len(batch)
+		recordSearched(j.manager, instanceName, "episode", batch...)
+		logger.Debug("triggered search",
+			"series", series.Title,
+			"episode_count", len(batch))
+	}
+
+	return found, searched, nil
+}
 
-			if !j.testRun {
-				// Acquire semaphore slot
-				searchSem <- struct{}{}
-				err := client.SearchEpisodes(ctx, missingEpisodeIDs)
-				<-searchSem // Release slot
+// queueFull reports whether an instance already has job_defaults.max_active_downloads
+// or more downloads in progress, in which case new searches would only add to the backlog
+func (j *MissingJob) queueFull(ctx context.Context, logger *slog.Logger, client *arrapi.Client) bool {
+	maxActive := j.manager.GetConfig().JobDefaults.MaxActiveDownloads
+	if maxActive <= 0 {
+		return false
+	}
 
-				if err != nil {
-					logger.Error("failed to trigger search",
-						"series", series.Title,
-						"episode_count", len(missingEpisodeIDs),
-						"error", err)
-				} else {
-					searched += len(missingEpisodeIDs)
-					logger.Debug("triggered search",
-						"series", series.Title,
-						"episode_count", len(missingEpisodeIDs))
-				}
-			} else {
-				logger.Debug("test run: would trigger search",
-					"series", series.Title,
-					"episode_count", len(missingEpisodeIDs))
-			}
+	queue, err := client.GetQueue(ctx)
+	if err != nil {
+		// Don't hold back searches just because the queue couldn't be checked
+		logger.Warn("failed to check queue size before searching", "error", err)
+		return false
+	}
+
+	active := 0
+	for _, item := range queue {
+		if item.Sizeleft > 0 {
+			active++
 		}
 	}
 
-	return found, searched, nil
+	if active >= maxActive {
+		logger.Info("skipping searches, download queue is full",
+			"active_downloads", active,
+			"max_active_downloads", maxActive)
+		return true
+	}
+
+	return false
 }
 
-// filterRecentlySearchedMovies filters out movies that have been searched within minDays
-func (j *MissingJob) filterRecentlySearchedMovies(movies []arrapi.Movie) []arrapi.Movie {
+// recordSearched notes that items were just searched, so they aren't searched
+// again before the *arr updates their last search time
+func recordSearched(manager *jobs.Manager, instanceName, kind string, ids ...int) {
+	history := manager.GetSearchHistory()
+	now := time.Now()
+	for _, id := range ids {
+		history.Record(searches.Key(instanceName, kind, id), now)
+	}
+}
+
+// filterRecentlySearchedMovies filters out movies that have been searched within
+// minDays, by the *arr's account or as recorded by this job
+func (j *MissingJob) filterRecentlySearchedMovies(instanceName string, movies []arrapi.Movie) []arrapi.Movie {
 	if j.minDaysBetweenSearches <= 0 {
 		return movies
 	}
@@ -236,22 +379,24 @@ func (j *MissingJob) filterRecentlySearchedMovies(movies []arrapi.Movie) []arrap
 	threshold := now.AddDate(0, 0, -j.minDaysBetweenSearches)
 
 	var eligible []arrapi.Movie
+	history := j.manager.GetSearchHistory()
 	for _, movie := range movies {
-		if movie.LastSearchTime == nil {
+		lastSearch := history.Latest(searches.Key(instanceName, "movie", movie.ID), movie.LastSearchTime)
+		if lastSearch == nil {
 			// Never searched, eligible
 			eligible = append(eligible, movie)
 			continue
 		}
 
-		if movie.LastSearchTime.Before(threshold) {
+		if lastSearch.Before(threshold) {
 			// Last search was more than minDays ago
 			eligible = append(eligible, movie)
 		} else {
-			daysAgo := int(now.Sub(*movie.LastSearchTime).Hours() / 24)
+			daysAgo := int(now.Sub(*lastSearch).Hours() / 24)
 			j.logger.Debug("skipping recently searched movie",
 				"movie_id", movie.ID,
 				"title", movie.Title,
-				"last_search", movie.LastSearchTime.Format(time.RFC3339),
+				"last_search", lastSearch.Format(time.RFC3339),
 				"days_ago", daysAgo,
 				"min_days", j.minDaysBetweenSearches,
 			)
@@ -260,8 +405,9 @@ func (j *MissingJob) filterRecentlySearchedMovies(movies []arrapi.Movie) []arrap
 	return eligible
 }
 
-// filterRecentlySearchedEpisodes filters out episodes that have been searched within minDays
-func (j *MissingJob) filterRecentlySearchedEpisodes(episodes []arrapi.Episode) []arrapi.Episode {
+// filterRecentlySearchedEpisodes filters out episodes that have been searched within
+// minDays, by the *arr's account or as recorded by this job
+func (j *MissingJob) filterRecentlySearchedEpisodes(instanceName string, episodes []arrapi.Episode) []arrapi.Episode {
 	if j.minDaysBetweenSearches <= 0 {
 		return episodes
 	}
@@ -270,22 +416,24 @@ func (j *MissingJob) filterRecentlySearchedEpisodes(episodes []arrapi.Episode) [
 	threshold := now.AddDate(0, 0, -j.minDaysBetweenSearches)
 
 	var eligible []arrapi.Episode
+	history := j.manager.GetSearchHistory()
 	for _, ep := range episodes {
-		if ep.LastSearchTime == nil {
+		lastSearch := history.Latest(searches.Key(instanceName, "episode", ep.ID), ep.LastSearchTime)
+		if lastSearch == nil {
 			// Never searched, eligible
 			eligible = append(eligible, ep)
 			continue
 		}
 
-		if ep.LastSearchTime.Before(threshold) {
+		if lastSearch.Before(threshold) {
 			// Last search was more than minDays ago
 			eligible = append(eligible, ep)
 		} else {
-			daysAgo := int(now.Sub(*ep.LastSearchTime).Hours() / 24)
+			daysAgo := int(now.Sub(*lastSearch).Hours() / 24)
 			j.logger.Debug("skipping recently searched episode",
 				"episode_id", ep.ID,
 				"title", ep.Title,
-				"last_search", ep.LastSearchTime.Format(time.RFC3339),
+				"last_search", lastSearch.Format(time.RFC3339),
 				"days_ago", daysAgo,
 				"min_days", j.minDaysBetweenSearches,
 			)
@@ -299,9 +447,16 @@ func (j *MissingJob) searchMissingRadarr(ctx context.Context, instanceName strin
 	logger := j.logger.With("instance", instanceName, "type", "radarr")
 	logger.Debug("searching for missing movies")
 
+	if j.queueFull(ctx, logger, client.Client) {
+		return 0, 0, nil
+	}
+
 	// Get all movies
 	allMovies, err := client.GetAllMovies(ctx)
 	if err != nil {
+		if jobs.Unsupported(logger, instanceName, "movie", err) {
+			return 0, 0, nil
+		}
 		return 0, 0, fmt.Errorf("failed to get movies: %w", err)
 	}
 
@@ -323,15 +478,21 @@ func (j *MissingJob) searchMissingRadarr(ctx context.Context, instanceName strin
 	}
 
 	// Filter out recently searched movies
-	eligibleMovies := j.filterRecentlySearchedMovies(missingMovies)
+	eligibleMovies := j.filterRecentlySearchedMovies(instanceName, missingMovies)
 
 	for _, movie := range eligibleMovies {
+		if err := ctx.Err(); err != nil {
+			return found, searched, err
+		}
+
 		found++
 		logger.Debug("found missing movie", "title", movie.Title, "year", movie.Year)
 
 		if !j.testRun {
 			// Acquire semaphore slot
-			searchSem <- struct{}{}
+			if err := acquireSearchSlot(ctx, searchSem); err != nil {
+				return found, searched, err
+			}
 			err := client.SearchMovie(ctx, movie.ID)
 			<-searchSem // Release slot
 
@@ -342,6 +503,7 @@ func (j *MissingJob) searchMissingRadarr(ctx context.Context, instanceName strin
 					"error", err)
 			} else {
 				searched++
+				recordSearched(j.manager, instanceName, "movie", movie.ID)
 				logger.Debug("triggered search", "movie", movie.Title, "year", movie.Year)
 			}
 		} else {
@@ -352,13 +514,23 @@ func (j *MissingJob) searchMissingRadarr(ctx context.Context, instanceName strin
 	return found, searched, nil
 }
 
+// hasAired reports whether an episode has aired. Anime often has no air date
+// in Sonarr; with include_anime such episodes count as aired once they have
+// an absolute episode number.
+func (j *MissingJob) hasAired(ep arrapi.Episode, now time.Time) bool {
+	if ep.AirDateUTC.IsZero() {
+		return j.cfg.IncludeAnime && ep.AbsoluteEpisodeNumber != nil && *ep.AbsoluteEpisodeNumber > 0
+	}
+	return ep.AirDateUTC.Before(now)
+}
+
 // getSonarrClients retrieves all Sonarr clients from the manager
 func (j *MissingJob) getSonarrClients() (map[string]*arrapi.SonarrClient, error) {
 	clients := make(map[string]*arrapi.SonarrClient)
 
 	// Access config to get Sonarr instance names
 	for _, inst := range j.manager.GetConfig().Instances.Sonarr {
-		if !inst.Enabled {
+		if !inst.Enabled || !jobs.InInstances(j.cfg.Instances, inst.Name) {
 			continue
 		}
 
@@ -379,7 +551,7 @@ func (j *MissingJob) getRadarrClients() (map[string]*arrapi.RadarrClient, error)
 
 	// Access config to get Radarr instance names
 	for _, inst := range j.manager.GetConfig().Instances.Radarr {
-		if !inst.Enabled {
+		if !inst.Enabled || !jobs.InInstances(j.cfg.Instances, inst.Name) {
 			continue
 		}
 
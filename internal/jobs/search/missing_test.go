@@ -0,0 +1,501 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+func TestMissingSkipsSearchWhenQueueFull(t *testing.T) {
+	tests := []struct {
+		name         string
+		active       int
+		wantSearches int32
+	}{
+		{name: "queue at max", active: 2, wantSearches: 0},
+		{name: "queue below max", active: 1, wantSearches: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var searches atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v3/queue":
+					queue := arrapi.QueueResponse{Records: []arrapi.QueueItem{
+						// Finished downloads waiting on import don't count
+						{ID: 100, Title: "Imported.Movie", Sizeleft: 0},
+					}}
+					for i := 0; i < tt.active; i++ {
+						queue.Records = append(queue.Records, arrapi.QueueItem{ID: i + 1, Sizeleft: 1024})
+					}
+					queue.TotalRecords = len(queue.Records)
+					_ = json.NewEncoder(w).Encode(queue)
+				case "/api/v3/movie":
+					_ = json.NewEncoder(w).Encode([]arrapi.Movie{
+						{ID: 1, Title: "Missing Movie", Monitored: true, IsAvailable: true},
+					})
+				case "/api/v3/command":
+					searches.Add(1)
+					w.WriteHeader(http.StatusCreated)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			cfg := &config.Config{
+				Instances: config.InstancesConfig{
+					Radarr: []config.InstanceConfig{{Name: "radarr", URL: server.URL, APIKey: "test", Enabled: true}},
+				},
+				JobDefaults: config.JobDefaultsConfig{MaxActiveDownloads: 2},
+			}
+
+			m := jobs.NewManager(cfg, logger, "")
+			defer m.Close()
+			m.RegisterArrClient("radarr", arrapi.NewClient(arrapi.ClientConfig{
+				Name:    "radarr",
+				BaseURL: server.URL,
+				APIKey:  "test",
+				Logger:  logger,
+			}))
+
+			jobCfg := &config.SearchJobConfig{Enabled: true, MaxConcurrentSearches: 1}
+			job := NewMissingJob("search_missing", jobCfg, m, logger, false)
+
+			require.NoError(t, job.Run(context.Background()))
+			assert.Equal(t, tt.wantSearches, searches.Load())
+		})
+	}
+}
+
+func TestMissingCancelledMidSearch(t *testing.T) {
+	searchStarted := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/movie":
+			var movies []arrapi.Movie
+			for i := 1; i <= 20; i++ {
+				movies = append(movies, arrapi.Movie{ID: i, Title: "Missing Movie", Monitored: true, IsAvailable: true})
+			}
+			_ = json.NewEncoder(w).Encode(movies)
+		case "/api/v3/command":
+			// Drain the body so the server notices when the client hangs up
+			_, _ = io.Copy(io.Discard, r.Body)
+			select {
+			case searchStarted <- struct{}{}:
+			default:
+			}
+			// Simulate a slow *arr that only gives up when the client does
+			<-r.Context().Done()
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		Instances: config.InstancesConfig{
+			Radarr: []config.InstanceConfig{{Name: "radarr", URL: server.URL, APIKey: "test", Enabled: true}},
+		},
+	}
+
+	m := jobs.NewManager(cfg, logger, "")
+	defer m.Close()
+	m.RegisterArrClient("radarr", arrapi.NewClient(arrapi.ClientConfig{
+		Name:    "radarr",
+		BaseURL: server.URL,
+		APIKey:  "test",
+		Logger:  logger,
+	}))
+
+	jobCfg := &config.SearchJobConfig{Enabled: true, MaxConcurrentSearches: 1}
+	job := NewMissingJob("search_missing", jobCfg, m, logger, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- job.Run(ctx) }()
+
+	select {
+	case <-searchStarted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("search was never triggered")
+	}
+	cancel()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+}
+
+func TestMissingRemembersSearches(t *testing.T) {
+	var searches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/movie":
+			// The arr hasn't caught up, so never reports a last search time
+			_ = json.NewEncoder(w).Encode([]arrapi.Movie{
+				{ID: 1, Title: "Missing Movie", Monitored: true, IsAvailable: true},
+			})
+		case "/api/v3/command":
+			searches.Add(1)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		Instances: config.InstancesConfig{
+			Radarr: []config.InstanceConfig{{Name: "radarr", URL: server.URL, APIKey: "test", Enabled: true}},
+		},
+	}
+	strikesPath := filepath.Join(t.TempDir(), "strikes.json")
+
+	newJob := func() (*MissingJob, *jobs.Manager) {
+		m := jobs.NewManager(cfg, logger, strikesPath)
+		m.RegisterArrClient("radarr", arrapi.NewClient(arrapi.ClientConfig{
+			Name:    "radarr",
+			BaseURL: server.URL,
+			APIKey:  "test",
+			Logger:  logger,
+		}))
+		jobCfg := &config.SearchJobConfig{Enabled: true, MinDaysBetweenSearches: 1, MaxConcurrentSearches: 1}
+		return NewMissingJob("search_missing", jobCfg, m, logger, false), m
+	}
+
+	job, m := newJob()
+	require.NoError(t, job.Run(context.Background()))
+	require.NoError(t, job.Run(context.Background()))
+	assert.Equal(t, int32(1), searches.Load(), "movie searched this run should be skipped on the next")
+	m.Close()
+
+	// The search history survives a restart
+	job, m = newJob()
+	defer m.Close()
+	require.NoError(t, job.Run(context.Background()))
+	assert.Equal(t, int32(1), searches.Load())
+}
+
+func TestMissingIncludeAnime(t *testing.T) {
+	absolute := 12
+	tests := []struct {
+		name         string
+		includeAnime bool
+		wantEpisodes []int
+	}{
+		{name: "anime excluded by default", includeAnime: false, wantEpisodes: []int{1}},
+		{name: "anime included", includeAnime: true, wantEpisodes: []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var searched []int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v3/series":
+					_ = json.NewEncoder(w).Encode([]arrapi.Series{{ID: 1, Title: "Anime Series", Monitored: true}})
+				case "/api/v3/episode":
+					_ = json.NewEncoder(w).Encode([]arrapi.Episode{
+						{ID: 1, SeriesID: 1, Monitored: true, AirDateUTC: time.Now().Add(-48 * time.Hour)},
+						{ID: 2, SeriesID: 1, Monitored: true, AbsoluteEpisodeNumber: &absolute},
+						// No air date and no absolute number: nothing to go on
+						{ID: 3, SeriesID: 1, Monitored: true},
+						{ID: 4, SeriesID: 1, Monitored: true, AirDateUTC: time.Now().Add(48 * time.Hour)},
+					})
+				case "/api/v3/command":
+					var cmd arrapi.CommandBody
+					_ = json.NewDecoder(r.Body).Decode(&cmd)
+					searched = append(searched, cmd.EpisodeIDs...)
+					w.WriteHeader(http.StatusCreated)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			cfg := &config.Config{
+				Instances: config.InstancesConfig{
+					Sonarr: []config.InstanceConfig{{Name: "sonarr", URL: server.URL, APIKey: "test", Enabled: true}},
+				},
+			}
+
+			m := jobs.NewManager(cfg, logger, "")
+			defer m.Close()
+			m.RegisterArrClient("sonarr", arrapi.NewClient(arrapi.ClientConfig{
+				Name:    "sonarr",
+				BaseURL: server.URL,
+				APIKey:  "test",
+				Logger:  logger,
+			}))
+
+			jobCfg := &config.SearchJobConfig{Enabled: true, MaxConcurrentSearches: 1, IncludeAnime: tt.includeAnime}
+			job := NewMissingJob("search_missing", jobCfg, m, logger, false)
+
+			require.NoError(t, job.Run(context.Background()))
+			assert.Equal(t, tt.wantEpisodes, searched)
+		})
+	}
+}
+
+func TestMissingSkipsUnsupportedEndpoint(t *testing.T) {
+	var searches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/queue":
+			_ = json.NewEncoder(w).Encode(arrapi.QueueResponse{})
+		case "/api/v3/command":
+			searches.Add(1)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			// The instance doesn't provide /api/v3/movie
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		Instances: config.InstancesConfig{
+			Radarr: []config.InstanceConfig{{Name: "radarr", URL: server.URL, APIKey: "test", Enabled: true}},
+		},
+	}
+
+	m := jobs.NewManager(cfg, logger, "")
+	defer m.Close()
+	m.RegisterArrClient("radarr", arrapi.NewClient(arrapi.ClientConfig{
+		Name:    "radarr",
+		BaseURL: server.URL,
+		APIKey:  "test",
+		Logger:  logger,
+	}))
+
+	jobCfg := &config.SearchJobConfig{Enabled: true, MaxConcurrentSearches: 1}
+	job := NewMissingJob("search_missing", jobCfg, m, logger, false)
+
+	assert.NoError(t, job.Run(context.Background()), "an unsupported endpoint shouldn't fail the job")
+	assert.Zero(t, searches.Load())
+}
+
+func TestMissingInstances(t *testing.T) {
+	newServer := func(searches *atomic.Int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/v3/queue":
+				_ = json.NewEncoder(w).Encode(arrapi.QueueResponse{})
+			case "/api/v3/movie":
+				_ = json.NewEncoder(w).Encode([]arrapi.Movie{
+					{ID: 1, Title: "Missing Movie", Monitored: true, IsAvailable: true},
+				})
+			case "/api/v3/command":
+				searches.Add(1)
+				w.WriteHeader(http.StatusCreated)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	}
+	var scopedSearches, otherSearches atomic.Int32
+	scoped := newServer(&scopedSearches)
+	defer scoped.Close()
+	other := newServer(&otherSearches)
+	defer other.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		Instances: config.InstancesConfig{
+			Radarr: []config.InstanceConfig{
+				{Name: "radarr", URL: scoped.URL, APIKey: "test", Enabled: true},
+				{Name: "radarr-4k", URL: other.URL, APIKey: "test", Enabled: true},
+			},
+		},
+	}
+
+	m := jobs.NewManager(cfg, logger, "")
+	defer m.Close()
+	for _, inst := range cfg.Instances.Radarr {
+		m.RegisterArrClient(inst.Name, arrapi.NewClient(arrapi.ClientConfig{
+			Name:    inst.Name,
+			BaseURL: inst.URL,
+			APIKey:  "test",
+			Logger:  logger,
+		}))
+	}
+
+	jobCfg := &config.SearchJobConfig{Enabled: true, MaxConcurrentSearches: 1, Instances: []string{"Radarr"}}
+	job := NewMissingJob("search_missing", jobCfg, m, logger, false)
+
+	require.NoError(t, job.Run(context.Background()))
+	assert.Equal(t, int32(1), scopedSearches.Load(), "instance in scope should be searched")
+	assert.Zero(t, otherSearches.Load(), "instance out of scope should be skipped")
+}
+
+func TestMissingBatchesEpisodeSearches(t *testing.T) {
+	aired := time.Now().Add(-48 * time.Hour)
+	var mu sync.Mutex
+	var batches [][]int
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/series":
+			_ = json.NewEncoder(w).Encode([]arrapi.Series{
+				{ID: 1, Title: "Long Series", Monitored: true},
+				{ID: 2, Title: "Short Series", Monitored: true},
+				{ID: 3, Title: "Another Series", Monitored: true},
+			})
+		case "/api/v3/episode":
+			seriesID, _ := strconv.Atoi(r.URL.Query().Get("seriesId"))
+			count := 1
+			if seriesID == 1 {
+				count = 5
+			}
+			var episodes []arrapi.Episode
+			for i := 1; i <= count; i++ {
+				episodes = append(episodes, arrapi.Episode{ID: seriesID*100 + i, SeriesID: seriesID, Monitored: true, AirDateUTC: aired})
+			}
+			_ = json.NewEncoder(w).Encode(episodes)
+		case "/api/v3/command":
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				highest := maxInFlight.Load()
+				if n <= highest || maxInFlight.CompareAndSwap(highest, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+
+			var cmd arrapi.CommandBody
+			_ = json.NewDecoder(r.Body).Decode(&cmd)
+			mu.Lock()
+			batches = append(batches, cmd.EpisodeIDs)
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		Instances: config.InstancesConfig{
+			Sonarr: []config.InstanceConfig{{Name: "sonarr", URL: server.URL, APIKey: "test", Enabled: true}},
+		},
+	}
+
+	m := jobs.NewManager(cfg, logger, "")
+	defer m.Close()
+	m.RegisterArrClient("sonarr", arrapi.NewClient(arrapi.ClientConfig{
+		Name:    "sonarr",
+		BaseURL: server.URL,
+		APIKey:  "test",
+		Logger:  logger,
+	}))
+
+	jobCfg := &config.SearchJobConfig{Enabled: true, MaxConcurrentSearches: 2, EpisodesPerSearch: 2}
+	job := NewMissingJob("search_missing", jobCfg, m, logger, false)
+
+	require.NoError(t, job.Run(context.Background()))
+
+	var longSeries [][]int
+	for _, batch := range batches {
+		assert.LessOrEqual(t, len(batch), 2, "no command should exceed episodes_per_search")
+		if batch[0]/100 == 1 {
+			longSeries = append(longSeries, batch)
+		}
+	}
+	assert.ElementsMatch(t, [][]int{{101, 102}, {103, 104}, {105}}, longSeries)
+	assert.Len(t, batches, 5)
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2), "searches in flight should be bounded by max_concurrent_searches")
+	assert.Equal(t, 7, job.Stats().Removed)
+}
+
+func TestMissingSeriesFailureDoesNotStopOthers(t *testing.T) {
+	aired := time.Now().Add(-48 * time.Hour)
+	var mu sync.Mutex
+	var searchedIDs []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/series":
+			_ = json.NewEncoder(w).Encode([]arrapi.Series{
+				{ID: 1, Title: "Broken Episodes", Monitored: true},
+				{ID: 2, Title: "Broken Search", Monitored: true},
+				{ID: 3, Title: "Healthy", Monitored: true},
+				{ID: 4, Title: "Also Healthy", Monitored: true},
+			})
+		case "/api/v3/episode":
+			seriesID, _ := strconv.Atoi(r.URL.Query().Get("seriesId"))
+			if seriesID == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode([]arrapi.Episode{{ID: seriesID * 100, SeriesID: seriesID, Monitored: true, AirDateUTC: aired}})
+		case "/api/v3/command":
+			var cmd arrapi.CommandBody
+			_ = json.NewDecoder(r.Body).Decode(&cmd)
+			if len(cmd.EpisodeIDs) > 0 && cmd.EpisodeIDs[0] == 200 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			searchedIDs = append(searchedIDs, cmd.EpisodeIDs...)
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		Instances: config.InstancesConfig{
+			Sonarr: []config.InstanceConfig{{Name: "sonarr", URL: server.URL, APIKey: "test", Enabled: true}},
+		},
+	}
+
+	m := jobs.NewManager(cfg, logger, "")
+	defer m.Close()
+	m.RegisterArrClient("sonarr", arrapi.NewClient(arrapi.ClientConfig{
+		Name:    "sonarr",
+		BaseURL: server.URL,
+		APIKey:  "test",
+		Logger:  logger,
+	}))
+
+	// Run with -race: the series are searched in parallel
+	jobCfg := &config.SearchJobConfig{Enabled: true, MaxConcurrentSearches: 4}
+	job := NewMissingJob("search_missing", jobCfg, m, logger, false)
+
+	require.NoError(t, job.Run(context.Background()), "a failing series is logged, not a job error")
+	assert.ElementsMatch(t, []int{300, 400}, searchedIDs, "healthy series should still be searched")
+	assert.Equal(t, 2, job.Stats().Removed)
+}
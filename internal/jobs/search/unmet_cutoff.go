@@ -9,6 +9,7 @@ import (
 	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
 	"github.com/jmylchreest/go-decluttarr/internal/config"
 	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+	"github.com/jmylchreest/go-decluttarr/internal/searches"
 )
 
 // UnmetCutoffJob searches for items that don't meet quality cutoff
@@ -55,6 +56,24 @@ func (j *UnmetCutoffJob) Enabled() bool {
 	return j.enabled
 }
 
+// SetTestRun switches the job in or out of test-run mode
+func (j *UnmetCutoffJob) SetTestRun(testRun bool) {
+	j.testRun = testRun
+}
+
+// Interval returns how often the job runs, or 0 to run every cycle
+func (j *UnmetCutoffJob) Interval() time.Duration {
+	return j.cfg.Interval
+}
+
+// Order returns the job's position in the cycle, after removal jobs by default
+func (j *UnmetCutoffJob) Order() int {
+	if j.cfg.Order != nil {
+		return *j.cfg.Order
+	}
+	return jobs.SearchJobOrder
+}
+
 // Stats returns the statistics from the last run
 func (j *UnmetCutoffJob) Stats() jobs.JobStats {
 	return jobs.JobStats{
@@ -84,6 +103,9 @@ func (j *UnmetCutoffJob) Run(ctx context.Context) error {
 
 	// Process each arr instance
 	for instanceName, client := range allClients {
+		if !jobs.InInstances(j.cfg.Instances, instanceName) {
+			continue
+		}
 		if err := j.processArrInstance(ctx, instanceName, client); err != nil {
 			j.logger.Error("failed to process arr instance",
 				"instance", instanceName,
@@ -100,8 +122,9 @@ func (j *UnmetCutoffJob) Run(ctx context.Context) error {
 	return nil
 }
 
-// filterRecentlySearchedItems filters out items that have been searched within minDays
-func (j *UnmetCutoffJob) filterRecentlySearchedItems(items []arrapi.CutoffUnmetItem) []arrapi.CutoffUnmetItem {
+// filterRecentlySearchedItems filters out items that have been searched within
+// minDays, by the *arr's account or as recorded under the item's search history key
+func (j *UnmetCutoffJob) filterRecentlySearchedItems(items []arrapi.CutoffUnmetItem, key func(arrapi.CutoffUnmetItem) string) []arrapi.CutoffUnmetItem {
 	if j.minDaysBetweenSearches <= 0 {
 		return items
 	}
@@ -110,22 +133,24 @@ func (j *UnmetCutoffJob) filterRecentlySearchedItems(items []arrapi.CutoffUnmetI
 	threshold := now.AddDate(0, 0, -j.minDaysBetweenSearches)
 
 	var eligible []arrapi.CutoffUnmetItem
+	history := j.manager.GetSearchHistory()
 	for _, item := range items {
-		if item.LastSearchTime == nil {
+		lastSearch := history.Latest(key(item), item.LastSearchTime)
+		if lastSearch == nil {
 			// Never searched, eligible
 			eligible = append(eligible, item)
 			continue
 		}
 
-		if item.LastSearchTime.Before(threshold) {
+		if lastSearch.Before(threshold) {
 			// Last search was more than minDays ago
 			eligible = append(eligible, item)
 		} else {
-			daysAgo := int(now.Sub(*item.LastSearchTime).Hours() / 24)
+			daysAgo := int(now.Sub(*lastSearch).Hours() / 24)
 			j.logger.Debug("skipping recently searched item",
 				"item_id", item.ID,
 				"title", item.Title,
-				"last_search", item.LastSearchTime.Format(time.RFC3339),
+				"last_search", lastSearch.Format(time.RFC3339),
 				"days_ago", daysAgo,
 				"min_days", j.minDaysBetweenSearches,
 			)
@@ -171,6 +196,9 @@ func (j *UnmetCutoffJob) processSonarr(ctx context.Context, instanceName string,
 	// Get cutoff unmet episodes
 	items, err := sonarrClient.GetCutoffUnmet(ctx)
 	if err != nil {
+		if jobs.Unsupported(j.logger, instanceName, "wanted/cutoff", err) {
+			return nil
+		}
 		return fmt.Errorf("failed to get cutoff unmet episodes: %w", err)
 	}
 
@@ -180,7 +208,9 @@ func (j *UnmetCutoffJob) processSonarr(ctx context.Context, instanceName string,
 	j.lastFound += len(items)
 
 	// Filter out recently searched episodes
-	eligibleItems := j.filterRecentlySearchedItems(items)
+	eligibleItems := j.filterRecentlySearchedItems(items, func(item arrapi.CutoffUnmetItem) string {
+		return searches.Key(instanceName, "episode", item.ID)
+	})
 
 	j.logger.Debug("eligible cutoff unmet episodes after filtering",
 		"instance", instanceName,
@@ -246,6 +276,7 @@ func (j *UnmetCutoffJob) processSonarr(ctx context.Context, instanceName string,
 						"error", err)
 					continue
 				}
+				recordSearched(j.manager, instanceName, "episode", episodeIDs...)
 
 				// Add delay between searches to avoid overwhelming the arr instance
 				time.Sleep(2 * time.Second)
@@ -266,6 +297,9 @@ func (j *UnmetCutoffJob) processRadarr(ctx context.Context, instanceName string,
 	// Get cutoff unmet movies
 	items, err := radarrClient.GetCutoffUnmet(ctx)
 	if err != nil {
+		if jobs.Unsupported(j.logger, instanceName, "wanted/cutoff", err) {
+			return nil
+		}
 		return fmt.Errorf("failed to get cutoff unmet movies: %w", err)
 	}
 
@@ -275,7 +309,12 @@ func (j *UnmetCutoffJob) processRadarr(ctx context.Context, instanceName string,
 	j.lastFound += len(items)
 
 	// Filter out recently searched movies
-	eligibleItems := j.filterRecentlySearchedItems(items)
+	eligibleItems := j.filterRecentlySearchedItems(items, func(item arrapi.CutoffUnmetItem) string {
+		if item.MovieID != nil {
+			return searches.Key(instanceName, "movie", *item.MovieID)
+		}
+		return searches.Key(instanceName, "movie", item.ID)
+	})
 
 	j.logger.Debug("eligible cutoff unmet movies after filtering",
 		"instance", instanceName,
@@ -327,6 +366,7 @@ func (j *UnmetCutoffJob) processRadarr(ctx context.Context, instanceName string,
 					"error", err)
 				continue
 			}
+			recordSearched(j.manager, instanceName, "movie", movieID)
 
 			// Add delay between searches to avoid overwhelming the arr instance
 			time.Sleep(2 * time.Second)
@@ -0,0 +1,92 @@
+package jobs
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// SummaryEvent identifies cycle summary lines among other NDJSON output
+const SummaryEvent = "cycle_summary"
+
+// CycleSummary is the machine-readable summary of a cycle, written as a single
+// NDJSON line for log pipelines. Its field names are a stable schema: fields
+// may be added, but existing ones aren't renamed or removed.
+type CycleSummary struct {
+	Event        string               `json:"event"`
+	StartTime    time.Time            `json:"start_time"`
+	EndTime      time.Time            `json:"end_time"`
+	DurationMS   int64                `json:"duration_ms"`
+	TestRun      bool                 `json:"test_run"`
+	JobsRun      int                  `json:"jobs_run"`
+	JobsFailed   int                  `json:"jobs_failed"`
+	Found        int                  `json:"found"`
+	RemovedCount int                  `json:"removed_count"`
+	Jobs         map[string]JobResult `json:"jobs"`
+	Removed      []SummaryRemoval     `json:"removed"`
+	Strikes      SummaryStrikes       `json:"strikes"`
+	Errors       []string             `json:"errors"`
+}
+
+// SummaryRemoval is a download removed during the cycle
+type SummaryRemoval struct {
+	Job        string `json:"job"`
+	Source     string `json:"source"`
+	DownloadID string `json:"download_id"`
+	Name       string `json:"name"`
+	Reason     string `json:"reason"`
+}
+
+// SummaryStrikes are the cycle's strike counts
+type SummaryStrikes struct {
+	Added     int `json:"added"`
+	Cleared   int `json:"cleared"`
+	Recovered int `json:"recovered"`
+	Tracked   int `json:"tracked"`
+}
+
+// NewCycleSummary builds the summary of a cycle from its stats. Every job that
+// ran is listed, and empty lists are [] rather than null.
+func NewCycleSummary(stats *CycleStats, testRun bool) CycleSummary {
+	summary := CycleSummary{
+		Event:      SummaryEvent,
+		StartTime:  stats.StartTime,
+		EndTime:    stats.EndTime,
+		DurationMS: stats.Duration.Milliseconds(),
+		TestRun:    testRun,
+		JobsRun:    stats.JobsRun,
+		JobsFailed: stats.JobsFailed,
+		Jobs:       make(map[string]JobResult, len(stats.ItemsFound)),
+		Removed:    make([]SummaryRemoval, 0, len(stats.Removed)),
+		Strikes: SummaryStrikes{
+			Added:     stats.StrikesAdded,
+			Cleared:   stats.StrikesReset,
+			Recovered: stats.StrikesRecovered,
+			Tracked:   stats.TotalStrikes,
+		},
+		Errors: append([]string{}, stats.Errors...),
+	}
+
+	for job, found := range stats.ItemsFound {
+		removed := stats.ItemsRemoved[job]
+		summary.Jobs[job] = JobResult{Found: found, Removed: removed}
+		summary.Found += found
+		summary.RemovedCount += removed
+	}
+	for _, d := range stats.Removed {
+		summary.Removed = append(summary.Removed, SummaryRemoval{
+			Job:        d.Job,
+			Source:     d.Source,
+			DownloadID: d.DownloadID,
+			Name:       d.Name,
+			Reason:     d.Reason,
+		})
+	}
+
+	return summary
+}
+
+// WriteCycleSummary writes the summary of a cycle to w as one NDJSON line
+func WriteCycleSummary(w io.Writer, stats *CycleStats, testRun bool) error {
+	return json.NewEncoder(w).Encode(NewCycleSummary(stats, testRun))
+}
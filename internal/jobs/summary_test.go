@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCycleSummary(t *testing.T) {
+	start := time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)
+	stats := &CycleStats{
+		StartTime:    start,
+		EndTime:      start.Add(1500 * time.Millisecond),
+		Duration:     1500 * time.Millisecond,
+		JobsRun:      2,
+		ItemsFound:   map[string]int{"remove_stalled": 2, "remove_slow": 0},
+		ItemsRemoved: map[string]int{"remove_stalled": 1, "remove_slow": 0},
+		StrikesAdded: 2,
+		TotalStrikes: 1,
+		Removed: []RemovedDownload{
+			{Job: "remove_stalled", Source: "sonarr", DownloadID: "abc", Name: "Stalled.Item", Reason: "download stalled"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCycleSummary(&buf, stats, true))
+
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"), "summary should be a single NDJSON line")
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	for _, key := range []string{
+		"event", "start_time", "end_time", "duration_ms", "test_run", "jobs_run", "jobs_failed",
+		"found", "removed_count", "jobs", "removed", "strikes", "errors",
+	} {
+		assert.Contains(t, line, key)
+	}
+	assert.Equal(t, "cycle_summary", line["event"])
+	assert.Equal(t, float64(1500), line["duration_ms"])
+	assert.Equal(t, true, line["test_run"])
+	assert.Equal(t, float64(2), line["found"])
+	assert.Equal(t, float64(1), line["removed_count"])
+	assert.Equal(t, []any{}, line["errors"], "empty lists should be [] rather than null")
+
+	jobs := line["jobs"].(map[string]any)
+	assert.Equal(t, map[string]any{"found": float64(2), "removed": float64(1)}, jobs["remove_stalled"])
+	assert.Contains(t, jobs, "remove_slow", "jobs that ran should be listed even when they found nothing")
+
+	assert.Equal(t, []any{map[string]any{
+		"job":         "remove_stalled",
+		"source":      "sonarr",
+		"download_id": "abc",
+		"name":        "Stalled.Item",
+		"reason":      "download stalled",
+	}}, line["removed"])
+	assert.Equal(t, map[string]any{
+		"added":     float64(2),
+		"cleared":   float64(0),
+		"recovered": float64(0),
+		"tracked":   float64(1),
+	}, line["strikes"])
+}
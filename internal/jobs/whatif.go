@@ -0,0 +1,106 @@
+package jobs
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+)
+
+// Tracker types reported by WhatIf
+const (
+	TrackerPrivate = "private"
+	TrackerPublic  = "public"
+	TrackerUnknown = "unknown" // not in a download client, or its properties couldn't be fetched
+)
+
+// WhatIfReport explains how the registered jobs would treat a download
+type WhatIfReport struct {
+	DownloadID  string
+	Instances   []string        // instances whose queue tracks the download
+	Detected    map[string]bool // job name -> whether the job's detection matches
+	Strikes     int
+	InClient    bool
+	TrackerType string
+	Protected   bool
+	Action      string // what GetRemovalAction returns once max strikes is reached
+}
+
+// Found reports whether the download is known to any *arr queue, download
+// client or the strikes handler
+func (r *WhatIfReport) Found() bool {
+	return len(r.Instances) > 0 || r.InClient || r.Strikes > 0
+}
+
+// WhatIf evaluates a download against every enabled job that can detect
+// queue items without side effects, through the same filters and torrent
+// states a run uses, and reports its strikes, tracker type, protection and
+// removal action. Nothing is changed. Queues that can't be fetched are skipped.
+func (m *Manager) WhatIf(ctx context.Context, downloadID string) *WhatIfReport {
+	report := &WhatIfReport{
+		DownloadID:  downloadID,
+		Detected:    make(map[string]bool),
+		Strikes:     m.strikes.Get(downloadID),
+		TrackerType: TrackerUnknown,
+	}
+
+	// Jobs filter whole queues (e.g. protect_newest), so the queues of the
+	// instances tracking the download are evaluated in full
+	queues := make(map[string][]arrapi.QueueItem)
+	for name, client := range m.GetAllArrClients() {
+		queue, err := client.GetQueue(ctx)
+		if err != nil {
+			m.logger.Debug("failed to fetch queue for what-if", "instance", name, "error", err)
+			continue
+		}
+		for _, item := range queue {
+			if strings.EqualFold(item.DownloadID, downloadID) {
+				queues[name] = queue
+				report.Instances = append(report.Instances, name)
+				break
+			}
+		}
+	}
+	sort.Strings(report.Instances)
+
+	m.mu.RLock()
+	jobs := m.jobs
+	m.mu.RUnlock()
+
+	if len(queues) > 0 {
+		torrents := m.GetAllTorrents(ctx)
+		for _, job := range jobs {
+			if dj, ok := job.(DetectingJob); ok && job.Enabled() {
+				report.Detected[job.Name()] = detects(dj, queues, torrents, downloadID)
+			}
+		}
+	}
+
+	if torrent, client := m.findTorrentByHash(ctx, downloadID); torrent != nil {
+		report.InClient = true
+		report.Protected = m.isProtected(torrent)
+		if private, err := trackerPrivate(ctx, client, torrent); err == nil {
+			report.TrackerType = TrackerPublic
+			if private {
+				report.TrackerType = TrackerPrivate
+			}
+		}
+	}
+	report.Action = m.GetRemovalAction(ctx, downloadID)
+
+	return report
+}
+
+// detects reports whether a job's detection matches a download in any of queues
+func detects(job DetectingJob, queues map[string][]arrapi.QueueItem, torrents map[string]downloadclient.Torrent, downloadID string) bool {
+	for name, queue := range queues {
+		for _, item := range job.Detect(name, queue, torrents) {
+			if strings.EqualFold(item.DownloadID, downloadID) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// TraceMessage is the message of general.trace_decisions records. They exist
+// to explain each item, so the sampler never drops them.
+const TraceMessage = "decision trace"
+
+// Sampler thins out debug lines that repeat for every queue item or torrent,
+// emitting the first of each message and every Nth after it. Warnings and
+// above, and decision traces, are never dropped. Counts are shared by every logger the sampler
+// wraps, and start over on Reset, e.g. at the start of each cycle.
+type Sampler struct {
+	every int
+	mu    sync.Mutex
+	seen  map[string]int // debug message -> lines logged since Reset
+}
+
+// NewSampler creates a sampler emitting every Nth repeat of a debug message.
+// Values below 2 emit every line.
+func NewSampler(every int) *Sampler {
+	return &Sampler{every: every, seen: make(map[string]int)}
+}
+
+// Logger wraps logger so its debug lines are sampled
+func (s *Sampler) Logger(logger *slog.Logger) *slog.Logger {
+	if s.every < 2 {
+		return logger
+	}
+	return slog.New(&sampledHandler{sampler: s, next: logger.Handler()})
+}
+
+// Reset starts counting each message from the beginning again
+func (s *Sampler) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.seen)
+}
+
+// keep reports whether the next debug line with this message is emitted
+func (s *Sampler) keep(message string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := s.seen[message]
+	s.seen[message] = n + 1
+	return n%s.every == 0
+}
+
+// sampledHandler drops the debug records its sampler doesn't keep
+type sampledHandler struct {
+	sampler *Sampler
+	next    slog.Handler
+}
+
+func (h *sampledHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *sampledHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level <= slog.LevelDebug && r.Message != TraceMessage && !h.sampler.keep(r.Message) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *sampledHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &sampledHandler{sampler: h.sampler, next: h.next.WithAttrs(attrs)}
+}
+
+func (h *sampledHandler) WithGroup(name string) slog.Handler {
+	return &sampledHandler{sampler: h.sampler, next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	sampler := NewSampler(10)
+	logger := sampler.Logger(base).With("job", "remove_stalled")
+
+	for i := 0; i < 100; i++ {
+		logger.Debug("added strike to stalled download", "item", i)
+	}
+	logger.Debug("starting stalled removal job")
+	logger.Warn("failed to remove download")
+	logger.Warn("failed to remove download")
+	for i := 0; i < 20; i++ {
+		logger.Debug(TraceMessage, "download_id", i)
+	}
+
+	count := func(msg string) int {
+		return strings.Count(buf.String(), msg)
+	}
+	assert.Equal(t, 10, count("added strike to stalled download"), "one in ten per-item lines should be emitted")
+	assert.Equal(t, 1, count("starting stalled removal job"), "the first line of a message is always emitted")
+	assert.Equal(t, 2, count("failed to remove download"), "warnings aren't sampled")
+	assert.Equal(t, 20, count(TraceMessage), "decision traces aren't sampled")
+	assert.Contains(t, buf.String(), "job=remove_stalled")
+
+	// Counts start over each cycle
+	buf.Reset()
+	sampler.Reset()
+	logger.Debug("added strike to stalled download", "item", 0)
+	assert.Equal(t, 1, count("added strike to stalled download"))
+}
+
+func TestSamplerDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	logger := NewSampler(0).Logger(base)
+	assert.Same(t, base, logger, "sampling below 2 should leave the logger alone")
+	for i := 0; i < 5; i++ {
+		logger.Debug("per item")
+	}
+	assert.Equal(t, 5, strings.Count(buf.String(), "per item"))
+}
@@ -0,0 +1,128 @@
+// Package match provides the glob, wildcard and regex message matching shared
+// by jobs that accept user-defined patterns
+package match
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RegexPrefix marks a pattern as a regular expression rather than a glob
+const RegexPrefix = "re:"
+
+// regexCache holds compiled regex patterns, keyed by the pattern without its prefix
+var regexCache sync.Map // string -> *compiledRegex
+
+type compiledRegex struct {
+	re  *regexp.Regexp
+	err error
+}
+
+// Any reports whether text matches any of the patterns.
+// Patterns are matched case-insensitively and may be:
+//   - a glob understood by filepath.Match (e.g. "Not an upgrade?")
+//   - a "*" wildcard pattern (e.g. "*sample*", "prefix*", "*suffix")
+//   - an exact string
+//   - a regular expression prefixed with "re:" (e.g. "re:^unpack(ing)? failed")
+//
+// Invalid regex patterns never match; use Validate to report them up front.
+func Any(text string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if Match(text, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether text matches a single pattern (see Any)
+func Match(text, pattern string) bool {
+	if expr, ok := strings.CutPrefix(pattern, RegexPrefix); ok {
+		re, err := compile(expr)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(text)
+	}
+
+	textLower := strings.ToLower(text)
+	patternLower := strings.ToLower(pattern)
+
+	// Try filepath.Match for standard glob patterns
+	if matched, err := filepath.Match(patternLower, textLower); err == nil && matched {
+		return true
+	}
+
+	// Fallback to simple wildcard matching for patterns with *
+	if strings.Contains(patternLower, "*") && wildcardMatch(textLower, patternLower) {
+		return true
+	}
+
+	// Exact match fallback
+	return textLower == patternLower
+}
+
+// Validate returns an error for the first pattern that is an invalid regex
+func Validate(patterns []string) error {
+	for _, pattern := range patterns {
+		expr, ok := strings.CutPrefix(pattern, RegexPrefix)
+		if !ok {
+			continue
+		}
+		if _, err := compile(expr); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// compile compiles a case-insensitive regex, caching the result (including
+// failures) so each pattern is only compiled once
+func compile(expr string) (*regexp.Regexp, error) {
+	if cached, ok := regexCache.Load(expr); ok {
+		c := cached.(*compiledRegex)
+		return c.re, c.err
+	}
+
+	re, err := regexp.Compile("(?i)" + expr)
+	cached, _ := regexCache.LoadOrStore(expr, &compiledRegex{re: re, err: err})
+	c := cached.(*compiledRegex)
+	return c.re, c.err
+}
+
+// wildcardMatch performs simple wildcard matching
+// Supports patterns like "*text*", "prefix*", "*suffix"
+func wildcardMatch(text, pattern string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return text == pattern
+	}
+
+	// Check prefix
+	if parts[0] != "" && !strings.HasPrefix(text, parts[0]) {
+		return false
+	}
+
+	// Check suffix
+	if parts[len(parts)-1] != "" && !strings.HasSuffix(text, parts[len(parts)-1]) {
+		return false
+	}
+
+	// Check middle parts
+	pos := len(parts[0])
+	for i := 1; i < len(parts)-1; i++ {
+		if parts[i] == "" {
+			continue
+		}
+		idx := strings.Index(text[pos:], parts[i])
+		if idx == -1 {
+			return false
+		}
+		pos += idx + len(parts[i])
+	}
+
+	return true
+}
@@ -0,0 +1,68 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		pattern string
+		want    bool
+	}{
+		{name: "glob", text: "Not an upgrade", pattern: "not an upgrad?", want: true},
+		{name: "glob character class", text: "Sample file", pattern: "[sS]ample*", want: true},
+		{name: "wildcard contains", text: "File is a Sample, skipping", pattern: "*sample*", want: true},
+		{name: "wildcard prefix", text: "Unpacking failed: disk full", pattern: "unpacking*", want: true},
+		{name: "wildcard suffix", text: "Import failed, not an upgrade", pattern: "*not an upgrade", want: true},
+		{name: "wildcard miss", text: "Download client unavailable", pattern: "*sample*", want: false},
+		{name: "exact", text: "Not an upgrade", pattern: "NOT AN UPGRADE", want: true},
+		{name: "exact miss", text: "Not an upgrade for existing file", pattern: "not an upgrade", want: false},
+		{name: "regex", text: "Unpacking failed", pattern: "re:^unpack(ing)? failed$", want: true},
+		{name: "regex is case-insensitive", text: "CRC MISMATCH in part 3", pattern: `re:crc mismatch in part \d+`, want: true},
+		{name: "regex miss", text: "Unpack failed later", pattern: "re:^unpack(ing)? failed$", want: false},
+		{name: "regex metacharacters are not globs", text: "anything", pattern: "re:.*", want: true},
+		{name: "invalid regex never matches", text: "(unclosed", pattern: "re:(unclosed", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Match(tt.text, tt.pattern))
+		})
+	}
+}
+
+func TestAny(t *testing.T) {
+	patterns := []string{"*sample*", "re:^no files found"}
+
+	assert.True(t, Any("Sample detected", patterns))
+	assert.True(t, Any("No files found are eligible for import", patterns))
+	assert.False(t, Any("Download client unavailable", patterns))
+	assert.False(t, Any("Sample detected", nil))
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(nil))
+	require.NoError(t, Validate([]string{"*sample*", "[", "re:^ok$"}))
+
+	err := Validate([]string{"re:^ok$", "re:(unclosed"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid pattern "re:(unclosed"`)
+}
+
+func TestCompileCaches(t *testing.T) {
+	first, err := compile("cache[d]?")
+	require.NoError(t, err)
+	second, err := compile("cache[d]?")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	_, err = compile("(bad")
+	require.Error(t, err)
+	_, err = compile("(bad")
+	require.Error(t, err)
+}
@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+// Email encryption modes
+const (
+	EncryptionStartTLS = "starttls"
+	EncryptionTLS      = "tls"
+	EncryptionNone     = "none"
+)
+
+// emailTimeout bounds a whole SMTP exchange when ctx has no deadline
+const emailTimeout = 30 * time.Second
+
+// EmailNotifier sends cycle summaries by email over SMTP
+type EmailNotifier struct {
+	host       string
+	port       int
+	username   string
+	password   string
+	from       string
+	to         []string
+	encryption string
+	tlsConfig  *tls.Config
+}
+
+// NewEmailNotifier creates an SMTP email notifier
+func NewEmailNotifier(cfg config.EmailConfig) *EmailNotifier {
+	encryption := strings.ToLower(cfg.Encryption)
+	if encryption == "" {
+		encryption = EncryptionStartTLS
+	}
+
+	return &EmailNotifier{
+		host:       cfg.Host,
+		port:       cfg.Port,
+		username:   cfg.Username,
+		password:   cfg.Password,
+		from:       cfg.From,
+		to:         cfg.To,
+		encryption: encryption,
+		tlsConfig:  &tls.Config{ServerName: cfg.Host, MinVersion: tls.VersionTLS12},
+	}
+}
+
+// Name returns the notifier name
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+// Notify emails a cycle summary to all recipients
+func (n *EmailNotifier) Notify(ctx context.Context, stats *jobs.CycleStats) error {
+	subject, body := renderSummary(stats)
+	return n.send(ctx, subject, body)
+}
+
+// send delivers a single message
+func (n *EmailNotifier) send(ctx context.Context, subject, body string) error {
+	addr := net.JoinHostPort(n.host, strconv.Itoa(n.port))
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, emailTimeout)
+		defer cancel()
+	}
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if n.encryption == EncryptionTLS {
+		conn = tls.Client(conn, n.tlsConfig)
+	}
+
+	c, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if n.encryption == EncryptionStartTLS {
+		if err := c.StartTLS(n.tlsConfig); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+
+	if n.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", n.username, n.password, n.host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := c.Mail(n.from); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	for _, rcpt := range n.to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("smtp rcpt %s: %w", rcpt, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write([]byte(n.buildMessage(subject, body))); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+
+	return c.Quit()
+}
+
+// buildMessage renders the RFC 5322 message with headers
+func (n *EmailNotifier) buildMessage(subject, body string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return b.String()
+}
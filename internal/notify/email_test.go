@@ -0,0 +1,145 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+)
+
+// smtpStub is a minimal in-process SMTP server that records one message
+type smtpStub struct {
+	ln   net.Listener
+	mu   sync.Mutex
+	from string
+	to   []string
+	data string
+	done chan struct{}
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	s := &smtpStub{ln: ln, done: make(chan struct{})}
+	go s.serve()
+	return s
+}
+
+func (s *smtpStub) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStub) serve() {
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	defer close(s.done)
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 stub ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimSpace(line)
+		upper := strings.ToUpper(cmd)
+
+		switch {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 stub")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.mu.Lock()
+			s.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+			s.mu.Unlock()
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			s.mu.Lock()
+			s.to = append(s.to, strings.Trim(cmd[len("RCPT TO:"):], "<> "))
+			s.mu.Unlock()
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 go ahead")
+			var b strings.Builder
+			for {
+				dl, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dl == ".\r\n" {
+					break
+				}
+				b.WriteString(dl)
+			}
+			s.mu.Lock()
+			s.data = b.String()
+			s.mu.Unlock()
+			reply("250 queued")
+		case upper == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestEmailNotify(t *testing.T) {
+	stub := newSMTPStub(t)
+
+	n := NewEmailNotifier(config.EmailConfig{
+		Host:       "127.0.0.1",
+		Port:       stub.port(),
+		From:       "decluttarr@example.com",
+		To:         []string{"admin@example.com", "ops@example.com"},
+		Encryption: EncryptionNone,
+	})
+
+	require.NoError(t, n.Notify(context.Background(), testStats()))
+	<-stub.done
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
+	assert.Equal(t, "decluttarr@example.com", stub.from)
+	assert.Equal(t, []string{"admin@example.com", "ops@example.com"}, stub.to)
+	assert.Contains(t, stub.data, "Subject: go-decluttarr: 2 removed, 1 errors\r\n")
+	assert.Contains(t, stub.data, "To: admin@example.com, ops@example.com\r\n")
+	assert.Contains(t, stub.data, "remove_stalled: 2 removed (3 found)\r\n")
+	assert.Contains(t, stub.data, "- remove_slow: timeout\r\n")
+}
+
+func TestEmailNotifyConnectionRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := ln.Addr().(*net.TCPAddr).Port
+	require.NoError(t, ln.Close())
+
+	n := NewEmailNotifier(config.EmailConfig{
+		Host:       "127.0.0.1",
+		Port:       port,
+		From:       "decluttarr@example.com",
+		To:         []string{"admin@example.com"},
+		Encryption: EncryptionNone,
+	})
+
+	err = n.Notify(context.Background(), testStats())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connect to 127.0.0.1:"+strconv.Itoa(port))
+}
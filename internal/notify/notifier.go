@@ -0,0 +1,170 @@
+// Package notify sends cycle summaries to external notification services
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+// Notifier delivers a cycle summary to a notification service
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, stats *jobs.CycleStats) error
+}
+
+// Registry fans cycle summaries out to all configured notifiers
+type Registry struct {
+	notifiers []Notifier
+	events    []config.NotifyEvents // strike events reported, by notifier
+	logger    *slog.Logger
+}
+
+// NewRegistry creates a registry with the notifiers enabled in cfg
+func NewRegistry(cfg config.NotificationsConfig, logger *slog.Logger) *Registry {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	r := &Registry{logger: logger.With("component", "notify")}
+
+	if cfg.Pushover.Enabled {
+		r.register(NewPushoverNotifier(cfg.Pushover), cfg.Pushover.NotifyEvents)
+	}
+	if cfg.Email.Enabled {
+		r.register(NewEmailNotifier(cfg.Email), cfg.Email.NotifyEvents)
+	}
+
+	return r
+}
+
+// Register adds a notifier to the registry that reports no strike events
+func (r *Registry) Register(n Notifier) {
+	r.register(n, config.NotifyEvents{})
+}
+
+func (r *Registry) register(n Notifier, events config.NotifyEvents) {
+	r.notifiers = append(r.notifiers, n)
+	r.events = append(r.events, events)
+	r.logger.Debug("registered notifier", "notifier", n.Name())
+}
+
+// Len returns the number of registered notifiers
+func (r *Registry) Len() int {
+	return len(r.notifiers)
+}
+
+// NotifyAll sends stats to every notifier, each seeing only the struck
+// downloads its strike events select. Cycles where nothing was removed,
+// nothing failed and no selected strike event occurred are not sent.
+// Notifier errors are logged, not returned.
+func (r *Registry) NotifyAll(ctx context.Context, stats *jobs.CycleStats) {
+	if stats == nil {
+		return
+	}
+
+	for i, n := range r.notifiers {
+		filtered := withStrikeEvents(stats, r.events[i])
+		if !worthNotifying(filtered) {
+			continue
+		}
+
+		if err := n.Notify(ctx, filtered); err != nil {
+			r.logger.Error("failed to send notification",
+				"notifier", n.Name(),
+				"error", err)
+			continue
+		}
+		r.logger.Debug("sent notification", "notifier", n.Name())
+	}
+}
+
+// worthNotifying reports whether a cycle removed anything, had errors or
+// struck a download worth reporting
+func worthNotifying(stats *jobs.CycleStats) bool {
+	return totalRemoved(stats) > 0 || len(stats.Errors) > 0 || len(stats.Struck) > 0
+}
+
+// withStrikeEvents returns a copy of stats keeping only the struck downloads
+// that events selects
+func withStrikeEvents(stats *jobs.CycleStats, events config.NotifyEvents) *jobs.CycleStats {
+	filtered := *stats
+	filtered.Struck = nil
+	for _, d := range stats.Struck {
+		if reportsStrike(events, d) {
+			filtered.Struck = append(filtered.Struck, d)
+		}
+	}
+	return &filtered
+}
+
+// reportsStrike reports whether events selects a struck download. Downloads
+// that reached their limit are covered by the removal summary instead.
+func reportsStrike(events config.NotifyEvents, d jobs.StruckDownload) bool {
+	left := d.StrikesLeft()
+	if left <= 0 {
+		return false
+	}
+	if events.NearRemoval && left == 1 {
+		return true
+	}
+	return events.ApproachingStrikes > 0 && d.Strikes >= events.ApproachingStrikes
+}
+
+func totalRemoved(stats *jobs.CycleStats) int {
+	total := 0
+	for _, n := range stats.ItemsRemoved {
+		total += n
+	}
+	return total
+}
+
+// renderSummary renders a cycle summary as a title and plain-text body
+func renderSummary(stats *jobs.CycleStats) (title, body string) {
+	removed := totalRemoved(stats)
+	title = fmt.Sprintf("go-decluttarr: %d removed", removed)
+	if len(stats.Errors) > 0 {
+		title += fmt.Sprintf(", %d errors", len(stats.Errors))
+	}
+	if len(stats.Struck) > 0 {
+		title += fmt.Sprintf(", %d nearing removal", len(stats.Struck))
+	}
+
+	var b strings.Builder
+
+	names := make([]string, 0, len(stats.ItemsRemoved))
+	for name := range stats.ItemsRemoved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if stats.ItemsRemoved[name] == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %d removed (%d found)\n", name, stats.ItemsRemoved[name], stats.ItemsFound[name])
+	}
+
+	if len(stats.Struck) > 0 {
+		b.WriteString("\nNearing removal:\n")
+		for _, d := range stats.Struck {
+			fmt.Fprintf(&b, "- %s (%s): %d of %d strikes\n", d.Name, d.Job, d.Strikes, d.MaxStrikes)
+		}
+	}
+
+	if len(stats.Errors) > 0 {
+		b.WriteString("\nErrors:\n")
+		for _, e := range stats.Errors {
+			fmt.Fprintf(&b, "- %s\n", e)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nJobs run: %d, failed: %d, duration: %s\n", stats.JobsRun, stats.JobsFailed, stats.Duration.Round(1e6))
+
+	return title, b.String()
+}
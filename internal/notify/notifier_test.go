@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// testStats returns cycle stats with one removal and one error
+func testStats() *jobs.CycleStats {
+	return &jobs.CycleStats{
+		JobsRun:      2,
+		JobsFailed:   1,
+		Duration:     1500 * time.Millisecond,
+		ItemsFound:   map[string]int{"remove_stalled": 3, "remove_slow": 0},
+		ItemsRemoved: map[string]int{"remove_stalled": 2, "remove_slow": 0},
+		Errors:       []string{"remove_slow: timeout"},
+	}
+}
+
+// recordingNotifier records the stats it is sent
+type recordingNotifier struct {
+	sent []*jobs.CycleStats
+	err  error
+}
+
+func (n *recordingNotifier) Name() string { return "recording" }
+
+func (n *recordingNotifier) Notify(ctx context.Context, stats *jobs.CycleStats) error {
+	n.sent = append(n.sent, stats)
+	return n.err
+}
+
+func TestRenderSummary(t *testing.T) {
+	title, body := renderSummary(testStats())
+
+	assert.Equal(t, "go-decluttarr: 2 removed, 1 errors", title)
+	assert.Contains(t, body, "remove_stalled: 2 removed (3 found)")
+	assert.NotContains(t, body, "remove_slow: 0 removed")
+	assert.Contains(t, body, "- remove_slow: timeout")
+	assert.Contains(t, body, "Jobs run: 2, failed: 1, duration: 1.5s")
+}
+
+func TestRegistryNotifyAll(t *testing.T) {
+	r := NewRegistry(config.NotificationsConfig{}, testLogger())
+	assert.Equal(t, 0, r.Len())
+
+	ok := &recordingNotifier{}
+	failing := &recordingNotifier{err: errors.New("boom")}
+	r.Register(failing)
+	r.Register(ok)
+
+	// A failing notifier doesn't stop the others
+	r.NotifyAll(context.Background(), testStats())
+	assert.Len(t, failing.sent, 1)
+	assert.Len(t, ok.sent, 1)
+
+	// Quiet cycles are not sent
+	r.NotifyAll(context.Background(), &jobs.CycleStats{ItemsRemoved: map[string]int{"remove_stalled": 0}})
+	r.NotifyAll(context.Background(), nil)
+	assert.Len(t, ok.sent, 1)
+}
+
+func TestNewRegistryFromConfig(t *testing.T) {
+	r := NewRegistry(config.NotificationsConfig{
+		Pushover: config.PushoverConfig{Enabled: true, Token: "t", User: "u"},
+		Email:    config.EmailConfig{Enabled: true, Host: "smtp.example.com", Port: 587, From: "a@example.com", To: []string{"b@example.com"}},
+	}, testLogger())
+
+	require.Equal(t, 2, r.Len())
+	assert.Equal(t, "pushover", r.notifiers[0].Name())
+	assert.Equal(t, "email", r.notifiers[1].Name())
+}
+
+func TestReportsStrike(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  config.NotifyEvents
+		strikes int
+		want    bool
+	}{
+		{name: "no events", strikes: 2},
+		{name: "near removal at max-1", events: config.NotifyEvents{NearRemoval: true}, strikes: 2, want: true},
+		{name: "near removal below max-1", events: config.NotifyEvents{NearRemoval: true}, strikes: 1},
+		{name: "near removal once removed", events: config.NotifyEvents{NearRemoval: true}, strikes: 3},
+		{name: "approaching below threshold", events: config.NotifyEvents{ApproachingStrikes: 2}, strikes: 1},
+		{name: "approaching at threshold", events: config.NotifyEvents{ApproachingStrikes: 2}, strikes: 2, want: true},
+		{name: "approaching once removed", events: config.NotifyEvents{ApproachingStrikes: 2}, strikes: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := jobs.StruckDownload{Name: "Slow.Show", Job: "remove_slow", Strikes: tt.strikes, MaxStrikes: 3}
+			assert.Equal(t, tt.want, reportsStrike(tt.events, d))
+		})
+	}
+}
+
+func TestRegistryNotifiesNearRemoval(t *testing.T) {
+	r := NewRegistry(config.NotificationsConfig{}, testLogger())
+
+	quiet := &recordingNotifier{}
+	near := &recordingNotifier{}
+	r.Register(quiet)
+	r.register(near, config.NotifyEvents{NearRemoval: true})
+
+	stats := &jobs.CycleStats{
+		ItemsRemoved: map[string]int{"remove_slow": 0},
+		Struck: []jobs.StruckDownload{
+			{DownloadID: "a", Name: "First.Strike", Job: "remove_slow", Strikes: 1, MaxStrikes: 3},
+			{DownloadID: "b", Name: "Last.Chance", Job: "remove_slow", Strikes: 2, MaxStrikes: 3},
+		},
+	}
+	r.NotifyAll(context.Background(), stats)
+
+	assert.Empty(t, quiet.sent, "strikes alone don't notify without strike events")
+	require.Len(t, near.sent, 1)
+	require.Len(t, near.sent[0].Struck, 1)
+	assert.Equal(t, "Last.Chance", near.sent[0].Struck[0].Name)
+	assert.Len(t, stats.Struck, 2, "the cycle's stats are not modified")
+
+	title, body := renderSummary(near.sent[0])
+	assert.Equal(t, "go-decluttarr: 0 removed, 1 nearing removal", title)
+	assert.Contains(t, body, "- Last.Chance (remove_slow): 2 of 3 strikes")
+}
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+	"github.com/jmylchreest/go-decluttarr/pkg/httpclient"
+)
+
+// pushoverAPIURL is the Pushover message endpoint
+const pushoverAPIURL = "https://api.pushover.net/1/messages.json"
+
+// PushoverNotifier sends cycle summaries via Pushover
+type PushoverNotifier struct {
+	apiURL   string
+	token    string
+	user     string
+	priority int
+	retry    time.Duration
+	expire   time.Duration
+	http     *httpclient.Client
+}
+
+// NewPushoverNotifier creates a Pushover notifier
+func NewPushoverNotifier(cfg config.PushoverConfig) *PushoverNotifier {
+	return &PushoverNotifier{
+		apiURL:   pushoverAPIURL,
+		token:    cfg.Token,
+		user:     cfg.User,
+		priority: cfg.Priority,
+		retry:    cfg.Retry,
+		expire:   cfg.Expire,
+		http:     httpclient.New(httpclient.DefaultConfig()),
+	}
+}
+
+// Name returns the notifier name
+func (n *PushoverNotifier) Name() string {
+	return "pushover"
+}
+
+// Notify sends a cycle summary to Pushover
+func (n *PushoverNotifier) Notify(ctx context.Context, stats *jobs.CycleStats) error {
+	title, body := renderSummary(stats)
+
+	data := url.Values{}
+	data.Set("token", n.token)
+	data.Set("user", n.user)
+	data.Set("title", title)
+	data.Set("message", body)
+	data.Set("priority", strconv.Itoa(n.priority))
+	if n.priority == 2 {
+		data.Set("retry", strconv.Itoa(int(n.retry.Seconds())))
+		data.Set("expire", strconv.Itoa(int(n.expire.Seconds())))
+	}
+
+	resp, err := n.http.Post(ctx, n.apiURL, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("pushover returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
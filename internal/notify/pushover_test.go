@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+)
+
+func TestPushoverNotify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "app-token", r.FormValue("token"))
+		assert.Equal(t, "user-key", r.FormValue("user"))
+		assert.Equal(t, "1", r.FormValue("priority"))
+		assert.Equal(t, "go-decluttarr: 2 removed, 1 errors", r.FormValue("title"))
+		assert.Contains(t, r.FormValue("message"), "remove_stalled: 2 removed")
+
+		_, _ = w.Write([]byte(`{"status":1,"request":"abc"}`))
+	}))
+	defer server.Close()
+
+	n := NewPushoverNotifier(config.PushoverConfig{Token: "app-token", User: "user-key", Priority: 1})
+	n.apiURL = server.URL
+
+	require.NoError(t, n.Notify(context.Background(), testStats()))
+}
+
+func TestPushoverNotifyEmergency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "2", r.FormValue("priority"))
+		assert.Equal(t, "60", r.FormValue("retry"))
+		assert.Equal(t, "3600", r.FormValue("expire"))
+
+		_, _ = w.Write([]byte(`{"status":1,"request":"abc","receipt":"xyz"}`))
+	}))
+	defer server.Close()
+
+	n := NewPushoverNotifier(config.PushoverConfig{Token: "app-token", User: "user-key", Priority: 2, Retry: time.Minute, Expire: time.Hour})
+	n.apiURL = server.URL
+
+	require.NoError(t, n.Notify(context.Background(), testStats()))
+}
+
+func TestPushoverNotifyError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"user":"invalid","status":0}`))
+	}))
+	defer server.Close()
+
+	n := NewPushoverNotifier(config.PushoverConfig{Token: "app-token", User: "bad"})
+	n.apiURL = server.URL
+
+	err := n.Notify(context.Background(), testStats())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 400")
+}
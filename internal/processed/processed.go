@@ -0,0 +1,159 @@
+// Package processed remembers the library entries removals tagged with
+// general.processed_tag, so their downloads are skipped for a while and the
+// tag can be taken off again once that has passed
+package processed
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry records when a library entry in an *arr instance was tagged
+type Entry struct {
+	Instance string    `json:"instance"`
+	Kind     string    `json:"kind"` // "series", "movie", "artist" or "author"
+	ID       int       `json:"id"`
+	Tag      string    `json:"tag"` // the label applied, so it can be removed after a config change
+	Tagged   time.Time `json:"tagged"`
+}
+
+// Set holds the tagged library entries, with persistence
+type Set struct {
+	entries     map[string]Entry // key: Key(instance, kind, id)
+	mu          sync.RWMutex
+	persistPath string
+	logger      *slog.Logger
+}
+
+// NewSet creates a new set of tagged entries, loading persisted entries from
+// persistPath if set
+func NewSet(persistPath string, logger *slog.Logger) *Set {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	s := &Set{
+		entries:     make(map[string]Entry),
+		persistPath: persistPath,
+		logger:      logger.With("component", "processed"),
+	}
+
+	if persistPath != "" {
+		if err := s.Load(); err != nil {
+			logger.Warn("failed to load persisted processed entries, starting fresh", "error", err)
+		}
+	}
+
+	return s
+}
+
+// Key identifies a library entry in an *arr instance, e.g. Key("sonarr", "series", 42)
+func Key(instanceName, kind string, id int) string {
+	return fmt.Sprintf("%s/%s/%d", instanceName, kind, id)
+}
+
+// Record marks a library entry as tagged with tag at t
+func (s *Set) Record(instanceName, kind string, id int, tag string, t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[Key(instanceName, kind, id)] = Entry{Instance: instanceName, Kind: kind, ID: id, Tag: tag, Tagged: t}
+}
+
+// Tagged returns when a library entry was tagged, if it has been
+func (s *Set) Tagged(instanceName, kind string, id int) (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.entries[Key(instanceName, kind, id)]
+	return entry.Tagged, ok
+}
+
+// Expired returns the entries tagged at or before cutoff
+func (s *Set) Expired(cutoff time.Time) []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var expired []Entry
+	for _, entry := range s.entries {
+		if !entry.Tagged.After(cutoff) {
+			expired = append(expired, entry)
+		}
+	}
+	return expired
+}
+
+// Forget removes a library entry
+func (s *Set) Forget(instanceName, kind string, id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, Key(instanceName, kind, id))
+}
+
+// Count returns the number of tagged entries
+func (s *Set) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries)
+}
+
+// Save persists the entries to disk
+func (s *Set) Save() error {
+	if s.persistPath == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	s.mu.RUnlock()
+
+	if err != nil {
+		return fmt.Errorf("marshal processed entries: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.persistPath), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	// Write atomically via temp file
+	tmpPath := s.persistPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, s.persistPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	s.logger.Debug("persisted processed entries", "path", s.persistPath, "count", len(s.entries))
+	return nil
+}
+
+// Load restores the entries from disk
+func (s *Set) Load() error {
+	if s.persistPath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.persistPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // No file yet, not an error
+		}
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return fmt.Errorf("unmarshal processed entries: %w", err)
+	}
+
+	s.logger.Debug("loaded persisted processed entries", "path", s.persistPath, "count", len(s.entries))
+	return nil
+}
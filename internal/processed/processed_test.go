@@ -0,0 +1,57 @@
+package processed
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestRecord(t *testing.T) {
+	s := NewSet("", testLogger())
+	now := time.Now()
+
+	_, ok := s.Tagged("sonarr", "series", 42)
+	assert.False(t, ok)
+
+	s.Record("sonarr", "series", 42, "processed", now)
+	tagged, ok := s.Tagged("sonarr", "series", 42)
+	require.True(t, ok)
+	assert.True(t, tagged.Equal(now))
+	_, ok = s.Tagged("sonarr-4k", "series", 42)
+	assert.False(t, ok, "entries are per instance")
+
+	s.Forget("sonarr", "series", 42)
+	assert.Equal(t, 0, s.Count())
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "processed.json")
+	now := time.Now().Truncate(time.Second)
+
+	s := NewSet(path, testLogger())
+	s.Record("sonarr", "series", 42, "processed", now)
+	s.Record("radarr", "movie", 7, "processed", now.Add(-2*time.Hour))
+	require.NoError(t, s.Save())
+
+	loaded := NewSet(path, testLogger())
+	assert.Equal(t, 2, loaded.Count())
+	tagged, ok := loaded.Tagged("sonarr", "series", 42)
+	require.True(t, ok)
+	assert.True(t, tagged.Equal(now))
+
+	expired := loaded.Expired(now.Add(-time.Hour))
+	require.Len(t, expired, 1, "only the entry tagged before the cutoff has expired")
+	assert.Equal(t, "radarr", expired[0].Instance)
+	assert.Equal(t, "movie", expired[0].Kind)
+	assert.Equal(t, 7, expired[0].ID)
+	assert.Equal(t, "processed", expired[0].Tag)
+}
@@ -0,0 +1,167 @@
+// Package searches records when search jobs last searched each item, so an
+// item isn't searched again before the *arr reports the search itself
+package searches
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultMaxAge is how long a search is remembered
+const DefaultMaxAge = 30 * 24 * time.Hour
+
+// History holds the last time each item was searched, with persistence
+type History struct {
+	searched    map[string]time.Time // key: item key, value: last search time
+	mu          sync.RWMutex
+	persistPath string
+	logger      *slog.Logger
+}
+
+// NewHistory creates a new search history, loading persisted searches from
+// persistPath if set
+func NewHistory(persistPath string, logger *slog.Logger) *History {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	h := &History{
+		searched:    make(map[string]time.Time),
+		persistPath: persistPath,
+		logger:      logger.With("component", "searches"),
+	}
+
+	if persistPath != "" {
+		if err := h.Load(); err != nil {
+			logger.Warn("failed to load persisted search history, starting fresh", "error", err)
+		}
+	}
+
+	return h
+}
+
+// Key identifies an item in an *arr instance, e.g. Key("sonarr", "episode", 42)
+func Key(instanceName, kind string, id int) string {
+	return fmt.Sprintf("%s/%s/%d", instanceName, kind, id)
+}
+
+// Record marks an item as searched at t
+func (h *History) Record(key string, t time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.searched[key] = t
+}
+
+// LastSearched returns when an item was last searched, if it has been
+func (h *History) LastSearched(key string) (time.Time, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	t, ok := h.searched[key]
+	return t, ok
+}
+
+// Latest returns the later of the *arr-reported search time and the recorded
+// one for key, or nil if neither is known. The *arr only updates its time once
+// the search command completes, so the recorded time covers the gap.
+func (h *History) Latest(key string, arrTime *time.Time) *time.Time {
+	recorded, ok := h.LastSearched(key)
+	if !ok {
+		return arrTime
+	}
+	if arrTime != nil && arrTime.After(recorded) {
+		return arrTime
+	}
+	return &recorded
+}
+
+// Count returns the number of items with a recorded search
+func (h *History) Count() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.searched)
+}
+
+// Save persists the search history to disk
+func (h *History) Save() error {
+	if h.persistPath == "" {
+		return nil
+	}
+
+	h.mu.RLock()
+	data, err := json.MarshalIndent(h.searched, "", "  ")
+	h.mu.RUnlock()
+
+	if err != nil {
+		return fmt.Errorf("marshal search history: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.persistPath), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	// Write atomically via temp file
+	tmpPath := h.persistPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, h.persistPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	h.logger.Debug("persisted search history", "path", h.persistPath, "count", len(h.searched))
+	return nil
+}
+
+// Load restores the search history from disk
+func (h *History) Load() error {
+	if h.persistPath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(h.persistPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // No file yet, not an error
+		}
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := json.Unmarshal(data, &h.searched); err != nil {
+		return fmt.Errorf("unmarshal search history: %w", err)
+	}
+
+	h.logger.Debug("loaded persisted search history", "path", h.persistPath, "count", len(h.searched))
+	return nil
+}
+
+// Cleanup forgets searches made longer ago than maxAge
+func (h *History) Cleanup(maxAge time.Duration) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+
+	for key, searched := range h.searched {
+		if searched.Before(cutoff) {
+			delete(h.searched, key)
+			removed++
+		}
+	}
+
+	if removed > 0 {
+		h.logger.Debug("cleaned up old searches", "removed", removed, "max_age", maxAge)
+	}
+
+	return removed
+}
@@ -0,0 +1,54 @@
+package searches
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestLatest(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	later := now.Add(time.Hour)
+
+	h := NewHistory("", testLogger())
+	key := Key("radarr", "movie", 1)
+
+	assert.Nil(t, h.Latest(key, nil), "never searched")
+	assert.Equal(t, &earlier, h.Latest(key, &earlier), "only the arr time is known")
+
+	h.Record(key, now)
+	assert.True(t, h.Latest(key, nil).Equal(now), "only the recorded time is known")
+	assert.True(t, h.Latest(key, &earlier).Equal(now), "recorded search is more recent than the arr's")
+	assert.True(t, h.Latest(key, &later).Equal(later), "arr search is more recent than the recorded one")
+	assert.Nil(t, h.Latest(Key("sonarr", "movie", 1), nil), "keys are per instance")
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "searches.json")
+	searched := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	h := NewHistory(path, testLogger())
+	h.Record(Key("sonarr", "episode", 42), searched)
+	h.Record(Key("radarr", "movie", 7), time.Now().Add(-2*DefaultMaxAge))
+	require.NoError(t, h.Save())
+
+	loaded := NewHistory(path, testLogger())
+	assert.Equal(t, 2, loaded.Count())
+	got, ok := loaded.LastSearched(Key("sonarr", "episode", 42))
+	require.True(t, ok)
+	assert.True(t, got.Equal(searched))
+
+	assert.Equal(t, 1, loaded.Cleanup(DefaultMaxAge))
+	_, ok = loaded.LastSearched(Key("radarr", "movie", 7))
+	assert.False(t, ok, "old searches are forgotten")
+}
@@ -0,0 +1,294 @@
+// Package server provides the built-in HTTP server used for health checks and
+// cycle statistics
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+	"github.com/jmylchreest/go-decluttarr/internal/strikes"
+	"github.com/jmylchreest/go-decluttarr/internal/version"
+)
+
+// Server serves health and status endpoints for a running instance
+type Server struct {
+	httpServer *http.Server
+	manager    *jobs.Manager
+	logger     *slog.Logger
+	started    time.Time
+	runToken   string
+	startRun   func() bool
+}
+
+// HealthResponse is the body returned by /healthz
+type HealthResponse struct {
+	Status    string     `json:"status"`
+	Version   string     `json:"version"`
+	Uptime    string     `json:"uptime"`
+	LastCycle *time.Time `json:"last_cycle,omitempty"`
+}
+
+// CycleStatsResponse is the JSON representation of a cycle's statistics
+type CycleStatsResponse struct {
+	StartTime        time.Time      `json:"start_time"`
+	EndTime          time.Time      `json:"end_time"`
+	Duration         string         `json:"duration"`
+	JobsRun          int            `json:"jobs_run"`
+	JobsFailed       int            `json:"jobs_failed"`
+	ItemsFound       map[string]int `json:"items_found"`
+	ItemsRemoved     map[string]int `json:"items_removed"`
+	StrikesAdded     int            `json:"strikes_added"`
+	StrikesReset     int            `json:"strikes_reset"`
+	StrikesRecovered int            `json:"strikes_recovered"`
+	TotalStrikes     int            `json:"total_strikes"`
+	Errors           []string       `json:"errors"`
+}
+
+// WhatIfResponse is the body returned by /whatif
+type WhatIfResponse struct {
+	DownloadID  string          `json:"download_id"`
+	Instances   []string        `json:"instances"`
+	Detected    map[string]bool `json:"detected"`
+	Strikes     int             `json:"strikes"`
+	InClient    bool            `json:"in_client"`
+	TrackerType string          `json:"tracker_type"`
+	Protected   bool            `json:"protected"`
+	Action      string          `json:"action"`
+}
+
+// RunResponse is the body returned by POST /run
+type RunResponse struct {
+	Status string `json:"status"`
+}
+
+// ErrorResponse is the body returned for failed requests
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// newCycleStatsResponse converts manager stats into their JSON representation
+func newCycleStatsResponse(stats *jobs.CycleStats) CycleStatsResponse {
+	return CycleStatsResponse{
+		StartTime:        stats.StartTime,
+		EndTime:          stats.EndTime,
+		Duration:         stats.Duration.String(),
+		JobsRun:          stats.JobsRun,
+		JobsFailed:       stats.JobsFailed,
+		ItemsFound:       stats.ItemsFound,
+		ItemsRemoved:     stats.ItemsRemoved,
+		StrikesAdded:     stats.StrikesAdded,
+		StrikesReset:     stats.StrikesReset,
+		StrikesRecovered: stats.StrikesRecovered,
+		TotalStrikes:     stats.TotalStrikes,
+		Errors:           stats.Errors,
+	}
+}
+
+// New creates a server listening on addr (e.g. ":9595")
+func New(addr string, manager *jobs.Manager, logger *slog.Logger) *Server {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	s := &Server{
+		manager: manager,
+		logger:  logger.With("component", "http_server"),
+		started: time.Now(),
+	}
+
+	s.httpServer = &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return s
+}
+
+// Handler returns the HTTP handler with all routes registered
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /stats", s.handleStats)
+	mux.HandleFunc("GET /stats/history", s.handleStatsHistory)
+	mux.HandleFunc("GET /strikes", s.handleStrikes)
+	mux.HandleFunc("GET /whatif", s.handleWhatIf)
+	mux.HandleFunc("POST /run", s.handleRun)
+	return mux
+}
+
+// EnableRun enables POST /run, which calls start to begin a cycle in the
+// background. Requests must send token as a bearer token. start returns false
+// when a cycle is already running.
+func (s *Server) EnableRun(token string, start func() bool) {
+	s.runToken = token
+	s.startRun = start
+}
+
+// Start begins listening in the background. Listen errors (e.g. port in use)
+// are returned immediately.
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.httpServer.Addr, err)
+	}
+
+	s.logger.Info("http server listening", "address", ln.Addr().String())
+
+	go func() {
+		if err := s.httpServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("http server stopped", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown gracefully stops the server
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	resp := HealthResponse{
+		Status:  "ok",
+		Version: version.String(),
+		Uptime:  time.Since(s.started).Round(time.Second).String(),
+	}
+
+	if s.manager != nil {
+		if stats := s.manager.GetLastStats(); stats != nil {
+			resp.LastCycle = &stats.EndTime
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	var stats *jobs.CycleStats
+	if s.manager != nil {
+		stats = s.manager.GetLastStats()
+	}
+	if stats == nil {
+		writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "no cycle has completed yet"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newCycleStatsResponse(stats))
+}
+
+func (s *Server) handleStatsHistory(w http.ResponseWriter, r *http.Request) {
+	resp := make([]CycleStatsResponse, 0)
+	if s.manager != nil {
+		for _, stats := range s.manager.GetStatsHistory() {
+			resp = append(resp, newCycleStatsResponse(stats))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleStrikes returns the current strike records keyed by download ID
+func (s *Server) handleStrikes(w http.ResponseWriter, r *http.Request) {
+	resp := make(map[string]*strikes.StrikeRecord)
+	if s.manager != nil {
+		resp = s.manager.GetStrikesHandler().GetAllRecords()
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleWhatIf reports how the jobs would treat the download given by the
+// download_id query parameter, without changing anything
+func (s *Server) handleWhatIf(w http.ResponseWriter, r *http.Request) {
+	downloadID := r.URL.Query().Get("download_id")
+	if downloadID == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "download_id is required"})
+		return
+	}
+	if s.manager == nil {
+		writeJSON(w, http.StatusServiceUnavailable, ErrorResponse{Error: "no job manager"})
+		return
+	}
+
+	report := s.manager.WhatIf(r.Context(), downloadID)
+	if !report.Found() {
+		writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "download not found in any queue or download client"})
+		return
+	}
+
+	instances := report.Instances
+	if instances == nil {
+		instances = []string{}
+	}
+	writeJSON(w, http.StatusOK, WhatIfResponse{
+		DownloadID:  report.DownloadID,
+		Instances:   instances,
+		Detected:    report.Detected,
+		Strikes:     report.Strikes,
+		InClient:    report.InClient,
+		TrackerType: report.TrackerType,
+		Protected:   report.Protected,
+		Action:      report.Action,
+	})
+}
+
+// handleRun starts a cycle out of band
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	if s.runToken == "" || s.startRun == nil {
+		writeJSON(w, http.StatusForbidden, ErrorResponse{Error: "run endpoint is disabled; set server.api_token to enable it"})
+		return
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.runToken)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, ErrorResponse{Error: "invalid or missing API token"})
+		return
+	}
+
+	if !s.startRun() {
+		writeJSON(w, http.StatusConflict, ErrorResponse{Error: "a cycle is already running"})
+		return
+	}
+
+	s.logger.Info("cycle triggered via http")
+	writeJSON(w, http.StatusAccepted, RunResponse{Status: "started"})
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// Check performs a health check against a running instance's /healthz URL,
+// returning an error unless it responds with 200 OK
+func Check(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("health check request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,288 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jmylchreest/go-decluttarr/internal/arrapi"
+	"github.com/jmylchreest/go-decluttarr/internal/config"
+	"github.com/jmylchreest/go-decluttarr/internal/downloadclient"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs"
+	"github.com/jmylchreest/go-decluttarr/internal/jobs/removal"
+	"github.com/jmylchreest/go-decluttarr/internal/strikes"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// newTestServer serves the server's handler from an httptest server
+func newTestServer(t *testing.T) (*jobs.Manager, *httptest.Server) {
+	t.Helper()
+
+	m := jobs.NewManager(&config.Config{}, testLogger(), "")
+	t.Cleanup(m.Close)
+
+	s := New(":0", m, testLogger())
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+
+	return m, ts
+}
+
+// getJSON fetches url and decodes the JSON response into v
+func getJSON(t *testing.T, url string, v interface{}) int {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	return resp.StatusCode
+}
+
+func TestHealthz(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/healthz")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var body HealthResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "ok", body.Status)
+	assert.Nil(t, body.LastCycle)
+}
+
+func TestCheck(t *testing.T) {
+	t.Run("healthy instance", func(t *testing.T) {
+		_, ts := newTestServer(t)
+		assert.NoError(t, Check(context.Background(), ts.URL+"/healthz"))
+	})
+
+	t.Run("unhealthy status", func(t *testing.T) {
+		stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer stub.Close()
+
+		err := Check(context.Background(), stub.URL+"/healthz")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "503")
+	})
+
+	t.Run("nothing listening", func(t *testing.T) {
+		stub := httptest.NewServer(http.NotFoundHandler())
+		url := stub.URL + "/healthz"
+		stub.Close()
+
+		assert.Error(t, Check(context.Background(), url))
+	})
+}
+
+func TestStartAndShutdown(t *testing.T) {
+	s := New("127.0.0.1:0", nil, testLogger())
+	require.NoError(t, s.Start())
+	require.NoError(t, s.Shutdown(context.Background()))
+}
+
+func TestStats(t *testing.T) {
+	m, ts := newTestServer(t)
+
+	var errResp ErrorResponse
+	assert.Equal(t, http.StatusNotFound, getJSON(t, ts.URL+"/stats", &errResp))
+	assert.NotEmpty(t, errResp.Error)
+
+	require.NoError(t, m.RunAll(context.Background()))
+
+	var stats CycleStatsResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, ts.URL+"/stats", &stats))
+	assert.False(t, stats.EndTime.IsZero())
+	assert.NotEmpty(t, stats.Duration)
+}
+
+func TestStatsHistory(t *testing.T) {
+	m, ts := newTestServer(t)
+
+	var history []CycleStatsResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, ts.URL+"/stats/history", &history))
+	assert.Empty(t, history)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, m.RunAll(context.Background()))
+	}
+
+	assert.Equal(t, http.StatusOK, getJSON(t, ts.URL+"/stats/history", &history))
+	require.Len(t, history, 3)
+	assert.False(t, history[0].StartTime.After(history[2].StartTime), "history should be oldest first")
+}
+
+func TestStrikes(t *testing.T) {
+	m, ts := newTestServer(t)
+
+	var records map[string]strikes.StrikeRecord
+	assert.Equal(t, http.StatusOK, getJSON(t, ts.URL+"/strikes", &records))
+	assert.Empty(t, records)
+
+	m.GetStrikesHandler().Add("abc", "remove_stalled", "Stalled.Item", "download stalled")
+
+	assert.Equal(t, http.StatusOK, getJSON(t, ts.URL+"/strikes", &records))
+	require.Contains(t, records, "abc")
+	assert.Equal(t, 1, records["abc"].Count)
+	assert.Equal(t, "download stalled", records["abc"].LastReason)
+	require.Len(t, records["abc"].History, 1)
+	assert.Equal(t, "remove_stalled", records["abc"].History[0].Job)
+}
+
+// whatIfClient is a download client holding private torrents
+type whatIfClient struct {
+	downloadclient.Client
+	torrents []downloadclient.Torrent
+}
+
+func (c *whatIfClient) GetTorrents(ctx context.Context) ([]downloadclient.Torrent, error) {
+	return c.torrents, nil
+}
+
+func (c *whatIfClient) GetTorrent(ctx context.Context, hash string) (*downloadclient.Torrent, error) {
+	for _, torrent := range c.torrents {
+		if strings.EqualFold(hash, torrent.Hash) {
+			return &torrent, nil
+		}
+	}
+	return nil, fmt.Errorf("torrent not found: %s", hash)
+}
+
+func (c *whatIfClient) IsPrivateTracker(ctx context.Context, hash string) (bool, error) {
+	return true, nil
+}
+
+func TestWhatIf(t *testing.T) {
+	queue := []arrapi.QueueItem{
+		{ID: 1, Title: "Stalled.Item", DownloadID: "ABC123", Status: "stalled"},
+		{ID: 2, Title: "Other.Item", DownloadID: "other", Status: "downloading"},
+		// The *arr reports a stall the client doesn't, so a run leaves it be
+		{ID: 3, Title: "Moving.Item", DownloadID: "DEF456", Status: "stalled"},
+	}
+	arr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(arrapi.QueueResponse{Page: 1, PageSize: 1000, TotalRecords: len(queue), Records: queue})
+	}))
+	defer arr.Close()
+
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			PrivateTrackerHandling: "obsolete_tag",
+			PublicTrackerHandling:  "remove",
+			ObsoleteTag:            "Obsolete",
+		},
+		JobDefaults: config.JobDefaultsConfig{MaxStrikes: 3},
+	}
+	m := jobs.NewManager(cfg, testLogger(), "")
+	defer m.Close()
+	m.RegisterArrClient("sonarr", arrapi.NewClient(arrapi.ClientConfig{Name: "sonarr", BaseURL: arr.URL, APIKey: "test", Logger: testLogger()}))
+	m.RegisterDownloadClient("qbittorrent", &whatIfClient{torrents: []downloadclient.Torrent{
+		{Hash: "abc123", State: downloadclient.StateStalled},
+		{Hash: "def456", State: downloadclient.StateDownloading},
+	}})
+
+	stalled := removal.NewStalledJob("remove_stalled", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), true)
+	failed := removal.NewFailedDownloadsJob("remove_failed_downloads", &config.JobConfig{Enabled: true}, &cfg.JobDefaults, m, testLogger(), true)
+	m.RegisterJob(stalled)
+	m.RegisterJob(failed)
+	m.GetStrikesHandler().Add("ABC123", "remove_stalled", "Stalled.Item", "download stalled")
+
+	ts := httptest.NewServer(New(":0", m, testLogger()).Handler())
+	defer ts.Close()
+
+	var resp, moving WhatIfResponse
+	require.Equal(t, http.StatusOK, getJSON(t, ts.URL+"/whatif?download_id=ABC123", &resp))
+	require.Equal(t, http.StatusOK, getJSON(t, ts.URL+"/whatif?download_id=DEF456", &moving))
+	assert.Equal(t, map[string]bool{"remove_stalled": true, "remove_failed_downloads": false}, resp.Detected)
+	assert.Equal(t, map[string]bool{"remove_stalled": false, "remove_failed_downloads": false}, moving.Detected)
+	assert.Equal(t, []string{"sonarr"}, resp.Instances)
+	assert.Equal(t, 1, resp.Strikes)
+	assert.True(t, resp.InClient)
+	assert.Equal(t, jobs.TrackerPrivate, resp.TrackerType)
+	assert.False(t, resp.Protected)
+	assert.Equal(t, m.GetRemovalAction(context.Background(), "ABC123"), resp.Action)
+	assert.Equal(t, "tag", resp.Action)
+	assert.Equal(t, 1, m.GetStrikesHandler().Get("ABC123"), "what-if must not strike")
+
+	// The report matches the decision of a test run
+	require.NoError(t, stalled.Run(context.Background()))
+	assert.Equal(t, 2, m.GetStrikesHandler().Get("ABC123"), "detected downloads are struck")
+	assert.Equal(t, 0, m.GetStrikesHandler().Get("DEF456"), "downloads not detected aren't struck")
+
+	var errResp ErrorResponse
+	assert.Equal(t, http.StatusBadRequest, getJSON(t, ts.URL+"/whatif", &errResp))
+	assert.Equal(t, http.StatusNotFound, getJSON(t, ts.URL+"/whatif?download_id=missing", &errResp))
+}
+
+func TestRun(t *testing.T) {
+	m := jobs.NewManager(&config.Config{}, testLogger(), "")
+	t.Cleanup(m.Close)
+
+	// A cycle stays running until release is closed
+	var mu sync.Mutex
+	started := 0
+	release := make(chan struct{})
+	start := func() bool {
+		if !mu.TryLock() {
+			return false
+		}
+		started++
+		go func() {
+			<-release
+			mu.Unlock()
+		}()
+		return true
+	}
+
+	s := New(":0", m, testLogger())
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+
+	post := func(token string) int {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/run", nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusForbidden, post("secret"), "disabled without a token")
+
+	s.EnableRun("secret", start)
+	assert.Equal(t, http.StatusUnauthorized, post(""))
+	assert.Equal(t, http.StatusUnauthorized, post("wrong"))
+	assert.Zero(t, started)
+
+	assert.Equal(t, http.StatusAccepted, post("secret"))
+	assert.Equal(t, http.StatusConflict, post("secret"), "a concurrent run is rejected")
+	close(release)
+
+	assert.Eventually(t, func() bool { return post("secret") == http.StatusAccepted }, time.Second, 10*time.Millisecond,
+		"a run can start once the previous one finishes")
+	assert.Equal(t, 2, started)
+}
@@ -10,23 +10,51 @@ import (
 	"time"
 )
 
+// MaxHistory is the number of strike events kept per record
+const MaxHistory = 10
+
+// DefaultMaxAge is how long a strike record is kept after it was last seen
+const DefaultMaxAge = 7 * 24 * time.Hour
+
+// SchemaVersion is the version of the strikes file format written by Save.
+// Version 0 files are a bare map of download ID to record, written before
+// the file was versioned; they may lack the reason and history fields.
+const SchemaVersion = 1
+
+// strikesFile is the persisted strikes file
+type strikesFile struct {
+	Version int                      `json:"version"`
+	Strikes map[string]*StrikeRecord `json:"strikes"`
+}
+
 // StrikeRecord holds strike info with metadata
 type StrikeRecord struct {
-	Count     int       `json:"count"`
-	FirstSeen time.Time `json:"first_seen"`
-	LastSeen  time.Time `json:"last_seen"`
-	Job       string    `json:"job"`
-	Name      string    `json:"name,omitempty"`
+	Count      int           `json:"count"`
+	FirstSeen  time.Time     `json:"first_seen"`
+	LastSeen   time.Time     `json:"last_seen"`
+	Job        string        `json:"job"`
+	Name       string        `json:"name,omitempty"`
+	LastReason string        `json:"last_reason,omitempty"`
+	History    []StrikeEvent `json:"history,omitempty"` // oldest first, capped at MaxHistory
+}
+
+// StrikeEvent records a single strike being added
+type StrikeEvent struct {
+	Time   time.Time `json:"time"`
+	Job    string    `json:"job"`
+	Reason string    `json:"reason,omitempty"`
 }
 
 // Handler manages strike tracking for download items with persistence
 type Handler struct {
-	strikes      map[string]*StrikeRecord // key: downloadID, value: strike record
-	mu           sync.RWMutex
-	persistPath  string
-	logger       *slog.Logger
-	strikesAdded int // count for current cycle
-	strikesReset int // count for current cycle
+	strikes          map[string]*StrikeRecord // key: downloadID, value: strike record
+	mu               sync.RWMutex
+	persistPath      string
+	memoryOnly       bool // the persist path isn't writable, so strikes aren't saved
+	logger           *slog.Logger
+	strikesAdded     int // count for current cycle
+	strikesReset     int // count for current cycle
+	strikesRecovered int // count for current cycle, subset of strikesReset
 }
 
 // NewHandler creates a new strikes handler
@@ -46,18 +74,47 @@ func NewHandler(persistPath string, logger *slog.Logger) *Handler {
 		if err := h.Load(); err != nil {
 			logger.Warn("failed to load persisted strikes, starting fresh", "error", err)
 		}
+
+		// Warn once here rather than failing every save, e.g. on a read-only mount
+		if err := probeWritable(filepath.Dir(persistPath)); err != nil {
+			h.memoryOnly = true
+			h.logger.Warn("STRIKES WILL NOT BE SAVED: data directory is not writable, "+
+				"keeping strikes in memory only; they are lost on restart",
+				"path", persistPath,
+				"error", err)
+		}
 	}
 
 	return h
 }
 
-// Add increments the strike count for a download ID
-func (h *Handler) Add(downloadID, job, name string) int {
+// probeWritable checks that files can be created in dir, creating it if needed
+func probeWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".write-probe-*")
+	if err != nil {
+		return err
+	}
+	_ = f.Close()
+	return os.Remove(f.Name())
+}
+
+// MemoryOnly reports whether strikes are kept in memory only because the
+// persist path isn't writable
+func (h *Handler) MemoryOnly() bool {
+	return h.memoryOnly
+}
+
+// Add increments the strike count for a download ID, recording why the strike was added
+func (h *Handler) Add(downloadID, job, name, reason string) int {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	now := time.Now()
-	if record, exists := h.strikes[downloadID]; exists {
+	record, exists := h.strikes[downloadID]
+	if exists {
 		record.Count++
 		record.LastSeen = now
 		record.Job = job
@@ -65,17 +122,24 @@ func (h *Handler) Add(downloadID, job, name string) int {
 			record.Name = name
 		}
 	} else {
-		h.strikes[downloadID] = &StrikeRecord{
+		record = &StrikeRecord{
 			Count:     1,
 			FirstSeen: now,
 			LastSeen:  now,
 			Job:       job,
 			Name:      name,
 		}
+		h.strikes[downloadID] = record
+	}
+
+	record.LastReason = reason
+	record.History = append(record.History, StrikeEvent{Time: now, Job: job, Reason: reason})
+	if len(record.History) > MaxHistory {
+		record.History = record.History[len(record.History)-MaxHistory:]
 	}
 
 	h.strikesAdded++
-	return h.strikes[downloadID].Count
+	return record.Count
 }
 
 // Get returns the current strike count for a download ID
@@ -98,9 +162,14 @@ func (h *Handler) GetRecord(downloadID string) (*StrikeRecord, bool) {
 	if !exists {
 		return nil, false
 	}
-	// Return a copy
-	copy := *record
-	return &copy, true
+	return record.clone(), true
+}
+
+// clone returns a deep copy of the record
+func (r *StrikeRecord) clone() *StrikeRecord {
+	c := *r
+	c.History = append([]StrikeEvent(nil), r.History...)
+	return &c
 }
 
 // Reset clears the strike count for a download ID
@@ -114,6 +183,23 @@ func (h *Handler) Reset(downloadID string) {
 	}
 }
 
+// Recover clears the strikes for a download that recovered on its own rather
+// than being removed. Recoveries are counted separately to help tune max_strikes.
+func (h *Handler) Recover(downloadID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if record, exists := h.strikes[downloadID]; exists {
+		delete(h.strikes, downloadID)
+		h.strikesReset++
+		h.strikesRecovered++
+		h.logger.Debug("download recovered, strikes cleared",
+			"download_id", downloadID,
+			"strikes", record.Count,
+			"job", record.Job)
+	}
+}
+
 // Clear removes all strike records
 func (h *Handler) Clear() {
 	h.mu.Lock()
@@ -141,32 +227,35 @@ func (h *Handler) GetAllRecords() map[string]*StrikeRecord {
 
 	result := make(map[string]*StrikeRecord, len(h.strikes))
 	for k, v := range h.strikes {
-		copy := *v
-		result[k] = &copy
+		result[k] = v.clone()
 	}
 	return result
 }
 
-// ResetCycleCounters resets the per-cycle counters and returns previous values
-func (h *Handler) ResetCycleCounters() (added, reset int) {
+// ResetCycleCounters resets the per-cycle counters and returns previous values.
+// recovered counts the resets that came from Recover.
+func (h *Handler) ResetCycleCounters() (added, reset, recovered int) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	added = h.strikesAdded
 	reset = h.strikesReset
+	recovered = h.strikesRecovered
 	h.strikesAdded = 0
 	h.strikesReset = 0
+	h.strikesRecovered = 0
 	return
 }
 
-// Save persists strikes to disk
+// Save persists strikes to disk. It does nothing when the handler is in
+// memory-only mode.
 func (h *Handler) Save() error {
-	if h.persistPath == "" {
+	if h.persistPath == "" || h.memoryOnly {
 		return nil
 	}
 
 	h.mu.RLock()
-	data, err := json.MarshalIndent(h.strikes, "", "  ")
+	data, err := json.MarshalIndent(strikesFile{Version: SchemaVersion, Strikes: h.strikes}, "", "  ")
 	h.mu.RUnlock()
 
 	if err != nil {
@@ -194,7 +283,8 @@ func (h *Handler) Save() error {
 	return nil
 }
 
-// Load restores strikes from disk
+// Load restores strikes from disk, migrating files written in an older
+// format. Files from a newer version than SchemaVersion aren't loaded.
 func (h *Handler) Load() error {
 	if h.persistPath == "" {
 		return nil
@@ -208,17 +298,80 @@ func (h *Handler) Load() error {
 		return fmt.Errorf("read file: %w", err)
 	}
 
+	records, version, err := decodeStrikes(data)
+	if err != nil {
+		return err
+	}
+	if version > SchemaVersion {
+		return fmt.Errorf("strikes file version %d is newer than supported version %d", version, SchemaVersion)
+	}
+	if version < SchemaVersion {
+		migrate(records)
+		h.logger.Info("migrated strikes file",
+			"path", h.persistPath,
+			"from_version", version,
+			"to_version", SchemaVersion,
+			"count", len(records))
+	}
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	if err := json.Unmarshal(data, &h.strikes); err != nil {
-		return fmt.Errorf("unmarshal strikes: %w", err)
-	}
-
+	h.strikes = records
 	h.logger.Debug("loaded persisted strikes", "path", h.persistPath, "count", len(h.strikes))
 	return nil
 }
 
+// decodeStrikes decodes a strikes file of any version, returning its records
+// and version
+func decodeStrikes(data []byte) (map[string]*StrikeRecord, int, error) {
+	var probe struct {
+		Version *int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, 0, fmt.Errorf("unmarshal strikes: %w", err)
+	}
+
+	records := make(map[string]*StrikeRecord)
+	if probe.Version == nil {
+		// Version 0: the records themselves, unwrapped
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, 0, fmt.Errorf("unmarshal strikes: %w", err)
+		}
+		return records, 0, nil
+	}
+
+	file := strikesFile{Strikes: records}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, 0, fmt.Errorf("unmarshal strikes: %w", err)
+	}
+	if file.Strikes == nil {
+		file.Strikes = records
+	}
+	return file.Strikes, file.Version, nil
+}
+
+// migrate upgrades records from an older version in place, filling in the
+// fields they predate from what they do have
+func migrate(records map[string]*StrikeRecord) {
+	for id, record := range records {
+		if record == nil {
+			delete(records, id)
+			continue
+		}
+		if record.Count < 1 {
+			record.Count = 1
+		}
+		if record.FirstSeen.IsZero() {
+			record.FirstSeen = record.LastSeen
+		}
+		if len(record.History) == 0 {
+			// The strike that made it last seen is the only one on record
+			record.History = []StrikeEvent{{Time: record.LastSeen, Job: record.Job, Reason: record.LastReason}}
+		}
+	}
+}
+
 // Cleanup removes stale strikes not seen in the given duration
 func (h *Handler) Cleanup(maxAge time.Duration) int {
 	h.mu.Lock()
@@ -240,3 +393,23 @@ func (h *Handler) Cleanup(maxAge time.Duration) int {
 
 	return removed
 }
+
+// Purge removes records not seen within maxAge from the strikes file at path,
+// returning the number removed and remaining. A missing file is not an error.
+func Purge(path string, maxAge time.Duration, logger *slog.Logger) (removed, remaining int, err error) {
+	// Not NewHandler: a file that fails to load must not be overwritten
+	h := NewHandler("", logger)
+	h.persistPath = path
+	if err := h.Load(); err != nil {
+		return 0, 0, fmt.Errorf("load strikes: %w", err)
+	}
+
+	removed = h.Cleanup(maxAge)
+	if removed > 0 {
+		if err := h.Save(); err != nil {
+			return 0, 0, fmt.Errorf("save strikes: %w", err)
+		}
+	}
+
+	return removed, h.Count(), nil
+}
@@ -1,10 +1,13 @@
 package strikes
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -86,7 +89,7 @@ func TestAdd(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			count := h.Add(tt.downloadID, tt.job, tt.itemName)
+			count := h.Add(tt.downloadID, tt.job, tt.itemName, "")
 			if count != tt.wantCount {
 				t.Errorf("expected count %d, got %d", tt.wantCount, count)
 			}
@@ -103,9 +106,9 @@ func TestGet(t *testing.T) {
 	h := NewHandler("", slog.New(slog.NewTextHandler(os.Stderr, nil)))
 
 	// Add some strikes
-	h.Add("dl1", "job1", "item1")
-	h.Add("dl1", "job1", "item1")
-	h.Add("dl2", "job2", "item2")
+	h.Add("dl1", "job1", "item1", "")
+	h.Add("dl1", "job1", "item1", "")
+	h.Add("dl2", "job2", "item2", "")
 
 	tests := []struct {
 		name       string
@@ -143,9 +146,9 @@ func TestHasExceeded(t *testing.T) {
 	h := NewHandler("", slog.New(slog.NewTextHandler(os.Stderr, nil)))
 
 	// Add strikes
-	h.Add("dl1", "job1", "item1") // 1
-	h.Add("dl1", "job1", "item1") // 2
-	h.Add("dl1", "job1", "item1") // 3
+	h.Add("dl1", "job1", "item1", "") // 1
+	h.Add("dl1", "job1", "item1", "") // 2
+	h.Add("dl1", "job1", "item1", "") // 3
 
 	tests := []struct {
 		name        string
@@ -193,9 +196,9 @@ func TestReset(t *testing.T) {
 	h := NewHandler("", slog.New(slog.NewTextHandler(os.Stderr, nil)))
 
 	// Add strikes
-	h.Add("dl1", "job1", "item1")
-	h.Add("dl1", "job1", "item1")
-	h.Add("dl2", "job2", "item2")
+	h.Add("dl1", "job1", "item1", "")
+	h.Add("dl1", "job1", "item1", "")
+	h.Add("dl2", "job2", "item2", "")
 
 	// Reset dl1
 	h.Reset("dl1")
@@ -275,9 +278,9 @@ func TestSaveLoad(t *testing.T) {
 	h1 := NewHandler(persistPath, slog.New(slog.NewTextHandler(os.Stderr, nil)))
 
 	// Add strikes
-	h1.Add("dl1", "job1", "item1")
-	h1.Add("dl1", "job1", "item1")
-	h1.Add("dl2", "job2", "item2")
+	h1.Add("dl1", "job1", "item1", "")
+	h1.Add("dl1", "job1", "item1", "")
+	h1.Add("dl2", "job2", "item2", "")
 
 	// Save
 	if err := h1.Save(); err != nil {
@@ -349,7 +352,7 @@ func TestSaveLoadCorrupt(t *testing.T) {
 	}
 
 	// Should still be able to add strikes
-	h.Add("dl1", "job1", "item1")
+	h.Add("dl1", "job1", "item1", "")
 	if h.Get("dl1") != 1 {
 		t.Errorf("expected to be able to add strikes after corrupt load")
 	}
@@ -359,14 +362,14 @@ func TestResetCycleCounters(t *testing.T) {
 	h := NewHandler("", slog.New(slog.NewTextHandler(os.Stderr, nil)))
 
 	// Add and reset strikes
-	h.Add("dl1", "job1", "item1")
-	h.Add("dl2", "job2", "item2")
-	h.Add("dl3", "job3", "item3")
+	h.Add("dl1", "job1", "item1", "")
+	h.Add("dl2", "job2", "item2", "")
+	h.Add("dl3", "job3", "item3", "")
 	h.Reset("dl1")
 	h.Reset("dl2")
 
 	// Get cycle counters
-	added, reset := h.ResetCycleCounters()
+	added, reset, _ := h.ResetCycleCounters()
 
 	if added != 3 {
 		t.Errorf("expected added 3, got %d", added)
@@ -384,8 +387,8 @@ func TestResetCycleCounters(t *testing.T) {
 	}
 
 	// Add more strikes
-	h.Add("dl4", "job4", "item4")
-	added, reset = h.ResetCycleCounters()
+	h.Add("dl4", "job4", "item4", "")
+	added, reset, _ = h.ResetCycleCounters()
 
 	if added != 1 {
 		t.Errorf("expected added 1 in new cycle, got %d", added)
@@ -395,6 +398,78 @@ func TestResetCycleCounters(t *testing.T) {
 	}
 }
 
+func TestStrikeReasonAndHistory(t *testing.T) {
+	h := NewHandler("", slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	h.Add("dl1", "remove_stalled", "item1", "download stalled")
+	h.Add("dl1", "remove_slow", "item1", "download speed 10 B/s below minimum 100 B/s")
+
+	record, ok := h.GetRecord("dl1")
+	if !ok {
+		t.Fatal("expected record to exist")
+	}
+	if record.LastReason != "download speed 10 B/s below minimum 100 B/s" {
+		t.Errorf("unexpected last reason %q", record.LastReason)
+	}
+	if len(record.History) != 2 {
+		t.Fatalf("expected 2 history events, got %d", len(record.History))
+	}
+	if record.History[0].Job != "remove_stalled" || record.History[0].Reason != "download stalled" {
+		t.Errorf("unexpected first event %+v", record.History[0])
+	}
+
+	// Returned records must not share history with the handler
+	record.History[0].Reason = "modified"
+	if again, _ := h.GetRecord("dl1"); again.History[0].Reason != "download stalled" {
+		t.Error("GetRecord should return a deep copy")
+	}
+
+	for i := 0; i < MaxHistory+5; i++ {
+		h.Add("dl2", "remove_stalled", "item2", fmt.Sprintf("strike %d", i))
+	}
+
+	record, _ = h.GetRecord("dl2")
+	if len(record.History) != MaxHistory {
+		t.Fatalf("expected history capped at %d, got %d", MaxHistory, len(record.History))
+	}
+	if record.History[0].Reason != "strike 5" {
+		t.Errorf("expected oldest events to be dropped, first is %q", record.History[0].Reason)
+	}
+	if record.Count != MaxHistory+5 {
+		t.Errorf("expected count %d, got %d", MaxHistory+5, record.Count)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	h := NewHandler("", slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	h.Add("removed", "job1", "item1", "")
+	h.Add("recovered", "job1", "item2", "")
+	h.Reset("removed")
+	h.Recover("recovered")
+	h.Recover("unknown")
+
+	if h.Get("recovered") != 0 {
+		t.Errorf("expected strikes cleared on recover, got %d", h.Get("recovered"))
+	}
+
+	added, reset, recovered := h.ResetCycleCounters()
+	if added != 2 {
+		t.Errorf("expected added 2, got %d", added)
+	}
+	if reset != 2 {
+		t.Errorf("expected reset 2, got %d", reset)
+	}
+	if recovered != 1 {
+		t.Errorf("expected recovered 1, got %d", recovered)
+	}
+
+	_, _, recovered = h.ResetCycleCounters()
+	if recovered != 0 {
+		t.Errorf("expected recovered 0 in new cycle, got %d", recovered)
+	}
+}
+
 func TestConcurrentAccess(t *testing.T) {
 	h := NewHandler("", slog.New(slog.NewTextHandler(os.Stderr, nil)))
 
@@ -408,7 +483,7 @@ func TestConcurrentAccess(t *testing.T) {
 		go func(id int) {
 			defer wg.Done()
 			for j := 0; j < operationsPerGoroutine; j++ {
-				h.Add("dl1", "job1", "item1")
+				h.Add("dl1", "job1", "item1", "")
 			}
 		}(i)
 	}
@@ -431,7 +506,7 @@ func TestConcurrentAccess(t *testing.T) {
 			defer wg.Done()
 			dlID := "dl" + string(rune('2'+id))
 			for j := 0; j < operationsPerGoroutine/10; j++ {
-				h.Add(dlID, "job", "item")
+				h.Add(dlID, "job", "item", "")
 				h.Reset(dlID)
 			}
 		}(i)
@@ -450,7 +525,7 @@ func TestGetRecord(t *testing.T) {
 	h := NewHandler("", slog.New(slog.NewTextHandler(os.Stderr, nil)))
 
 	now := time.Now()
-	h.Add("dl1", "job1", "item1")
+	h.Add("dl1", "job1", "item1", "")
 
 	// Get record
 	rec, exists := h.GetRecord("dl1")
@@ -481,9 +556,9 @@ func TestGetRecord(t *testing.T) {
 func TestGetAllRecords(t *testing.T) {
 	h := NewHandler("", slog.New(slog.NewTextHandler(os.Stderr, nil)))
 
-	h.Add("dl1", "job1", "item1")
-	h.Add("dl2", "job2", "item2")
-	h.Add("dl2", "job2", "item2")
+	h.Add("dl1", "job1", "item1", "")
+	h.Add("dl2", "job2", "item2", "")
+	h.Add("dl2", "job2", "item2", "")
 
 	records := h.GetAllRecords()
 
@@ -508,9 +583,9 @@ func TestGetAllRecords(t *testing.T) {
 func TestClear(t *testing.T) {
 	h := NewHandler("", slog.New(slog.NewTextHandler(os.Stderr, nil)))
 
-	h.Add("dl1", "job1", "item1")
-	h.Add("dl2", "job2", "item2")
-	h.Add("dl3", "job3", "item3")
+	h.Add("dl1", "job1", "item1", "")
+	h.Add("dl2", "job2", "item2", "")
+	h.Add("dl3", "job3", "item3", "")
 
 	if h.Count() != 3 {
 		t.Fatalf("expected 3 records before clear, got %d", h.Count())
@@ -523,7 +598,7 @@ func TestClear(t *testing.T) {
 	}
 
 	// Verify we can still add after clear
-	h.Add("dl4", "job4", "item4")
+	h.Add("dl4", "job4", "item4", "")
 	if h.Get("dl4") != 1 {
 		t.Error("expected to be able to add after clear")
 	}
@@ -532,7 +607,7 @@ func TestClear(t *testing.T) {
 func TestSaveNoPersistPath(t *testing.T) {
 	h := NewHandler("", slog.New(slog.NewTextHandler(os.Stderr, nil)))
 
-	h.Add("dl1", "job1", "item1")
+	h.Add("dl1", "job1", "item1", "")
 
 	// Save should be no-op without error
 	if err := h.Save(); err != nil {
@@ -557,7 +632,7 @@ func TestSaveCreateDirectory(t *testing.T) {
 	persistPath := filepath.Join(tmpDir, "subdir", "nested", "strikes.json")
 
 	h := NewHandler(persistPath, slog.New(slog.NewTextHandler(os.Stderr, nil)))
-	h.Add("dl1", "job1", "item1")
+	h.Add("dl1", "job1", "item1", "")
 
 	// Save should create directory
 	if err := h.Save(); err != nil {
@@ -573,13 +648,13 @@ func TestAddUpdatesMetadata(t *testing.T) {
 	h := NewHandler("", slog.New(slog.NewTextHandler(os.Stderr, nil)))
 
 	// First add
-	h.Add("dl1", "job1", "item1")
+	h.Add("dl1", "job1", "item1", "")
 	rec1, _ := h.GetRecord("dl1")
 
 	time.Sleep(10 * time.Millisecond)
 
 	// Second add with different job and name
-	h.Add("dl1", "job2", "item2")
+	h.Add("dl1", "job2", "item2", "")
 	rec2, _ := h.GetRecord("dl1")
 
 	if rec2.Count != 2 {
@@ -604,7 +679,7 @@ func TestSaveAtomicWrite(t *testing.T) {
 	persistPath := filepath.Join(tmpDir, "strikes.json")
 
 	h := NewHandler(persistPath, slog.New(slog.NewTextHandler(os.Stderr, nil)))
-	h.Add("dl1", "job1", "item1")
+	h.Add("dl1", "job1", "item1", "")
 
 	// Save
 	if err := h.Save(); err != nil {
@@ -623,8 +698,215 @@ func TestSaveAtomicWrite(t *testing.T) {
 		t.Fatalf("failed to read persist file: %v", err)
 	}
 
-	var strikes map[string]*StrikeRecord
-	if err := json.Unmarshal(data, &strikes); err != nil {
+	var file strikesFile
+	if err := json.Unmarshal(data, &file); err != nil {
 		t.Errorf("persist file contains invalid JSON: %v", err)
 	}
+	if file.Version != SchemaVersion || file.Strikes["dl1"] == nil {
+		t.Errorf("expected version %d file holding dl1, got version %d with %d records", SchemaVersion, file.Version, len(file.Strikes))
+	}
+}
+
+func TestLoadMigratesVersion0(t *testing.T) {
+	persistPath := filepath.Join(t.TempDir(), "strikes.json")
+
+	// An unversioned file from before strike reasons and history were recorded
+	v0 := `{
+  "abc123": {"count": 2, "first_seen": "2026-01-01T10:00:00Z", "last_seen": "2026-01-01T11:00:00Z", "job": "remove_stalled", "name": "Some.Show.S01E01"},
+  "def456": {"count": 1, "last_seen": "2026-01-02T09:00:00Z", "job": "remove_slow"},
+  "gone": null
+}`
+	if err := os.WriteFile(persistPath, []byte(v0), 0644); err != nil {
+		t.Fatalf("failed to write v0 file: %v", err)
+	}
+
+	var logs bytes.Buffer
+	h := NewHandler(persistPath, slog.New(slog.NewTextHandler(&logs, nil)))
+
+	if h.Count() != 2 {
+		t.Fatalf("expected 2 migrated records, got %d", h.Count())
+	}
+	if strings.Contains(logs.String(), "starting fresh") {
+		t.Error("an old file should be migrated, not discarded")
+	}
+	if !strings.Contains(logs.String(), "migrated strikes file") {
+		t.Error("expected the migration to be logged")
+	}
+
+	rec, _ := h.GetRecord("abc123")
+	if rec.Count != 2 || rec.Job != "remove_stalled" || rec.Name != "Some.Show.S01E01" {
+		t.Errorf("expected existing fields to be kept, got %+v", rec)
+	}
+	lastSeen := time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC)
+	if len(rec.History) != 1 || !rec.History[0].Time.Equal(lastSeen) || rec.History[0].Job != "remove_stalled" {
+		t.Errorf("expected history filled from the last strike, got %+v", rec.History)
+	}
+
+	rec, _ = h.GetRecord("def456")
+	if !rec.FirstSeen.Equal(rec.LastSeen) {
+		t.Errorf("expected missing first_seen to default to last_seen, got %v", rec.FirstSeen)
+	}
+
+	// Further strikes build on the migrated record
+	if got := h.Add("abc123", "remove_stalled", "", "download stalled"); got != 3 {
+		t.Errorf("expected 3 strikes after adding to a migrated record, got %d", got)
+	}
+
+	// Saving writes the current version, which loads without migrating
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	logs.Reset()
+	h2 := NewHandler(persistPath, slog.New(slog.NewTextHandler(&logs, nil)))
+	if h2.Get("abc123") != 3 || h2.Count() != 2 {
+		t.Errorf("expected saved records to reload, got %d records", h2.Count())
+	}
+	if strings.Contains(logs.String(), "migrated") {
+		t.Error("a current file shouldn't be migrated again")
+	}
+}
+
+func TestLoadUnsupportedVersion(t *testing.T) {
+	persistPath := filepath.Join(t.TempDir(), "strikes.json")
+	newer := fmt.Sprintf(`{"version": %d, "strikes": {"abc123": {"count": 1}}}`, SchemaVersion+1)
+	if err := os.WriteFile(persistPath, []byte(newer), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	h := NewHandler("", slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	h.persistPath = persistPath
+	err := h.Load()
+	if err == nil || !strings.Contains(err.Error(), "newer than supported") {
+		t.Errorf("expected a newer version to be rejected, got %v", err)
+	}
+	if h.Count() != 0 {
+		t.Errorf("expected no records from an unsupported file, got %d", h.Count())
+	}
+}
+
+func TestPurge(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	path := filepath.Join(t.TempDir(), "strikes.json")
+
+	now := time.Now()
+	seed := map[string]*StrikeRecord{
+		"old":    {Count: 2, FirstSeen: now.Add(-10 * 24 * time.Hour), LastSeen: now.Add(-8 * 24 * time.Hour), Job: "job1"},
+		"recent": {Count: 1, FirstSeen: now.Add(-time.Hour), LastSeen: now.Add(-time.Hour), Job: "job2"},
+	}
+	data, err := json.Marshal(seed)
+	if err != nil {
+		t.Fatalf("marshal seed: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write seed: %v", err)
+	}
+
+	removed, remaining, err := Purge(path, DefaultMaxAge, logger)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if removed != 1 || remaining != 1 {
+		t.Errorf("expected 1 removed and 1 remaining, got %d and %d", removed, remaining)
+	}
+
+	h := NewHandler(path, logger)
+	if _, ok := h.GetRecord("old"); ok {
+		t.Error("expected old record to be purged from the file")
+	}
+	if _, ok := h.GetRecord("recent"); !ok {
+		t.Error("expected recent record to remain in the file")
+	}
+
+	// A missing file is nothing to purge
+	if _, _, err := Purge(filepath.Join(t.TempDir(), "missing.json"), DefaultMaxAge, logger); err != nil {
+		t.Errorf("unexpected error for missing file: %v", err)
+	}
+
+	// A corrupt file is reported and left untouched
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatalf("write corrupt file: %v", err)
+	}
+	if _, _, err := Purge(path, DefaultMaxAge, logger); err == nil {
+		t.Error("expected error for corrupt file")
+	}
+	if got, _ := os.ReadFile(path); string(got) != "not json" {
+		t.Errorf("corrupt file should not be overwritten, got %q", got)
+	}
+}
+
+func TestReadOnlyDataDir(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T) string // returns the persist path
+	}{
+		{
+			name: "read-only directory",
+			setup: func(t *testing.T) string {
+				dir := t.TempDir()
+				if err := os.Chmod(dir, 0555); err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { _ = os.Chmod(dir, 0755) })
+				if probeWritable(dir) == nil {
+					t.Skip("directory permissions aren't enforced for this user")
+				}
+				return filepath.Join(dir, "strikes.json")
+			},
+		},
+		{
+			name: "directory can't be created",
+			setup: func(t *testing.T) string {
+				file := filepath.Join(t.TempDir(), "not-a-dir")
+				if err := os.WriteFile(file, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return filepath.Join(file, "strikes.json")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tt.setup(t)
+
+			var logs bytes.Buffer
+			h := NewHandler(path, slog.New(slog.NewTextHandler(&logs, nil)))
+			if !h.MemoryOnly() {
+				t.Fatal("expected memory-only mode when the data dir isn't writable")
+			}
+
+			h.Add("dl1", "job1", "item1", "")
+			for i := 0; i < 3; i++ {
+				if err := h.Save(); err != nil {
+					t.Errorf("Save in memory-only mode should be a no-op, got: %v", err)
+				}
+			}
+			if h.Get("dl1") != 1 {
+				t.Error("strikes should still be tracked in memory")
+			}
+			if _, err := os.Stat(path); err == nil {
+				t.Error("strikes file should not have been written")
+			}
+
+			if n := strings.Count(logs.String(), "STRIKES WILL NOT BE SAVED"); n != 1 {
+				t.Errorf("expected a single prominent warning, got %d:\n%s", n, logs.String())
+			}
+		})
+	}
+}
+
+func TestWritableDataDir(t *testing.T) {
+	dir := t.TempDir()
+	h := NewHandler(filepath.Join(dir, "strikes.json"), slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if h.MemoryOnly() {
+		t.Fatal("writable data dir should persist strikes")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("probe file should be removed, found %d entries", len(entries))
+	}
 }
@@ -0,0 +1,178 @@
+// Package tombstones remembers downloads removed recently, so a release the
+// *arr grabs again straight after its removal can be recognized
+package tombstones
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxAge is how long a removal is remembered
+const DefaultMaxAge = 24 * time.Hour
+
+// Tombstone records the recent removals of a download
+type Tombstone struct {
+	Removals    int       `json:"removals"`
+	LastRemoved time.Time `json:"last_removed"`
+	Name        string    `json:"name,omitempty"`
+}
+
+// Set holds the tombstones of recently removed downloads, with persistence
+type Set struct {
+	tombstones  map[string]*Tombstone // key: lowercase download ID
+	mu          sync.RWMutex
+	persistPath string
+	logger      *slog.Logger
+}
+
+// NewSet creates a new tombstone set, loading persisted tombstones from
+// persistPath if set
+func NewSet(persistPath string, logger *slog.Logger) *Set {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	s := &Set{
+		tombstones:  make(map[string]*Tombstone),
+		persistPath: persistPath,
+		logger:      logger.With("component", "tombstones"),
+	}
+
+	if persistPath != "" {
+		if err := s.Load(); err != nil {
+			logger.Warn("failed to load persisted tombstones, starting fresh", "error", err)
+		}
+	}
+
+	return s
+}
+
+// Record marks a download as removed at t, returning how many times it has
+// been removed since its tombstone was last forgotten
+func (s *Set) Record(downloadID, name string, t time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(downloadID)
+	tombstone, ok := s.tombstones[key]
+	if !ok {
+		tombstone = &Tombstone{}
+		s.tombstones[key] = tombstone
+	}
+	tombstone.Removals++
+	tombstone.LastRemoved = t
+	if name != "" {
+		tombstone.Name = name
+	}
+	return tombstone.Removals
+}
+
+// Removals returns how many times a download has recently been removed
+func (s *Set) Removals(downloadID string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if tombstone, ok := s.tombstones[strings.ToLower(downloadID)]; ok {
+		return tombstone.Removals
+	}
+	return 0
+}
+
+// Forget removes a download's tombstone
+func (s *Set) Forget(downloadID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tombstones, strings.ToLower(downloadID))
+}
+
+// Count returns the number of tombstoned downloads
+func (s *Set) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.tombstones)
+}
+
+// Save persists the tombstones to disk
+func (s *Set) Save() error {
+	if s.persistPath == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	data, err := json.MarshalIndent(s.tombstones, "", "  ")
+	s.mu.RUnlock()
+
+	if err != nil {
+		return fmt.Errorf("marshal tombstones: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.persistPath), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	// Write atomically via temp file
+	tmpPath := s.persistPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, s.persistPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	s.logger.Debug("persisted tombstones", "path", s.persistPath, "count", len(s.tombstones))
+	return nil
+}
+
+// Load restores the tombstones from disk
+func (s *Set) Load() error {
+	if s.persistPath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.persistPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // No file yet, not an error
+		}
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := json.Unmarshal(data, &s.tombstones); err != nil {
+		return fmt.Errorf("unmarshal tombstones: %w", err)
+	}
+
+	s.logger.Debug("loaded persisted tombstones", "path", s.persistPath, "count", len(s.tombstones))
+	return nil
+}
+
+// Cleanup forgets downloads last removed longer ago than maxAge
+func (s *Set) Cleanup(maxAge time.Duration) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+
+	for key, tombstone := range s.tombstones {
+		if tombstone.LastRemoved.Before(cutoff) {
+			delete(s.tombstones, key)
+			removed++
+		}
+	}
+
+	if removed > 0 {
+		s.logger.Debug("cleaned up old tombstones", "removed", removed, "max_age", maxAge)
+	}
+
+	return removed
+}
@@ -0,0 +1,47 @@
+package tombstones
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestRecord(t *testing.T) {
+	s := NewSet("", testLogger())
+
+	assert.Equal(t, 0, s.Removals("ABC123"))
+	assert.Equal(t, 1, s.Record("ABC123", "Bad.Release", time.Now()))
+	assert.Equal(t, 2, s.Record("abc123", "", time.Now()), "download IDs are case-insensitive")
+	assert.Equal(t, 2, s.Removals("Abc123"))
+
+	s.Forget("ABC123")
+	assert.Equal(t, 0, s.Removals("ABC123"))
+	assert.Equal(t, 0, s.Count())
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tombstones.json")
+
+	s := NewSet(path, testLogger())
+	s.Record("recent", "Recent.Release", time.Now())
+	s.Record("recent", "Recent.Release", time.Now())
+	s.Record("old", "Old.Release", time.Now().Add(-2*DefaultMaxAge))
+	require.NoError(t, s.Save())
+
+	loaded := NewSet(path, testLogger())
+	assert.Equal(t, 2, loaded.Count())
+	assert.Equal(t, 2, loaded.Removals("recent"))
+
+	assert.Equal(t, 1, loaded.Cleanup(DefaultMaxAge))
+	assert.Equal(t, 0, loaded.Removals("old"), "old removals are forgotten")
+	assert.Equal(t, 2, loaded.Removals("recent"))
+}
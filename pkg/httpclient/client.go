@@ -17,6 +17,11 @@ type Config struct {
 	MaxIdleConns    int
 	IdleConnTimeout time.Duration
 	SkipTLSVerify   bool
+	UserAgent       string            // sent on every request; "" keeps Go's default
+	Headers         map[string]string // extra headers sent on every request
+	// RequestsPerSecond paces requests so a fragile server isn't overwhelmed;
+	// 0 = unlimited
+	RequestsPerSecond float64
 }
 
 // DefaultConfig returns sensible default configuration
@@ -31,8 +36,11 @@ func DefaultConfig() Config {
 
 // Client wraps http.Client with convenient methods
 type Client struct {
-	http    *http.Client
-	timeout time.Duration
+	http      *http.Client
+	timeout   time.Duration
+	userAgent string
+	headers   map[string]string
+	limiter   *limiter // nil = unlimited
 }
 
 // New creates a new HTTP client with the given configuration
@@ -48,15 +56,32 @@ func New(cfg Config) *Client {
 			Transport: transport,
 			Timeout:   cfg.Timeout,
 		},
-		timeout: cfg.Timeout,
+		timeout:   cfg.Timeout,
+		userAgent: cfg.UserAgent,
+		headers:   cfg.Headers,
+		limiter:   newLimiter(cfg.RequestsPerSecond),
 	}
 }
 
 // Do executes HTTP request with context
 // Note: http.Client.Timeout handles the overall timeout including body read.
 // We don't add context timeout here as it would cancel before body is fully read.
+// Configured headers don't replace ones the request already sets, so they
+// can't clobber authentication or content type headers. When requests are
+// rate limited, Do waits its turn first.
 func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if err := c.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
 	req = req.WithContext(ctx)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	for key, value := range c.headers {
+		if req.Header.Get(key) == "" {
+			req.Header.Set(key, value)
+		}
+	}
 	return c.http.Do(req)
 }
 
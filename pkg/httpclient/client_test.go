@@ -209,3 +209,87 @@ func TestClientContextTimeout(t *testing.T) {
 		t.Fatal("expected context timeout error")
 	}
 }
+
+func TestClientUserAgentAndHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("User-Agent"); got != "go-decluttarr-test/1.0" {
+			t.Errorf("expected custom User-Agent, got %q", got)
+		}
+		if got := r.Header.Get("Remote-User"); got != "decluttarr" {
+			t.Errorf("expected Remote-User header, got %q", got)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("configured headers should not replace request headers, got Content-Type %q", got)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := DefaultConfig()
+	cfg.UserAgent = "go-decluttarr-test/1.0"
+	cfg.Headers = map[string]string{
+		"remote-user":  "decluttarr",
+		"Content-Type": "text/plain",
+	}
+	client := New(cfg)
+
+	resp, err := client.PostJSON(context.Background(), server.URL, map[string]string{"key": "value"})
+	if err != nil {
+		t.Fatalf("PostJSON failed: %v", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestClientRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	timeRequests := func(client *Client, n int) time.Duration {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			resp, err := client.Get(context.Background(), server.URL)
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+			_ = resp.Body.Close()
+		}
+		return time.Since(start)
+	}
+
+	// Four requests at 20/s are spaced 50ms apart
+	throttled := DefaultConfig()
+	throttled.RequestsPerSecond = 20
+	if elapsed := timeRequests(New(throttled), 4); elapsed < 150*time.Millisecond {
+		t.Errorf("expected throttled requests to take at least 150ms, took %v", elapsed)
+	}
+
+	if elapsed := timeRequests(New(DefaultConfig()), 4); elapsed >= 150*time.Millisecond {
+		t.Errorf("expected unthrottled requests not to be paced, took %v", elapsed)
+	}
+}
+
+func TestClientRateLimitContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := DefaultConfig()
+	cfg.RequestsPerSecond = 0.5
+	client := New(cfg)
+
+	resp, err := client.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("first request shouldn't wait: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	// The next slot is 2s away, so waiting for it gives up with the context
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.Get(ctx, server.URL); err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded waiting for the rate limit, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package httpclient
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter paces requests to at most one per interval, queueing callers in
+// arrival order. There is no burst: a client idle for a while still waits an
+// interval between its next requests.
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time // earliest time the next request may start
+}
+
+// newLimiter creates a limiter for requestsPerSecond, or nil when it's 0 or
+// less, meaning unlimited
+func newLimiter(requestsPerSecond float64) *limiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return &limiter{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+}
+
+// wait blocks until the caller may make a request, or ctx is done
+func (l *limiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}